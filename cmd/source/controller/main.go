@@ -17,11 +17,18 @@ limitations under the License.
 package main
 
 import (
+	filteredfactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
 
+	"knative.dev/eventing-redis/pkg/source/reconciler/pubsubsource"
 	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
 )
 
 func main() {
-	sharedmain.Main("redis-controller", streamsource.NewController)
+	// The controller only caches the secrets environment variables reference
+	// that are labeled for it.
+	ctx := filteredfactory.WithSelectors(signals.NewContext(), resources.EnvSecretSelector)
+	sharedmain.MainWithContext(ctx, "redis-controller", streamsource.NewController, pubsubsource.NewController)
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+
+	kadapter "knative.dev/eventing-redis/pkg/source/adapter"
+)
+
+// The trim job trims the streams of a source once, following its trimming
+// policy. The controller runs it from a CronJob, with the environment of the
+// receive adapter of the source.
+func main() {
+	ctx := signals.NewContext()
+
+	config := &kadapter.Config{}
+	if err := envconfig.Process("", config); err != nil {
+		log.Fatal("Cannot process environment variables: ", err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("Cannot create logger: ", err)
+	}
+	defer logger.Sync()
+	ctx = logging.WithLogger(ctx, logger.Sugar().With(zap.String("source", config.SourceName)))
+
+	kubeClient := kubernetes.NewForConfigOrDie(injection.ParseAndGetRESTConfigOrDie())
+	if err := kadapter.RunTrimJob(ctx, config, kubeClient.CoreV1()); err != nil {
+		logger.Fatal("Cannot trim the streams", zap.Error(err))
+	}
+}
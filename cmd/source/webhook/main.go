@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/certificates"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/conversion"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	"knative.dev/eventing-redis/pkg/source/apis/feature"
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1beta1"
+	redisstreamsourceinformer "knative.dev/eventing-redis/pkg/source/client/injection/informers/sources/v1alpha1/redisstreamsource"
+)
+
+// types are the resources defaulted and validated by the webhook.
+var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+	v1alpha1.SchemeGroupVersion.WithKind("RedisStreamSource"): &v1alpha1.RedisStreamSource{},
+	v1alpha1.SchemeGroupVersion.WithKind("RedisPubSubSource"): &v1alpha1.RedisPubSubSource{},
+}
+
+func NewValidationAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	featureStore := feature.NewStore(logging.FromContext(ctx).Named("feature-config-store"))
+	featureStore.WatchConfigs(cmw)
+
+	client := kubeclient.Get(ctx)
+	getSecret := func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+		return client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	sourceLister := redisstreamsourceinformer.Get(ctx).Lister()
+	listSources := func(context.Context) ([]*v1alpha1.RedisStreamSource, error) {
+		return sourceLister.List(labels.Everything())
+	}
+
+	return validation.NewAdmissionController(ctx,
+		// Name of the resource webhook.
+		"validation.webhook.redis.sources.knative.dev",
+
+		// The path on which to serve the webhook.
+		"/resource-validation",
+
+		// The resources to validate.
+		types,
+
+		// A function that infuses the context passed to Validate with custom metadata.
+		func(ctx context.Context) context.Context {
+			ctx = v1alpha1.WithSecretGetter(featureStore.ToContext(ctx), getSecret)
+			return v1alpha1.WithSourceLister(ctx, listSources)
+		},
+
+		// Whether to disallow unknown fields.
+		true,
+	)
+}
+
+func NewDefaultingAdmissionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return defaulting.NewAdmissionController(ctx,
+		// Name of the resource webhook.
+		"defaulting.webhook.redis.sources.knative.dev",
+
+		// The path on which to serve the webhook.
+		"/resource-defaulting",
+
+		// The resources to default.
+		types,
+
+		// A function that infuses the context passed to SetDefaults.
+		nil,
+
+		// Whether to disallow unknown fields.
+		true,
+	)
+}
+
+func NewConversionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return conversion.NewConversionController(ctx,
+		// The path on which to serve the webhook.
+		"/resource-conversion",
+
+		// The kinds whose versions are converted. v1beta1 converts to and from
+		// v1alpha1, the version RedisStreamSources are stored as.
+		map[schema.GroupKind]conversion.GroupKindConversion{
+			v1alpha1.Kind("RedisStreamSource"): {
+				DefinitionName: "redisstreamsources.sources.knative.dev",
+				HubVersion:     v1beta1.SchemeGroupVersion.Version,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					v1alpha1.SchemeGroupVersion.Version: &v1alpha1.RedisStreamSource{},
+					v1beta1.SchemeGroupVersion.Version:  &v1beta1.RedisStreamSource{},
+				},
+			},
+		},
+
+		// A function that infuses the context passed to the conversions.
+		nil,
+	)
+}
+
+func main() {
+	ctx := webhook.WithOptions(signals.NewContext(), webhook.Options{
+		ServiceName: "redis-webhook",
+		Port:        webhook.PortFromEnv(8443),
+		SecretName:  webhook.SecretNameFromEnv("redis-webhook-certs"),
+	})
+
+	sharedmain.MainWithContext(ctx, "redis-webhook",
+		certificates.NewController,
+		NewDefaultingAdmissionController,
+		NewValidationAdmissionController,
+		NewConversionController,
+	)
+}
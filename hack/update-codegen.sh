@@ -36,11 +36,23 @@ ${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
   "sources:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
 
+# v1beta1 sources are converted to v1alpha1 by the webhook, the clients only
+# use v1alpha1.
+${CODEGEN_PKG}/generate-groups.sh "deepcopy" \
+  knative.dev/eventing-redis/pkg/source/client knative.dev/eventing-redis/pkg/source/apis \
+  "sources:v1beta1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
+
 ${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
   knative.dev/eventing-redis/pkg/sink/client knative.dev/eventing-redis/pkg/sink/apis \
   "sinks:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
 
+${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
+  knative.dev/eventing-redis/pkg/channel/client knative.dev/eventing-redis/pkg/channel/apis \
+  "messaging:v1alpha1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
+
 group "Knative Codegen"
 
 # Knative Injection
@@ -54,6 +66,11 @@ ${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
   "sinks:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
 
+${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
+  knative.dev/eventing-redis/pkg/channel/client knative.dev/eventing-redis/pkg/channel/apis \
+  "messaging:v1alpha1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
+
 group "Update deps post-codegen"
 
 # Make sure our dependencies are up-to-date
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// RedisChannelConditionReady has status True when the RedisChannel is ready to receive and deliver events.
+	RedisChannelConditionReady = apis.ConditionReady
+
+	// RedisChannelConditionDispatcherReady has status True when every replica of the dispatcher
+	// StatefulSet runs its latest revision and is ready.
+	RedisChannelConditionDispatcherReady apis.ConditionType = "DispatcherReady"
+
+	// RedisChannelConditionAddressable has status True when the Service of the dispatcher exists
+	// and the RedisChannel has its address.
+	RedisChannelConditionAddressable apis.ConditionType = "Addressable"
+
+	// RedisChannelConditionDeadLetterSinkResolved has status True when the dead-letter sink of the
+	// delivery spec of the RedisChannel resolved to a URI, or when there is none.
+	RedisChannelConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
+)
+
+var redisChannelCondSet = apis.NewLivingConditionSet(
+	RedisChannelConditionDispatcherReady,
+	RedisChannelConditionAddressable,
+	RedisChannelConditionDeadLetterSinkResolved,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*RedisChannel) GetConditionSet() apis.ConditionSet {
+	return redisChannelCondSet
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (*RedisChannel) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisChannel")
+}
+
+// GetUntypedSpec returns the spec of the RedisChannel.
+func (c *RedisChannel) GetUntypedSpec() interface{} {
+	return c.Spec
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *RedisChannelStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redisChannelCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *RedisChannelStatus) GetTopLevelCondition() *apis.Condition {
+	return redisChannelCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *RedisChannelStatus) InitializeConditions() {
+	redisChannelCondSet.Manage(s).InitializeConditions()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *RedisChannelStatus) IsReady() bool {
+	return redisChannelCondSet.Manage(s).IsHappy()
+}
+
+// SetAddress sets the address of the channel, the URL of the Service of its dispatcher.
+func (s *RedisChannelStatus) SetAddress(url *apis.URL) {
+	if url == nil {
+		s.Address = nil
+		redisChannelCondSet.Manage(s).MarkFalse(RedisChannelConditionAddressable, "EmptyHostname", "The hostname of the channel is empty.")
+		return
+	}
+	s.Address = &duckv1.Addressable{
+		Name: pointer.String(url.Scheme),
+		URL:  url,
+	}
+	redisChannelCondSet.Manage(s).MarkTrue(RedisChannelConditionAddressable)
+}
+
+// MarkNoAddress sets the condition that the channel has no address, as the
+// Service of its dispatcher cannot be reconciled.
+func (s *RedisChannelStatus) MarkNoAddress(reason, messageFormat string, messageA ...interface{}) {
+	s.Address = nil
+	redisChannelCondSet.Manage(s).MarkFalse(RedisChannelConditionAddressable, reason, messageFormat, messageA...)
+}
+
+// PropagateDispatcherStatus marks the dispatcher ready once every replica of its
+// StatefulSet runs the latest revision, that is delivers to the latest
+// subscribers, and is ready.
+func (s *RedisChannelStatus) PropagateDispatcherStatus(ss *appsv1.StatefulSet) {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	if ss.Status.ObservedGeneration >= ss.Generation && ss.Status.UpdatedReplicas == replicas && ss.Status.ReadyReplicas == replicas {
+		redisChannelCondSet.Manage(s).MarkTrue(RedisChannelConditionDispatcherReady)
+	} else {
+		redisChannelCondSet.Manage(s).MarkUnknown(RedisChannelConditionDispatcherReady, "StatefulSetUnavailable", "The StatefulSet '%s' is rolling out.", ss.Name)
+	}
+}
+
+// MarkDispatcherFailed sets the condition that the dispatcher StatefulSet cannot be reconciled.
+func (s *RedisChannelStatus) MarkDispatcherFailed(reason, messageFormat string, messageA ...interface{}) {
+	redisChannelCondSet.Manage(s).MarkFalse(RedisChannelConditionDispatcherReady, reason, messageFormat, messageA...)
+}
+
+// PropagateSubscribers sets the status of each subscriber: ready once the
+// dispatcher is, as it then delivers to all of them, unknown until then.
+func (s *RedisChannelStatus) PropagateSubscribers(subscribers []eventingduckv1.SubscriberSpec) {
+	ready, message := corev1.ConditionTrue, ""
+	if !s.GetCondition(RedisChannelConditionDispatcherReady).IsTrue() {
+		ready, message = corev1.ConditionUnknown, "The dispatcher is not ready."
+	}
+
+	s.Subscribers = nil
+	for _, sub := range subscribers {
+		s.Subscribers = append(s.Subscribers, eventingduckv1.SubscriberStatus{
+			UID:                sub.UID,
+			ObservedGeneration: sub.Generation,
+			Ready:              ready,
+			Message:            message,
+		})
+	}
+}
+
+// MarkDeadLetterSinkResolved sets the condition that the dead-letter sink resolved to uri.
+func (s *RedisChannelStatus) MarkDeadLetterSinkResolved(uri *apis.URL) {
+	s.DeliveryStatus = eventingduckv1.DeliveryStatus{DeadLetterSinkURI: uri}
+	redisChannelCondSet.Manage(s).MarkTrue(RedisChannelConditionDeadLetterSinkResolved)
+}
+
+// MarkDeadLetterSinkNotResolved sets the condition that the dead-letter sink cannot be resolved.
+func (s *RedisChannelStatus) MarkDeadLetterSinkNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	s.DeliveryStatus = eventingduckv1.DeliveryStatus{}
+	redisChannelCondSet.Manage(s).MarkFalse(RedisChannelConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
+// MarkNoDeadLetterSink sets the condition that no dead-letter sink is configured.
+func (s *RedisChannelStatus) MarkNoDeadLetterSink() {
+	s.DeliveryStatus = eventingduckv1.DeliveryStatus{}
+	redisChannelCondSet.Manage(s).MarkTrueWithReason(RedisChannelConditionDeadLetterSinkResolved, "DeadLetterSinkNotConfigured", "No dead-letter sink is configured.")
+}
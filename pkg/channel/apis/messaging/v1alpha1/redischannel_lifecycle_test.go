@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	_ = duck.VerifyType(&RedisChannel{}, &duckv1.Conditions{})
+	_ = duck.VerifyType(&RedisChannel{}, &eventingduckv1.Channelable{})
+)
+
+var (
+	rolledOutStatefulSet = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dispatcher", Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(1)},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 1, ReadyReplicas: 1},
+	}
+	rollingOutStatefulSet = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dispatcher", Generation: 3},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(1)},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 3, UpdatedReplicas: 0, ReadyReplicas: 1},
+	}
+)
+
+func TestRedisChannelStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name string
+		s    func(s *RedisChannelStatus)
+		want bool
+	}{{
+		name: "initialized",
+		s:    func(s *RedisChannelStatus) {},
+	}, {
+		name: "dispatcher rolled out",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rolledOutStatefulSet)
+		},
+	}, {
+		name: "all ready",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rolledOutStatefulSet)
+			s.SetAddress(apis.HTTP("example"))
+			s.MarkNoDeadLetterSink()
+		},
+		want: true,
+	}, {
+		name: "dispatcher rolling out",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rollingOutStatefulSet)
+			s.SetAddress(apis.HTTP("example"))
+			s.MarkNoDeadLetterSink()
+		},
+	}, {
+		name: "no address",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rolledOutStatefulSet)
+			s.SetAddress(nil)
+			s.MarkNoDeadLetterSink()
+		},
+	}, {
+		name: "dead-letter sink resolved",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rolledOutStatefulSet)
+			s.SetAddress(apis.HTTP("example"))
+			s.MarkDeadLetterSinkResolved(apis.HTTP("dls"))
+		},
+		want: true,
+	}, {
+		name: "dead-letter sink not resolved",
+		s: func(s *RedisChannelStatus) {
+			s.PropagateDispatcherStatus(rolledOutStatefulSet)
+			s.SetAddress(apis.HTTP("example"))
+			s.MarkDeadLetterSinkNotResolved("NotFound", "")
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &RedisChannelStatus{}
+			s.InitializeConditions()
+			test.s(s)
+			if got := s.IsReady(); got != test.want {
+				t.Errorf("IsReady() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedisChannelStatusPropagateSubscribers(t *testing.T) {
+	subscribers := []eventingduckv1.SubscriberSpec{{UID: "a", Generation: 1}, {UID: "b", Generation: 4}}
+
+	s := &RedisChannelStatus{}
+	s.InitializeConditions()
+	s.PropagateDispatcherStatus(rollingOutStatefulSet)
+	s.PropagateSubscribers(subscribers)
+	if got := len(s.Subscribers); got != 2 {
+		t.Fatalf("len(Subscribers) = %d, want 2", got)
+	}
+	for _, sub := range s.Subscribers {
+		if sub.Ready != corev1.ConditionUnknown {
+			t.Errorf("Subscriber %s Ready = %v while rolling out, want Unknown", sub.UID, sub.Ready)
+		}
+	}
+
+	s.PropagateDispatcherStatus(rolledOutStatefulSet)
+	s.PropagateSubscribers(subscribers)
+	for i, sub := range s.Subscribers {
+		if sub.UID != subscribers[i].UID || sub.ObservedGeneration != subscribers[i].Generation {
+			t.Errorf("Subscriber %d = %s/%d, want %s/%d", i, sub.UID, sub.ObservedGeneration, subscribers[i].UID, subscribers[i].Generation)
+		}
+		if sub.Ready != corev1.ConditionTrue {
+			t.Errorf("Subscriber %s Ready = %v once rolled out, want True", sub.UID, sub.Ready)
+		}
+	}
+
+	s.PropagateSubscribers(nil)
+	if len(s.Subscribers) != 0 {
+		t.Errorf("Subscribers = %v without subscribers, want none", s.Subscribers)
+	}
+}
+
+func TestRedisChannelGetGroupVersionKind(t *testing.T) {
+	c := &RedisChannel{}
+	if got, want := c.GetGroupVersionKind().Kind, "RedisChannel"; got != want {
+		t.Errorf("GetGroupVersionKind().Kind = %q, want %q", got, want)
+	}
+	if got, want := c.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition = %v, want %v", got, want)
+	}
+}
+
+func TestRedisChannelStreamName(t *testing.T) {
+	c := &RedisChannel{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "orders"}}
+	if got, want := c.StreamName(), "ns/orders"; got != want {
+		t.Errorf("StreamName() = %q, want %q", got, want)
+	}
+	if got, want := c.Spec.GetMaxLen(), int64(DefaultMaxLen); got != want {
+		t.Errorf("GetMaxLen() = %d, want %d", got, want)
+	}
+	c.Spec.MaxLen = 500
+	if got, want := c.Spec.GetMaxLen(), int64(500); got != want {
+		t.Errorf("GetMaxLen() = %d, want %d", got, want)
+	}
+}
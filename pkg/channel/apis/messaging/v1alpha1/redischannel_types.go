@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisChannel is a channel storing its events in a Redis stream, which are
+// delivered to each of its subscribers through a consumer group of its own.
+type RedisChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisChannelSpec   `json:"spec,omitempty"`
+	Status RedisChannelStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that RedisChannel should be implementing.
+var (
+	_ runtime.Object     = (*RedisChannel)(nil)
+	_ kmeta.OwnerRefable = (*RedisChannel)(nil)
+	_ apis.HasSpec       = (*RedisChannel)(nil)
+	_ duckv1.KRShaped    = (*RedisChannel)(nil)
+)
+
+// DefaultMaxLen is the approximate number of events kept in the stream of a
+// RedisChannel without a MaxLen.
+const DefaultMaxLen = 10000
+
+// RedisChannelSpec defines the desired state of the RedisChannel.
+type RedisChannelSpec struct {
+	// Address is the address of the Redis instance storing the events, as a
+	// redis:// URL.
+	Address string `json:"address"`
+
+	// MaxLen is the approximate number of events kept in the stream of the
+	// channel: older events are trimmed as new ones are added, whether they
+	// were delivered to every subscriber or not. Defaults to DefaultMaxLen.
+	// +optional
+	MaxLen int64 `json:"maxLen,omitempty"`
+
+	// Channel conforms to Duck type Channelable.
+	eventingduckv1.ChannelableSpec `json:",inline"`
+}
+
+// RedisChannelStatus defines the observed state of RedisChannel.
+type RedisChannelStatus struct {
+	// Channel conforms to Duck type ChannelableStatus.
+	eventingduckv1.ChannelableStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisChannelList contains a list of RedisChannel.
+type RedisChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisChannel `json:"items"`
+}
+
+// GetStatus retrieves the status of the RedisChannel. Implements the KRShaped interface.
+func (c *RedisChannel) GetStatus() *duckv1.Status {
+	return &c.Status.Status
+}
+
+// GetMaxLen returns the approximate number of events kept in the stream of the channel.
+func (s *RedisChannelSpec) GetMaxLen() int64 {
+	if s.MaxLen > 0 {
+		return s.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+// StreamName returns the name of the Redis stream storing the events of the
+// channel, scoped to its namespace and name.
+func (c *RedisChannel) StreamName() string {
+	return c.Namespace + "/" + c.Name
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+// FakeRedisChannels implements RedisChannelInterface
+type FakeRedisChannels struct {
+	Fake *FakeMessagingV1alpha1
+	ns   string
+}
+
+var redischannelsResource = v1alpha1.SchemeGroupVersion.WithResource("redischannels")
+
+var redischannelsKind = v1alpha1.SchemeGroupVersion.WithKind("RedisChannel")
+
+// Get takes name of the redisChannel, and returns the corresponding redisChannel object, and an error if there is any.
+func (c *FakeRedisChannels) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RedisChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(redischannelsResource, c.ns, name), &v1alpha1.RedisChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisChannel), err
+}
+
+// List takes label and field selectors, and returns the list of RedisChannels that match those selectors.
+func (c *FakeRedisChannels) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RedisChannelList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(redischannelsResource, redischannelsKind, c.ns, opts), &v1alpha1.RedisChannelList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RedisChannelList{ListMeta: obj.(*v1alpha1.RedisChannelList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RedisChannelList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested redisChannels.
+func (c *FakeRedisChannels) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(redischannelsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a redisChannel and creates it.  Returns the server's representation of the redisChannel, and an error, if there is any.
+func (c *FakeRedisChannels) Create(ctx context.Context, redisChannel *v1alpha1.RedisChannel, opts v1.CreateOptions) (result *v1alpha1.RedisChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(redischannelsResource, c.ns, redisChannel), &v1alpha1.RedisChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisChannel), err
+}
+
+// Update takes the representation of a redisChannel and updates it. Returns the server's representation of the redisChannel, and an error, if there is any.
+func (c *FakeRedisChannels) Update(ctx context.Context, redisChannel *v1alpha1.RedisChannel, opts v1.UpdateOptions) (result *v1alpha1.RedisChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(redischannelsResource, c.ns, redisChannel), &v1alpha1.RedisChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisChannel), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRedisChannels) UpdateStatus(ctx context.Context, redisChannel *v1alpha1.RedisChannel, opts v1.UpdateOptions) (*v1alpha1.RedisChannel, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(redischannelsResource, "status", c.ns, redisChannel), &v1alpha1.RedisChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisChannel), err
+}
+
+// Delete takes name of the redisChannel and deletes it. Returns an error if one occurs.
+func (c *FakeRedisChannels) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(redischannelsResource, c.ns, name, opts), &v1alpha1.RedisChannel{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRedisChannels) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(redischannelsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RedisChannelList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched redisChannel.
+func (c *FakeRedisChannels) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RedisChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(redischannelsResource, c.ns, name, pt, data, subresources...), &v1alpha1.RedisChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisChannel), err
+}
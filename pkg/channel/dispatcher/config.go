@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dispatcher
+
+import (
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+// Config is the configuration of the RedisChannel dispatcher.
+type Config struct {
+	adapter.EnvConfig
+
+	Address string `envconfig:"ADDRESS" required:"true"`
+	Stream  string `envconfig:"STREAM" required:"true"`
+	PodName string `envconfig:"NAME" required:"true"`
+
+	// MaxLen is the approximate number of events kept in the stream, see messagingv1alpha1.RedisChannelSpec.MaxLen.
+	MaxLen int64 `envconfig:"MAX_LEN" default:"10000"`
+
+	// Subscribers are the subscribers of the channel as a JSON list of
+	// eventingduckv1.SubscriberSpec, each with the delivery options used for it.
+	Subscribers string `envconfig:"SUBSCRIBERS"`
+}
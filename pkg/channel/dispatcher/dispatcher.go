@@ -0,0 +1,425 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dispatcher implements the dispatcher of the RedisChannel, which adds
+// the events sent to the channel to its Redis stream, and delivers them to
+// each subscriber through a consumer group of its own.
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	redisParse "github.com/go-redis/redis/v8"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rickb777/date/period"
+	"go.uber.org/zap"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// eventField is the field of the stream entries holding the event, in the
+	// structured mode of the JSON event format.
+	eventField = "event"
+
+	// port is the port the dispatcher receives the events sent to the channel on.
+	port = 8080
+
+	// readCount and readBlock bound the number of entries read at once for a
+	// subscriber, and how long to wait for new ones before checking for
+	// shutdown.
+	readCount = 10
+	readBlock = time.Second
+
+	// retryDelay is how long to wait before reading again for a subscriber
+	// once the connection to Redis failed.
+	retryDelay = time.Second
+
+	// redeliverDelay is how long the entries a subscriber did not accept stay
+	// pending before they are delivered again.
+	redeliverDelay = 30 * time.Second
+
+	// backoffDelay is the delay before the first retry of delivering an event
+	// when the delivery options of the subscriber set none.
+	backoffDelay = 50 * time.Millisecond
+
+	// Extensions set on the events sent to the dead-letter sink, as Knative
+	// Eventing channels and brokers do.
+	errorDestExtension = "knativeerrordest" // the destination that did not accept the event
+	errorCodeExtension = "knativeerrorcode" // the status code of its last response, if any
+)
+
+func NewEnvConfig() adapter.EnvConfigAccessor {
+	return &Config{}
+}
+
+type Dispatcher struct {
+	config      *Config
+	logger      *zap.Logger
+	client      cloudevents.Client
+	subscribers []eventingduckv1.SubscriberSpec
+	pool        *redis.Pool
+
+	// redeliverDelay is how long the entries left pending wait before they
+	// are delivered again.
+	redeliverDelay time.Duration
+}
+
+func NewDispatcher(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
+	config := processed.(*Config)
+
+	return &Dispatcher{
+		config:         config,
+		logger:         logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
+		client:         ceClient,
+		redeliverDelay: redeliverDelay,
+	}
+}
+
+// Start creates the consumer groups of the subscribers, delivers the events of
+// the stream to each of them, and adds the events sent to the channel to the
+// stream, until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	if d.config.Subscribers != "" {
+		if err := json.Unmarshal([]byte(d.config.Subscribers), &d.subscribers); err != nil {
+			d.logger.Error("Invalid subscribers", zap.Error(err))
+			return err
+		}
+	}
+
+	opt, err := redisParse.ParseURL(d.config.Address)
+	if err != nil {
+		d.logger.Error("Invalid Redis address", zap.Error(err))
+		return err
+	}
+	d.pool = &redis.Pool{
+		// Each subscriber holds a connection, the events received share the others.
+		MaxIdle: len(d.subscribers) + 10,
+		Dial: func() (redis.Conn, error) {
+			// No AUTH is sent without a password.
+			return redis.Dial("tcp", opt.Addr,
+				redis.DialUsername(opt.Username),
+				redis.DialPassword(opt.Password),
+				redis.DialDatabase(opt.DB),
+			)
+		},
+	}
+	defer d.pool.Close()
+
+	conn := d.pool.Get()
+	err = d.syncGroups(conn)
+	conn.Close()
+	if err != nil {
+		d.logger.Error("Cannot create the consumer groups of the subscribers", zap.Error(err))
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, sub := range d.subscribers {
+		wg.Add(1)
+		go func(sub eventingduckv1.SubscriberSpec) {
+			defer wg.Done()
+			d.dispatch(ctx, sub)
+		}(sub)
+	}
+	defer wg.Wait()
+
+	receiver, err := cloudevents.NewClientHTTP(cloudevents.WithPort(port))
+	if err != nil {
+		d.logger.Error("Cannot create the receiver", zap.Error(err))
+		return err
+	}
+	d.logger.Info("Receiving events", zap.Int("port", port), zap.Int("subscribers", len(d.subscribers)))
+	return receiver.StartReceiver(ctx, d.receive)
+}
+
+// receive adds the event sent to the channel to the stream, trimming the
+// stream to about MaxLen entries. The sender is told to retry when Redis
+// fails.
+func (d *Dispatcher) receive(ctx context.Context, event cloudevents.Event) protocol.Result {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return cehttp.NewResult(http.StatusBadRequest, "cannot encode the event: %w", err)
+	}
+
+	conn := d.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("XADD", d.config.Stream, "MAXLEN", "~", d.config.MaxLen, "*", eventField, data); err != nil {
+		d.logger.Error("Cannot add the event to the stream", zap.String("id", event.ID()), zap.Error(err))
+		return cehttp.NewResult(http.StatusServiceUnavailable, "cannot store the event: %w", err)
+	}
+	return nil
+}
+
+// syncGroups creates the consumer group of each subscriber, named after its
+// UID, which receives the events added from now on, and deletes the groups of
+// the subscribers removed from the channel.
+func (d *Dispatcher) syncGroups(conn redis.Conn) error {
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", d.config.Stream))
+	if err != nil && !isNoSuchKey(err) {
+		return err
+	}
+
+	subscribed := make(map[string]bool, len(d.subscribers))
+	for _, sub := range d.subscribers {
+		group := string(sub.UID)
+		subscribed[group] = true
+		if _, ok := groups[group]; ok {
+			continue
+		}
+		if err := d.createGroup(conn, group); err != nil {
+			return err
+		}
+	}
+
+	for group := range groups {
+		if subscribed[group] {
+			continue
+		}
+		d.logger.Info("Deleting the consumer group of a removed subscriber", zap.String("subscriber", group))
+		if _, err := conn.Do("XGROUP", "DESTROY", d.config.Stream, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createGroup creates the consumer group of a subscriber, and the stream when
+// it does not exist yet.
+func (d *Dispatcher) createGroup(conn redis.Conn, group string) error {
+	d.logger.Info("Creating the consumer group of a subscriber", zap.String("subscriber", group))
+	_, err := conn.Do("XGROUP", "CREATE", d.config.Stream, group, "$", "MKSTREAM")
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// dispatch delivers the entries of the stream to a subscriber until ctx is
+// done: first the entries it was delivered but did not acknowledge before the
+// dispatcher restarted, then the new ones. Each entry is acknowledged once
+// delivered, or dead-lettered: the others stay pending, and are read again
+// from the pending entries of the consumer once redeliverDelay elapsed, or
+// when the dispatcher restarts.
+func (d *Dispatcher) dispatch(ctx context.Context, sub eventingduckv1.SubscriberSpec) {
+	group := string(sub.UID)
+	logger := d.logger.With(zap.String("subscriber", group))
+
+	var conn redis.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	id := "0" // the pending entries of the consumer
+	// redeliverAt is when to read the entries left pending again.
+	var redeliverAt time.Time
+	for ctx.Err() == nil {
+		if id == ">" && !redeliverAt.IsZero() && !time.Now().Before(redeliverAt) {
+			id = "0"
+			redeliverAt = time.Time{}
+		}
+		if conn == nil {
+			conn = d.pool.Get()
+		}
+		reply, err := redis.Values(conn.Do("XREADGROUP", "GROUP", group, d.config.PodName, "COUNT", readCount, "BLOCK", readBlock.Milliseconds(), "STREAMS", d.config.Stream, id))
+		if errors.Is(err, redis.ErrNil) {
+			continue // no new entry
+		}
+		if err != nil && isNoGroup(err) {
+			// The stream or the group was deleted.
+			if err = d.createGroup(conn, group); err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			logger.Error("Cannot read from the stream", zap.Error(err))
+			conn.Close()
+			conn = nil
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		elems, err := scan.ScanXReadReply(reply, nil)
+		if err != nil || len(elems) != 1 {
+			logger.Error("Unexpected XREADGROUP reply", zap.Error(err))
+			continue
+		}
+		if id != ">" && len(elems[0].Items) == 0 {
+			id = ">" // no more pending entries
+			continue
+		}
+		for _, item := range elems[0].Items {
+			if ctx.Err() != nil {
+				// The rest of the entries read stay pending.
+				break
+			}
+			if !d.deliver(ctx, logger, sub, item) {
+				logger.Warn("Leaving the entry pending", zap.String("id", item.ID))
+				if redeliverAt.IsZero() {
+					redeliverAt = time.Now().Add(d.redeliverDelay)
+				}
+			} else if _, err := conn.Do("XACK", d.config.Stream, group, item.ID); err != nil {
+				logger.Error("Cannot ack message", zap.String("id", item.ID), zap.Error(err))
+			}
+			if id != ">" {
+				id = item.ID
+			}
+		}
+	}
+}
+
+// deliver sends the event of an entry to the subscriber, and its response, if
+// any, to the reply. The event is sent to the dead-letter sink of the
+// subscriber, if any, when either does not accept it after the retries. It
+// returns whether the entry is done with: its event was delivered, or
+// dead-lettered, or it has none.
+func (d *Dispatcher) deliver(ctx context.Context, logger *zap.Logger, sub eventingduckv1.SubscriberSpec, item scan.StreamItem) bool {
+	event, err := entryEvent(item.FieldValues)
+	if err != nil {
+		// Entries were added by the dispatcher, or another writer of the stream.
+		logger.Error("Cannot decode the event of the entry, dropping it", zap.String("id", item.ID), zap.Error(err))
+		return true
+	}
+
+	ctx = withRetries(ctx, sub.Delivery)
+	response := event
+	if sub.SubscriberURI != nil {
+		var result protocol.Result
+		response, result = d.client.Request(cloudevents.ContextWithTarget(ctx, sub.SubscriberURI.String()), *event)
+		if !accepted(result) {
+			logger.Error("Failed to deliver the event to the subscriber", zap.String("id", event.ID()), zap.Any("result", result))
+			return d.deadLetter(ctx, logger, sub, event, sub.SubscriberURI.String(), result)
+		}
+	}
+
+	if response == nil || sub.ReplyURI == nil {
+		return true
+	}
+	if result := d.client.Send(cloudevents.ContextWithTarget(ctx, sub.ReplyURI.String()), *response); !cloudevents.IsACK(result) {
+		logger.Error("Failed to deliver the reply", zap.String("id", event.ID()), zap.Any("result", result))
+		return d.deadLetter(ctx, logger, sub, event, sub.ReplyURI.String(), result)
+	}
+	return true
+}
+
+// deadLetter sends the event the destination did not accept, with the result
+// of the last attempt, to the dead-letter sink of the subscriber, if any. It
+// returns whether the dead-letter sink accepted the event.
+func (d *Dispatcher) deadLetter(ctx context.Context, logger *zap.Logger, sub eventingduckv1.SubscriberSpec, event *cloudevents.Event, destination string, result protocol.Result) bool {
+	if sub.Delivery == nil || sub.Delivery.DeadLetterSink == nil || sub.Delivery.DeadLetterSink.URI == nil {
+		return false
+	}
+
+	copied := event.Clone()
+	copied.SetExtension(errorDestExtension, destination)
+	if statusCode, ok := statusCode(result); ok {
+		copied.SetExtension(errorCodeExtension, statusCode)
+	}
+	ctx = cloudevents.ContextWithTarget(ctx, sub.Delivery.DeadLetterSink.URI.String())
+	if result := d.client.Send(ctx, copied); !cloudevents.IsACK(result) {
+		logger.Error("Failed to send the event to the dead-letter sink", zap.String("id", event.ID()), zap.Any("result", result))
+		return false
+	}
+	return true
+}
+
+// entryEvent returns the event held by an entry of the stream.
+func entryEvent(fieldValues []string) (*cloudevents.Event, error) {
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		if fieldValues[i] != eventField {
+			continue
+		}
+		event := cloudevents.NewEvent()
+		if err := json.Unmarshal([]byte(fieldValues[i+1]), &event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+	return nil, fmt.Errorf("no %q field", eventField)
+}
+
+// withRetries returns a context making the client retry sending an event as
+// the delivery options of the subscriber tell.
+func withRetries(ctx context.Context, delivery *eventingduckv1.DeliverySpec) context.Context {
+	if delivery == nil || delivery.Retry == nil || *delivery.Retry <= 0 {
+		return ctx
+	}
+	delay := backoffDelay
+	if delivery.BackoffDelay != nil {
+		if p, err := period.Parse(*delivery.BackoffDelay); err == nil && p.DurationApprox() > 0 {
+			delay = p.DurationApprox()
+		}
+	}
+	if delivery.BackoffPolicy != nil && *delivery.BackoffPolicy == eventingduckv1.BackoffPolicyLinear {
+		return cloudevents.ContextWithRetriesLinearBackoff(ctx, delay, int(*delivery.Retry))
+	}
+	return cloudevents.ContextWithRetriesExponentialBackoff(ctx, delay, int(*delivery.Retry))
+}
+
+// accepted returns whether the subscriber accepted the event: the client
+// reports the responses without an event as ACK, whatever their status code.
+func accepted(result protocol.Result) bool {
+	if !cloudevents.IsACK(result) {
+		return false
+	}
+	code, ok := statusCode(result)
+	return !ok || code < http.StatusMultipleChoices
+}
+
+// statusCode returns the status code of the last response when sending an
+// event, if any.
+func statusCode(result protocol.Result) (int, bool) {
+	var retries *cehttp.RetriesResult
+	if errors.As(result, &retries) {
+		result = retries.Result
+	}
+	var httpResult *cehttp.Result
+	if !errors.As(result, &httpResult) {
+		return 0, false
+	}
+	return httpResult.StatusCode, true
+}
+
+// isNoSuchKey returns whether the error tells the stream does not exist.
+func isNoSuchKey(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such key") || strings.Contains(msg, "no longer exists")
+}
+
+// isNoGroup returns whether the error tells the consumer group, or the
+// stream, does not exist.
+func isNoGroup(err error) bool {
+	return strings.HasPrefix(err.Error(), "NOGROUP")
+}
@@ -0,0 +1,454 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// fakeConn records the commands sent to Redis, and answers them with reply.
+type fakeConn struct {
+	mu       sync.Mutex
+	commands [][]interface{}
+	reply    func(commandName string, args ...interface{}) (interface{}, error)
+}
+
+func (c *fakeConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if commandName == "" {
+		return nil, nil // flushed when returned to the pool
+	}
+	c.mu.Lock()
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	c.mu.Unlock()
+	if c.reply == nil {
+		return "OK", nil
+	}
+	return c.reply(commandName, args...)
+}
+
+func (c *fakeConn) Close() error                      { return nil }
+func (c *fakeConn) Err() error                        { return nil }
+func (c *fakeConn) Send(string, ...interface{}) error { return nil }
+func (c *fakeConn) Flush() error                      { return nil }
+func (c *fakeConn) Receive() (interface{}, error)     { return nil, nil }
+
+// commandsNamed returns the commands sent with the given name.
+func (c *fakeConn) commandsNamed(name string) (commands [][]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, command := range c.commands {
+		if command[0] == name {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+func newTestDispatcher(t *testing.T, conn *fakeConn, subscribers ...eventingduckv1.SubscriberSpec) *Dispatcher {
+	t.Helper()
+	client, err := cloudevents.NewClientHTTP()
+	require.NoError(t, err)
+	return &Dispatcher{
+		config: &Config{
+			Stream:  "ns/channel",
+			PodName: "dispatcher-0",
+			MaxLen:  100,
+		},
+		logger:      zap.NewNop(),
+		client:      client,
+		subscribers: subscribers,
+		pool:        &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+	}
+}
+
+func newTestEvent(id string) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(id)
+	event.SetType("dev.knative.test")
+	event.SetSource("test")
+	_ = event.SetData(cloudevents.ApplicationJSON, map[string]string{"hello": "world"})
+	return event
+}
+
+// entriesReply returns the reply of XREADGROUP with an entry holding each event.
+func entriesReply(stream string, events ...cloudevents.Event) interface{} {
+	items := []interface{}{}
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		items = append(items, []interface{}{[]byte(event.ID() + "-0"), []interface{}{[]byte(eventField), data}})
+	}
+	return []interface{}{[]interface{}{[]byte(stream), items}}
+}
+
+// eventServer records the events it receives, and answers with status and
+// response, if any.
+type eventServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func newEventServer(t *testing.T, status int, response *cloudevents.Event) *eventServer {
+	s := &eventServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := cehttp.NewEventFromHTTPRequest(r)
+		require.NoError(t, err)
+		s.mu.Lock()
+		s.events = append(s.events, *event)
+		s.mu.Unlock()
+
+		if response != nil {
+			w.Header().Set("ce-specversion", response.SpecVersion())
+			w.Header().Set("ce-id", response.ID())
+			w.Header().Set("ce-type", response.Type())
+			w.Header().Set("ce-source", response.Source())
+			w.Header().Set("Content-Type", response.DataContentType())
+		}
+		w.WriteHeader(status)
+		if response != nil {
+			_, _ = w.Write(response.Data())
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *eventServer) received() []cloudevents.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]cloudevents.Event(nil), s.events...)
+}
+
+func (s *eventServer) url(t *testing.T) *apis.URL {
+	u, err := apis.ParseURL(s.URL)
+	require.NoError(t, err)
+	return u
+}
+
+func TestReceive_AddsEventToStream(t *testing.T) {
+	conn := &fakeConn{}
+	d := newTestDispatcher(t, conn)
+	event := newTestEvent("1")
+
+	result := d.receive(context.Background(), event)
+	require.True(t, cloudevents.IsACK(result), "result: %v", result)
+
+	xadds := conn.commandsNamed("XADD")
+	require.Len(t, xadds, 1)
+	require.Equal(t, []interface{}{"XADD", "ns/channel", "MAXLEN", "~", int64(100), "*", eventField}, xadds[0][:7])
+
+	stored, err := entryEvent([]string{eventField, string(xadds[0][7].([]byte))})
+	require.NoError(t, err)
+	require.Equal(t, event.ID(), stored.ID())
+	require.Equal(t, event.Data(), stored.Data())
+}
+
+func TestReceive_RejectsWhenRedisFails(t *testing.T) {
+	conn := &fakeConn{reply: func(string, ...interface{}) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	}}
+	d := newTestDispatcher(t, conn)
+
+	result := d.receive(context.Background(), newTestEvent("1"))
+	require.False(t, cloudevents.IsACK(result))
+	code, ok := statusCode(result)
+	require.True(t, ok)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestSyncGroups(t *testing.T) {
+	conn := &fakeConn{reply: func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XINFO" {
+			return "OK", nil
+		}
+		group := func(name string) interface{} {
+			return []interface{}{[]byte("name"), []byte(name), []byte("consumers"), int64(1), []byte("pending"), int64(0), []byte("last-delivered-id"), []byte("0-0")}
+		}
+		return []interface{}{group("kept"), group("removed")}, nil
+	}}
+	d := newTestDispatcher(t, conn, eventingduckv1.SubscriberSpec{UID: "kept"}, eventingduckv1.SubscriberSpec{UID: "added"})
+
+	require.NoError(t, d.syncGroups(conn))
+	require.Equal(t, [][]interface{}{
+		{"XGROUP", "CREATE", "ns/channel", "added", "$", "MKSTREAM"},
+	}, filter(conn.commandsNamed("XGROUP"), "CREATE"))
+	require.Equal(t, [][]interface{}{
+		{"XGROUP", "DESTROY", "ns/channel", "removed"},
+	}, filter(conn.commandsNamed("XGROUP"), "DESTROY"))
+}
+
+func TestSyncGroups_CreatesStream(t *testing.T) {
+	conn := &fakeConn{reply: func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName == "XINFO" {
+			return nil, redis.Error("ERR no such key")
+		}
+		return "OK", nil
+	}}
+	d := newTestDispatcher(t, conn, eventingduckv1.SubscriberSpec{UID: "a"})
+
+	require.NoError(t, d.syncGroups(conn))
+	require.Equal(t, [][]interface{}{
+		{"XGROUP", "CREATE", "ns/channel", "a", "$", "MKSTREAM"},
+	}, conn.commandsNamed("XGROUP"))
+}
+
+func filter(commands [][]interface{}, subcommand string) (filtered [][]interface{}) {
+	for _, command := range commands {
+		if command[1] == subcommand {
+			filtered = append(filtered, command)
+		}
+	}
+	return filtered
+}
+
+func TestDeliver_SendsResponseToReply(t *testing.T) {
+	response := newTestEvent("response")
+	subscriber := newEventServer(t, http.StatusOK, &response)
+	reply := newEventServer(t, http.StatusAccepted, nil)
+	d := newTestDispatcher(t, &fakeConn{})
+	sub := eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriber.url(t), ReplyURI: reply.url(t)}
+
+	event := newTestEvent("1")
+	data, _ := json.Marshal(event)
+	require.True(t, d.deliver(context.Background(), d.logger, sub, scan.StreamItem{ID: "1-0", FieldValues: []string{eventField, string(data)}}))
+
+	require.Len(t, subscriber.received(), 1)
+	require.Equal(t, "1", subscriber.received()[0].ID())
+	require.Len(t, reply.received(), 1)
+	require.Equal(t, "response", reply.received()[0].ID())
+}
+
+func TestDeliver_DeadLettersRejectedEvent(t *testing.T) {
+	subscriber := newEventServer(t, http.StatusServiceUnavailable, nil)
+	deadLetterSink := newEventServer(t, http.StatusAccepted, nil)
+	d := newTestDispatcher(t, &fakeConn{})
+	retry := int32(2)
+	sub := eventingduckv1.SubscriberSpec{
+		UID:           "a",
+		SubscriberURI: subscriber.url(t),
+		Delivery: &eventingduckv1.DeliverySpec{
+			Retry:          &retry,
+			BackoffDelay:   pointerTo("PT0.1S"),
+			DeadLetterSink: &duckv1.Destination{URI: deadLetterSink.url(t)},
+		},
+	}
+
+	event := newTestEvent("1")
+	data, _ := json.Marshal(event)
+	require.True(t, d.deliver(context.Background(), d.logger, sub, scan.StreamItem{ID: "1-0", FieldValues: []string{eventField, string(data)}}))
+
+	require.Len(t, subscriber.received(), 3, "the first attempt and the retries")
+	require.Len(t, deadLetterSink.received(), 1)
+	dead := deadLetterSink.received()[0]
+	require.Equal(t, "1", dead.ID())
+	require.Equal(t, subscriber.URL, dead.Extensions()[errorDestExtension])
+	require.EqualValues(t, "503", dead.Extensions()[errorCodeExtension])
+}
+
+func pointerTo(s string) *string {
+	return &s
+}
+
+func TestDispatch_DeliversPendingThenNewEntries(t *testing.T) {
+	subscriber := newEventServer(t, http.StatusAccepted, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var readIDs []string
+	replies := []interface{}{
+		entriesReply("ns/channel", newTestEvent("1")), // pending
+		entriesReply("ns/channel"),                    // no more pending
+		entriesReply("ns/channel", newTestEvent("2")), // new
+	}
+	conn := &fakeConn{}
+	conn.reply = func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XREADGROUP" {
+			return int64(1), nil
+		}
+		readIDs = append(readIDs, args[len(args)-1].(string))
+		if len(replies) == 0 {
+			cancel()
+			return nil, nil // timed out
+		}
+		reply := replies[0]
+		replies = replies[1:]
+		return reply, nil
+	}
+	d := newTestDispatcher(t, conn)
+
+	d.dispatch(ctx, eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriber.url(t)})
+
+	require.Equal(t, []string{"0", "1-0", ">", ">"}, readIDs)
+	require.Len(t, subscriber.received(), 2)
+	require.Equal(t, [][]interface{}{
+		{"XACK", "ns/channel", "a", "1-0"},
+		{"XACK", "ns/channel", "a", "2-0"},
+	}, conn.commandsNamed("XACK"))
+	require.Equal(t, []interface{}{"XREADGROUP", "GROUP", "a", "dispatcher-0", "COUNT", readCount, "BLOCK", readBlock.Milliseconds(), "STREAMS", "ns/channel", "0"}, conn.commandsNamed("XREADGROUP")[0])
+}
+
+func TestDispatch_LeavesUndeliveredEntriesPending(t *testing.T) {
+	subscriber := newEventServer(t, http.StatusServiceUnavailable, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reads := 0
+	conn := &fakeConn{}
+	conn.reply = func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XREADGROUP" {
+			return int64(1), nil
+		}
+		reads++
+		if reads == 1 {
+			return entriesReply("ns/channel", newTestEvent("1")), nil
+		}
+		cancel()
+		return nil, nil
+	}
+	d := newTestDispatcher(t, conn)
+
+	// Without a dead-letter sink, the event the subscriber rejected is kept.
+	d.dispatch(ctx, eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriber.url(t)})
+
+	require.Len(t, subscriber.received(), 1)
+	require.Empty(t, conn.commandsNamed("XACK"))
+}
+
+func TestDispatch_RedeliversPendingEntries(t *testing.T) {
+	// The subscriber rejects the first attempt only.
+	var requests int
+	var mu sync.Mutex
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+	subscriberURL, err := apis.ParseURL(subscriber.URL)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var readIDs []string
+	replies := []interface{}{
+		entriesReply("ns/channel"),                    // no pending entries
+		entriesReply("ns/channel", newTestEvent("1")), // new, rejected
+		entriesReply("ns/channel", newTestEvent("1")), // pending, accepted
+		entriesReply("ns/channel"),                    // no more pending
+	}
+	conn := &fakeConn{}
+	conn.reply = func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XREADGROUP" {
+			return int64(1), nil
+		}
+		readIDs = append(readIDs, args[len(args)-1].(string))
+		if len(replies) == 0 {
+			cancel()
+			return nil, nil // timed out
+		}
+		reply := replies[0]
+		replies = replies[1:]
+		return reply, nil
+	}
+	d := newTestDispatcher(t, conn)
+
+	d.dispatch(ctx, eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriberURL})
+
+	require.Equal(t, []string{"0", ">", "0", "1-0", ">"}, readIDs)
+	require.Equal(t, 2, requests)
+	require.Equal(t, [][]interface{}{
+		{"XACK", "ns/channel", "a", "1-0"},
+	}, conn.commandsNamed("XACK"))
+}
+
+func TestDispatch_StopsDeliveringOnShutdown(t *testing.T) {
+	subscriber := newEventServer(t, http.StatusAccepted, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conn := &fakeConn{}
+	conn.reply = func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XREADGROUP" {
+			return int64(1), nil
+		}
+		// The dispatcher shuts down while the entries are read.
+		cancel()
+		return entriesReply("ns/channel", newTestEvent("1"), newTestEvent("2")), nil
+	}
+	d := newTestDispatcher(t, conn)
+
+	d.dispatch(ctx, eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriber.url(t)})
+
+	require.Empty(t, subscriber.received())
+	require.Empty(t, conn.commandsNamed("XACK"))
+}
+
+func TestDispatch_RecreatesDeletedGroup(t *testing.T) {
+	subscriber := newEventServer(t, http.StatusAccepted, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reads := 0
+	conn := &fakeConn{}
+	conn.reply = func(commandName string, args ...interface{}) (interface{}, error) {
+		if commandName != "XREADGROUP" {
+			return "OK", nil
+		}
+		reads++
+		if reads == 1 {
+			return nil, redis.Error("NOGROUP No such key 'ns/channel' or consumer group 'a' in XREADGROUP with GROUP option")
+		}
+		cancel()
+		return nil, nil
+	}
+	d := newTestDispatcher(t, conn)
+
+	d.dispatch(ctx, eventingduckv1.SubscriberSpec{UID: "a", SubscriberURI: subscriber.url(t)})
+
+	require.Equal(t, [][]interface{}{
+		{"XGROUP", "CREATE", "ns/channel", "a", "$", "MKSTREAM"},
+	}, conn.commandsNamed("XGROUP"))
+}
+
+func TestEntryEvent_MissingField(t *testing.T) {
+	_, err := entryEvent([]string{"other", "value"})
+	require.Error(t, err)
+	_, err = entryEvent(nil) // deleted since it was read
+	require.Error(t, err)
+}
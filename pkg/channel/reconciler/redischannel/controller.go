@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redischannel
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+	"k8s.io/client-go/tools/cache"
+
+	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	statefulsetinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/statefulset"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/resolver"
+
+	"knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	redischannelinformer "knative.dev/eventing-redis/pkg/channel/client/injection/informers/messaging/v1alpha1/redischannel"
+	redischannelreconciler "knative.dev/eventing-redis/pkg/channel/client/injection/reconciler/messaging/v1alpha1/redischannel"
+	"knative.dev/eventing-redis/pkg/reconciler"
+)
+
+// envConfig will be used to extract the required environment variables using
+// github.com/kelseyhightower/envconfig. If this configuration cannot be extracted, then
+// NewController will panic.
+type envConfig struct {
+	Image string `envconfig:"REDISCHANNEL_DISPATCHER_IMAGE" required:"true"`
+}
+
+// NewController initializes the controller and is called by the generated code
+// Registers event handlers to enqueue events
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	env := &envConfig{}
+	if err := envconfig.Process("", env); err != nil {
+		logging.FromContext(ctx).Panicf("unable to process RedisChannel's required environment variables: %v", err)
+	}
+
+	statefulsetInformer := statefulsetinformer.Get(ctx)
+	serviceInformer := serviceinformer.Get(ctx)
+	redisChannelInformer := redischannelinformer.Get(ctx)
+
+	r := &Reconciler{
+		ssr:             &reconciler.StatefulSetReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		svcr:            &reconciler.ServiceReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		configs:         reconcilersource.WatchConfigurations(ctx, component, cmw),
+		dispatcherImage: env.Image,
+	}
+
+	impl := redischannelreconciler.NewImpl(ctx, r)
+
+	r.uriResolver = resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
+
+	logging.FromContext(ctx).Info("Setting up event handlers")
+
+	redisChannelInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	statefulsetInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&v1alpha1.RedisChannel{}),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	serviceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&v1alpha1.RedisChannel{}),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
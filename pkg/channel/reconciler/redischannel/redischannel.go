@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redischannel
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/network"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	redischannelreconciler "knative.dev/eventing-redis/pkg/channel/client/injection/reconciler/messaging/v1alpha1/redischannel"
+	"knative.dev/eventing-redis/pkg/channel/reconciler/redischannel/resources"
+	"knative.dev/eventing-redis/pkg/reconciler"
+)
+
+const component = "redischannel"
+
+func newWarningDeadLetterSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "DeadLetterSinkNotFound", "Dead-letter sink not found: %s", string(b))
+}
+
+// Reconciler reconciles a redischannel object
+type Reconciler struct {
+	ssr             *reconciler.StatefulSetReconciler
+	svcr            *reconciler.ServiceReconciler
+	dispatcherImage string
+	uriResolver     *resolver.URIResolver
+	configs         reconcilersource.ConfigAccessor
+}
+
+// Check that our Reconciler implements ReconcileKind.
+var _ redischannelreconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, channel *messagingv1alpha1.RedisChannel) pkgreconciler.Event {
+	deadLetterSinkURI, event := r.resolveDeadLetterSink(ctx, channel)
+	if event != nil {
+		return event
+	}
+
+	subscribers := effectiveSubscribers(channel, deadLetterSinkURI)
+	expectedStatefulSet := resources.MakeDispatcher(channel, r.dispatcherImage, subscribers)
+	if r.configs != nil {
+		// The dispatcher follows the logging, metrics and tracing
+		// configuration of the controller.
+		container := &expectedStatefulSet.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, r.configs.ToEnvVars()...)
+	}
+	ss, event := r.ssr.ReconcileStatefulSet(ctx, channel, expectedStatefulSet)
+	if ss == nil {
+		channel.Status.MarkDispatcherFailed("StatefulSetFailed", "%v", event)
+		return event
+	}
+	channel.Status.PropagateDispatcherStatus(ss)
+
+	svc, event := r.svcr.ReconcileService(ctx, channel, resources.MakeService(channel))
+	if svc == nil {
+		channel.Status.MarkNoAddress("ServiceFailed", "%v", event)
+		return event
+	}
+	channel.Status.SetAddress(&apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname(svc.Name, svc.Namespace),
+	})
+
+	channel.Status.PropagateSubscribers(channel.Spec.Subscribers)
+	return nil
+}
+
+// resolveDeadLetterSink resolves the dead-letter sink of the channel, if any, to
+// the URI its subscribers without a delivery of their own dead-letter to.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, channel *messagingv1alpha1.RedisChannel) (*apis.URL, pkgreconciler.Event) {
+	if channel.Spec.Delivery == nil || channel.Spec.Delivery.DeadLetterSink == nil {
+		channel.Status.MarkNoDeadLetterSink()
+		return nil, nil
+	}
+
+	dest := channel.Spec.Delivery.DeadLetterSink.DeepCopy()
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = channel.GetNamespace()
+	}
+	uri, err := r.uriResolver.URIFromDestinationV1(ctx, *dest, channel)
+	if err != nil {
+		channel.Status.MarkDeadLetterSinkNotResolved("NotFound", "Dead-letter sink not found: %v", err)
+		return nil, newWarningDeadLetterSinkNotFound(dest)
+	}
+	channel.Status.MarkDeadLetterSinkResolved(uri)
+	return uri, nil
+}
+
+// effectiveSubscribers returns the subscribers of the channel with the delivery the
+// dispatcher follows for each of them: its own, resolved by its Subscription, or
+// else the delivery of the channel with its resolved dead-letter sink.
+func effectiveSubscribers(channel *messagingv1alpha1.RedisChannel, deadLetterSinkURI *apis.URL) []eventingduckv1.SubscriberSpec {
+	subscribers := make([]eventingduckv1.SubscriberSpec, 0, len(channel.Spec.Subscribers))
+	for _, sub := range channel.Spec.Subscribers {
+		sub := *sub.DeepCopy()
+		if sub.Delivery == nil && channel.Spec.Delivery != nil {
+			sub.Delivery = channel.Spec.Delivery.DeepCopy()
+			sub.Delivery.DeadLetterSink = nil
+			if deadLetterSinkURI != nil {
+				sub.Delivery.DeadLetterSink = &duckv1.Destination{URI: deadLetterSinkURI}
+			}
+		}
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers
+}
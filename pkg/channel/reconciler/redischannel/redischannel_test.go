@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redischannel
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+func TestEffectiveSubscribers(t *testing.T) {
+	own := &eventingduckv1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("own-dls.example.com")},
+		Retry:          pointer.Int32(1),
+	}
+	channelDLS := apis.HTTP("channel-dls.example.com")
+
+	tests := []struct {
+		name              string
+		delivery          *eventingduckv1.DeliverySpec
+		deadLetterSinkURI *apis.URL
+		want              []*eventingduckv1.DeliverySpec
+	}{{
+		name: "no channel delivery",
+		want: []*eventingduckv1.DeliverySpec{own, nil},
+	}, {
+		name: "channel delivery",
+		delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{Ref: &duckv1.KReference{Kind: "Service", Name: "dls"}},
+			Retry:          pointer.Int32(3),
+		},
+		deadLetterSinkURI: channelDLS,
+		want: []*eventingduckv1.DeliverySpec{own, {
+			DeadLetterSink: &duckv1.Destination{URI: channelDLS},
+			Retry:          pointer.Int32(3),
+		}},
+	}, {
+		name:     "channel delivery without dead-letter sink",
+		delivery: &eventingduckv1.DeliverySpec{Retry: pointer.Int32(3)},
+		want:     []*eventingduckv1.DeliverySpec{own, {Retry: pointer.Int32(3)}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			channel := &messagingv1alpha1.RedisChannel{
+				Spec: messagingv1alpha1.RedisChannelSpec{
+					ChannelableSpec: eventingduckv1.ChannelableSpec{
+						SubscribableSpec: eventingduckv1.SubscribableSpec{
+							Subscribers: []eventingduckv1.SubscriberSpec{
+								{UID: "with-delivery", Delivery: own},
+								{UID: "without-delivery"},
+							},
+						},
+						Delivery: test.delivery,
+					},
+				},
+			}
+
+			got := effectiveSubscribers(channel, test.deadLetterSinkURI)
+
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d subscribers, want %d", len(got), len(test.want))
+			}
+			for i, want := range test.want {
+				if diff := cmp.Diff(want, got[i].Delivery); diff != "" {
+					t.Errorf("unexpected delivery of %s (-want, +got): %s", got[i].UID, diff)
+				}
+			}
+			if channel.Spec.Subscribers[1].Delivery != nil {
+				t.Error("the subscribers of the channel were modified")
+			}
+		})
+	}
+}
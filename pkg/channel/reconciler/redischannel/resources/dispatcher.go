@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+// DispatcherPort is the port the dispatcher receives the events sent to the channel on.
+const DispatcherPort = 8080
+
+func DispatcherName(channel *messagingv1alpha1.RedisChannel) string {
+	return kmeta.ChildName(fmt.Sprintf("redischannel-%s-", channel.Name), string(channel.UID))
+}
+
+// MakeDispatcher generates (but does not insert into K8s) the dispatcher StatefulSet of
+// a RedisChannel. It has a single replica, whose stable pod name is the consumer
+// name of every consumer group: a restarted dispatcher delivers the entries it
+// read but did not acknowledge before reading new ones.
+//
+// subscribers are the subscribers of the channel with their effective delivery.
+func MakeDispatcher(channel *messagingv1alpha1.RedisChannel, image string, subscribers []eventingduckv1.SubscriberSpec) *appsv1.StatefulSet {
+	labels := Labels(channel.Name)
+
+	value, _ := json.Marshal(subscribers) // subscriber specs always marshal
+	env := []corev1.EnvVar{{
+		Name:  "ADDRESS",
+		Value: channel.Spec.Address,
+	}, {
+		Name:  "STREAM",
+		Value: channel.StreamName(),
+	}, {
+		Name:  "MAX_LEN",
+		Value: strconv.FormatInt(channel.Spec.GetMaxLen(), 10),
+	}, {
+		Name:  "SUBSCRIBERS",
+		Value: string(value),
+	}, {
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}, {
+		Name: "NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: channel.Namespace,
+			Name:      DispatcherName(channel),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(channel),
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Replicas: pointer.Int32(1),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "dispatcher",
+							Image: image,
+							Env:   env,
+							Ports: []corev1.ContainerPort{{
+								Name:          "http",
+								ContainerPort: DispatcherPort,
+								Protocol:      corev1.ProtocolTCP,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+func TestMakeDispatcher(t *testing.T) {
+	channel := &messagingv1alpha1.RedisChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "channel-name",
+			Namespace: "channel-namespace",
+			UID:       "1234",
+		},
+		Spec: messagingv1alpha1.RedisChannelSpec{
+			Address: "redis://redis:6379",
+			MaxLen:  500,
+		},
+	}
+	subscribers := []eventingduckv1.SubscriberSpec{{
+		UID:           "sub-1",
+		SubscriberURI: apis.HTTP("subscriber.example.com"),
+	}}
+
+	got := MakeDispatcher(channel, "test-image", subscribers)
+
+	if got.Namespace != "channel-namespace" || got.Name != DispatcherName(channel) {
+		t.Errorf("StatefulSet = %s/%s, want channel-namespace/%s", got.Namespace, got.Name, DispatcherName(channel))
+	}
+	if got.Spec.Replicas == nil || *got.Spec.Replicas != 1 {
+		t.Errorf("Replicas = %v, want 1", got.Spec.Replicas)
+	}
+	if len(got.OwnerReferences) != 1 || got.OwnerReferences[0].Kind != "RedisChannel" {
+		t.Errorf("OwnerReferences = %v, want the channel", got.OwnerReferences)
+	}
+
+	container := got.Spec.Template.Spec.Containers[0]
+	if container.Image != "test-image" {
+		t.Errorf("Image = %q, want test-image", container.Image)
+	}
+	if len(container.Ports) != 1 || container.Ports[0].ContainerPort != DispatcherPort {
+		t.Errorf("Ports = %v, want %d", container.Ports, DispatcherPort)
+	}
+
+	env := make(map[string]string)
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"ADDRESS": "redis://redis:6379",
+		"STREAM":  "channel-namespace/channel-name",
+		"MAX_LEN": "500",
+	} {
+		if env[name] != want {
+			t.Errorf("%s = %q, want %q", name, env[name], want)
+		}
+	}
+
+	var gotSubscribers []eventingduckv1.SubscriberSpec
+	if err := json.Unmarshal([]byte(env["SUBSCRIBERS"]), &gotSubscribers); err != nil {
+		t.Fatal("SUBSCRIBERS is not a list of subscribers:", err)
+	}
+	if diff := cmp.Diff(subscribers, gotSubscribers); diff != "" {
+		t.Error("unexpected SUBSCRIBERS (-want, +got):", diff)
+	}
+}
+
+func TestMakeService(t *testing.T) {
+	channel := &messagingv1alpha1.RedisChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "channel-name",
+			Namespace: "channel-namespace",
+			UID:       "1234",
+		},
+	}
+
+	got := MakeService(channel)
+
+	if got.Namespace != "channel-namespace" || got.Name != "channel-name-kn-channel" {
+		t.Errorf("Service = %s/%s, want channel-namespace/channel-name-kn-channel", got.Namespace, got.Name)
+	}
+	if diff := cmp.Diff(Labels(channel.Name), got.Spec.Selector); diff != "" {
+		t.Error("unexpected selector (-want, +got):", diff)
+	}
+	if len(got.Spec.Ports) != 1 || got.Spec.Ports[0].Port != 80 || got.Spec.Ports[0].TargetPort != intstr.FromInt(DispatcherPort) {
+		t.Errorf("Ports = %v, want 80 to %d", got.Spec.Ports, DispatcherPort)
+	}
+}
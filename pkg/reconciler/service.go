@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// newServiceCreated makes a new reconciler event with event type Normal, and
+// reason ServiceCreated.
+func newServiceCreated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "ServiceCreated", "created service: \"%s/%s\"", namespace, name)
+}
+
+// newServiceFailed makes a new reconciler event with event type Warning, and
+// reason ServiceFailed.
+func newServiceFailed(namespace, name string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ServiceFailed", "failed to create service: \"%s/%s\", %w", namespace, name, err)
+}
+
+// newServiceUpdated makes a new reconciler event with event type Normal, and
+// reason ServiceUpdated.
+func newServiceUpdated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "ServiceUpdated", "updated service: \"%s/%s\"", namespace, name)
+}
+
+type ServiceReconciler struct {
+	KubeClientSet kubernetes.Interface
+}
+
+func (r *ServiceReconciler) ReconcileService(ctx context.Context, owner kmeta.OwnerRefable, expected *corev1.Service) (*corev1.Service, pkgreconciler.Event) {
+	namespace := owner.GetObjectMeta().GetNamespace()
+	svc, err := r.KubeClientSet.CoreV1().Services(namespace).Get(ctx, expected.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		svc, err = r.KubeClientSet.CoreV1().Services(namespace).Create(ctx, expected, metav1.CreateOptions{})
+		if err != nil {
+			return nil, newServiceFailed(expected.Namespace, expected.Name, err)
+		}
+		return svc, newServiceCreated(svc.Namespace, svc.Name)
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting service %q: %v", expected.Name, err)
+	} else if !metav1.IsControlledBy(svc, owner.GetObjectMeta()) {
+		return nil, fmt.Errorf("service %q is not owned by %s %q",
+			svc.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
+	} else if !equality.Semantic.DeepDerivative(expected.Spec, svc.Spec) {
+		// The cluster IP is allocated by the API server: keep it.
+		clusterIP := svc.Spec.ClusterIP
+		svc.Spec = expected.Spec
+		svc.Spec.ClusterIP = clusterIP
+		if svc, err = r.KubeClientSet.CoreV1().Services(namespace).Update(ctx, svc, metav1.UpdateOptions{}); err != nil {
+			return svc, err
+		}
+		return svc, newServiceUpdated(svc.Namespace, svc.Name)
+	} else {
+		logging.FromContext(ctx).Debugw("Reusing existing service", zap.Any("service", svc))
+	}
+	return svc, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestReconcileStatefulSet_Resources(t *testing.T) {
+	source := &sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "uid"},
+	}
+	statefulSet := func(resources corev1.ResourceRequirements) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            "adapter",
+				OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:      "receive-adapter",
+							Image:     "adapter",
+							Resources: resources,
+						}},
+					},
+				},
+			},
+		}
+	}
+	limited := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+	}
+
+	tests := []struct {
+		name     string
+		existing corev1.ResourceRequirements
+		expected corev1.ResourceRequirements
+		updated  bool
+	}{{
+		name:     "set",
+		expected: limited,
+		updated:  true,
+	}, {
+		name:     "unchanged",
+		existing: limited,
+		expected: limited,
+	}, {
+		name:     "unset",
+		existing: limited,
+		updated:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := kubefake.NewSimpleClientset(statefulSet(test.existing))
+			r := &StatefulSetReconciler{KubeClientSet: client}
+
+			ra, event := r.ReconcileStatefulSet(context.Background(), source, statefulSet(test.expected))
+			if updated := event != nil; updated != test.updated {
+				t.Fatalf("updated = %v (%v), want %v", updated, event, test.updated)
+			}
+
+			got, err := client.AppsV1().StatefulSets("ns").Get(context.Background(), ra.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resources := got.Spec.Template.Spec.Containers[0].Resources; !equality.Semantic.DeepEqual(resources, test.expected) {
+				t.Errorf("resources = %v, want %v", resources, test.expected)
+			}
+		})
+	}
+}
+
+func TestReconcileStatefulSet_TemplateAnnotations(t *testing.T) {
+	source := &sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "uid"},
+	}
+	statefulSet := func(hash string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            "adapter",
+				OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"hash": hash}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "receive-adapter", Image: "adapter"}},
+					},
+				},
+			},
+		}
+	}
+	client := kubefake.NewSimpleClientset(statefulSet("v1"))
+	r := &StatefulSetReconciler{KubeClientSet: client}
+
+	if _, event := r.ReconcileStatefulSet(context.Background(), source, statefulSet("v1")); event != nil {
+		t.Errorf("ReconcileStatefulSet() = %v, want no update", event)
+	}
+	if _, event := r.ReconcileStatefulSet(context.Background(), source, statefulSet("v2")); event == nil {
+		t.Error("ReconcileStatefulSet() = nil, want an update")
+	}
+	got, err := client.AppsV1().StatefulSets("ns").Get(context.Background(), "adapter", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash := got.Spec.Template.Annotations["hash"]; hash != "v2" {
+		t.Errorf("pod template annotation = %q, want %q", hash, "v2")
+	}
+}
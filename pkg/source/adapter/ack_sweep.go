@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+)
+
+// ackSweeper collects the entries delivered by all the consumers, to
+// acknowledge them together with a single XACK instead of one per entry.
+type ackSweeper struct {
+	mu          sync.Mutex
+	ids         []interface{}
+	checkpoints *checkpointer // nil unless the last acknowledged entries are checkpointed
+}
+
+// add records that the entry is to be acknowledged by the next sweep.
+func (s *ackSweeper) add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = append(s.ids, id)
+}
+
+// sweep acknowledges the entries recorded since the last sweep. On failure,
+// they are kept for the next sweep.
+func (s *ackSweeper) sweep(conn redis.Conn, streamName, groupName string) (int, error) {
+	s.mu.Lock()
+	ids := s.ids
+	s.ids = nil
+	s.mu.Unlock()
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if _, err := conn.Do("XACK", append([]interface{}{streamName, groupName}, ids...)...); err != nil {
+		s.mu.Lock()
+		s.ids = append(ids, s.ids...)
+		s.mu.Unlock()
+		return 0, err
+	}
+	if s.checkpoints != nil {
+		for _, id := range ids {
+			s.checkpoints.ack(streamName, groupName, id.(string))
+		}
+	}
+	return len(ids), nil
+}
+
+// sweepAcks acknowledges the delivered entries every interval until ctx is
+// done. The entries delivered afterwards are swept once the consumers stopped.
+func (a *Adapter) sweepAcks(ctx context.Context, pool *redis.Pool, streamName, groupName string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn := pool.Get()
+			a.sweepAcksOnce(conn, streamName, groupName)
+			conn.Close()
+		}
+	}
+}
+
+func (a *Adapter) sweepAcksOnce(conn redis.Conn, streamName, groupName string) {
+	n, err := a.acks.sweep(conn, streamName, groupName)
+	if err != nil {
+		a.logger.Error("Cannot ack messages", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		a.logger.Info("Acknowledged messages", zap.Int("count", n))
+		if a.trims != nil {
+			a.trims.ack()
+		}
+	}
+}
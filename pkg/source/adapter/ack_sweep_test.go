@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProcessEntry_AckSweep(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("2-0"), entryReply("3-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}, acks: &ackSweeper{}}
+
+	for i := 0; i < 3; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+	require.Equal(t, 3, client.sent)
+	require.Empty(t, conn.acks, "entries must not be acknowledged before the sweep")
+
+	// A failed sweep keeps the entries for the next one
+	conn.ackErr = errors.New("connection reset by peer")
+	_, err := a.acks.sweep(conn, "mystream", "mygroup")
+	require.Error(t, err)
+	conn.ackErr = nil
+
+	n, err := a.acks.sweep(conn, "mystream", "mygroup")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []string{"1-0", "2-0", "3-0"}, conn.acks)
+	require.Equal(t, 1, conn.ackCalls)
+
+	// Nothing left to sweep
+	n, err = a.acks.sweep(conn, "mystream", "mygroup")
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.Equal(t, 1, conn.ackCalls)
+}
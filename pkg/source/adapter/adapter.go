@@ -22,28 +22,36 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 	scan "knative.dev/eventing-redis/pkg/source/redis"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	redisParse "github.com/go-redis/redis/v8"
 	"github.com/gomodule/redigo/redis"
 	"go.uber.org/zap"
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
 )
 
 const (
 	// RedisStreamSourceEventType is the default RedisStreamSource CloudEvent type.
 	RedisStreamSourceEventType = "dev.knative.sources.redisstream"
-	blockms                    = 5000                  // block for 5s before timing out
-	count                      = 1                     // read one redis entry at a time
-	retryNumTimes              = 5                     // maximum number for retries  TODO: Can move this to config?
-	retryWaitPeriod            = 50 * time.Millisecond // amount of time to wait (50ms) TODO: Can move this to config?
+	retryNumTimes              = 5                     // default number of retries of delivering an event
+	retryWaitPeriod            = 50 * time.Millisecond // default delay before the first retry
+	sequenceExtension          = "sequence"            // CloudEvents Sequence extension attribute
+	redisSeqExtension          = "redisseq"            // extension attribute holding the shared sequence counter
+	redisAdapterPodExtension   = "redisadapterpod"     // extension attribute holding the name of the adapter pod
+	redisAdapterNodeExtension  = "redisadapternode"    // extension attribute holding the name of the adapter node
 )
 
 func NewEnvConfig() adapter.EnvConfigAccessor {
@@ -51,101 +59,402 @@ func NewEnvConfig() adapter.EnvConfigAccessor {
 }
 
 type Adapter struct {
-	config *Config
-	logger *zap.Logger
-	client cloudevents.Client
-	source string
+	config          *Config
+	logger          *zap.Logger
+	client          cloudevents.Client
+	source          string
+	deliveryWindow  *sourcesv1alpha1.DeliveryWindow
+	sinkHeaders     http.Header
+	additionalSinks []*additionalSink
+	fieldMapping    map[string]string // extension attributes by field of the entries
+	requiredFields  []string          // mapped fields the entries must have
+	schemas         *schemaCache      // nil unless the entries are validated against a schema
+	dedup           dedupStore
+	acks            *ackSweeper   // nil when every entry is acknowledged once delivered
+	trims           *trimmer      // nil unless the stream is trimmed
+	checkpoints     *checkpointer // nil unless the last acknowledged entries are checkpointed
+	cursors         *cursorStore  // where the last acknowledged entries are checkpointed to
+	resumeIDs       sourcesv1alpha1.Cursors
+	pool            *redis.Pool       // connections to retry acks on, when the consumer's one is broken
+	redisTLS        *tls.Config       // nil unless TLS is configured for the connections to Redis
+	passwords       *rotatingPassword // nil unless the Redis password is read from a file or exchanged for an OIDC token
+	drains          *drainer          // nil until started
+	auditor         *auditor
+	failures        *failureReporter
+	deadLetters     cloudevents.Client  // nil unless a dead-letter sink is configured
+	batches         *batchSender        // nil unless entries are delivered in batches
+	connection      connectionReporter  // nil unless the adapter runs for a source
+	leaseHolders    leaseHolderReporter // nil unless the adapter runs for a source
+	connectionState connectionState     // last reported, shared by the health check and the consumers
+	background      sync.WaitGroup      // events sent in the background
+	minID           *scan.StreamID
 }
 
 func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 	config := processed.(*Config)
 
+	var deliveryWindow *sourcesv1alpha1.DeliveryWindow
+	if config.DeliveryWindowStart != "" || config.DeliveryWindowEnd != "" {
+		deliveryWindow = &sourcesv1alpha1.DeliveryWindow{
+			Start:    config.DeliveryWindowStart,
+			End:      config.DeliveryWindowEnd,
+			Timezone: config.DeliveryWindowTimezone,
+		}
+	}
+
 	return &Adapter{
-		config: config,
-		logger: logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
-		client: ceClient,
-		source: fmt.Sprintf("%s/%s", config.Address, config.Stream),
+		config:          config,
+		logger:          logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
+		client:          ceClient,
+		source:          config.eventSource(config.Stream),
+		deliveryWindow:  deliveryWindow,
+		sinkHeaders:     loadSinkHeaders(),
+		additionalSinks: loadAdditionalSinks(),
+		fieldMapping:    loadFieldMapping(),
+		requiredFields:  loadRequiredFields(),
 	}
 }
 
 func (a *Adapter) Start(ctx context.Context) error {
+	// The deliveries in flight when the pod is terminated complete within its
+	// termination grace period.
+	a.drains = a.newDrainer(ctx, a.drainWindow())
+
+	if a.deliveryWindow != nil {
+		if _, err := a.deliveryWindow.Contains(time.Now()); err != nil {
+			a.logger.Error("Invalid delivery window", zap.Error(err))
+			return err
+		}
+	}
+
+	if a.config.MinID != "" {
+		minID, err := scan.ParseID(a.config.MinID, scan.EntryPosition)
+		if err != nil {
+			a.logger.Error("Invalid minimum entry ID", zap.Error(err))
+			return err
+		}
+		a.minID = &minID.StreamID
+	}
+
+	if a.config.DedupKey != "" {
+		a.dedup = &redisSetDedup{key: a.config.DedupKey}
+	}
+
+	if err := a.useRedisTLS(); err != nil {
+		a.logger.Error("Invalid Redis TLS configuration", zap.Error(err))
+		return err
+	}
+
+	if err := a.usePasswordFile(); err != nil {
+		a.logger.Error("Cannot read the Redis password file", zap.Error(err))
+		return err
+	}
+	if a.passwords != nil {
+		if err := a.watchPasswordFile(ctx); err != nil {
+			a.logger.Error("Cannot watch the Redis password file", zap.Error(err))
+			return err
+		}
+	}
+
+	oidcClient := &http.Client{}
+	refreshAfter, err := a.useOIDCPassword(ctx, oidcClient)
+	if err != nil {
+		a.logger.Error("Cannot exchange the OIDC token for the Redis password", zap.Error(err))
+		return err
+	}
+	if a.config.RedisOIDCTokenURL != "" {
+		go a.refreshOIDCPasswordEvery(ctx, oidcClient, refreshAfter)
+	}
+
+	if a.config.CheckpointInterval > 0 {
+		if a.cursors == nil {
+			a.cursors = newCursorStore(ctx, a.config.Namespace, a.config.CursorConfigMap)
+		}
+		if err := a.resumeFromCursors(ctx); err != nil {
+			a.logger.Error("Cannot read the last acknowledged entries", zap.Error(err))
+			return err
+		}
+		go a.checkpointEvery(ctx)
+	}
+
+	if a.config.SourceName != "" {
+		annotator := newSourceAnnotator(ctx, a.config.Namespace, a.config.SourceName)
+		if a.connection == nil {
+			a.connection = annotator
+		}
+		if a.leaseHolders == nil {
+			a.leaseHolders = annotator
+		}
+	}
+
+	// The user is logged to correlate the adapter with the ACL logs of Redis.
+	if username, ok := a.redisUser(); ok {
+		a.logger.Info("Authenticating to Redis", zap.String("username", username))
+	}
 
-	waitGroup := &sync.WaitGroup{}
 	pool := a.newPool(a.config.Address)
+	a.pool = pool
 
 	conn, err := pool.Dial()
 	if err != nil {
+		if scan.IsTLSError(err) {
+			a.logger.Error("Cannot negotiate TLS with Redis", zap.Error(err))
+		}
+		a.reportConnection(ctx, err)
 		return err
 	}
+	defer conn.Close()
 
-	streamName := a.config.Stream
-	groupName := a.config.Group
-	if groupName == "" { //No group was specified in Source Spec
-		groupName = a.config.PodName // Build consumer group name from stateful set pod name of adapter
+	go a.checkConnection(ctx, pool, a.config.HealthCheckInterval)
+
+	numConsumers, err := strconv.Atoi(a.config.NumConsumers)
+	if err != nil {
+		a.logger.Error("Cannot convert numConsumers to int", zap.Error(err))
+		return err
 	}
+	a.logger.Info("Number of consumers from config:", zap.Int("NumConsumers", numConsumers))
 
-	a.logger.Info("Retrieving group info", zap.String("group", groupName))
-	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
+	// All consumers deliver events through the same transport.
+	transport, err := newSinkTransport(numConsumers, a.config.DisableHTTP2)
+	if err != nil {
+		a.logger.Error("Cannot create sink transport", zap.Error(err))
+		return err
+	}
+	if err := a.useSinkTransport(ctx, transport); err != nil {
+		a.logger.Error("Cannot create sink client", zap.Error(err))
+		return err
+	}
+	if err := a.useAuditSink(transport); err != nil {
+		a.logger.Error("Cannot create audit sink client", zap.Error(err))
+		return err
+	}
+	if err := a.useFailureReportSink(transport); err != nil {
+		a.logger.Error("Cannot create failure report sink client", zap.Error(err))
+		return err
+	}
+	if err := a.useDeadLetterSink(transport); err != nil {
+		a.logger.Error("Cannot create dead-letter sink client", zap.Error(err))
+		return err
+	}
+	if err := a.useAdditionalSinks(transport); err != nil {
+		a.logger.Error("Cannot create additional sink clients", zap.Error(err))
+		return err
+	}
 
+	if a.config.SchemaURL != "" {
+		// The consumers start once the schema was downloaded, or could not be.
+		a.schemas = &schemaCache{}
+		client := &http.Client{}
+		go a.fetchSchemaEvery(ctx, client, a.fetchSchema(ctx, client))
+	}
+
+	consume := func(ctx context.Context) error {
+		return a.consume(ctx, pool, conn, numConsumers)
+	}
+	if a.config.LeaseName != "" {
+		err = a.whileLeading(ctx, a.newLeaseLock(ctx), consume)
+	} else {
+		err = consume(ctx)
+	}
+	if a.checkpoints != nil {
+		// The entries acknowledged while draining are checkpointed too.
+		a.checkpoint(context.Background())
+	}
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "no such key") || strings.Contains(strings.ToLower(err.Error()), "no longer exists") {
-			// stream does not exist, may have been deleted accidentally
-			a.logger.Info("Creating stream and consumer group", zap.String("group", groupName))
-			//XGROUP CREATE creates the stream automatically, if it doesn't exist, when MKSTREAM subcommand is specified as last argument
-			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, "$", "MKSTREAM")
-			if err != nil {
-				a.logger.Error("Cannot create stream and consumer group", zap.Error(err))
-				return err
-			}
-		} else {
+		return err
+	}
+
+	a.logger.Info("Done. All consumers are stopped now.")
+
+	return nil
+}
+
+// consume reads the streams with numConsumers consumers until ctx is done,
+// then drains them.
+func (a *Adapter) consume(ctx context.Context, pool *redis.Pool, conn redis.Conn, numConsumers int) error {
+	if streams := a.config.Streams; len(streams) > 1 {
+		return a.runStreams(ctx, pool, conn, streams, numConsumers)
+	}
+
+	// The target ConfigMap, when mounted, overrides the stream and group of the spec.
+	initial := target{stream: a.config.Stream, group: a.config.Group}
+	var changes <-chan target
+	if dir := a.config.TargetPath; dir != "" {
+		spec := initial
+		var err error
+		if initial, err = readTarget(dir, spec); err != nil {
+			a.logger.Error("Cannot read stream target", zap.Error(err))
 			return err
 		}
+		changes = a.watchTarget(ctx, dir, spec, initial, targetPollInterval)
+	}
+
+	return a.followTarget(ctx, initial, changes, func(ctx context.Context, t target) error {
+		a.useTarget(ctx, t)
+		return a.run(ctx, pool, conn, t, numConsumers)
+	})
+}
+
+// run reads the stream of the target with numConsumers consumers until ctx is
+// done, then drains them: the consumers deliver their pending entries, and the
+// delivered entries are acknowledged, before returning.
+func (a *Adapter) run(ctx context.Context, pool *redis.Pool, conn redis.Conn, t target, numConsumers int) error {
+	r, err := a.startReading(ctx, pool, conn, t, numConsumers)
+	if err != nil {
+		return err
+	}
+	a.runConsumers(ctx, pool, []*streamReader{r}, numConsumers)
+	return r.stop(conn)
+}
+
+// streamReader is the state of reading one of the streams of the adapter.
+type streamReader struct {
+	a        *Adapter        // with the configuration and state of the stream
+	ctx      context.Context // tagged with the stream
+	stream   string
+	group    string
+	owned    bool       // whether the group is owned by this pod, and destroyed with it
+	reclaims *reclaimer // nil unless the pending entries are reclaimed
+}
+
+// startReading creates the consumer group of the target if needed, and starts
+// the background tasks of its stream, until ctx is done.
+func (a *Adapter) startReading(ctx context.Context, pool *redis.Pool, conn redis.Conn, t target, numConsumers int) (*streamReader, error) {
+	streamName := t.stream
+	groupName := t.group
+	if groupName == "" { //No group was specified in Source Spec
+		groupName = a.config.PodName // Build consumer group name from stateful set pod name of adapter
+	}
+
+	if err := a.ensureGroup(conn, streamName, groupName); err != nil {
+		return nil, err
+	}
 
+	if tagged, err := a.withStreamTags(ctx, streamName); err != nil {
+		a.logger.Warn("Cannot tag the metrics of the stream", zap.Error(err))
 	} else {
+		ctx = tagged
+	}
 
-		if _, ok := groups[groupName]; ok {
-			a.logger.Info("Reusing consumer group", zap.String("group", groupName))
-		} else {
-			a.logger.Info("Creating consumer group", zap.String("group", groupName))
-			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, "$")
-			if err != nil {
-				a.logger.Error("Cannot create consumer group", zap.Error(err))
-				return err
-			}
+	if interval := a.config.AckSweepInterval; interval > 0 {
+		if minIdle := a.config.ReclaimMinIdleTime; minIdle > 0 && minIdle <= interval {
+			a.logger.Warn("Reclaim minimum idle time is not longer than the ack sweep interval, delivered messages may be reclaimed before they are acknowledged",
+				zap.Duration("minIdleTime", minIdle), zap.Duration("ackSweepInterval", interval))
 		}
+		a.acks = &ackSweeper{checkpoints: a.checkpoints}
+		go a.sweepAcks(ctx, pool, streamName, groupName, interval)
+	}
 
+	if interval := a.config.LagSampleInterval; interval > 0 {
+		go a.sampleLagEvery(ctx, pool, streamName, groupName, interval)
 	}
 
-	numConsumers, err := strconv.Atoi(a.config.NumConsumers)
-	if err != nil {
-		a.logger.Error("Cannot convert numConsumers to int", zap.Error(err))
-		return err
+	if a.config.TrimMaxLen > 0 {
+		a.trims = &trimmer{}
+		go a.trimEvery(ctx, pool, streamName, trimInterval)
+	}
+
+	var reclaims *reclaimer
+	if a.config.ReclaimMinIdleTime > 0 {
+		reclaims = newReclaimer(numConsumers)
+		go a.reclaimPending(ctx, pool, streamName, groupName, reclaims)
+	}
+
+	return &streamReader{a: a, ctx: ctx, stream: streamName, group: groupName, owned: t.group == "", reclaims: reclaims}, nil
+}
+
+// stop waits for the events of the stream sent in the background, acknowledges
+// the delivered entries, and destroys the consumer group owned by this pod.
+// The consumers must be stopped.
+func (r *streamReader) stop(conn redis.Conn) error {
+	a := r.a
+	a.background.Wait() // wait for the events sent in the background
+
+	if a.acks != nil {
+		a.sweepAcksOnce(conn, r.stream, r.group)
 	}
-	a.logger.Info("Number of consumers from config:", zap.Int("NumConsumers", numConsumers))
 
+	a.logger.Info("All consumers are stopped.", zap.String("group", r.group))
+
+	// A group shared by the replicas of the adapter outlives any single
+	// replica, e.g. when scaling down. Only groups owned by this pod are destroyed.
+	if r.owned {
+		_, err := conn.Do("XGROUP", "DESTROY", r.stream, r.group)
+		if err != nil {
+			a.logger.Error("Cannot destroy consumer group", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// runConsumers reads the streams with numConsumers consumers until ctx is
+// done, then drains them. Each consumer reads all the streams at once.
+func (a *Adapter) runConsumers(ctx context.Context, pool *redis.Pool, readers []*streamReader, numConsumers int) {
+	waitGroup := &sync.WaitGroup{}
 	for i := 0; i < numConsumers; i++ {
 		waitGroup.Add(1)
 
 		go func(wg *sync.WaitGroup, j int) {
 			defer wg.Done()
 
-			conn, _ := pool.Dial()
+			conn, err := pool.Dial()
+			if err != nil {
+				a.logger.Error("Cannot connect to Redis", zap.Error(err))
+				return
+			}
 
-			consumerName := fmt.Sprintf("%s-%d", groupName, j)
-			xreadID := "0" //Initial ID to read pending messages
+			// Reading blocks until an entry is added without a read block timeout.
+			var unblocks *unblocker
+			if a.readBlock() == 0 {
+				unblocks = &unblocker{}
+				unblocks.track(conn)
+				stopped := make(chan struct{})
+				defer close(stopped)
+				go a.unblockOnDone(ctx, pool, unblocks, stopped)
+			}
+
+			consumerName := a.consumerName(j)
+			retries := newRetryState(a.config)
+			process := make([]processFunc, len(readers))
+			batches := make([]*batcher, len(readers))
+			xreadIDs := make([]string, len(readers))
+			for k, r := range readers {
+				process[k] = r.a.processEntry
+				if r.a.batches != nil {
+					batches[k] = r.a.newBatcher()
+					process[k] = batches[k].process
+				}
+				xreadIDs[k] = "0" //Initial ID to read pending messages
+			}
 			a.logger.Info("Listening for messages", zap.String("consumerName", consumerName))
 
 			for {
 				select {
 				case <-ctx.Done(): //received a SIGINT or SIGTERM signal. Need to process pending messages and shut down consumer group
 
-					for xreadID == "0" {
-						xreadID = a.processEntry(ctx, conn, streamName, groupName, consumerName, xreadID, true)
-					}
+					for k, r := range readers {
+						xreadID := xreadIDs[k]
+						if batches[k] != nil && len(batches[k].events) > 0 {
+							xreadID = "0" // deliver the batch, whose entries are pending
+						}
+						for xreadID != scan.NewID {
+							if !a.mayDeliver(time.Now()) {
+								r.a.logger.Warn("Drain window elapsed, leaving pending messages for the next start", zap.String("consumerName", consumerName))
+								break
+							}
+							xreadID = process[k](r.ctx, conn, r.stream, r.group, consumerName, xreadID, retries, true)
+						}
 
-					_, err := conn.Do("XGROUP", "DELCONSUMER", streamName, groupName, consumerName)
-					if err != nil {
-						a.logger.Error("Cannot delete consumer", zap.Error(err))
+						// Deleting the consumer drops its pending entries, which
+						// are delivered again on the next start otherwise.
+						if pending, err := hasPending(conn, r.stream, r.group, consumerName); err != nil {
+							r.a.logger.Error("Cannot read pending messages", zap.Error(err))
+						} else if pending {
+							r.a.logger.Info("Keeping consumer with pending messages", zap.String("consumerName", consumerName))
+						} else if _, err := conn.Do("XGROUP", "DELCONSUMER", r.stream, r.group, consumerName); err != nil {
+							r.a.logger.Error("Cannot delete consumer", zap.Error(err))
+						}
 					}
 
 					a.logger.Info("Consumer shut down", zap.String("consumerName", consumerName))
@@ -153,79 +462,493 @@ func (a *Adapter) Start(ctx context.Context) error {
 					conn.Close()
 					return
 				default:
-					xreadID = a.processEntry(ctx, conn, streamName, groupName, consumerName, xreadID, false)
+					if wait := a.untilDeliveryWindow(time.Now()); wait > 0 {
+						a.logger.Info("Outside of delivery window, pausing consumer", zap.String("consumerName", consumerName), zap.Duration("wait", wait))
+						select {
+						case <-ctx.Done():
+						case <-time.After(wait):
+						}
+						continue
+					}
+					for k, r := range readers {
+						if r.reclaims != nil && xreadIDs[k] == scan.NewID && r.reclaims.reclaimed(j) {
+							xreadIDs[k] = "0" // read the entries claimed for this consumer
+						}
+					}
+					if len(readers) == 1 {
+						xreadIDs[0] = process[0](readers[0].ctx, conn, readers[0].stream, readers[0].group, consumerName, xreadIDs[0], retries, false)
+					} else {
+						xreadIDs = a.processStreams(conn, readers, batches, consumerName, xreadIDs, retries)
+					}
+					// The connection dropped, e.g. Redis is restarting, or the password changed.
+					if conn.Err() != nil || a.stalePassword(conn) {
+						if conn, err = a.reconnect(ctx, pool, conn, retries); err != nil {
+							a.logger.Info("Consumer shut down", zap.String("consumerName", consumerName))
+							return
+						}
+						if unblocks != nil {
+							unblocks.track(conn)
+						}
+					}
 				}
 			}
 		}(waitGroup, i)
 	}
 
 	waitGroup.Wait() // wait for all consumers
+}
 
-	a.logger.Info("Quit signal received, gracefully shutdown all consumers.")
+// ensureGroup creates the consumer group, and the stream, when they do not
+// exist. New groups start reading after the last entry they acknowledged, if
+// checkpointed, or else from the configured start ID.
+func (a *Adapter) ensureGroup(conn redis.Conn, streamName, groupName string) error {
+	a.logger.Info("Retrieving group info", zap.String("group", groupName))
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
 
-	_, err = conn.Do("XGROUP", "DESTROY", streamName, groupName)
 	if err != nil {
-		a.logger.Error("Cannot destroy consumer group", zap.Error(err))
-		return err
-	}
-	conn.Close()
+		if strings.Contains(strings.ToLower(err.Error()), "no such key") || strings.Contains(strings.ToLower(err.Error()), "no longer exists") {
+			// stream does not exist, may have been deleted accidentally
+			a.logger.Info("Creating stream and consumer group", zap.String("group", groupName))
+			//XGROUP CREATE creates the stream automatically, if it doesn't exist, when MKSTREAM subcommand is specified as last argument
+			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, a.startID(streamName, groupName), "MKSTREAM")
+			if err != nil {
+				a.logger.Error("Cannot create stream and consumer group", zap.Error(err))
+				return err
+			}
+		} else {
+			return err
+		}
 
-	a.logger.Info("Done. All consumers are stopped now.")
+	} else {
 
+		if _, ok := groups[groupName]; ok {
+			a.logger.Info("Reusing consumer group", zap.String("group", groupName))
+		} else {
+			a.logger.Info("Creating consumer group", zap.String("group", groupName))
+			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, a.startID(streamName, groupName))
+			if err != nil {
+				a.logger.Error("Cannot create consumer group", zap.Error(err))
+				return err
+			}
+		}
+
+	}
 	return nil
 }
 
-func (a *Adapter) processEntry(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, isShuttingDown bool) string {
-	// Retry configuration. Can retry more times to not lose events.
-	ctx = cloudevents.ContextWithRetriesExponentialBackoff(ctx, retryWaitPeriod, retryNumTimes)
+// consumerName returns the name of the j-th consumer of this adapter. It is
+// derived from the stable, ordinal-based name of the StatefulSet pod so that
+// consumers keep their identity, and their pending entries, across restarts and
+// never collide with the consumers of other replicas sharing the same group.
+func (a *Adapter) consumerName(j int) string {
+	return fmt.Sprintf("%s-%d", a.config.PodName, j)
+}
+
+// untilDeliveryWindow returns how long to wait before reading from the stream
+// is allowed again, or zero when no delivery window is configured or now is within it.
+func (a *Adapter) untilDeliveryWindow(now time.Time) time.Duration {
+	if a.deliveryWindow == nil {
+		return 0
+	}
+	wait, _ := a.deliveryWindow.Until(now) // validated in Start
+	return wait
+}
+
+// processFunc reads the next entries of a stream and delivers them, returning
+// the ID to read from in the next iteration, like processEntry.
+type processFunc func(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, retries *retryState, isShuttingDown bool) string
 
+// processEntry reads up to ReadCount entries and delivers them in turn. It
+// returns the ID to read from in the next iteration. The entries after one
+// left to be read again from the pending entries, e.g. when the sink is held,
+// are not delivered before it: they are pending too, and read after it.
+func (a *Adapter) processEntry(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, retries *retryState, isShuttingDown bool) string {
 	//XREAD reads all the pending messages when xreadID=="0" and new messages when xreadID==">"
-	reply, err := conn.Do("XREADGROUP", "GROUP", groupName, consumerName, "COUNT", count, "BLOCK", blockms, "STREAMS", streamName, xreadID)
+	reply, err := conn.Do("XREADGROUP", "GROUP", groupName, consumerName, "COUNT", a.readCount(), "BLOCK", a.readBlock(), "STREAMS", streamName, xreadID)
 	if err != nil {
-		a.logger.Error("Cannot read from stream", zap.Error(err))
+		a.readFailed(err, retries, isShuttingDown)
+		return xreadID
+	}
+	retries.redis.Reset()
+	retries.resharding.Reset()
+	deliveredAt := time.Now()
+
+	items, err := scanEntries(reply)
+	if err != nil {
+		a.logger.Error("Cannot convert reply", zap.Error(err))
 		if !isShuttingDown {
-			time.Sleep(1 * time.Second)
+			time.Sleep(retries.redis.Next())
 		}
 		return xreadID
 	}
+	return a.deliverEntries(ctx, conn, streamName, groupName, consumerName, items, deliveredAt, xreadID, retries, isShuttingDown)
+}
+
+// readFailed logs why reading from the streams failed, and backs off before
+// reading again unless shutting down.
+func (a *Adapter) readFailed(err error, retries *retryState, isShuttingDown bool) {
+	if isResharding(err) {
+		a.logger.Warn("Redis cluster is resharding, waiting before reading from stream", zap.Error(err))
+	} else {
+		a.logger.Error("Cannot read from stream", zap.Error(err))
+	}
+	if !isShuttingDown {
+		time.Sleep(retries.forRedisError(err).Next())
+	}
+}
+
+// deliverEntries delivers the entries read from the stream in turn, and
+// returns the ID to read from in the next iteration, like processEntry.
+func (a *Adapter) deliverEntries(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, items []scan.StreamItem, deliveredAt time.Time, xreadID string, retries *retryState, isShuttingDown bool) string {
+	if len(items) == 0 { // no more pending messages, or timed out blocking
+		return scan.NewID //ID to read new messages in next iteration
+	}
+
+	ctx = a.withDeliveryRetries(ctx)
+	for i := range items {
+		next := a.deliverEntry(ctx, conn, streamName, groupName, consumerName, &items[i], deliveredAt, xreadID, retries, isShuttingDown)
+		if next != xreadID && next != items[i].ID {
+			return next
+		}
+		xreadID = next
+	}
+	return xreadID
+}
 
-	event, err := a.toEvent(reply)
+// deliverEntry delivers an entry read by deliverEntries, and returns the ID to
+// read from next.
+func (a *Adapter) deliverEntry(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, item *scan.StreamItem, deliveredAt time.Time, xreadID string, retries *retryState, isShuttingDown bool) string {
+	event, err := a.newEvent(ctx, item)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "number of items not equal to one (got 0)") || // no more pending messages or
-			strings.Contains(strings.ToLower(err.Error()), "expected a reply of type array") { // Xreadgroup timed out blocking after blockms seconds
-			xreadID = ">" //ID to read new messages in next iteration
-		} else {
-			a.logger.Error("Cannot convert reply", zap.Error(err))
+		a.logger.Error("Cannot convert reply", zap.Error(err))
+		if !isShuttingDown {
+			time.Sleep(retries.redis.Next())
+		}
+		return xreadID
+	}
+
+	a.logger.Info("Consumer read a message", zap.String("consumerName", consumerName))
+	metrics.Record(ctx, entryReadCountM.M(1))
+	ctx, endSpan := a.startEntrySpan(ctx, streamName, event, item.FieldValues)
+	defer endSpan(nil) // the entry was skipped
+	a.setClaimDeadline(event, deliveredAt)
+	a.markRedelivered(event, xreadID)
+
+	if a.belowMinID(event.ID()) {
+		a.logger.Info("Skipping message below the minimum entry ID", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+		return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+	}
+
+	if len(item.FieldValues) == 0 {
+		metrics.Record(ctx, emptyEntryCountM.M(1))
+		switch sourcesv1alpha1.EmptyEntryPolicy(a.config.OnEmptyEntry) {
+		case sourcesv1alpha1.EmptyEntryEmit:
+		case sourcesv1alpha1.EmptyEntryDeadLetter:
+			if err := a.deadLetter(conn, streamName, groupName, event.ID(), deadLetterEmptyEntry, 0, nil); err != nil {
+				a.logger.Error("Cannot dead-letter message without fields", zap.String("id", event.ID()), zap.Error(err))
+				xreadID = "0" //ID to read pending message in next iteration
+				if !isShuttingDown {
+					time.Sleep(retries.forRedisError(err).Next())
+				}
+				return xreadID
+			}
+			a.logger.Info("Dead-lettered message without fields", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+			return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+		default:
+			a.logger.Info("Skipping message without fields", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+			return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+		}
+	}
+
+	if field, missing := a.missingField(item.FieldValues); missing && len(item.FieldValues) > 0 {
+		var acked bool
+		if xreadID, acked = a.dropMissingField(ctx, conn, streamName, groupName, consumerName, event, field, xreadID, retries, isShuttingDown); !acked {
 			if !isShuttingDown {
-				time.Sleep(1 * time.Second)
+				time.Sleep(retries.sink.Next())
 			}
+			return pastPending(xreadID, event.ID())
 		}
 		return xreadID
 	}
 
-	a.logger.Info("Consumer read a message", zap.String("consumerName", consumerName))
+	switch verdict, violation := a.checkSchema(ctx, event, item.FieldValues); verdict {
+	case schemaReject:
+		var acked bool
+		if xreadID, acked = a.rejectSchemaViolation(ctx, conn, streamName, groupName, consumerName, event, violation, xreadID, retries, isShuttingDown); !acked {
+			if !isShuttingDown {
+				time.Sleep(retries.sink.Next())
+			}
+			return pastPending(xreadID, event.ID())
+		}
+		return xreadID
+	case schemaHold:
+		a.logger.Warn("Schema not available, leaving message pending", zap.String("id", event.ID()))
+		if !isShuttingDown {
+			time.Sleep(retries.sink.Next())
+		}
+		return pastPending(xreadID, event.ID())
+	}
+
+	if a.dedup != nil {
+		seen, err := a.dedup.Seen(conn, event.ID())
+		if err != nil {
+			a.logger.Error("Cannot check whether the event was emitted", zap.Error(err))
+			xreadID = "0" //ID to read pending message in next iteration
+			if !isShuttingDown {
+				time.Sleep(retries.forRedisError(err).Next())
+			}
+			return xreadID
+		}
+		if seen {
+			a.logger.Info("Skipping message already emitted", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+			return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+		}
+	}
+
+	if wait := a.untilDeliveryDelay(event.ID(), time.Now()); wait > 0 {
+		// The entry stays pending while waiting, and for the next start when
+		// shutting down. The entries after it are not old enough either.
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Delivery delay not elapsed, leaving pending messages for the next start", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+			return scan.NewID // stop reading pending messages
+		case <-time.After(wait):
+		}
+	}
+
+	if a.config.SequenceCounter {
+		if err := a.stampSequence(ctx, conn, groupName, event); err != nil {
+			a.logger.Error("Cannot increment sequence counter", zap.Error(err))
+			xreadID = "0" //ID to read pending message in next iteration
+			if !isShuttingDown {
+				time.Sleep(retries.forRedisError(err).Next())
+			}
+			return xreadID
+		}
+	}
+
+	result := a.client.Send(a.withSinkHeaders(a.sendContext(ctx), event), *event)
+	endSpan(result)
+	if a.alreadyDelivered(result) {
+		a.logger.Info("Sink already has the event", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+		retries.sink.Reset()
+	} else if !cloudevents.IsACK(result) {
+		metrics.Record(ctx, deliveryFailureCountM.M(1))
+		if a.config.HoldOnSinkUnavailable && !isShuttingDown && sinkUnavailable(result) {
+			a.logger.Warn("Sink is unavailable, holding message", zap.String("consumerName", consumerName), zap.Any("result", result))
+			select {
+			case <-ctx.Done():
+			case <-time.After(retries.sink.Next()):
+			}
+			return "0" //ID to read pending message in next iteration
+		}
+		a.reportFailure(ctx, groupName, result)
+		if a.sendToDeadLetterSink(ctx, event, result) {
+			a.logger.Warn("Failed to send cloudevent, sent it to the dead-letter sink", zap.String("id", event.ID()), zap.Any("result", result))
+			return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+		}
+		// The entry stays pending, to be delivered again on the next start.
+		a.logger.Error("Failed to send cloudevent, leaving message pending", zap.String("id", event.ID()), zap.Any("result", result))
+		if !isShuttingDown {
+			time.Sleep(retries.sink.Next())
+		}
+		return pastPending(xreadID, event.ID())
+	} else {
+		retries.sink.Reset()
+	}
+	metrics.Record(ctx, eventDeliveredCountM.M(1))
 
-	if result := a.client.Send(ctx, *event); !cloudevents.IsACK(result) { //  Event is lost
-		a.logger.Error("Failed to send cloudevent", zap.Any("result", result))
+	if !a.sendToAdditionalSinks(ctx, event) {
+		if isShuttingDown {
+			a.logger.Warn("Required sink did not get the event, leaving pending messages for the next start", zap.String("consumerName", consumerName))
+			return scan.NewID // stop reading pending messages
+		}
+		a.logger.Warn("Required sink did not get the event, holding message", zap.String("consumerName", consumerName))
+		select {
+		case <-ctx.Done():
+		case <-time.After(retries.sink.Next()):
+		}
+		return "0" //ID to read pending message in next iteration
 	}
 
-	_, err = conn.Do("XACK", streamName, groupName, event.ID())
+	if a.dedup != nil {
+		if err := a.dedup.Record(conn, event.ID()); err != nil {
+			a.logger.Error("Cannot record the emitted event, it may be emitted again", zap.String("id", event.ID()), zap.Error(err))
+		}
+	}
+
+	if a.config.DisableAutoAck {
+		a.audit(ctx, event)
+		a.confirmDelivery(ctx, event.ID(), event, a.callbackURL(item.FieldValues))
+		return pastPending(xreadID, event.ID())
+	}
+
+	err = a.ackWithRetries(ctx, conn, streamName, groupName, event.ID())
 	if err != nil {
 		a.logger.Error("Cannot ack message", zap.Error(err))
 		xreadID = "0" //ID to read pending message in next iteration
 		if !isShuttingDown {
-			time.Sleep(1 * time.Second)
+			time.Sleep(retries.forRedisError(err).Next())
 		}
 		return xreadID
 	}
 	a.logger.Info("Consumer acknowledged the message", zap.String("consumerName", consumerName))
+
+	a.audit(ctx, event)
+	a.confirmDelivery(ctx, event.ID(), event, a.callbackURL(item.FieldValues))
 	return xreadID
 }
 
-func (a *Adapter) newPool(address string) *redis.Pool {
-	opt, err := redisParse.ParseURL(address)
+// pastPending returns the ID to read from after leaving an entry pending:
+// new entries when reading them, or the pending entries after it otherwise,
+// instead of reading the same entry again.
+func pastPending(xreadID, id string) string {
+	if xreadID == scan.NewID {
+		return xreadID
+	}
+	return id
+}
+
+// hasPending returns whether the consumer still has pending entries.
+func hasPending(conn redis.Conn, streamName, groupName, consumerName string) (bool, error) {
+	pending, err := redis.Values(conn.Do("XPENDING", streamName, groupName, "-", "+", 1, consumerName))
+	return len(pending) > 0, err
+}
+
+// markRedelivered appends the redelivered type suffix to the type of an event
+// read from the pending entries of the consumer, which were delivered before.
+func (a *Adapter) markRedelivered(event *cloudevents.Event, xreadID string) {
+	if a.config.RedeliveredTypeSuffix == "" || xreadID == scan.NewID {
+		return
+	}
+	event.SetType(event.Type() + a.config.RedeliveredTypeSuffix)
+}
+
+// ack acknowledges the entry, or records it to be acknowledged by the next
+// sweep when acks are swept.
+func (a *Adapter) ack(conn redis.Conn, streamName, groupName, id string) error {
+	if a.acks != nil {
+		a.acks.add(id)
+		return nil
+	}
+	_, err := conn.Do("XACK", streamName, groupName, id)
+	if err == nil && a.trims != nil {
+		a.trims.ack()
+	}
+	if err == nil && a.checkpoints != nil {
+		a.checkpoints.ack(streamName, groupName, id)
+	}
+	return err
+}
+
+// ackSkipped acknowledges an entry skipped without being delivered.
+func (a *Adapter) ackSkipped(conn redis.Conn, streamName, groupName, id, xreadID string, retries *retryState, isShuttingDown bool) string {
+	if err := a.ack(conn, streamName, groupName, id); err != nil {
+		a.logger.Error("Cannot ack message", zap.Error(err))
+		xreadID = "0" //ID to read pending message in next iteration
+		if !isShuttingDown {
+			time.Sleep(retries.forRedisError(err).Next())
+		}
+	}
+	return xreadID
+}
+
+// setBinaryData sets the data of the event to the raw bytes of the configured
+// field, if the entry has it.
+func (a *Adapter) setBinaryData(event *cloudevents.Event, fieldValues []string) {
+	contentType := a.config.BinaryDataContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		if fieldValues[i] == a.config.BinaryDataField {
+			// Strings hold the bytes read from Redis as is.
+			_ = event.SetData(contentType, []byte(fieldValues[i+1]))
+			return
+		}
+	}
+}
+
+// untilDeliveryDelay returns how long to wait before the entry with the given
+// ID is old enough to be delivered, or zero when no delivery delay is configured.
+// It is at most the delivery delay, for entries added with an explicit ID in
+// the future, or while the clocks of Redis and the adapter are skewed.
+func (a *Adapter) untilDeliveryDelay(id string, now time.Time) time.Duration {
+	if a.config.DeliveryDelay <= 0 {
+		return 0
+	}
+	sid, err := scan.ParseStreamID(id)
 	if err != nil {
-		panic(err)
+		return 0
+	}
+	wait := time.UnixMilli(int64(sid.Ms)).Add(a.config.DeliveryDelay).Sub(now)
+	if wait > a.config.DeliveryDelay {
+		return a.config.DeliveryDelay
+	}
+	return wait
+}
+
+// belowMinID returns true when the entry ID is before the configured minimum
+// entry ID, if any.
+func (a *Adapter) belowMinID(id string) bool {
+	if a.minID == nil {
+		return false
+	}
+	sid, err := scan.ParseStreamID(id)
+	return err == nil && sid.Less(*a.minID)
+}
+
+// sinkUnavailable returns true when the sink could not be reached, e.g. because
+// it has no address or refuses connections, or responded that it is temporarily
+// unavailable. Other failures, such as an event that cannot be encoded or a
+// sink that does not respond in time, are not holding the entries.
+func sinkUnavailable(result protocol.Result) bool {
+	statusCode, ok := sinkStatusCode(result)
+	if !ok {
+		var retries *cehttp.RetriesResult
+		if errors.As(result, &retries) {
+			result = retries.Result
+		}
+		var opErr *net.OpError
+		var dnsErr *net.DNSError
+		return errors.As(result, &opErr) || errors.As(result, &dnsErr)
+	}
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// sinkStatusCode returns the status code of the last response of the sink
+// when sending an event, if any.
+func sinkStatusCode(result protocol.Result) (int, bool) {
+	var retries *cehttp.RetriesResult
+	if errors.As(result, &retries) {
+		result = retries.Result
+	}
+	var httpResult *cehttp.Result
+	if !errors.As(result, &httpResult) {
+		return 0, false
+	}
+	return httpResult.StatusCode, true
+}
+
+func (a *Adapter) newPool(address string) *redis.Pool {
+	var dial func() (redis.Conn, error)
+	switch {
+	case a.config.SentinelMasterName != "":
+		dial = a.dialMaster
+	case len(a.config.ClusterAddresses) > 0:
+		dial = a.dialStreamNode
+	default:
+		opt, err := scan.ParseAddress(address)
+		if err != nil {
+			panic(err)
+		}
+		dial = func() (redis.Conn, error) {
+			return a.dial(opt)
+		}
 	}
 
 	return &redis.Pool{
@@ -236,40 +959,120 @@ func (a *Adapter) newPool(address string) *redis.Pool {
 		// Dial is an application supplied function for creating and
 		// configuring a connection.
 		Dial: func() (redis.Conn, error) {
-			var c redis.Conn
-			if opt.Password != "" && a.config.TLSCertificate != "" {
-				roots := x509.NewCertPool()
-				ok := roots.AppendCertsFromPEM([]byte(a.config.TLSCertificate))
-				if !ok {
-					panic(err)
-				}
-				c, err = redis.Dial("tcp", opt.Addr,
-					redis.DialUsername(opt.Username),
-					redis.DialPassword(opt.Password),
-					redis.DialTLSConfig(&tls.Config{
-						RootCAs: roots,
-					}),
-					redis.DialTLSSkipVerify(true),
-					redis.DialUseTLS(true),
-					redis.DialDatabase(opt.DB),
-				)
+			if a.passwords == nil {
+				conn, err := dial()
 				if err != nil {
-					panic(err)
-				}
-			} else {
-				c, err = redis.Dial("tcp", opt.Addr,
-					redis.DialDatabase(opt.DB),
-				)
-				if err != nil {
-					panic(err)
+					return nil, err
 				}
+				return &timedConn{Conn: conn, observe: recordCommandLatency}, nil
+			}
+			// The generation is read first: a password changing while
+			// dialing only makes the connection be replaced once more.
+			_, generation := a.passwords.get()
+			conn, err := dial()
+			if err != nil {
+				return nil, err
+			}
+			return &passwordConn{Conn: &timedConn{Conn: conn, observe: recordCommandLatency}, generation: generation}, nil
+		},
+		// Idle connections authenticated with a former password are
+		// replaced, so that they do not outlive its revocation.
+		TestOnBorrow: func(conn redis.Conn, _ time.Time) error {
+			if a.stalePassword(conn) {
+				return errPasswordChanged
 			}
-			return c, err
+			return nil
 		},
 	}
 }
 
+// useRedisTLS configures TLS for the connections to Redis, when the source has
+// a TLS secret.
+func (a *Adapter) useRedisTLS() error {
+	if a.config.RedisTLSCACert == "" {
+		return nil
+	}
+	tlsConfig, err := scan.NewTLSConfig([]byte(a.config.RedisTLSCACert), []byte(a.config.RedisTLSCert), []byte(a.config.RedisTLSKey))
+	if err != nil {
+		return err
+	}
+	tlsConfig.InsecureSkipVerify = a.config.RedisTLSInsecureSkipVerify
+	a.redisTLS = tlsConfig
+	return nil
+}
+
+// dial connects to Redis.
+func (a *Adapter) dial(opt *redisParse.Options) (redis.Conn, error) {
+	if password := a.redisPassword(); password != "" {
+		// An empty username authenticates the default user with the
+		// password only.
+		auth := *opt
+		auth.Username, auth.Password = a.config.RedisUsername, password
+		opt = &auth
+	}
+	if a.redisTLS != nil {
+		return redis.Dial("tcp", opt.Addr,
+			redis.DialUsername(opt.Username),
+			redis.DialPassword(opt.Password),
+			redis.DialTLSConfig(a.redisTLS),
+			redis.DialUseTLS(true),
+			redis.DialDatabase(opt.DB),
+		)
+	}
+	if opt.Password != "" && a.config.TLSCertificate != "" {
+		roots := x509.NewCertPool()
+		if ok := roots.AppendCertsFromPEM([]byte(a.config.TLSCertificate)); !ok {
+			return nil, errors.New("cannot parse TLS certificate")
+		}
+		return redis.Dial("tcp", opt.Addr,
+			redis.DialUsername(opt.Username),
+			redis.DialPassword(opt.Password),
+			redis.DialTLSConfig(&tls.Config{
+				RootCAs: roots,
+			}),
+			redis.DialTLSSkipVerify(true),
+			redis.DialUseTLS(true),
+			redis.DialDatabase(opt.DB),
+		)
+	}
+	// No AUTH is sent without a password.
+	return redis.Dial("tcp", opt.Addr,
+		redis.DialUsername(opt.Username),
+		redis.DialPassword(opt.Password),
+		redis.DialDatabase(opt.DB),
+	)
+}
+
 func (a *Adapter) toEvent(reply interface{}) (*cloudevents.Event, error) {
+	item, err := scanEntry(reply)
+	if err != nil {
+		return nil, err
+	}
+	return a.newEvent(context.Background(), item)
+}
+
+// scanEntries returns the entries read by XREADGROUP, none when it timed out
+// blocking.
+func scanEntries(reply interface{}) ([]scan.StreamItem, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, nil // nil when timed out blocking
+	}
+
+	// Assert only one stream
+	if len(values) != 1 {
+		return nil, fmt.Errorf("number of values not equal to one (got %d)", len(values))
+	}
+
+	elems, err := scan.ScanXReadReply(values, nil)
+	if err != nil {
+		return nil, err
+	}
+	return elems[0].Items, nil
+}
+
+// scanEntry returns the single entry read by XREADGROUP.
+func scanEntry(reply interface{}) (*scan.StreamItem, error) {
 	values, err := redis.Values(reply, nil)
 	if err != nil {
 		return nil, errors.New("expected a reply of type array")
@@ -290,13 +1093,47 @@ func (a *Adapter) toEvent(reply interface{}) (*cloudevents.Event, error) {
 		return nil, fmt.Errorf("number of items not equal to one (got %d)", len(elems[0].Items))
 	}
 
-	item := elems[0].Items[0]
+	return &elems[0].Items[0], nil
+}
 
+// newEvent returns the event built from the entry.
+func (a *Adapter) newEvent(ctx context.Context, item *scan.StreamItem) (*cloudevents.Event, error) {
 	event := cloudevents.NewEvent()
-	event.SetType(RedisStreamSourceEventType)
+	eventType, fallback := a.eventType(item.FieldValues)
+	if fallback && len(item.FieldValues) > 0 {
+		metrics.Record(ctx, eventTypeFallbackCountM.M(1))
+	}
+	event.SetType(eventType)
 	event.SetSource(a.source)
-	event.SetData(cloudevents.ApplicationJSON, item.FieldValues)
+	fieldValues := a.mapFields(&event, item.FieldValues)
+	if a.config.BinaryDataField != "" {
+		a.setBinaryData(&event, item.FieldValues)
+	} else if a.config.DataEncoding == sourcesv1alpha1.DataEncodingMsgPack {
+		event.SetData(msgPackContentType, marshalMsgPack(fieldValues))
+	} else {
+		data, err := a.marshalJSON(fieldValues)
+		if err != nil {
+			return nil, err
+		}
+		event.SetData(cloudevents.ApplicationJSON, data)
+	}
 	event.SetID(item.ID)
+	if a.config.PartitionKeyField != "" {
+		a.setPartitionKey(&event, item.FieldValues)
+	}
+	if a.config.SequenceExtension {
+		// Entry IDs (<ms>-<seq>) increase monotonically within a stream.
+		event.SetExtension(sequenceExtension, item.ID)
+	}
+	if len(a.config.Streams) > 1 {
+		event.SetExtension(redisStreamExtension, a.config.Stream)
+	}
+	if a.config.ProvenanceExtensions {
+		event.SetExtension(redisAdapterPodExtension, a.config.PodName)
+		if a.config.NodeName != "" {
+			event.SetExtension(redisAdapterNodeExtension, a.config.NodeName)
+		}
+	}
 
 	return &event, nil
 }
@@ -18,9 +18,22 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/kelseyhightower/envconfig"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
 )
 
 func TestAdapter_Start(t *testing.T) {
@@ -33,3 +46,412 @@ func TestAdapter_Start(t *testing.T) {
 
 	cancel()
 }
+
+func TestAdapter_ConsumerName(t *testing.T) {
+	a := &Adapter{config: &Config{Group: "mygroup", PodName: "redissource-mystream-1234-2"}}
+
+	require.Equal(t, "redissource-mystream-1234-2-0", a.consumerName(0))
+	require.Equal(t, "redissource-mystream-1234-2-3", a.consumerName(3))
+
+	other := &Adapter{config: &Config{Group: "mygroup", PodName: "redissource-mystream-1234-0"}}
+	require.NotEqual(t, a.consumerName(0), other.consumerName(0))
+}
+
+func TestAdapter_SequenceExtension(t *testing.T) {
+	reply := entryReply("1601553600000-3").reply
+
+	a := &Adapter{config: &Config{}}
+	event, err := a.toEvent(reply)
+	require.NoError(t, err)
+	require.NotContains(t, event.Extensions(), sequenceExtension)
+
+	a = &Adapter{config: &Config{SequenceExtension: true}}
+	event, err = a.toEvent(reply)
+	require.NoError(t, err)
+	require.Equal(t, "1601553600000-3", event.Extensions()[sequenceExtension])
+	require.Equal(t, event.ID(), event.Extensions()[sequenceExtension])
+}
+
+func TestAdapter_ProvenanceExtensions(t *testing.T) {
+	reply := entryReply("1601553600000-3").reply
+
+	a := &Adapter{config: &Config{PodName: "source-abc-0", NodeName: "node-1"}}
+	event, err := a.toEvent(reply)
+	require.NoError(t, err)
+	require.NotContains(t, event.Extensions(), redisAdapterPodExtension)
+	require.NotContains(t, event.Extensions(), redisAdapterNodeExtension)
+
+	t.Setenv("ADDRESS", "redis:6379")
+	t.Setenv("STREAM", "mystream")
+	t.Setenv("GROUP", "mygroup")
+	t.Setenv("NAME", "source-abc-0")
+	t.Setenv("NUM_CONSUMERS", "1")
+	t.Setenv("TLS_CERTIFICATE", "")
+	t.Setenv("PROVENANCE_EXTENSIONS", "true")
+	t.Setenv("NODE_NAME", "node-1")
+	config := &Config{}
+	require.NoError(t, envconfig.Process("", config))
+
+	a = &Adapter{config: config}
+	event, err = a.toEvent(reply)
+	require.NoError(t, err)
+	require.Equal(t, "source-abc-0", event.Extensions()[redisAdapterPodExtension])
+	require.Equal(t, "node-1", event.Extensions()[redisAdapterNodeExtension])
+}
+
+func TestSinkUnavailable(t *testing.T) {
+	ctx := cloudevents.ContextWithRetriesExponentialBackoff(context.Background(), time.Millisecond, 1)
+
+	send := func(t *testing.T, target string) protocol.Result {
+		client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(target))
+		require.NoError(t, err)
+		event := cloudevents.NewEvent()
+		event.SetID("1-0")
+		event.SetType(RedisStreamSourceEventType)
+		event.SetSource("test")
+		return client.Send(ctx, event)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	require.True(t, sinkUnavailable(send(t, closed.URL)))
+	require.False(t, sinkUnavailable(errors.New("cannot encode event")))
+	require.False(t, sinkUnavailable(context.DeadlineExceeded))
+
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{statusCode: http.StatusBadRequest},
+		{statusCode: http.StatusNotFound},
+		{statusCode: http.StatusInternalServerError},
+		{statusCode: http.StatusBadGateway, want: true},
+		{statusCode: http.StatusServiceUnavailable, want: true},
+		{statusCode: http.StatusGatewayTimeout, want: true},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.statusCode), func(t *testing.T) {
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCode)
+			}))
+			defer sink.Close()
+			require.Equal(t, test.want, sinkUnavailable(send(t, sink.URL)))
+		})
+	}
+}
+
+func TestProcessEntry_HoldOnSinkUnavailable(t *testing.T) {
+	unavailable := cehttp.NewResult(http.StatusServiceUnavailable, "no healthy upstream")
+
+	tests := []struct {
+		name     string
+		hold     bool
+		result   protocol.Result
+		wantID   string
+		wantAcks []string
+	}{{
+		name:   "hold",
+		hold:   true,
+		result: unavailable,
+		wantID: "0",
+	}, {
+		name:   "hold, sink rejects the event",
+		hold:   true,
+		result: cehttp.NewResult(http.StatusBadRequest, "invalid event"),
+		wantID: ">",
+	}, {
+		name:   "no hold",
+		result: unavailable,
+		wantID: ">",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			client := &fakeClient{results: []protocol.Result{test.result}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{HoldOnSinkUnavailable: test.hold}}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+			require.Equal(t, test.wantID, xreadID)
+			require.Equal(t, test.wantAcks, conn.acks)
+		})
+	}
+
+	// Shutting down stops holding.
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	client := &fakeClient{results: []protocol.Result{unavailable}}
+	retries := testRetryState()
+	retries.sink = backoff{initial: time.Hour, max: time.Hour}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{HoldOnSinkUnavailable: true}}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	require.Equal(t, "0", a.processEntry(ctx, conn, "mystream", "mygroup", "consumer", ">", retries, false))
+	require.Less(t, time.Since(start), time.Second)
+
+	// Delivery resumes from the pending entries once the sink is back.
+	conn = &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("1-0")}}
+	client = &fakeClient{results: []protocol.Result{unavailable, protocol.ResultACK}}
+	a = &Adapter{logger: zap.NewNop(), client: client, config: &Config{HoldOnSinkUnavailable: true}}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	xreadID = a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, testRetryState(), false)
+	require.Equal(t, "0", xreadID)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestProcessEntry_LeavePending(t *testing.T) {
+	rejected := cehttp.NewResult(http.StatusBadRequest, "invalid event")
+
+	tests := []struct {
+		name           string
+		disableAutoAck bool
+		xreadID        string
+		result         protocol.Result
+		wantID         string
+		wantAcks       []string
+	}{{
+		name:     "delivered",
+		xreadID:  ">",
+		result:   protocol.ResultACK,
+		wantID:   ">",
+		wantAcks: []string{"1-0"},
+	}, {
+		name:    "failed",
+		xreadID: ">",
+		result:  rejected,
+		wantID:  ">",
+	}, {
+		name:    "failed pending entry",
+		xreadID: "0",
+		result:  rejected,
+		wantID:  "1-0",
+	}, {
+		name:           "auto ack disabled",
+		disableAutoAck: true,
+		xreadID:        ">",
+		result:         protocol.ResultACK,
+		wantID:         ">",
+	}, {
+		name:           "auto ack disabled, pending entry",
+		disableAutoAck: true,
+		xreadID:        "0",
+		result:         protocol.ResultACK,
+		wantID:         "1-0",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			client := &fakeClient{results: []protocol.Result{test.result}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{DisableAutoAck: test.disableAutoAck}}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", test.xreadID, testRetryState(), false)
+			require.Equal(t, test.wantID, xreadID)
+			require.Equal(t, test.wantAcks, conn.acks)
+		})
+	}
+
+	// Skipped entries are acknowledged even when auto ack is disabled.
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	a := &Adapter{logger: zap.NewNop(), client: &fakeClient{}, config: &Config{DisableAutoAck: true}, minID: &scan.StreamID{Ms: 2}}
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestProcessEntry_MinID(t *testing.T) {
+	ids := []string{"1-0", "5-2", "5-3", "4-9", "6-0", "5-1"}
+	reads := make([]fakeReply, len(ids))
+	for i, id := range ids {
+		reads[i] = entryReply(id)
+	}
+	conn := &fakeConn{reads: reads}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}, minID: &scan.StreamID{Ms: 5, Seq: 3}}
+
+	for range ids {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+
+	var sent []string
+	for _, event := range client.events {
+		sent = append(sent, event.ID())
+	}
+	require.Equal(t, []string{"5-3", "6-0"}, sent)
+	require.Equal(t, ids, conn.acks)
+}
+
+func TestProcessEntry_RedeliveredTypeSuffix(t *testing.T) {
+	tests := map[string]struct {
+		suffix  string
+		xreadID string
+		want    string
+	}{
+		"first delivery": {
+			suffix:  ".redelivered",
+			xreadID: ">",
+			want:    RedisStreamSourceEventType,
+		},
+		"redelivery": {
+			suffix:  ".redelivered",
+			xreadID: "0",
+			want:    RedisStreamSourceEventType + ".redelivered",
+		},
+		"redelivery without suffix": {
+			xreadID: "0",
+			want:    RedisStreamSourceEventType,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{RedeliveredTypeSuffix: test.suffix}}
+
+			a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", test.xreadID, testRetryState(), false)
+
+			require.Len(t, client.events, 1)
+			require.Equal(t, test.want, client.events[0].Type())
+			require.Equal(t, "1-0", client.events[0].ID())
+		})
+	}
+}
+
+func TestAdapter_UntilDeliveryDelay(t *testing.T) {
+	now := time.UnixMilli(1600000060000)
+
+	a := &Adapter{config: &Config{DeliveryDelay: time.Minute}}
+	require.Equal(t, time.Minute, a.untilDeliveryDelay("1600000060000-0", now))
+	require.Equal(t, 30*time.Second, a.untilDeliveryDelay("1600000030000-5", now))
+	require.LessOrEqual(t, a.untilDeliveryDelay("1600000000000-0", now), time.Duration(0))
+	require.LessOrEqual(t, a.untilDeliveryDelay("1500000000000-0", now), time.Duration(0))
+	require.Equal(t, time.Minute, a.untilDeliveryDelay("1700000000000-0", now))
+
+	noDelay := &Adapter{config: &Config{}}
+	require.Zero(t, noDelay.untilDeliveryDelay("1600000060000-0", now))
+}
+
+func TestProcessEntry_DeliveryDelay(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	entryAt := func(at time.Time) fakeReply {
+		return entryReply(fmt.Sprintf("%d-0", at.UnixMilli()))
+	}
+
+	tests := []struct {
+		name    string
+		entry   fakeReply
+		wantMin time.Duration
+		wantMax time.Duration
+	}{{
+		name:    "new entry is deferred",
+		entry:   entryAt(time.Now()),
+		wantMin: delay / 2,
+		wantMax: 2 * delay,
+	}, {
+		name:    "old entry is delivered immediately",
+		entry:   entryAt(time.Now().Add(-time.Hour)),
+		wantMax: delay / 2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{test.entry}}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{DeliveryDelay: delay}}
+
+			start := time.Now()
+			a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+			elapsed := time.Since(start)
+
+			require.Equal(t, 1, client.sent)
+			require.GreaterOrEqual(t, elapsed, test.wantMin)
+			require.Less(t, elapsed, test.wantMax)
+		})
+	}
+}
+
+func TestProcessEntry_DeliveryDelayOnShutdown(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply(fmt.Sprintf("%d-0", time.Now().UnixMilli()))}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{DeliveryDelay: time.Hour}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	next := a.processEntry(ctx, conn, "mystream", "mygroup", "consumer", "0", testRetryState(), true)
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, scan.NewID, next)
+	require.Zero(t, client.sent)
+	require.Empty(t, conn.acks)
+}
+
+func TestAdapter_BinaryData(t *testing.T) {
+	payload := []byte{0xff, 0xfe, 0x00, 0x80, 'a', 0xc3, 0x28, '\n'}
+	reply := func(fieldValues ...[]byte) []interface{} {
+		fvs := make([]interface{}, len(fieldValues))
+		for i, fv := range fieldValues {
+			fvs[i] = fv
+		}
+		return []interface{}{
+			[]interface{}{[]byte("mystream"), []interface{}{
+				[]interface{}{[]byte("1-0"), fvs},
+			}},
+		}
+	}
+
+	var gotBody []byte
+	var gotContentType string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		contentType     string
+		reply           []interface{}
+		wantContentType string
+		wantData        []byte
+	}{{
+		name:            "default content type",
+		reply:           reply([]byte("key"), []byte("k1"), []byte("payload"), payload),
+		wantContentType: "application/octet-stream",
+		wantData:        payload,
+	}, {
+		name:            "content type",
+		contentType:     "application/protobuf",
+		reply:           reply([]byte("payload"), payload),
+		wantContentType: "application/protobuf",
+		wantData:        payload,
+	}, {
+		name:  "missing field",
+		reply: reply([]byte("key"), []byte("k1")),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := &Adapter{source: "test", config: &Config{BinaryDataField: "payload", BinaryDataContentType: test.contentType}}
+
+			event, err := a.toEvent(test.reply)
+			require.NoError(t, err)
+			require.Equal(t, test.wantData, event.Data())
+			require.Equal(t, test.wantContentType, event.DataContentType())
+
+			gotBody, gotContentType = nil, ""
+			require.True(t, cloudevents.IsACK(client.Send(context.Background(), *event)))
+			if test.wantData != nil {
+				require.Equal(t, test.wantData, gotBody)
+				require.Equal(t, test.wantContentType, gotContentType)
+			} else {
+				require.Empty(t, gotBody)
+			}
+		})
+	}
+}
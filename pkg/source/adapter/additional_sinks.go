@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+)
+
+const (
+	bestEffortTimeout = 5 * time.Second
+
+	// maxBestEffortInFlight bounds the deliveries to a slow best-effort sink.
+	// Events are not sent to it beyond it.
+	maxBestEffortInFlight = 100
+)
+
+// additionalSink is a sink the events are sent to along with the sink.
+type additionalSink struct {
+	uri string
+	// required sinks must get the events before their entries are acknowledged.
+	required bool
+	client   cloudevents.Client
+	inFlight chan struct{}
+}
+
+// loadAdditionalSinks reads the additional sinks from the
+// ADDITIONAL_SINK_<i>_URI and ADDITIONAL_SINK_<i>_REQUIRED environment variables.
+func loadAdditionalSinks() []*additionalSink {
+	var sinks []*additionalSink
+	for i := 0; ; i++ {
+		uri, ok := os.LookupEnv(fmt.Sprintf("ADDITIONAL_SINK_%d_URI", i))
+		if !ok {
+			break
+		}
+		required, _ := strconv.ParseBool(os.Getenv(fmt.Sprintf("ADDITIONAL_SINK_%d_REQUIRED", i)))
+		sinks = append(sinks, &additionalSink{uri: uri, required: required})
+	}
+	return sinks
+}
+
+// useAdditionalSinks creates the clients sending events to the additional
+// sinks through the given transport.
+func (a *Adapter) useAdditionalSinks(transport http.RoundTripper) error {
+	for _, sink := range a.additionalSinks {
+		client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.uri), cehttp.WithRoundTripper(transport))
+		if err != nil {
+			return err
+		}
+		sink.client = client
+		sink.inFlight = make(chan struct{}, maxBestEffortInFlight)
+	}
+	return nil
+}
+
+// sendToAdditionalSinks sends the event to the additional sinks. The required
+// sinks are sent the event concurrently, and waited for, while the best-effort
+// ones are sent it in the background. It returns false when a required sink
+// did not get the event.
+func (a *Adapter) sendToAdditionalSinks(ctx context.Context, event *cloudevents.Event) bool {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	delivered := true
+	for _, sink := range a.additionalSinks {
+		if !sink.required {
+			a.sendBestEffort(ctx, sink, event)
+			continue
+		}
+
+		wg.Add(1)
+		go func(sink *additionalSink) {
+			defer wg.Done()
+			if result := sink.client.Send(a.sendContext(ctx), *event); !cloudevents.IsACK(result) {
+				a.logger.Error("Failed to send cloudevent to required sink", zap.String("sink", sink.uri), zap.String("id", event.ID()), zap.Any("result", result))
+				mu.Lock()
+				delivered = false
+				mu.Unlock()
+			}
+		}(sink)
+	}
+	wg.Wait()
+	return delivered
+}
+
+// sendBestEffort sends a copy of the event to the best-effort sink in the
+// background. Failures are logged.
+func (a *Adapter) sendBestEffort(ctx context.Context, sink *additionalSink, event *cloudevents.Event) {
+	select {
+	case sink.inFlight <- struct{}{}:
+	default:
+		a.logger.Error("Too many events in flight to best-effort sink, dropping event", zap.String("sink", sink.uri), zap.String("id", event.ID()))
+		return
+	}
+
+	copied := event.Clone()
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		defer func() { <-sink.inFlight }()
+
+		// Not canceled on shutdown, as events sent in the background are waited for before exiting.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), bestEffortTimeout)
+		defer cancel()
+		if result := sink.client.Send(ctx, copied); !cloudevents.IsACK(result) {
+			a.logger.Warn("Failed to send cloudevent to best-effort sink", zap.String("sink", sink.uri), zap.String("id", copied.ID()), zap.Any("result", result))
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProcessEntry_AdditionalSinks(t *testing.T) {
+	unavailable := errors.New("sink unavailable")
+
+	tests := []struct {
+		name           string
+		required       protocol.Result
+		bestEffort     protocol.Result
+		isShuttingDown bool
+		wantID         string
+		wantAcks       []string
+	}{{
+		name:       "all delivered",
+		required:   protocol.ResultACK,
+		bestEffort: protocol.ResultACK,
+		wantID:     ">",
+		wantAcks:   []string{"1-0"},
+	}, {
+		name:       "best-effort sink fails",
+		required:   protocol.ResultACK,
+		bestEffort: unavailable,
+		wantID:     ">",
+		wantAcks:   []string{"1-0"},
+	}, {
+		name:       "required sink fails",
+		required:   unavailable,
+		bestEffort: protocol.ResultACK,
+		wantID:     "0",
+	}, {
+		name:           "required sink fails while shutting down",
+		required:       unavailable,
+		bestEffort:     protocol.ResultACK,
+		isShuttingDown: true,
+		wantID:         ">",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			required := &fakeClient{results: []protocol.Result{test.required}}
+			bestEffort := &fakeClient{results: []protocol.Result{test.bestEffort}}
+			a := &Adapter{
+				logger: zap.NewNop(),
+				client: &fakeClient{results: []protocol.Result{protocol.ResultACK}},
+				config: &Config{},
+				additionalSinks: []*additionalSink{
+					{uri: "http://required", required: true, client: required},
+					{uri: "http://best-effort", client: bestEffort, inFlight: make(chan struct{}, 1)},
+				},
+			}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), test.isShuttingDown)
+			a.background.Wait()
+
+			require.Equal(t, test.wantID, xreadID)
+			require.Equal(t, test.wantAcks, conn.acks)
+			require.Len(t, required.events, 1)
+			require.Len(t, bestEffort.events, 1)
+		})
+	}
+}
+
+func TestProcessEntry_RequiredSinkRecovers(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("1-0")}}
+	required := &fakeClient{results: []protocol.Result{errors.New("sink unavailable"), protocol.ResultACK}}
+	a := &Adapter{
+		logger:          zap.NewNop(),
+		client:          &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK}},
+		config:          &Config{},
+		additionalSinks: []*additionalSink{{uri: "http://required", required: true, client: required}},
+	}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, "0", xreadID)
+	require.Empty(t, conn.acks)
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, testRetryState(), false)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestLoadAdditionalSinks(t *testing.T) {
+	t.Setenv("ADDITIONAL_SINK_0_URI", "http://primary")
+	t.Setenv("ADDITIONAL_SINK_0_REQUIRED", "true")
+	t.Setenv("ADDITIONAL_SINK_1_URI", "http://metrics")
+	t.Setenv("ADDITIONAL_SINK_3_URI", "http://ignored")
+
+	sinks := loadAdditionalSinks()
+	require.Len(t, sinks, 2)
+	require.Equal(t, "http://primary", sinks[0].uri)
+	require.True(t, sinks[0].required)
+	require.Equal(t, "http://metrics", sinks[1].uri)
+	require.False(t, sinks[1].required)
+}
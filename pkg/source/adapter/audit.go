@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	auditTimeout = 5 * time.Second
+
+	// maxAuditsInFlight bounds the deliveries to a slow audit sink. Events
+	// are dropped from the audit trail, and counted as failures, beyond it.
+	maxAuditsInFlight = 100
+)
+
+// auditor sends a copy of the events to the audit sink, without blocking the
+// delivery to the sink.
+type auditor struct {
+	client   cloudevents.Client
+	inFlight chan struct{}
+}
+
+func newAuditor(client cloudevents.Client) *auditor {
+	return &auditor{client: client, inFlight: make(chan struct{}, maxAuditsInFlight)}
+}
+
+// useAuditSink creates the auditor sending events to the configured audit
+// sink through the given transport.
+func (a *Adapter) useAuditSink(transport http.RoundTripper) error {
+	if a.config.AuditSink == "" {
+		return nil
+	}
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(a.config.AuditSink), cehttp.WithRoundTripper(transport))
+	if err != nil {
+		return err
+	}
+	a.auditor = newAuditor(client)
+	return nil
+}
+
+// audit sends a copy of the event to the audit sink, if any, in the background.
+// Failures are logged and counted.
+func (a *Adapter) audit(ctx context.Context, event *cloudevents.Event) {
+	if a.auditor == nil {
+		return
+	}
+
+	select {
+	case a.auditor.inFlight <- struct{}{}:
+	default:
+		a.logger.Error("Too many events in flight to the audit sink, dropping event", zap.String("id", event.ID()))
+		metrics.Record(ctx, auditFailureCountM.M(1))
+		return
+	}
+
+	copied := event.Clone()
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		defer func() { <-a.auditor.inFlight }()
+
+		// Not canceled on shutdown, as audits are waited for before exiting.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditTimeout)
+		defer cancel()
+		if result := a.auditor.client.Send(ctx, copied); !cloudevents.IsACK(result) {
+			a.logger.Error("Failed to send cloudevent to the audit sink", zap.String("id", copied.ID()), zap.Any("result", result))
+			metrics.Record(ctx, auditFailureCountM.M(1))
+		}
+	}()
+}
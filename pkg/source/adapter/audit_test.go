@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProcessEntry_AuditSink(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("2-0"), entryReply("3-0")}}
+	client := &fakeClient{results: []protocol.Result{
+		protocol.ResultACK,
+		errors.New("sink unavailable"),
+		protocol.ResultACK,
+	}}
+	auditClient := &fakeClient{results: []protocol.Result{
+		protocol.ResultACK,
+		errors.New("audit sink unavailable"),
+	}}
+	a := &Adapter{logger: zap.NewNop(), client: client, auditor: newAuditor(auditClient), config: &Config{}}
+
+	for i := 0; i < 3; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+		a.background.Wait()
+	}
+
+	// Delivered events are audited, and audit failures do not prevent
+	// acknowledging entries. 2-0 is left pending.
+	require.Equal(t, 2, auditClient.sent)
+	require.Equal(t, "1-0", auditClient.events[0].ID())
+	require.Equal(t, "3-0", auditClient.events[1].ID())
+	require.Equal(t, []string{"1-0", "3-0"}, conn.acks)
+}
+
+func TestAdapter_AuditSinkInFlight(t *testing.T) {
+	auditClient := &fakeClient{}
+	a := &Adapter{logger: zap.NewNop(), auditor: newAuditor(auditClient)}
+	for i := 0; i < maxAuditsInFlight; i++ {
+		a.auditor.inFlight <- struct{}{}
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID("1-0")
+	a.audit(context.Background(), &event)
+	a.background.Wait()
+	require.Zero(t, auditClient.sent)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// defaultRedisUser is the user authenticated by AUTH with a password only.
+const defaultRedisUser = "default"
+
+// redisUser returns the Redis ACL user the connections authenticate as, and
+// false when they do not authenticate: the user of the auth of the source, or
+// else of the address URL.
+func (a *Adapter) redisUser() (string, bool) {
+	username, password := a.config.RedisUsername, a.redisPassword()
+	if password == "" && a.config.SentinelMasterName == "" && len(a.config.ClusterAddresses) == 0 {
+		if opt, err := scan.ParseAddress(a.config.Address); err == nil {
+			username, password = opt.Username, opt.Password
+		}
+	}
+	if password == "" {
+		return "", false
+	}
+	if username == "" {
+		return defaultRedisUser, true
+	}
+	return username, true
+}
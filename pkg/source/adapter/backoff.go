@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	redisBackoffInitial = 100 * time.Millisecond
+	redisBackoffMax     = 30 * time.Second
+	sinkBackoffInitial  = 100 * time.Millisecond
+	sinkBackoffMax      = 10 * time.Second
+
+	reshardingBackoffInitial = 500 * time.Millisecond
+	reshardingBackoffMax     = 5 * time.Second
+
+	ackRetryBackoffInitial = 50 * time.Millisecond
+	ackRetryBackoffMax     = time.Second
+)
+
+// backoff computes exponentially increasing delays between consecutive
+// failures. The delay is multiplied by multiplier, 2 when unset, after each
+// failure. With jitter, each delay is randomly shortened by up to half, so that
+// the consumers failing together do not all retry at once.
+type backoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     bool
+	failures   int
+}
+
+// Next records a failure and returns how long to wait before retrying.
+func (b *backoff) Next() time.Duration {
+	d := b.delay()
+	b.failures++
+	if b.jitter && d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+	return d
+}
+
+// delay returns the delay after the failures recorded so far, before jitter.
+func (b *backoff) delay() time.Duration {
+	if b.multiplier == 0 || b.multiplier == 2 {
+		if b.failures < 32 {
+			if next := b.initial << b.failures; next > 0 && next < b.max {
+				return next
+			}
+		}
+		return b.max
+	}
+	if next := float64(b.initial) * math.Pow(b.multiplier, float64(b.failures)); next < float64(b.max) {
+		return time.Duration(next)
+	}
+	return b.max
+}
+
+// Reset clears the failures recorded so far.
+func (b *backoff) Reset() {
+	b.failures = 0
+}
+
+// Failures returns the number of consecutive failures recorded so far.
+func (b *backoff) Failures() int {
+	return b.failures
+}
+
+// retryState holds the independent retry states of a consumer: failing to
+// read from Redis backs off reconnecting to Redis, failing to deliver to the
+// sink backs off delivering the next event. Waiting out a resharding Redis
+// cluster does not count as failing to read from Redis.
+type retryState struct {
+	redis      backoff
+	sink       backoff
+	resharding backoff
+}
+
+func newRetryState(config *Config) *retryState {
+	redis := backoff{initial: redisBackoffInitial, max: redisBackoffMax, jitter: true}
+	if config.RedisBackoffInitial > 0 {
+		redis.initial = config.RedisBackoffInitial
+	}
+	if config.RedisBackoffMax > 0 {
+		redis.max = config.RedisBackoffMax
+	}
+	if config.RedisBackoffMultiplier >= 1 {
+		redis.multiplier = config.RedisBackoffMultiplier
+	}
+	return &retryState{
+		redis:      redis,
+		sink:       backoff{initial: sinkBackoffInitial, max: sinkBackoffMax},
+		resharding: backoff{initial: reshardingBackoffInitial, max: reshardingBackoffMax},
+	}
+}
+
+// forRedisError returns the backoff to wait on after the given Redis error.
+func (r *retryState) forRedisError(err error) *backoff {
+	if isResharding(err) {
+		return &r.resharding
+	}
+	return &r.redis
+}
+
+// isResharding returns whether the error is one of the transient errors
+// returned by a Redis cluster while its slots are being moved.
+func isResharding(err error) bool {
+	var rerr redis.Error
+	if !errors.As(err, &rerr) {
+		return false
+	}
+	return strings.HasPrefix(string(rerr), "CLUSTERDOWN") || strings.HasPrefix(string(rerr), "TRYAGAIN")
+}
+
+// reconnect replaces a broken connection with a new one from the pool,
+// backing off on the Redis retry state until it succeeds or ctx is done.
+// Redis is reported unreachable while it cannot be reconnected to. The
+// backoff is reset by the first successful read, not by reconnecting, so that
+// a Redis accepting connections but failing reads is not retried in a loop.
+func (a *Adapter) reconnect(ctx context.Context, pool *redis.Pool, conn redis.Conn, retries *retryState) (redis.Conn, error) {
+	conn.Close()
+	for {
+		c, err := pool.Dial()
+		a.updateConnection(ctx, err)
+		if err == nil {
+			a.logger.Info("Reconnected to Redis")
+			return c, nil
+		}
+		a.logger.Error("Cannot reconnect to Redis", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retries.redis.Next()):
+		}
+	}
+}
+
+// ackWithRetries acknowledges a delivered entry, retrying up to AckRetries
+// times on failure, so that a transient Redis error does not make the entry
+// be delivered again. Retries use another connection from the pool when the
+// consumer's one is broken.
+func (a *Adapter) ackWithRetries(ctx context.Context, conn redis.Conn, streamName, groupName, id string) error {
+	err := a.ack(conn, streamName, groupName, id)
+	b := backoff{initial: ackRetryBackoffInitial, max: ackRetryBackoffMax}
+	for attempt := 1; err != nil && attempt <= a.config.AckRetries; attempt++ {
+		metrics.Record(ctx, ackRetryCountM.M(1))
+		a.logger.Warn("Cannot ack message, retrying", zap.String("id", id), zap.Int("attempt", attempt), zap.Error(err))
+		time.Sleep(b.Next())
+
+		if conn.Err() != nil && a.pool != nil {
+			c := a.pool.Get()
+			err = a.ack(c, streamName, groupName, id)
+			c.Close()
+		} else {
+			err = a.ack(conn, streamName, groupName, id)
+		}
+	}
+	if err == nil {
+		metrics.Record(ctx, ackCountM.M(1))
+	}
+	return err
+}
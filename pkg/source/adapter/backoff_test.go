@@ -0,0 +1,404 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeConn replies to XREADGROUP with the scripted replies, in order,
+// increments counters on INCR and acknowledges every entry, unless ackErr is
+// set. The first ackFailures XACKs fail.
+type fakeConn struct {
+	reads       []fakeReply
+	err         error
+	counters    map[string]int64
+	incrErr     error
+	acks        []string
+	ackCalls    int
+	ackErr      error
+	ackFailures int
+	sets        map[string]map[string]bool
+	added       []string
+}
+
+type fakeReply struct {
+	reply interface{}
+	err   error
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return c.err }
+func (c *fakeConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if cmd == "INCR" {
+		if c.incrErr != nil {
+			return nil, c.incrErr
+		}
+		if c.counters == nil {
+			c.counters = map[string]int64{}
+		}
+		key := args[0].(string)
+		c.counters[key]++
+		return c.counters[key], nil
+	}
+	if cmd == "XACK" {
+		if c.ackErr != nil {
+			return nil, c.ackErr
+		}
+		if c.ackFailures > 0 {
+			c.ackFailures--
+			return nil, errors.New("connection reset by peer")
+		}
+		c.ackCalls++
+		for _, id := range args[2:] {
+			c.acks = append(c.acks, id.(string))
+		}
+	}
+	if cmd == "XADD" {
+		c.added = append(c.added, args[0].(string))
+	}
+	if cmd == "SISMEMBER" {
+		if c.sets[args[0].(string)][args[1].(string)] {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	if cmd == "SADD" {
+		if c.sets == nil {
+			c.sets = map[string]map[string]bool{}
+		}
+		if c.sets[args[0].(string)] == nil {
+			c.sets[args[0].(string)] = map[string]bool{}
+		}
+		c.sets[args[0].(string)][args[1].(string)] = true
+	}
+	if cmd != "XREADGROUP" {
+		return int64(1), nil
+	}
+	r := c.reads[0]
+	c.reads = c.reads[1:]
+	return r.reply, r.err
+}
+func (c *fakeConn) Send(string, ...interface{}) error { return nil }
+func (c *fakeConn) Flush() error                      { return nil }
+func (c *fakeConn) Receive() (interface{}, error)     { return nil, nil }
+
+// fakeClient returns the scripted results, in order, when sending events.
+type fakeClient struct {
+	results []protocol.Result
+	sent    int
+	events  []cloudevents.Event
+}
+
+func (c *fakeClient) Send(_ context.Context, event cloudevents.Event) protocol.Result {
+	r := c.results[c.sent]
+	c.sent++
+	c.events = append(c.events, event)
+	return r
+}
+func (c *fakeClient) Request(context.Context, cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+	return nil, nil
+}
+func (c *fakeClient) StartReceiver(context.Context, interface{}) error { return nil }
+
+func entryReply(id string) fakeReply {
+	return fakeReply{reply: []interface{}{
+		[]interface{}{[]byte("mystream"), []interface{}{
+			[]interface{}{[]byte(id), []interface{}{[]byte("field"), []byte("value")}},
+		}},
+	}}
+}
+
+func testRetryState() *retryState {
+	return &retryState{
+		redis:      backoff{initial: time.Millisecond, max: time.Millisecond},
+		sink:       backoff{initial: time.Millisecond, max: time.Millisecond},
+		resharding: backoff{initial: time.Millisecond, max: time.Millisecond},
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := backoff{initial: 100 * time.Millisecond, max: time.Second}
+
+	require.Equal(t, 100*time.Millisecond, b.Next())
+	require.Equal(t, 200*time.Millisecond, b.Next())
+	require.Equal(t, 400*time.Millisecond, b.Next())
+	require.Equal(t, 800*time.Millisecond, b.Next())
+	require.Equal(t, time.Second, b.Next())
+	for i := 0; i < 100; i++ {
+		b.Next()
+	}
+	require.Equal(t, time.Second, b.Next())
+	require.Equal(t, 106, b.Failures())
+
+	b.Reset()
+	require.Equal(t, 0, b.Failures())
+	require.Equal(t, 100*time.Millisecond, b.Next())
+}
+
+func TestBackoff_Multiplier(t *testing.T) {
+	b := backoff{initial: 100 * time.Millisecond, max: time.Second, multiplier: 1.5}
+
+	require.Equal(t, 100*time.Millisecond, b.Next())
+	require.Equal(t, 150*time.Millisecond, b.Next())
+	require.Equal(t, 225*time.Millisecond, b.Next())
+	for i := 0; i < 100; i++ {
+		b.Next()
+	}
+	require.Equal(t, time.Second, b.Next())
+
+	b.Reset()
+	require.Equal(t, 100*time.Millisecond, b.Next())
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	b := backoff{initial: 100 * time.Millisecond, max: time.Second, jitter: true}
+
+	for i := 0; i < 100; i++ {
+		want := b.delay()
+		d := b.Next()
+		require.GreaterOrEqual(t, d, want/2)
+		require.LessOrEqual(t, d, want)
+	}
+	require.Equal(t, 100, b.Failures())
+}
+
+func TestNewRetryState(t *testing.T) {
+	retries := newRetryState(&Config{})
+	require.Equal(t, redisBackoffInitial, retries.redis.initial)
+	require.Equal(t, redisBackoffMax, retries.redis.max)
+	require.True(t, retries.redis.jitter)
+	require.False(t, retries.sink.jitter)
+
+	retries = newRetryState(&Config{RedisBackoffInitial: time.Second, RedisBackoffMax: time.Minute, RedisBackoffMultiplier: 3})
+	require.Equal(t, time.Second, retries.redis.initial)
+	require.Equal(t, time.Minute, retries.redis.max)
+	require.Equal(t, 3.0, retries.redis.multiplier)
+}
+
+func TestProcessEntry_RedisErrorDoesNotAffectSinkRetries(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{
+		{err: errors.New("connection reset by peer")},
+		entryReply("1-0"),
+	}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+	retries := testRetryState()
+	retries.sink.failures = 2
+
+	// Redis read error
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", retries, false)
+	require.Equal(t, "0", xreadID)
+	require.Equal(t, 1, retries.redis.Failures())
+	require.Equal(t, 2, retries.sink.Failures())
+	require.Equal(t, 0, client.sent)
+
+	// Successful read after reconnecting
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, retries, false)
+	require.Equal(t, 0, retries.redis.Failures())
+	require.Equal(t, 0, retries.sink.Failures())
+	require.Equal(t, 1, client.sent)
+}
+
+func TestProcessEntry_SinkErrorDoesNotAffectRedisRetries(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{
+		entryReply("1-0"),
+		entryReply("2-0"),
+	}}
+	client := &fakeClient{results: []protocol.Result{
+		errors.New("sink unavailable"),
+		protocol.ResultACK,
+	}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+	retries := testRetryState()
+	retries.redis.failures = 2
+
+	// Sink delivery error
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", retries, false)
+	require.Equal(t, 1, retries.sink.Failures())
+	require.Equal(t, 0, retries.redis.Failures())
+
+	retries.redis.failures = 2
+	conn.reads = append([]fakeReply{{err: errors.New("connection reset by peer")}}, conn.reads...)
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", retries, false)
+	require.Equal(t, 3, retries.redis.Failures())
+	require.Equal(t, 1, retries.sink.Failures())
+
+	// Successful delivery
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", retries, false)
+	require.Equal(t, 0, retries.sink.Failures())
+	require.Equal(t, 0, retries.redis.Failures())
+}
+
+func TestProcessEntry_WaitsOutClusterResharding(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{
+		{err: redis.Error("CLUSTERDOWN The cluster is down")},
+		{err: redis.Error("TRYAGAIN Multiple keys request during rehashing of slot")},
+		entryReply("1-0"),
+	}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+	retries := testRetryState()
+
+	// Resharding errors are waited out without counting as Redis failures
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", retries, false)
+	require.Equal(t, "0", xreadID)
+	xreadID = a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, retries, false)
+	require.Equal(t, "0", xreadID)
+	require.Equal(t, 2, retries.resharding.Failures())
+	require.Equal(t, 0, retries.redis.Failures())
+	require.NoError(t, conn.Err())
+	require.Equal(t, 0, client.sent)
+
+	// The cluster recovered
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, retries, false)
+	require.Equal(t, 0, retries.resharding.Failures())
+	require.Equal(t, 1, client.sent)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestProcessEntry_AckRetries(t *testing.T) {
+	tests := map[string]struct {
+		ackRetries  int
+		ackFailures int
+		wantXreadID string
+		wantAcks    []string
+	}{
+		"transient failure": {
+			ackRetries:  3,
+			ackFailures: 2,
+			wantXreadID: ">",
+			wantAcks:    []string{"1-0"},
+		},
+		"retries exhausted": {
+			ackRetries:  2,
+			ackFailures: 3,
+			wantXreadID: "0",
+		},
+		"retries disabled": {
+			ackFailures: 1,
+			wantXreadID: "0",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}, ackFailures: test.ackFailures}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{AckRetries: test.ackRetries}}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+			require.Equal(t, test.wantXreadID, xreadID)
+			require.Equal(t, 1, client.sent)
+			require.Equal(t, test.wantAcks, conn.acks)
+		})
+	}
+}
+
+func TestProcessEntry_AckRetriesOnNewConnection(t *testing.T) {
+	// The connection breaks while acknowledging
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}, ackFailures: 1, err: errors.New("EOF")}
+	pooled := &fakeConn{}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{
+		logger: zap.NewNop(),
+		client: client,
+		config: &Config{AckRetries: 1},
+		pool:   &redis.Pool{Dial: func() (redis.Conn, error) { return pooled, nil }},
+	}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, ">", xreadID)
+	require.Empty(t, conn.acks)
+	require.Equal(t, []string{"1-0"}, pooled.acks)
+}
+
+func TestIsResharding(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"clusterdown": {err: redis.Error("CLUSTERDOWN Hash slot not served"), want: true},
+		"tryagain":    {err: redis.Error("TRYAGAIN Multiple keys request during rehashing of slot"), want: true},
+		"wrapped":     {err: fmt.Errorf("incr: %w", redis.Error("CLUSTERDOWN The cluster is down")), want: true},
+		"other reply": {err: redis.Error("NOGROUP No such key 'mystream'")},
+		"connection":  {err: errors.New("connection reset by peer")},
+		"nil":         {},
+	}
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if got := isResharding(test.err); got != test.want {
+				t.Errorf("isResharding(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAdapter_Reconnect(t *testing.T) {
+	dials := 0
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		dials++
+		if dials < 3 {
+			return nil, errors.New("connection refused")
+		}
+		return &fakeConn{}, nil
+	}}
+	a := &Adapter{logger: zap.NewNop()}
+	retries := testRetryState()
+	retries.sink.failures = 1
+
+	conn, err := a.reconnect(context.Background(), pool, &fakeConn{err: errors.New("EOF")}, retries)
+	require.NoError(t, err)
+	require.NoError(t, conn.Err())
+	require.Equal(t, 3, dials)
+	require.Equal(t, 2, retries.redis.Failures())
+	require.Equal(t, 1, retries.sink.Failures())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pool.Dial = func() (redis.Conn, error) { return nil, errors.New("connection refused") }
+	_, err = a.reconnect(ctx, pool, conn, retries)
+	require.Error(t, err)
+}
+
+func TestAdapter_ReconnectReportsRedisUnreachable(t *testing.T) {
+	refused := errors.New("connection refused")
+	dials := 0
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		dials++
+		if dials < 4 {
+			return nil, refused
+		}
+		return &fakeConn{}, nil
+	}}
+	reporter := &recordingReporter{}
+	a := &Adapter{logger: zap.NewNop(), connection: reporter}
+
+	_, err := a.reconnect(context.Background(), pool, &fakeConn{err: errors.New("EOF")}, testRetryState())
+	require.NoError(t, err)
+	require.Equal(t, []error{refused, nil}, reporter.get())
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/metrics"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// batchContentType is the content type of the batched content mode of
+// CloudEvents, a JSON array of structured events.
+const batchContentType = "application/cloudevents-batch+json"
+
+// batchSender posts batches of events to the sink. The CloudEvents client
+// only sends single events.
+type batchSender struct {
+	client    *http.Client
+	target    string
+	headers   http.Header
+	overrides *duckv1.CloudEventOverrides
+}
+
+// useBatchSink creates the sender delivering the batches of events to the
+// sink through the given transport, when batching is enabled.
+func (a *Adapter) useBatchSink(transport http.RoundTripper) error {
+	if a.config.BatchSize <= 0 {
+		return nil
+	}
+	overrides, err := a.config.GetCloudEventOverrides()
+	if err != nil {
+		return err
+	}
+	a.batches = &batchSender{
+		client:    &http.Client{Transport: transport},
+		target:    a.config.GetSink(),
+		headers:   a.sinkHeaders,
+		overrides: overrides,
+	}
+	return nil
+}
+
+// encode returns the JSON representation of the event in a batch, with the
+// extensions overridden by the source, as the CloudEvents client of the
+// adapter sets them on single events. JSON data is embedded as is, other data
+// is base64 encoded.
+func (s *batchSender) encode(event *cloudevents.Event) ([]byte, error) {
+	if s.overrides != nil {
+		for name, value := range s.overrides.Extensions {
+			event.SetExtension(name, value)
+		}
+	}
+	if event.DataMediaType() == cloudevents.ApplicationJSON {
+		event.DataBase64 = false
+	}
+	return json.Marshal(event)
+}
+
+// send posts the encoded events to the sink. The result is an ACK when the
+// sink responds with a 2xx status code.
+func (s *batchSender) send(ctx context.Context, encoded [][]byte) protocol.Result {
+	body := append(append([]byte{'['}, bytes.Join(encoded, []byte{','})...), ']')
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range s.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", batchContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return cehttp.NewResult(resp.StatusCode, "%w", protocol.ResultACK)
+	}
+	return cehttp.NewResult(resp.StatusCode, "%w", protocol.ResultNACK)
+}
+
+// batcher gathers the entries read by a consumer into batches, delivered once
+// full or once the oldest entry of the batch waited for BatchLinger. The
+// entries of a batch stay pending until the sink accepts the whole batch.
+type batcher struct {
+	a       *Adapter
+	events  []*cloudevents.Event
+	encoded [][]byte
+	size    int                              // bytes of the encoded events
+	opened  time.Time                        // when the first event of the batch was read
+	next    []*cloudevents.Event             // read beyond BatchMaxBytes, for the next batch
+	held    map[string]bool                  // IDs of the entries read but not delivered yet
+	spans   map[string]func(protocol.Result) // ending the spans of the held entries
+	urls    map[string]string                // the callback URLs of the held entries
+}
+
+func (a *Adapter) newBatcher() *batcher {
+	return &batcher{a: a, held: make(map[string]bool), spans: make(map[string]func(protocol.Result)), urls: make(map[string]string)}
+}
+
+// full returns whether the batch cannot take more events.
+func (b *batcher) full() bool {
+	return len(b.events) >= b.a.config.BatchSize || len(b.next) > 0 ||
+		(b.a.config.BatchMaxBytes > 0 && b.size >= b.a.config.BatchMaxBytes)
+}
+
+// add adds the event to the batch, or keeps it for the next batch when it
+// would make the batch larger than BatchMaxBytes.
+func (b *batcher) add(event *cloudevents.Event) error {
+	data, err := b.a.batches.encode(event)
+	if err != nil {
+		return err
+	}
+	b.held[event.ID()] = true
+	if len(b.events) > 0 && (len(b.next) > 0 || (b.a.config.BatchMaxBytes > 0 && b.size+len(data)+1 > b.a.config.BatchMaxBytes)) {
+		b.next = append(b.next, event)
+		return nil
+	}
+	if len(b.events) == 0 {
+		b.opened = time.Now()
+	}
+	b.events = append(b.events, event)
+	b.encoded = append(b.encoded, data)
+	b.size += len(data) + 1 // with the separator
+	return nil
+}
+
+// reset empties the batch, and moves the events kept for the next batch into it.
+func (b *batcher) reset() {
+	for _, event := range b.events {
+		delete(b.held, event.ID())
+	}
+	next := b.next
+	b.events, b.encoded, b.size, b.next = nil, nil, 0, nil
+	for _, event := range next {
+		delete(b.held, event.ID())
+		_ = b.add(event) // encoded before
+	}
+}
+
+// lingered returns whether the batch waited long enough for more entries.
+func (b *batcher) lingered() bool {
+	return time.Since(b.opened) >= b.a.config.BatchLinger
+}
+
+// process reads the next entries into the batch, and delivers it when it is
+// full or lingered, or when shutting down. Like processEntry, it returns the
+// ID to read from in the next iteration.
+func (b *batcher) process(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, retries *retryState, isShuttingDown bool) string {
+	a := b.a
+	if len(b.events) > 0 && (b.full() || b.lingered()) {
+		return b.flush(ctx, conn, streamName, groupName, consumerName, xreadID, retries, isShuttingDown)
+	}
+
+	reply, err := conn.Do("XREADGROUP", "GROUP", groupName, consumerName, "COUNT", b.room(), "BLOCK", b.block(), "STREAMS", streamName, xreadID)
+	if err != nil {
+		a.logger.Error("Cannot read from stream", zap.Error(err))
+		if !isShuttingDown {
+			time.Sleep(retries.forRedisError(err).Next())
+		}
+		return xreadID
+	}
+	retries.redis.Reset()
+	retries.resharding.Reset()
+	deliveredAt := time.Now()
+
+	var items []scan.StreamItem
+	if values, err := redis.Values(reply, nil); err == nil && len(values) == 1 { // nil when timed out blocking
+		elems, err := scan.ScanXReadReply(values, nil)
+		if err != nil {
+			a.logger.Error("Cannot convert reply", zap.Error(err))
+			if !isShuttingDown {
+				time.Sleep(retries.redis.Next())
+			}
+			return xreadID
+		}
+		items = elems[0].Items
+	}
+	return b.addItems(ctx, conn, streamName, groupName, consumerName, items, deliveredAt, xreadID, retries, isShuttingDown)
+}
+
+// room returns how many entries the batch can take before it is full.
+func (b *batcher) room() int {
+	return b.a.config.BatchSize - len(b.events)
+}
+
+// block returns the BLOCK of XREADGROUP, in milliseconds: the read block
+// timeout, or no longer than the batch can linger once it has events.
+func (b *batcher) block() int64 {
+	if len(b.events) == 0 {
+		return b.a.readBlock()
+	}
+	block := time.Until(b.opened.Add(b.a.config.BatchLinger)).Milliseconds()
+	if block < 1 {
+		block = 1 // 0 blocks forever
+	}
+	return block
+}
+
+// addItems adds the entries read from the stream to the batch, and delivers it
+// when it is full, or when shutting down. Like processEntry, it returns the ID
+// to read from in the next iteration.
+func (b *batcher) addItems(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, items []scan.StreamItem, deliveredAt time.Time, xreadID string, retries *retryState, isShuttingDown bool) string {
+	a := b.a
+	if len(items) == 0 {
+		if isShuttingDown && len(b.events) > 0 {
+			b.flush(ctx, conn, streamName, groupName, consumerName, xreadID, retries, isShuttingDown)
+		}
+		return scan.NewID // no more pending entries, read new ones
+	}
+	metrics.Record(ctx, entryReadCountM.M(int64(len(items))))
+
+	for i := range items {
+		item := &items[i]
+		if xreadID != scan.NewID {
+			xreadID = item.ID // read the pending entries after it next
+		}
+		if b.held[item.ID] {
+			continue // already in the batch, read again with the pending entries
+		}
+		event, err := a.newEvent(ctx, item)
+		if err != nil {
+			a.logger.Error("Cannot convert entry, leaving message pending", zap.String("id", item.ID), zap.Error(err))
+			continue
+		}
+		a.setClaimDeadline(event, deliveredAt)
+		a.markRedelivered(event, xreadID)
+
+		if a.belowMinID(event.ID()) {
+			a.logger.Info("Skipping message below the minimum entry ID", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+			xreadID = a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+			continue
+		}
+		if len(item.FieldValues) == 0 {
+			metrics.Record(ctx, emptyEntryCountM.M(1))
+			switch sourcesv1alpha1.EmptyEntryPolicy(a.config.OnEmptyEntry) {
+			case sourcesv1alpha1.EmptyEntryEmit:
+			case sourcesv1alpha1.EmptyEntryDeadLetter:
+				if err := a.deadLetter(conn, streamName, groupName, event.ID(), deadLetterEmptyEntry, 0, nil); err != nil {
+					a.logger.Error("Cannot dead-letter message without fields", zap.String("id", event.ID()), zap.Error(err))
+					continue // left pending
+				}
+				a.logger.Info("Dead-lettered message without fields", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+				xreadID = a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+				continue
+			default:
+				a.logger.Info("Skipping message without fields", zap.String("consumerName", consumerName), zap.String("id", event.ID()))
+				xreadID = a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+				continue
+			}
+		}
+
+		if field, missing := a.missingField(item.FieldValues); missing && len(item.FieldValues) > 0 {
+			// Left pending when the dead-letter sink does not accept the event.
+			xreadID, _ = a.dropMissingField(ctx, conn, streamName, groupName, consumerName, event, field, xreadID, retries, isShuttingDown)
+			continue
+		}
+
+		switch verdict, violation := a.checkSchema(ctx, event, item.FieldValues); verdict {
+		case schemaReject:
+			// Left pending when the dead-letter sink does not accept the event.
+			xreadID, _ = a.rejectSchemaViolation(ctx, conn, streamName, groupName, consumerName, event, violation, xreadID, retries, isShuttingDown)
+			continue
+		case schemaHold:
+			a.logger.Warn("Schema not available, leaving message pending", zap.String("id", event.ID()))
+			continue
+		}
+
+		_, endSpan := a.startEntrySpan(ctx, streamName, event, item.FieldValues)
+		if err := b.add(event); err != nil {
+			a.logger.Error("Cannot encode event, leaving message pending", zap.String("id", event.ID()), zap.Error(err))
+			endSpan(err)
+			continue
+		}
+		b.spans[event.ID()] = endSpan
+		if url := a.callbackURL(item.FieldValues); url != "" {
+			b.urls[event.ID()] = url
+		}
+	}
+	a.logger.Info("Consumer read messages", zap.String("consumerName", consumerName), zap.Int("count", len(items)), zap.Int("batched", len(b.events)))
+
+	if len(b.events) > 0 && (b.full() || isShuttingDown) {
+		return b.flush(ctx, conn, streamName, groupName, consumerName, xreadID, retries, isShuttingDown)
+	}
+	return xreadID
+}
+
+// flush delivers the batch to the sink, then acknowledges its entries. When
+// the sink does not accept the batch, its events are sent to the dead-letter
+// sink, if any, or left pending.
+func (b *batcher) flush(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, retries *retryState, isShuttingDown bool) string {
+	a := b.a
+	events := b.events
+	result := a.batches.send(a.sendContext(ctx), b.encoded)
+	urls := make([]string, len(events))
+	for i, event := range events {
+		if endSpan, ok := b.spans[event.ID()]; ok {
+			endSpan(result)
+			delete(b.spans, event.ID())
+		}
+		urls[i] = b.urls[event.ID()]
+		delete(b.urls, event.ID())
+	}
+	b.reset()
+
+	if !cloudevents.IsACK(result) {
+		metrics.Record(ctx, deliveryFailureCountM.M(int64(len(events))))
+		if a.config.HoldOnSinkUnavailable && !isShuttingDown && sinkUnavailable(result) {
+			a.logger.Warn("Sink is unavailable, holding messages", zap.String("consumerName", consumerName), zap.Int("count", len(events)), zap.Any("result", result))
+			select {
+			case <-ctx.Done():
+			case <-time.After(retries.sink.Next()):
+			}
+			return "0" //ID to read pending messages in next iteration
+		}
+		a.reportFailure(ctx, groupName, result)
+		if a.deadLetters == nil {
+			// The entries stay pending, to be delivered again on the next start.
+			a.logger.Error("Failed to send batch, leaving messages pending", zap.Int("count", len(events)), zap.Any("result", result))
+			if !isShuttingDown {
+				time.Sleep(retries.sink.Next())
+			}
+			return pastPending(xreadID, events[len(events)-1].ID())
+		}
+		for _, event := range events {
+			if a.sendToDeadLetterSink(ctx, event, result) {
+				a.logger.Warn("Failed to send cloudevent, sent it to the dead-letter sink", zap.String("id", event.ID()), zap.Any("result", result))
+				xreadID = a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown)
+			}
+		}
+		return xreadID
+	}
+	retries.sink.Reset()
+	metrics.Record(ctx, eventDeliveredCountM.M(int64(len(events))))
+
+	for i, event := range events {
+		if err := a.ackWithRetries(ctx, conn, streamName, groupName, event.ID()); err != nil {
+			a.logger.Error("Cannot ack message", zap.Error(err))
+			xreadID = "0" //ID to read pending messages in next iteration
+			if !isShuttingDown {
+				time.Sleep(retries.forRedisError(err).Next())
+			}
+			continue
+		}
+		a.audit(ctx, event)
+		a.confirmDelivery(ctx, event.ID(), event, urls[i])
+	}
+	a.logger.Info("Consumer acknowledged the batch", zap.String("consumerName", consumerName), zap.Int("count", len(events)))
+	return xreadID
+}
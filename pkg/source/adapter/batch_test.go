@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// batchSink records the batches it receives, and responds with status.
+type batchSink struct {
+	status  int
+	batches [][]map[string]interface{}
+}
+
+func (s *batchSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != batchContentType {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, batch)
+	w.WriteHeader(s.status)
+}
+
+func (s *batchSink) ids(i int) []string {
+	var ids []string
+	for _, event := range s.batches[i] {
+		ids = append(ids, event["id"].(string))
+	}
+	return ids
+}
+
+func entriesReply(ids ...string) fakeReply {
+	var items []interface{}
+	for _, id := range ids {
+		items = append(items, []interface{}{[]byte(id), []interface{}{[]byte("field"), []byte("value")}})
+	}
+	return fakeReply{reply: []interface{}{[]interface{}{[]byte("mystream"), items}}}
+}
+
+func newBatchAdapter(t *testing.T, sink *batchSink, config *Config) *Adapter {
+	server := httptest.NewServer(sink)
+	t.Cleanup(server.Close)
+	a := &Adapter{logger: zap.NewNop(), config: config}
+	a.batches = &batchSender{client: server.Client(), target: server.URL}
+	return a
+}
+
+func TestBatcher_FlushWhenFull(t *testing.T) {
+	sink := &batchSink{status: http.StatusAccepted}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 2, BatchLinger: time.Hour})
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0")}}
+
+	xreadID := a.newBatcher().process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+
+	require.Equal(t, scan.NewID, xreadID)
+	require.Len(t, sink.batches, 1)
+	require.Equal(t, []string{"1-0", "2-0"}, sink.ids(0))
+	require.Equal(t, []interface{}{"field", "value"}, sink.batches[0][0]["data"])
+	require.Equal(t, []string{"1-0", "2-0"}, conn.acks)
+}
+
+func TestBatcher_FlushAfterLinger(t *testing.T) {
+	sink := &batchSink{status: http.StatusOK}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 10, BatchLinger: 20 * time.Millisecond})
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0"), {reply: nil}}}
+	b := a.newBatcher()
+
+	// The partial batch waits for more entries, until the read times out.
+	for i := 0; i < 2; i++ {
+		b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	}
+	require.Empty(t, sink.batches)
+	require.Empty(t, conn.acks)
+
+	time.Sleep(20 * time.Millisecond)
+	b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	require.Len(t, sink.batches, 1)
+	require.Equal(t, []string{"1-0"}, sink.ids(0))
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestBatcher_MaxBytes(t *testing.T) {
+	sink := &batchSink{status: http.StatusOK}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 10, BatchMaxBytes: 1, BatchLinger: time.Hour})
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0")}}
+	b := a.newBatcher()
+
+	// A batch holds at least one event, the next ones wait for the next batch.
+	for i := 0; i < 2; i++ {
+		b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	}
+	require.Len(t, sink.batches, 2)
+	require.Equal(t, []string{"1-0"}, sink.ids(0))
+	require.Equal(t, []string{"2-0"}, sink.ids(1))
+	require.Equal(t, []string{"1-0", "2-0"}, conn.acks)
+}
+
+func TestBatcher_NotAcceptedLeftPending(t *testing.T) {
+	sink := &batchSink{status: http.StatusInternalServerError}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 2, BatchLinger: time.Hour})
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0")}}
+
+	xreadID := a.newBatcher().process(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), false)
+
+	require.Len(t, sink.batches, 1)
+	require.Empty(t, conn.acks)
+	require.Equal(t, "2-0", xreadID) // read the pending entries after the batch
+}
+
+func TestBatcher_ShutdownSkipsHeldEntries(t *testing.T) {
+	sink := &batchSink{status: http.StatusOK}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 10, BatchLinger: time.Hour})
+	conn := &fakeConn{reads: []fakeReply{
+		entriesReply("1-0"),
+		// On shutdown, the pending entries are read again, including the batch.
+		entriesReply("1-0", "2-0"),
+	}}
+	b := a.newBatcher()
+
+	b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	require.Empty(t, sink.batches)
+
+	b.process(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), true)
+	require.Len(t, sink.batches, 1)
+	require.Equal(t, []string{"1-0", "2-0"}, sink.ids(0))
+	require.Equal(t, []string{"1-0", "2-0"}, conn.acks)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+)
+
+const callbackTimeout = 5 * time.Second
+
+// deliveryConfirmation is posted to producers once their entry has been
+// delivered to the sink and acknowledged.
+type deliveryConfirmation struct {
+	EntryID   string    `json:"entryId"`
+	EventID   string    `json:"eventId"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// callbackURL returns the URL the confirmation of the entry with the given
+// field-value pairs is posted to, or an empty string when it is not confirmed.
+// The field is read from the entry itself, whatever the encoding and the
+// mapping of the event data.
+func (a *Adapter) callbackURL(fieldValues []string) string {
+	if field := a.config.ProducerCallbackURLField; field != "" {
+		for i := 0; i+1 < len(fieldValues); i += 2 {
+			if fieldValues[i] == field {
+				return fieldValues[i+1]
+			}
+		}
+	}
+	return a.config.ProducerCallbackURL
+}
+
+// confirmDelivery posts the delivery confirmation of the entry with the given
+// ID to its producer at url, if any. Failures are logged only, the entry being
+// already acknowledged.
+func (a *Adapter) confirmDelivery(ctx context.Context, entryID string, event *cloudevents.Event, url string) {
+	if url == "" {
+		return
+	}
+
+	if err := a.postConfirmation(ctx, url, deliveryConfirmation{
+		EntryID:   entryID,
+		EventID:   event.ID(),
+		Timestamp: time.Now().UTC(),
+	}); err != nil {
+		a.logger.Error("Cannot confirm delivery to producer", zap.String("url", url), zap.String("entryID", entryID), zap.Error(err))
+	}
+}
+
+func (a *Adapter) postConfirmation(ctx context.Context, url string, confirmation deliveryConfirmation) error {
+	body, err := json.Marshal(confirmation)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callbackTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", cloudevents.ApplicationJSON)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestProcessEntry_ProducerCallback(t *testing.T) {
+	var confirmations []deliveryConfirmation
+	var paths []string
+	producer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c deliveryConfirmation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		confirmations = append(confirmations, c)
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer producer.Close()
+
+	entry := func(id string, fieldValues ...string) fakeReply {
+		fvs := make([]interface{}, len(fieldValues))
+		for i, fv := range fieldValues {
+			fvs[i] = []byte(fv)
+		}
+		return fakeReply{reply: []interface{}{
+			[]interface{}{[]byte("mystream"), []interface{}{
+				[]interface{}{[]byte(id), fvs},
+			}},
+		}}
+	}
+
+	conn := &fakeConn{reads: []fakeReply{
+		entry("1-0", "field", "value"),
+		entry("2-0", "callback", producer.URL+"/entry"),
+		entry("3-0", "field", "value"),
+		entry("4-0", "callback", producer.URL+"/failing"),
+		entry("5-0", "field", "value"),
+	}}
+	client := &fakeClient{results: []protocol.Result{
+		protocol.ResultACK,
+		protocol.ResultACK,
+		errors.New("sink unavailable"),
+		protocol.ResultACK,
+		protocol.ResultACK,
+	}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{
+		ProducerCallbackURL:      producer.URL + "/static",
+		ProducerCallbackURLField: "callback",
+	}}
+	retries := testRetryState()
+
+	xreadID := "0"
+	for range conn.reads {
+		xreadID = a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", xreadID, retries, false)
+	}
+
+	// Entry 3-0 was not delivered to the sink and is left pending, and the
+	// failing callback of 4-0 does not prevent 5-0 from being processed.
+	require.Equal(t, "3-0", xreadID)
+	require.Equal(t, []string{"/static", "/entry", "/failing", "/static"}, paths)
+	ids := make([]string, 0, len(confirmations))
+	for _, c := range confirmations {
+		require.Equal(t, c.EntryID, c.EventID)
+		require.False(t, c.Timestamp.IsZero())
+		ids = append(ids, c.EntryID)
+	}
+	require.Equal(t, []string{"1-0", "2-0", "4-0", "5-0"}, ids)
+}
+
+func TestProcessEntry_NoProducerCallback(t *testing.T) {
+	called := false
+	producer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer producer.Close()
+
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{ProducerCallbackURLField: "callback"}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), false)
+	require.Equal(t, 1, client.sent)
+	require.False(t, called)
+}
+
+func TestProcessEntry_ProducerCallbackEncodings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{{
+		name:   "msgpack",
+		config: Config{DataEncoding: sourcesv1alpha1.DataEncodingMsgPack},
+	}, {
+		name:   "binary data",
+		config: Config{BinaryDataField: "payload"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var paths []string
+			producer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+			}))
+			defer producer.Close()
+
+			conn := &fakeConn{reads: []fakeReply{{reply: []interface{}{
+				[]interface{}{[]byte("mystream"), []interface{}{
+					[]interface{}{[]byte("1-0"), []interface{}{
+						[]byte("payload"), []byte{0xff, 0x00},
+						[]byte("callback"), []byte(producer.URL + "/entry"),
+					}},
+				}},
+			}}}}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			config := test.config
+			config.ProducerCallbackURLField = "callback"
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &config}
+
+			a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), false)
+			require.Equal(t, 1, client.sent)
+			require.Equal(t, []string{"/entry"}, paths)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// claimDeadlineExtension is the extension attribute holding the time after
+// which the entry of the event may be reclaimed by another consumer.
+const claimDeadlineExtension = "redisclaimdeadline"
+
+// setClaimDeadline sets the redisclaimdeadline extension of the event when
+// reclaiming is enabled. The idle time of an entry is reset when it is
+// delivered to a consumer, so it may be reclaimed once it stays pending for
+// MinIdleTime after deliveredAt.
+func (a *Adapter) setClaimDeadline(event *cloudevents.Event, deliveredAt time.Time) {
+	if a.config.ReclaimMinIdleTime <= 0 {
+		return
+	}
+	event.SetExtension(claimDeadlineExtension, types.Timestamp{Time: claimDeadline(deliveredAt, a.config.ReclaimMinIdleTime)})
+}
+
+// claimDeadline returns the time after which an entry delivered at
+// deliveredAt may be reclaimed.
+func claimDeadline(deliveredAt time.Time, minIdleTime time.Duration) time.Time {
+	return deliveredAt.Add(minIdleTime).UTC()
+}
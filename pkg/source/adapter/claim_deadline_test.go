@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+func TestSetClaimDeadline(t *testing.T) {
+	deliveredAt := time.Date(2022, 3, 4, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	tests := map[string]struct {
+		minIdleTime time.Duration
+		want        string
+	}{
+		"reclaim disabled": {},
+		"reclaim enabled": {
+			minIdleTime: 90 * time.Second,
+			want:        "2022-03-04T09:01:30Z",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			a := &Adapter{config: &Config{ReclaimMinIdleTime: test.minIdleTime}}
+			event := cloudevents.NewEvent()
+			a.setClaimDeadline(&event, deliveredAt)
+
+			got, ok := event.Extensions()[claimDeadlineExtension]
+			if test.want == "" {
+				if ok {
+					t.Errorf("%s = %v, want unset", claimDeadlineExtension, got)
+				}
+				return
+			}
+			if s, err := types.Format(got); err != nil || s != test.want {
+				t.Errorf("%s = %q, want %q", claimDeadlineExtension, s, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	redisParse "github.com/go-redis/redis/v8"
+	"github.com/gomodule/redigo/redis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// dialStreamNode connects to the node of the cluster serving the stream, so
+// that new connections follow the slot of the stream when it moves.
+func (a *Adapter) dialStreamNode() (redis.Conn, error) {
+	node, err := scan.SlotNode(a.config.ClusterAddresses, scan.KeySlot(a.config.Stream), a.dialNode)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := a.dial(&redisParse.Options{Addr: node})
+	if err != nil {
+		return nil, err
+	}
+	return &followingConn{Conn: &askingConn{Conn: conn, dial: a.dialNode}, moved: scan.IsMoved}, nil
+}
+
+// dialNode connects to the cluster node at address.
+func (a *Adapter) dialNode(address string) (redis.Conn, error) {
+	return a.dial(&redisParse.Options{Addr: address})
+}
+
+// askingConn sends again to the node named by an ASK reply the commands
+// redirected while the slot of the stream is being migrated: the keys already
+// moved are served by the importing node, for that command only, once told
+// with ASKING. The connection keeps using the migrating node until the
+// migration ends with MOVED replies.
+type askingConn struct {
+	redis.Conn
+	dial func(address string) (redis.Conn, error)
+}
+
+func (c *askingConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(commandName, args...)
+	target, ok := scan.AskTarget(err)
+	if !ok {
+		return reply, err
+	}
+	conn, err := c.dial(target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.Do("ASKING"); err != nil {
+		return nil, err
+	}
+	return conn.Do(commandName, args...)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+)
+
+// importingConn records the commands sent to the node importing a slot.
+type importingConn struct {
+	redis.Conn
+	commands []string
+	closed   bool
+}
+
+func (c *importingConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.commands = append(c.commands, commandName)
+	return int64(1), nil
+}
+
+func (c *importingConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAskingConn(t *testing.T) {
+	importing := &importingConn{}
+	var dialed string
+	conn := &askingConn{
+		Conn: &replyConn{err: redis.Error("ASK 3999 127.0.0.1:6381")},
+		dial: func(address string) (redis.Conn, error) {
+			dialed = address
+			return importing, nil
+		},
+	}
+	reply, err := conn.Do("XACK", "{orders}", "group", "1-0")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), reply)
+	require.Equal(t, "127.0.0.1:6381", dialed)
+	require.Equal(t, []string{"ASKING", "XACK"}, importing.commands)
+	require.True(t, importing.closed)
+
+	// Other errors are returned as they are.
+	conn.Conn = &replyConn{err: redis.Error("MOVED 3999 127.0.0.1:6381")}
+	_, err = conn.Do("XACK", "{orders}", "group", "1-0")
+	require.Error(t, err)
+	require.Len(t, importing.commands, 2)
+}
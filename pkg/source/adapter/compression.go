@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const sinkProbeTimeout = 5 * time.Second
+
+// gzipRoundTripper compresses the body of the requests it sends with gzip.
+type gzipRoundTripper struct {
+	http.RoundTripper
+	// stream compresses the body while it is sent rather than beforehand.
+	stream bool
+}
+
+func (g *gzipRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return g.RoundTripper.RoundTrip(req)
+	}
+	if g.stream {
+		return g.roundTripStream(req)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	compressed := buf.Bytes()
+
+	// The request must not be modified, see http.RoundTripper.
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Encoding", "gzip")
+	req.ContentLength = int64(len(compressed))
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	return g.RoundTripper.RoundTrip(req)
+}
+
+// roundTripStream sends the request with its body compressed while it is
+// streamed to the sink, its compressed length being unknown.
+func (g *gzipRoundTripper) roundTripStream(req *http.Request) (*http.Response, error) {
+	getBody := req.GetBody
+	body := req.Body
+
+	// The request must not be modified, see http.RoundTripper.
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	req.Body = gzipStream(body)
+	req.GetBody = nil
+	if getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return gzipStream(body), nil
+		}
+	}
+	return g.RoundTripper.RoundTrip(req)
+}
+
+// sinkAcceptsGzip sends an OPTIONS request to the sink and returns true when
+// the sink advertises gzip in the Accept-Encoding header of its response.
+func sinkAcceptsGzip(ctx context.Context, transport http.RoundTripper, sink string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, sinkProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, sink, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	for _, value := range resp.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			if name, _, _ := strings.Cut(strings.TrimSpace(coding), ";"); strings.EqualFold(name, "gzip") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSinkContentEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		encoding       string
+		acceptEncoding string
+		wantEncoding   string
+	}{{
+		name:           "gzip",
+		encoding:       "gzip",
+		acceptEncoding: "br, gzip;q=0.8",
+		wantEncoding:   "gzip",
+	}, {
+		name:     "gzip not accepted by the sink",
+		encoding: "gzip",
+	}, {
+		name:           "no encoding",
+		acceptEncoding: "gzip",
+	}}
+
+	fieldValues := []string{"payload", strings.Repeat("redis stream entry ", 10000)}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotEncoding string
+			var gotFieldValues []string
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodOptions {
+					if test.acceptEncoding != "" {
+						w.Header().Set("Accept-Encoding", test.acceptEncoding)
+					}
+					return
+				}
+
+				gotEncoding = r.Header.Get("Content-Encoding")
+				var body io.Reader = r.Body
+				if gotEncoding == "gzip" {
+					zr, err := gzip.NewReader(r.Body)
+					require.NoError(t, err)
+					body = zr
+				}
+				require.NoError(t, json.NewDecoder(body).Decode(&gotFieldValues))
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			a := &Adapter{logger: zap.NewNop(), config: &Config{SinkContentEncoding: test.encoding}}
+			transport, err := newSinkTransport(1, false)
+			require.NoError(t, err)
+			client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL),
+				cehttp.WithRoundTripper(a.encodingRoundTripper(context.Background(), transport, sink.URL)))
+			require.NoError(t, err)
+
+			event := cloudevents.NewEvent()
+			event.SetID("1-0")
+			event.SetType(RedisStreamSourceEventType)
+			event.SetSource("test")
+			require.NoError(t, event.SetData(cloudevents.ApplicationJSON, fieldValues))
+
+			require.True(t, cloudevents.IsACK(client.Send(context.Background(), event)))
+			require.Equal(t, test.wantEncoding, gotEncoding)
+			require.Equal(t, fieldValues, gotFieldValues)
+		})
+	}
+}
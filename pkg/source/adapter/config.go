@@ -16,6 +16,8 @@ limitations under the License.
 package adapter
 
 import (
+	"time"
+
 	"knative.dev/eventing/pkg/adapter/v2"
 )
 
@@ -28,4 +30,215 @@ type Config struct {
 	PodName        string `envconfig:"NAME" required:"true"`
 	NumConsumers   string `envconfig:"NUM_CONSUMERS" required:"true"`
 	TLSCertificate string `envconfig:"TLS_CERTIFICATE" required:"true"`
+
+	// Streams read with the same group, Stream being the first one, see sourcesv1alpha1.RedisStreamSourceSpec.Streams.
+	Streams []string `envconfig:"STREAMS"`
+
+	// TLS of the connections to Redis, see sourcesv1alpha1.RedisStreamSourceSpec.TLS.
+	RedisTLSCACert string `envconfig:"REDIS_TLS_CA_CERT"`
+	RedisTLSCert   string `envconfig:"REDIS_TLS_CERT"`
+	RedisTLSKey    string `envconfig:"REDIS_TLS_KEY"`
+	// Credentials of the connections to Redis, see sourcesv1alpha1.RedisStreamSourceSpec.Auth.
+	// They replace the credentials of the address URL when a password is set.
+	RedisUsername string `envconfig:"REDIS_USERNAME"`
+	RedisPassword string `envconfig:"REDIS_PASSWORD"`
+	// RedisPasswordFile is the file the password is read from instead of RedisPassword, see sourcesv1alpha1.RedisAuth.PasswordFile.
+	RedisPasswordFile string `envconfig:"REDIS_PASSWORD_FILE"`
+	// OIDC token of the service account of the pod exchanged for the password at the token endpoint of a security token service, see sourcesv1alpha1.RedisOIDC.
+	RedisOIDCTokenFile string `envconfig:"REDIS_OIDC_TOKEN_FILE"`
+	RedisOIDCTokenURL  string `envconfig:"REDIS_OIDC_TOKEN_URL"`
+	RedisOIDCClientID  string `envconfig:"REDIS_OIDC_CLIENT_ID"`
+	RedisOIDCScope     string `envconfig:"REDIS_OIDC_SCOPE"`
+
+	// RedisTLSInsecureSkipVerify disables the verification of the Redis server.
+	RedisTLSInsecureSkipVerify bool `envconfig:"REDIS_TLS_INSECURE_SKIP_VERIFY" default:"false"`
+
+	// Redis Sentinel monitoring the master to connect to instead of Address, see sourcesv1alpha1.RedisSentinel.
+	SentinelMasterName string   `envconfig:"SENTINEL_MASTER_NAME"`
+	SentinelAddresses  []string `envconfig:"SENTINEL_ADDRESSES"`
+
+	// Nodes of the Redis cluster holding the stream, to connect to instead of Address, see sourcesv1alpha1.RedisCluster.
+	ClusterAddresses []string `envconfig:"CLUSTER_ADDRESSES"`
+
+	// Directory the target ConfigMap is mounted in, see sourcesv1alpha1.RedisStreamSourceSpec.TargetConfigMap.
+	TargetPath string `envconfig:"TARGET_PATH"`
+
+	// Daily time window outside of which the stream is not read.
+	DeliveryWindowStart    string `envconfig:"DELIVERY_WINDOW_START"`
+	DeliveryWindowEnd      string `envconfig:"DELIVERY_WINDOW_END"`
+	DeliveryWindowTimezone string `envconfig:"DELIVERY_WINDOW_TIMEZONE"`
+
+	// Minimum age of the entries before they are delivered.
+	DeliveryDelay time.Duration `envconfig:"DELIVERY_DELAY"`
+
+	DisableHTTP2 bool `envconfig:"DISABLE_HTTP2" default:"false"`
+
+	// Stream the request bodies to the sink, see sourcesv1alpha1.RedisStreamSourceSpec.StreamRequestBody.
+	StreamRequestBody bool `envconfig:"STREAM_REQUEST_BODY" default:"false"`
+
+	SinkContentEncoding string `envconfig:"SINK_CONTENT_ENCODING"`
+	RespectRetryAfter   bool   `envconfig:"RESPECT_RETRY_AFTER" default:"true"`
+	SequenceExtension   bool   `envconfig:"SEQUENCE_EXTENSION" default:"false"`
+	SequenceCounter     bool   `envconfig:"SEQUENCE_COUNTER" default:"false"`
+
+	// Tag events with the adapter pod and node, see sourcesv1alpha1.RedisStreamSourceSpec.ProvenanceExtensions.
+	ProvenanceExtensions bool   `envconfig:"PROVENANCE_EXTENSIONS" default:"false"`
+	NodeName             string `envconfig:"NODE_NAME"`
+
+	// Appended to the type of the events of redelivered entries, see sourcesv1alpha1.RedisStreamSourceSpec.RedeliveredTypeSuffix.
+	RedeliveredTypeSuffix string `envconfig:"REDELIVERED_TYPE_SUFFIX"`
+
+	// Type and source of the events, see sourcesv1alpha1.RedisStreamSourceSpec.Type,
+	// TypeFieldName and EventSource.
+	EventType     string `envconfig:"EVENT_TYPE"`
+	TypeFieldName string `envconfig:"TYPE_FIELD_NAME"`
+	EventSource   string `envconfig:"EVENT_SOURCE"`
+
+	// Field of the entries whose raw bytes are the data of the events, see sourcesv1alpha1.BinaryData.
+	BinaryDataField       string `envconfig:"BINARY_DATA_FIELD"`
+	BinaryDataContentType string `envconfig:"BINARY_DATA_CONTENT_TYPE"`
+
+	// Field of the entries the partitionkey of the events is computed from, see sourcesv1alpha1.PartitionKey.
+	PartitionKeyField      string `envconfig:"PARTITION_KEY_FIELD"`
+	PartitionKeyPartitions int    `envconfig:"PARTITION_KEY_PARTITIONS" default:"0"`
+
+	// Topic the events are produced to through the Kafka bridge at the sink, see sourcesv1alpha1.KafkaBridge.
+	KafkaBridgeTopic string `envconfig:"KAFKA_BRIDGE_TOPIC"`
+
+	// How entries are serialized to JSON, see sourcesv1alpha1.JSONOptions.
+	JSONDisableHTMLEscape bool `envconfig:"JSON_DISABLE_HTML_ESCAPE" default:"false"`
+	JSONIndent            bool `envconfig:"JSON_INDENT" default:"false"`
+
+	// How entries are serialized to the body of the events, see sourcesv1alpha1.RedisStreamSourceSpec.DataEncoding.
+	DataEncoding string `envconfig:"DATA_ENCODING" default:"json"`
+
+	// ConditionalRequests sends events with the If-None-Match header set to
+	// their ID, and treats 304 and 412 responses as delivered.
+	ConditionalRequests bool `envconfig:"CONDITIONAL_REQUESTS" default:"false"`
+
+	// HoldOnSinkUnavailable leaves the entries that cannot be delivered
+	// because the sink is unavailable in the pending entries list.
+	HoldOnSinkUnavailable bool `envconfig:"HOLD_ON_SINK_UNAVAILABLE" default:"false"`
+
+	// A report is sent to FailureReportSink when more than FailureReportThreshold
+	// events fail to be delivered within FailureReportWindow.
+	FailureReportSink      string        `envconfig:"FAILURE_REPORT_SINK"`
+	FailureReportThreshold int           `envconfig:"FAILURE_REPORT_THRESHOLD" default:"1"`
+	FailureReportWindow    time.Duration `envconfig:"FAILURE_REPORT_WINDOW" default:"1m"`
+
+	// Key of the Redis set holding the IDs of the events emitted, see sourcesv1alpha1.Dedup.
+	DedupKey string `envconfig:"DEDUP_KEY"`
+
+	// Number of times acknowledging a delivered entry is retried before it is left pending.
+	AckRetries int `envconfig:"ACK_RETRIES" default:"3"`
+
+	// Delivered entries are acknowledged together every AckSweepInterval, see sourcesv1alpha1.RedisStreamSourceSpec.AckSweepInterval.
+	AckSweepInterval time.Duration `envconfig:"ACK_SWEEP_INTERVAL"`
+
+	// The lag and pending entries of the consumer group are sampled every LagSampleInterval,
+	// see sourcesv1alpha1.RedisStreamSourceSpec.LagSampleInterval.
+	LagSampleInterval time.Duration `envconfig:"LAG_SAMPLE_INTERVAL" default:"30s"`
+
+	// Name of the source, tagging the lag metrics.
+	SourceName string `envconfig:"SOURCE_NAME"`
+
+	// The trim job of the source trims the streams with XTRIM <TrimStrategy> <TrimThreshold>,
+	// see sourcesv1alpha1.Trimming. It reports the entries trimmed in events of the source
+	// whose UID is SourceUID.
+	TrimStrategy    string `envconfig:"TRIM_STRATEGY"`
+	TrimThreshold   string `envconfig:"TRIM_THRESHOLD"`
+	TrimApproximate bool   `envconfig:"TRIM_APPROXIMATE"`
+	SourceUID       string `envconfig:"SOURCE_UID"`
+
+	// The stream is trimmed to about TrimMaxLen entries once entries are acknowledged,
+	// see sourcesv1alpha1.Trimming with OnAck.
+	TrimMaxLen int64 `envconfig:"TRIM_MAX_LEN"`
+	TrimExact  bool  `envconfig:"TRIM_EXACT" default:"false"`
+
+	// Fraction of the traces started by the adapter that are sampled, instead of the
+	// sample-rate of the tracing configuration, see sourcesv1alpha1.Tracing.
+	TracingSamplingRate *float64 `envconfig:"TRACING_SAMPLING_RATE"`
+
+	// The consumers only read the stream while the pod holds the Lease named LeaseName,
+	// see sourcesv1alpha1.RedisStreamSourceSpec.ExclusiveConsumer.
+	LeaseName     string        `envconfig:"LEASE_NAME"`
+	LeaseDuration time.Duration `envconfig:"LEASE_DURATION" default:"15s"`
+
+	// The IDs of the last entries acknowledged are checkpointed to the ConfigMap named
+	// CursorConfigMap every CheckpointInterval entries acknowledged, see
+	// sourcesv1alpha1.RedisStreamSourceSpec.CheckpointInterval.
+	CheckpointInterval int    `envconfig:"CHECKPOINT_INTERVAL"`
+	CursorConfigMap    string `envconfig:"CURSOR_CONFIGMAP"`
+
+	// The deliveries in flight when the adapter shuts down, and those of the pending entries,
+	// complete within TerminationGracePeriod, see sourcesv1alpha1.RedisStreamSourceSpec.TerminationGracePeriod.
+	TerminationGracePeriod time.Duration `envconfig:"TERMINATION_GRACE_PERIOD" default:"30s"`
+
+	// Redis is sent PING every HealthCheckInterval, see sourcesv1alpha1.RedisStreamSourceSpec.HealthCheckInterval.
+	HealthCheckInterval time.Duration `envconfig:"HEALTH_CHECK_INTERVAL" default:"30s"`
+
+	// Reconnecting to Redis backs off exponentially, see sourcesv1alpha1.ReconnectBackoff.
+	RedisBackoffInitial    time.Duration `envconfig:"REDIS_BACKOFF_INITIAL" default:"100ms"`
+	RedisBackoffMax        time.Duration `envconfig:"REDIS_BACKOFF_MAX" default:"30s"`
+	RedisBackoffMultiplier float64       `envconfig:"REDIS_BACKOFF_MULTIPLIER" default:"2"`
+
+	// Delivered entries are left pending for the sink to acknowledge, see sourcesv1alpha1.RedisStreamSourceSpec.DisableAutoAck.
+	DisableAutoAck bool `envconfig:"DISABLE_AUTO_ACK" default:"false"`
+
+	// XREADGROUP reads up to ReadCount entries at once, and waits up to ReadBlockTimeout
+	// for entries to be added, 0 waiting until one is, see sourcesv1alpha1.RedisStreamSourceSpec.ReadCount
+	// and ReadBlockTimeout.
+	ReadCount        int           `envconfig:"READ_COUNT" default:"1"`
+	ReadBlockTimeout time.Duration `envconfig:"READ_BLOCK_TIMEOUT" default:"5s"`
+
+	// Entries are delivered in batches of up to BatchSize events, see
+	// sourcesv1alpha1.RedisStreamSourceSpec.BatchSize, BatchMaxBytes and BatchLinger.
+	BatchSize     int           `envconfig:"BATCH_SIZE" default:"0"`
+	BatchMaxBytes int           `envconfig:"BATCH_MAX_BYTES" default:"0"`
+	BatchLinger   time.Duration `envconfig:"BATCH_LINGER" default:"1s"`
+
+	// Pending entries idle for ReclaimMinIdleTime are reclaimed every ReclaimInterval,
+	// see sourcesv1alpha1.Reclaim. Setting it adds the redisclaimdeadline extension to the events.
+	ReclaimMinIdleTime         time.Duration `envconfig:"RECLAIM_MIN_IDLE_TIME"`
+	ReclaimInterval            time.Duration `envconfig:"RECLAIM_INTERVAL"`
+	ReclaimMaxDeliveryAttempts int           `envconfig:"RECLAIM_MAX_DELIVERY_ATTEMPTS" default:"0"`
+
+	// What happens to the entries without fields, see sourcesv1alpha1.EmptyEntryPolicy.
+	OnEmptyEntry string `envconfig:"ON_EMPTY_ENTRY" default:"Skip"`
+
+	// What happens to the entries without a required field, see sourcesv1alpha1.MissingFieldPolicy.
+	OnMissingField string `envconfig:"ON_MISSING_FIELD" default:"Drop"`
+
+	// The entries are validated against the JSON Schema at SchemaURL, downloaded again every
+	// SchemaRefreshInterval, see sourcesv1alpha1.RedisStreamSourceSpec.SchemaURL and
+	// sourcesv1alpha1.SchemaValidationMode.
+	SchemaURL             string        `envconfig:"SCHEMA_URL"`
+	SchemaValidation      string        `envconfig:"SCHEMA_VALIDATION" default:"Warn"`
+	SchemaRefreshInterval time.Duration `envconfig:"SCHEMA_REFRESH_INTERVAL" default:"5m"`
+
+	// Key of the stream entries are dead-lettered to, see sourcesv1alpha1.RedisStreamSourceSpec.DeadLetterStream.
+	DeadLetterStream string `envconfig:"DEAD_LETTER_STREAM"`
+
+	// Where the consumer groups created by the adapter start reading from.
+	StartID string `envconfig:"START_ID" default:"$"`
+
+	// Entries before MinID are acknowledged without being delivered.
+	MinID string `envconfig:"MIN_ID"`
+
+	// How delivering an event to the sink is retried, see sourcesv1alpha1.RedisStreamSourceSpec.Delivery.
+	// The default is retryNumTimes retries with an exponential backoff starting at retryWaitPeriod.
+	DeliveryRetry         *int          `envconfig:"DELIVERY_RETRY"`
+	DeliveryBackoffPolicy string        `envconfig:"DELIVERY_BACKOFF_POLICY"`
+	DeliveryBackoffDelay  time.Duration `envconfig:"DELIVERY_BACKOFF_DELAY"`
+	DeliveryTimeout       time.Duration `envconfig:"DELIVERY_TIMEOUT"`
+
+	// DeadLetterSink receives the events the sink does not accept, see sourcesv1alpha1.RedisStreamSourceSpec.DeadLetterSink.
+	DeadLetterSink string `envconfig:"DEAD_LETTER_SINK"`
+
+	// AuditSink receives a copy of every event, see sourcesv1alpha1.RedisStreamSourceSpec.AuditSink.
+	AuditSink string `envconfig:"AUDIT_SINK"`
+
+	// Where delivery confirmations are posted to, see sourcesv1alpha1.ProducerCallback.
+	ProducerCallbackURL      string `envconfig:"PRODUCER_CALLBACK_URL"`
+	ProducerCallbackURLField string `envconfig:"PRODUCER_CALLBACK_URL_FIELD"`
 }
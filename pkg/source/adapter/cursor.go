@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// checkpointTimeout bounds the time spent writing the cursors.
+const checkpointTimeout = 10 * time.Second
+
+// checkpointer records the IDs of the last entries acknowledged by the
+// consumer groups of the adapter, to be checkpointed every interval entries.
+type checkpointer struct {
+	mu       sync.Mutex
+	cursors  sourcesv1alpha1.Cursors
+	acked    int
+	interval int
+	due      chan struct{} // signaled once interval entries were acknowledged
+}
+
+func newCheckpointer(interval int) *checkpointer {
+	return &checkpointer{
+		cursors:  sourcesv1alpha1.Cursors{},
+		interval: interval,
+		due:      make(chan struct{}, 1),
+	}
+}
+
+// ack records that the entry was acknowledged by the group. Entries are
+// acknowledged out of order by concurrent consumers, the cursor of the group
+// only moves forward.
+func (c *checkpointer) ack(streamName, groupName, id string) {
+	sid, err := scan.ParseStreamID(id)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groups := c.cursors[streamName]
+	if groups == nil {
+		groups = make(map[string]string)
+		c.cursors[streamName] = groups
+	}
+	if last, err := scan.ParseStreamID(groups[groupName]); err != nil || last.Less(sid) {
+		groups[groupName] = sid.String()
+	}
+	c.acked++
+	if c.acked >= c.interval {
+		c.acked = 0
+		select {
+		case c.due <- struct{}{}:
+		default: // a checkpoint is due already
+		}
+	}
+}
+
+// snapshot returns a copy of the cursors recorded.
+func (c *checkpointer) snapshot() sourcesv1alpha1.Cursors {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cursors.DeepCopy()
+}
+
+// cursorStore reads and writes the cursors in the cursor ConfigMap of the
+// source, shared by the replicas of the adapter.
+type cursorStore struct {
+	configMaps corev1client.ConfigMapInterface
+	name       string
+}
+
+func newCursorStore(ctx context.Context, namespace, name string) *cursorStore {
+	return &cursorStore{configMaps: kubeclient.Get(ctx).CoreV1().ConfigMaps(namespace), name: name}
+}
+
+// load returns the cursors checkpointed, none while the ConfigMap does not
+// exist yet.
+func (s *cursorStore) load(ctx context.Context) (sourcesv1alpha1.Cursors, error) {
+	configMap, err := s.configMaps.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return sourcesv1alpha1.Cursors{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sourcesv1alpha1.ParseCursors(configMap.Data)
+}
+
+// save writes the cursors to the ConfigMap, keeping the cursors of the other
+// consumer groups, e.g. of the other replicas.
+func (s *cursorStore) save(ctx context.Context, cursors sourcesv1alpha1.Cursors) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := s.configMaps.Get(ctx, s.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		stored, err := sourcesv1alpha1.ParseCursors(configMap.Data)
+		if err != nil {
+			stored = sourcesv1alpha1.Cursors{} // overwritten
+		}
+		for streamName, groups := range cursors {
+			if stored[streamName] == nil {
+				stored[streamName] = make(map[string]string, len(groups))
+			}
+			for groupName, id := range groups {
+				stored[streamName][groupName] = id
+			}
+		}
+		doc, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string, 1)
+		}
+		configMap.Data[sourcesv1alpha1.CursorsKey] = string(doc)
+		_, err = s.configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// resumeFromCursors reads the cursors checkpointed, where the consumer groups
+// created again start reading after, and starts recording the new ones.
+func (a *Adapter) resumeFromCursors(ctx context.Context) error {
+	resumeIDs, err := a.cursors.load(ctx)
+	if err != nil {
+		return err
+	}
+	a.resumeIDs = resumeIDs
+	a.checkpoints = newCheckpointer(a.config.CheckpointInterval)
+	return nil
+}
+
+// startID returns where the consumer group starts reading from when it is
+// created: after the last entry it acknowledged, if checkpointed, or else
+// from the configured start ID.
+func (a *Adapter) startID(streamName, groupName string) string {
+	if id := a.resumeIDs[streamName][groupName]; id != "" {
+		return id
+	}
+	return a.config.StartID
+}
+
+// checkpointEvery writes the cursors once a checkpoint is due, until ctx is
+// done.
+func (a *Adapter) checkpointEvery(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.checkpoints.due:
+			a.checkpoint(ctx)
+		}
+	}
+}
+
+// checkpoint writes the cursors recorded to the ConfigMap.
+func (a *Adapter) checkpoint(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, checkpointTimeout)
+	defer cancel()
+	cursors := a.checkpoints.snapshot()
+	if err := a.cursors.save(ctx, cursors); err != nil {
+		a.logger.Warn("Cannot checkpoint the last acknowledged entries", zap.String("configMap", a.cursors.name), zap.Error(err))
+		return
+	}
+	a.logger.Debug("Checkpointed the last acknowledged entries", zap.Any("cursors", cursors))
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestCheckpointer_Ack(t *testing.T) {
+	c := newCheckpointer(3)
+
+	c.ack("mystream", "mygroup", "2-0")
+	c.ack("mystream", "mygroup", "1-0") // acknowledged by a slower consumer
+	require.Len(t, c.due, 0, "no checkpoint is due before 3 entries")
+	c.ack("mystream", "othergroup", "1-5")
+	require.Len(t, c.due, 1)
+
+	require.Equal(t, sourcesv1alpha1.Cursors{
+		"mystream": {"mygroup": "2-0", "othergroup": "1-5"},
+	}, c.snapshot())
+}
+
+func TestAdapter_Checkpoint(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source-cursor"},
+		Data:       map[string]string{sourcesv1alpha1.CursorsKey: `{"mystream": {"adapter-0": "1-0", "adapter-1": "3-0"}}`},
+	})
+	a := &Adapter{
+		logger: zap.NewNop(),
+		config: &Config{StartID: "$", CheckpointInterval: 2},
+		cursors: &cursorStore{
+			configMaps: kubeClient.CoreV1().ConfigMaps("ns"),
+			name:       "source-cursor",
+		},
+	}
+	require.NoError(t, a.resumeFromCursors(context.Background()))
+	require.Equal(t, "1-0", a.startID("mystream", "adapter-0"))
+	require.Equal(t, "$", a.startID("mystream", "adapter-2"))
+	require.Equal(t, "$", a.startID("otherstream", "adapter-0"))
+
+	conn := &fakeConn{}
+	require.NoError(t, a.ack(conn, "mystream", "adapter-0", "4-0"))
+	require.NoError(t, a.ack(conn, "mystream", "adapter-0", "5-0"))
+	<-a.checkpoints.due
+	a.checkpoint(context.Background())
+
+	// The cursors of the other replicas are kept.
+	loaded, err := a.cursors.load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, sourcesv1alpha1.Cursors{"mystream": {"adapter-0": "5-0", "adapter-1": "3-0"}}, loaded)
+}
+
+func TestAdapter_CheckpointStreams(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source-cursor"},
+		Data:       map[string]string{sourcesv1alpha1.CursorsKey: `{"orders": {"adapter-0": "1-0"}, "payments": {"adapter-0": "2-0"}}`},
+	})
+	a := &Adapter{
+		logger: zap.NewNop(),
+		config: &Config{StartID: "$", CheckpointInterval: 2, Streams: []string{"orders", "payments"}},
+		cursors: &cursorStore{
+			configMaps: kubeClient.CoreV1().ConfigMaps("ns"),
+			name:       "source-cursor",
+		},
+	}
+	ctx := context.Background()
+	require.NoError(t, a.resumeFromCursors(ctx))
+
+	// The adapter of each stream resumes its group from the cursor of the
+	// stream, and checkpoints the entries it acknowledges.
+	orders, payments := a.forStream(ctx, "orders"), a.forStream(ctx, "payments")
+	require.Equal(t, "1-0", orders.startID("orders", "adapter-0"))
+	require.Equal(t, "2-0", payments.startID("payments", "adapter-0"))
+
+	conn := &fakeConn{}
+	require.NoError(t, orders.ack(conn, "orders", "adapter-0", "3-0"))
+	require.NoError(t, payments.ack(conn, "payments", "adapter-0", "4-0"))
+	<-a.checkpoints.due
+	a.checkpoint(ctx)
+
+	loaded, err := a.cursors.load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, sourcesv1alpha1.Cursors{"orders": {"adapter-0": "3-0"}, "payments": {"adapter-0": "4-0"}}, loaded)
+}
+
+func TestCursorStore_LoadWithoutConfigMap(t *testing.T) {
+	s := &cursorStore{configMaps: kubefake.NewSimpleClientset().CoreV1().ConfigMaps("ns"), name: "source-cursor"}
+
+	cursors, err := s.load(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, cursors)
+	require.Error(t, s.save(context.Background(), cursors), "the ConfigMap is created by the controller")
+}
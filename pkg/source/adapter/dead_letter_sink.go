@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+)
+
+// Extensions set on the events sent to the dead-letter sink, as Knative
+// Eventing channels and brokers do.
+const (
+	errorDestExtension = "knativeerrordest" // the sink that did not accept the event
+	errorCodeExtension = "knativeerrorcode" // the status code of its last response, if any
+	errorDataExtension = "knativeerrordata" // why it did not accept the event, base64-encoded
+)
+
+// maxErrorDataLength is the length the failure reason is truncated to before
+// it is encoded in the knativeerrordata extension, as Knative Eventing does.
+const maxErrorDataLength = 1024
+
+// useDeadLetterSink creates the client sending the events the sink does not
+// accept to the configured dead-letter sink through the given transport.
+func (a *Adapter) useDeadLetterSink(transport http.RoundTripper) error {
+	if a.config.DeadLetterSink == "" {
+		return nil
+	}
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(a.config.DeadLetterSink), cehttp.WithRoundTripper(transport))
+	if err != nil {
+		return err
+	}
+	a.deadLetters = client
+	return nil
+}
+
+// sendToDeadLetterSink sends the event the sink did not accept, with the
+// result of the last attempt as failure reason, to the dead-letter sink, if
+// any. It returns whether the dead-letter sink accepted the event. Failures
+// are logged and counted.
+func (a *Adapter) sendToDeadLetterSink(ctx context.Context, event *cloudevents.Event, result protocol.Result) bool {
+	if a.deadLetters == nil {
+		return false
+	}
+
+	copied := event.Clone()
+	copied.SetExtension(errorDestExtension, a.config.GetSink())
+	if statusCode, ok := sinkStatusCode(result); ok {
+		copied.SetExtension(errorCodeExtension, statusCode)
+	}
+	if result != nil {
+		copied.SetExtension(errorDataExtension, errorData(result))
+	}
+	if result := a.deadLetters.Send(a.sendContext(ctx), copied); !cloudevents.IsACK(result) {
+		a.logger.Error("Failed to send cloudevent to the dead-letter sink", zap.String("id", event.ID()), zap.Any("result", result))
+		metrics.Record(ctx, deadLetterSinkFailureCountM.M(1))
+		return false
+	}
+	return true
+}
+
+// errorData returns the failure reason of the result, truncated to
+// maxErrorDataLength bytes and base64-encoded.
+func errorData(result protocol.Result) string {
+	reason := result.Error()
+	if len(reason) > maxErrorDataLength {
+		reason = reason[:maxErrorDataLength]
+	}
+	return base64.StdEncoding.EncodeToString([]byte(reason))
+}
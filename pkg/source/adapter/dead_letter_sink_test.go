@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+func TestProcessEntry_DeadLetterSink(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("2-0")}}
+	rejected := cehttp.NewResult(http.StatusBadRequest, "invalid event")
+	client := &fakeClient{results: []protocol.Result{rejected, rejected}}
+	deadLetters := &fakeClient{results: []protocol.Result{
+		protocol.ResultACK,
+		errors.New("dead-letter sink unavailable"),
+	}}
+	a := &Adapter{logger: zap.NewNop(), client: client, deadLetters: deadLetters, config: &Config{
+		EnvConfig: adapter.EnvConfig{Sink: "http://sink.example.com"},
+	}}
+
+	for i := 0; i < 2; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+
+	// 1-0 is acknowledged once the dead-letter sink has it, 2-0 is left
+	// pending as the dead-letter sink failed too.
+	require.Equal(t, 2, deadLetters.sent)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+	require.Equal(t, "http://sink.example.com", deadLetters.events[0].Extensions()[errorDestExtension])
+	require.EqualValues(t, http.StatusBadRequest, deadLetters.events[0].Extensions()[errorCodeExtension])
+	data, err := base64.StdEncoding.DecodeString(deadLetters.events[0].Extensions()[errorDataExtension].(string))
+	require.NoError(t, err)
+	require.Equal(t, rejected.Error(), string(data))
+}
+
+func TestErrorData(t *testing.T) {
+	long := errors.New(strings.Repeat("x", 2*maxErrorDataLength))
+	data, err := base64.StdEncoding.DecodeString(errorData(long))
+	require.NoError(t, err)
+	require.Len(t, data, maxErrorDataLength)
+}
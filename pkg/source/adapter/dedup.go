@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import "github.com/gomodule/redigo/redis"
+
+// dedupStore holds the IDs of the events emitted, so that the events already
+// emitted, e.g. by another source or before a restart, are skipped.
+type dedupStore interface {
+	// Seen returns true when the event with the given ID was emitted.
+	Seen(conn redis.Conn, id string) (bool, error)
+	// Record records that the event with the given ID was emitted.
+	Record(conn redis.Conn, id string) error
+}
+
+// redisSetDedup holds the IDs of the events emitted in a Redis set.
+type redisSetDedup struct {
+	key string
+}
+
+func (d *redisSetDedup) Seen(conn redis.Conn, id string) (bool, error) {
+	return redis.Bool(conn.Do("SISMEMBER", d.key, id))
+}
+
+func (d *redisSetDedup) Record(conn redis.Conn, id string) error {
+	_, err := conn.Do("SADD", d.key, id)
+	return err
+}
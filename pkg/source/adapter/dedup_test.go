@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProcessEntry_Dedup(t *testing.T) {
+	conn := &fakeConn{
+		reads: []fakeReply{entryReply("1-0"), entryReply("2-0"), entryReply("3-0"), entryReply("1-0")},
+		// Emitted by another source sharing the dedup set.
+		sets: map[string]map[string]bool{"redisdedup:mystream": {"2-0": true}},
+	}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, errors.New("sink unavailable")}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}, dedup: &redisSetDedup{key: "redisdedup:mystream"}}
+
+	for i := 0; i < 4; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+
+	var sent []string
+	for _, event := range client.events {
+		sent = append(sent, event.ID())
+	}
+	// 2-0 was emitted by the other source and 1-0 before, 3-0 failed to be delivered.
+	require.Equal(t, []string{"1-0", "3-0"}, sent)
+	require.Equal(t, []string{"1-0", "2-0", "1-0"}, conn.acks)
+	require.Equal(t, map[string]bool{"1-0": true, "2-0": true}, conn.sets["redisdedup:mystream"])
+}
+
+type failingDedup struct{}
+
+func (failingDedup) Seen(redis.Conn, string) (bool, error) { return false, errors.New("READONLY") }
+func (failingDedup) Record(redis.Conn, string) error       { return nil }
+
+func TestProcessEntry_DedupUnavailable(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	client := &fakeClient{}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}, dedup: failingDedup{}}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+
+	// The entry stays pending until the dedup store can be checked.
+	require.Equal(t, "0", xreadID)
+	require.Empty(t, client.events)
+	require.Empty(t, conn.acks)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+// withDeliveryRetries returns a context making the sink client retry
+// delivering an event as configured. The entry is acknowledged, or its event
+// dead-lettered, only once the last attempt failed.
+func (a *Adapter) withDeliveryRetries(ctx context.Context) context.Context {
+	retries := retryNumTimes
+	if a.config.DeliveryRetry != nil {
+		retries = *a.config.DeliveryRetry
+	}
+	if retries <= 0 {
+		return ctx
+	}
+	delay := a.config.DeliveryBackoffDelay
+	if delay <= 0 {
+		delay = retryWaitPeriod
+	}
+	if a.config.DeliveryBackoffPolicy == string(eventingduckv1.BackoffPolicyLinear) {
+		return cloudevents.ContextWithRetriesLinearBackoff(ctx, delay, retries)
+	}
+	return cloudevents.ContextWithRetriesExponentialBackoff(ctx, delay, retries)
+}
+
+// timeoutRoundTripper bounds the time each request, including reading its
+// response, may take, so that each attempt to deliver an event times out on
+// its own.
+type timeoutRoundTripper struct {
+	http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.RoundTripper.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels the context of a request once its response is read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
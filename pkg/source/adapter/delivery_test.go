@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapter_WithDeliveryRetries(t *testing.T) {
+	var attempts int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer sink.Close()
+
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL))
+	require.NoError(t, err)
+
+	event := cloudevents.NewEvent()
+	event.SetID("1-0")
+	event.SetType(RedisStreamSourceEventType)
+	event.SetSource("redis://redis:6379/mystream")
+
+	zero, two := 0, 2
+	tests := []struct {
+		name         string
+		config       Config
+		wantAttempts int32
+	}{{
+		name:         "default",
+		config:       Config{DeliveryBackoffDelay: time.Millisecond},
+		wantAttempts: retryNumTimes + 1,
+	}, {
+		name:         "no retries",
+		config:       Config{DeliveryRetry: &zero},
+		wantAttempts: 1,
+	}, {
+		name:         "linear",
+		config:       Config{DeliveryRetry: &two, DeliveryBackoffPolicy: "linear", DeliveryBackoffDelay: time.Millisecond},
+		wantAttempts: 3,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+			a := &Adapter{config: &test.config}
+			result := client.Send(a.withDeliveryRetries(context.Background()), event)
+			require.False(t, cloudevents.IsACK(result))
+			require.Equal(t, test.wantAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestTimeoutRoundTripper(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	client := &http.Client{Transport: &timeoutRoundTripper{RoundTripper: http.DefaultTransport, timeout: 50 * time.Millisecond}}
+
+	resp, err := client.Get(sink.URL + "/fast")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	_, err = client.Get(sink.URL + "/slow")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// drainMargin is kept from the termination grace period of the pod to
+// acknowledge the delivered entries and checkpoint them once the deliveries
+// are done, before the pod is killed.
+const drainMargin = 2 * time.Second
+
+// drainer lets the deliveries outlive the shutdown of the adapter: the events
+// being delivered when it starts shutting down, and those of the pending
+// entries delivered while draining, are sent until the end of the drain
+// window instead of being cancelled.
+type drainer struct {
+	ctx      context.Context // done at the end of the drain window
+	mu       sync.Mutex
+	deadline time.Time // zero until the adapter starts shutting down
+}
+
+// newDrainer returns a drainer whose drain window starts once ctx is done, and
+// lasts window.
+func (a *Adapter) newDrainer(ctx context.Context, window time.Duration) *drainer {
+	drainCtx, stop := context.WithCancel(context.Background())
+	d := &drainer{ctx: drainCtx}
+	go func() {
+		<-ctx.Done()
+		a.logger.Info("Draining: stopped reading new entries, completing the in-flight deliveries", zap.Duration("window", window))
+		d.mu.Lock()
+		d.deadline = time.Now().Add(window)
+		d.mu.Unlock()
+		time.AfterFunc(window, stop)
+	}()
+	return d
+}
+
+// remaining returns how long is left of the drain window, and whether the
+// adapter is draining.
+func (d *drainer) remaining(now time.Time) (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.deadline.IsZero() {
+		return 0, false
+	}
+	return d.deadline.Sub(now), true
+}
+
+// drainWindow returns how long the deliveries may outlive the shutdown of the
+// adapter: the termination grace period of the pod, less the time needed to
+// acknowledge and checkpoint the entries delivered.
+func (a *Adapter) drainWindow() time.Duration {
+	if window := a.config.TerminationGracePeriod - drainMargin; window > 0 {
+		return window
+	}
+	return 0
+}
+
+// sendContext returns a context to send the event of an entry with: ctx, which
+// is cancelled when the adapter shuts down, but done at the end of the drain
+// window instead.
+func (a *Adapter) sendContext(ctx context.Context) context.Context {
+	if a.drains == nil {
+		return ctx
+	}
+	return drainingContext{Context: ctx, drain: a.drains.ctx}
+}
+
+// mayDeliver returns false once what is left of the drain window is too short
+// to deliver an entry within the delivery timeout: the entries not delivered
+// yet are left pending for the next start, instead of being cancelled half-way.
+func (a *Adapter) mayDeliver(now time.Time) bool {
+	if a.drains == nil {
+		return true
+	}
+	remaining, draining := a.drains.remaining(now)
+	if !draining {
+		return true
+	}
+	return remaining > 0 && remaining >= a.config.DeliveryTimeout
+}
+
+// drainingContext has the values of the context it wraps, but is only done
+// once drain is.
+type drainingContext struct {
+	context.Context
+	drain context.Context
+}
+
+func (c drainingContext) Deadline() (time.Time, bool) { return c.drain.Deadline() }
+func (c drainingContext) Done() <-chan struct{}       { return c.drain.Done() }
+func (c drainingContext) Err() error                  { return c.drain.Err() }
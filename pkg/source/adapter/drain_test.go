@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// ctxClient fails to send the events once the context they are sent with is
+// done, as the HTTP client does.
+type ctxClient struct {
+	fakeClient
+}
+
+func (c *ctxClient) Send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	c.sent++
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.events = append(c.events, event)
+	return protocol.ResultACK
+}
+
+func TestAdapter_Drain(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   time.Duration
+		wantAcks []string
+	}{{
+		name:     "within the drain window",
+		window:   time.Minute,
+		wantAcks: []string{"1-0"},
+	}, {
+		name:   "drain window elapsed",
+		window: 0,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, shutDown := context.WithCancel(context.Background())
+			a := &Adapter{logger: zap.NewNop(), client: &ctxClient{}, config: &Config{}}
+			a.drains = a.newDrainer(ctx, test.window)
+			shutDown()
+			require.Eventually(t, func() bool {
+				_, draining := a.drains.remaining(time.Now())
+				return draining
+			}, time.Second, time.Millisecond)
+			if test.window == 0 {
+				<-a.drains.ctx.Done()
+			}
+
+			// The pending entry is delivered while draining, though ctx is done,
+			// by the adapters reading each of several streams too.
+			for _, s := range []*Adapter{a, a.forStream(ctx, "payments")} {
+				conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+				s.processEntry(ctx, conn, "mystream", "mygroup", "consumer", "0", testRetryState(), true)
+				require.Equal(t, test.wantAcks, conn.acks)
+				require.Equal(t, test.window > 0, s.mayDeliver(time.Now()))
+			}
+		})
+	}
+}
+
+func TestAdapter_MayDeliver(t *testing.T) {
+	now := time.Now()
+	a := &Adapter{config: &Config{DeliveryTimeout: 5 * time.Second}}
+	require.True(t, a.mayDeliver(now), "deliveries are not bounded before starting")
+
+	a.drains = &drainer{}
+	require.True(t, a.mayDeliver(now), "deliveries are not bounded before draining")
+
+	a.drains.deadline = now.Add(10 * time.Second)
+	require.True(t, a.mayDeliver(now))
+	require.False(t, a.mayDeliver(now.Add(6*time.Second)), "a delivery could not complete before the end of the drain window")
+
+	a.config.DeliveryTimeout = 0
+	require.True(t, a.mayDeliver(now.Add(6*time.Second)))
+	require.False(t, a.mayDeliver(now.Add(10*time.Second)))
+}
+
+func TestAdapter_DrainWindow(t *testing.T) {
+	a := &Adapter{config: &Config{TerminationGracePeriod: 30 * time.Second}}
+	require.Equal(t, 28*time.Second, a.drainWindow())
+
+	a.config.TerminationGracePeriod = time.Second
+	require.Zero(t, a.drainWindow())
+}
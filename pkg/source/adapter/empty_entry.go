@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// Reasons recorded with the entries in the dead-letter stream.
+const (
+	deadLetterEmptyEntry          = "EmptyEntry"
+	deadLetterMaxDeliveryAttempts = "MaxDeliveryAttempts"
+)
+
+// deadLetter adds a record of the entry to the dead-letter stream: the fields
+// of the entry, if any, followed by its ID, the number of times it was
+// delivered, where it was read from and why it is dead-lettered.
+func (a *Adapter) deadLetter(conn redis.Conn, streamName, groupName, id, reason string, deliveries int, fields []string) error {
+	args := make([]interface{}, 0, 2+len(fields)+10)
+	args = append(args, a.deadLetterStream(streamName), "*")
+	for _, f := range fields {
+		args = append(args, f)
+	}
+	args = append(args,
+		"knative-original-id", id,
+		"knative-retry-count", deliveries,
+		"knative-stream", streamName,
+		"knative-group", groupName,
+		"knative-reason", reason)
+	_, err := conn.Do("XADD", args...)
+	return err
+}
+
+// deadLetterStream returns the key of the stream the entries of the stream
+// are dead-lettered to.
+func (a *Adapter) deadLetterStream(streamName string) string {
+	if a.config.DeadLetterStream != "" {
+		return a.config.DeadLetterStream
+	}
+	return sourcesv1alpha1.DeadLetterKey(streamName)
+}
+
+// entryFields returns the fields and values of the entry, or none when it was
+// deleted.
+func entryFields(conn redis.Conn, streamName, id string) ([]string, error) {
+	entries, err := redis.Values(conn.Do("XRANGE", streamName, id, id))
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	// The entry is [id, [field, value, ...]].
+	entry, err := redis.Values(entries[0], nil)
+	if err != nil || len(entry) != 2 {
+		return nil, errors.New("unexpected XRANGE reply")
+	}
+	if entry[1] == nil {
+		return nil, nil
+	}
+	return redis.Strings(entry[1], nil)
+}
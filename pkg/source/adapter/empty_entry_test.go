@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// emptyEntryReply is the reply to XREADGROUP for a pending entry deleted
+// since it was read.
+func emptyEntryReply(id string) fakeReply {
+	return fakeReply{reply: []interface{}{
+		[]interface{}{[]byte("mystream"), []interface{}{
+			[]interface{}{[]byte(id), nil},
+		}},
+	}}
+}
+
+func TestProcessEntry_EmptyEntry(t *testing.T) {
+	tests := map[string]struct {
+		policy         sourcesv1alpha1.EmptyEntryPolicy
+		wantSent       int
+		wantDeadLetter []string
+	}{
+		"default": {},
+		"skip": {
+			policy: sourcesv1alpha1.EmptyEntrySkip,
+		},
+		"emit": {
+			policy:   sourcesv1alpha1.EmptyEntryEmit,
+			wantSent: 1,
+		},
+		"dead letter": {
+			policy:         sourcesv1alpha1.EmptyEntryDeadLetter,
+			wantDeadLetter: []string{"redisdeadletter:mystream"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{emptyEntryReply("1-0")}}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{OnEmptyEntry: string(test.policy)}}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), false)
+
+			require.Equal(t, "0", xreadID)
+			require.Equal(t, test.wantSent, client.sent)
+			require.Equal(t, test.wantDeadLetter, conn.added)
+			require.Equal(t, []string{"1-0"}, conn.acks)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"fmt"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// eventSource returns the source of the events built from the entries of the
+// stream: the configured source, if any, with its variables replaced, or the
+// address of Redis followed by the stream.
+func (c *Config) eventSource(stream string) string {
+	if c.EventSource != "" {
+		return sourcesv1alpha1.ExpandEventSource(c.EventSource, stream, c.Namespace, c.SourceName)
+	}
+	return fmt.Sprintf("%s/%s", c.endpoint(), stream)
+}
+
+// eventType returns the type of the event built from the entry: the value of
+// its type field, if it has one, or else the configured type, or
+// RedisStreamSourceEventType. It also returns whether a type field is
+// configured and the entry does not have it.
+func (a *Adapter) eventType(fieldValues []string) (string, bool) {
+	fallback := false
+	if name := a.config.TypeFieldName; name != "" {
+		for i := 0; i+1 < len(fieldValues); i += 2 {
+			if fieldValues[i] == name && fieldValues[i+1] != "" {
+				return fieldValues[i+1], false
+			}
+		}
+		fallback = true
+	}
+	if a.config.EventType != "" {
+		return a.config.EventType, fallback
+	}
+	return RedisStreamSourceEventType, fallback
+}
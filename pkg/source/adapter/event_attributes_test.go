@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+func TestAdapter_EventAttributes(t *testing.T) {
+	entry := func(fieldValues ...string) []interface{} {
+		values := make([]interface{}, len(fieldValues))
+		for i, v := range fieldValues {
+			values[i] = []byte(v)
+		}
+		return []interface{}{
+			[]interface{}{[]byte("mystream"), []interface{}{
+				[]interface{}{[]byte("1-0"), values},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		config     Config
+		entry      []interface{}
+		wantType   string
+		wantSource string
+		wantData   string // the type field stays in the data
+	}{{
+		name:       "defaults",
+		config:     Config{Address: "redis://redis:6379", Stream: "mystream"},
+		entry:      entry("kind", "order.created"),
+		wantType:   RedisStreamSourceEventType,
+		wantSource: "redis://redis:6379/mystream",
+	}, {
+		name:       "static type and source",
+		config:     Config{Address: "redis://redis:6379", Stream: "mystream", EventType: "com.example.order", EventSource: "/orders"},
+		entry:      entry("kind", "order.created"),
+		wantType:   "com.example.order",
+		wantSource: "/orders",
+	}, {
+		name: "source with variables",
+		config: Config{
+			EnvConfig:   adapter.EnvConfig{Namespace: "shop"},
+			Address:     "redis://redis:6379",
+			Stream:      "orders:eu",
+			SourceName:  "orders",
+			EventSource: "/{namespace}/{name}/{streamKey}",
+		},
+		entry:      entry("kind", "order.created"),
+		wantType:   RedisStreamSourceEventType,
+		wantSource: "/shop/orders/orders:eu",
+	}, {
+		name:       "type from a field",
+		config:     Config{Address: "redis://redis:6379", Stream: "mystream", EventType: "com.example.order", TypeFieldName: "kind"},
+		entry:      entry("id", "42", "kind", "order.created"),
+		wantType:   "order.created",
+		wantSource: "redis://redis:6379/mystream",
+		wantData:   `["id","42","kind","order.created"]`,
+	}, {
+		name:       "type field missing",
+		config:     Config{Address: "redis://redis:6379", Stream: "mystream", EventType: "com.example.order", TypeFieldName: "kind"},
+		entry:      entry("id", "42"),
+		wantType:   "com.example.order",
+		wantSource: "redis://redis:6379/mystream",
+	}, {
+		name:       "type field empty",
+		config:     Config{Address: "redis://redis:6379", Stream: "mystream", TypeFieldName: "kind"},
+		entry:      entry("kind", ""),
+		wantType:   RedisStreamSourceEventType,
+		wantSource: "redis://redis:6379/mystream",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			a := &Adapter{config: &config, source: config.eventSource(config.Stream)}
+			event, err := a.toEvent(tt.entry)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, event.Type())
+			require.Equal(t, tt.wantSource, event.Source())
+			if tt.wantData != "" {
+				require.JSONEq(t, tt.wantData, string(event.Data()))
+			}
+		})
+	}
+}
+
+func TestAdapter_EventTypeFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		fieldValues  []string
+		wantFallback bool
+	}{{
+		name:        "no type field",
+		fieldValues: []string{"kind", "order.created"},
+	}, {
+		name:        "type field",
+		config:      Config{TypeFieldName: "kind"},
+		fieldValues: []string{"kind", "order.created"},
+	}, {
+		name:         "type field missing",
+		config:       Config{TypeFieldName: "kind"},
+		fieldValues:  []string{"id", "42"},
+		wantFallback: true,
+	}, {
+		name:         "type field empty",
+		config:       Config{TypeFieldName: "kind", EventType: "com.example.order"},
+		fieldValues:  []string{"kind", ""},
+		wantFallback: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			a := &Adapter{config: &config}
+			_, fallback := a.eventType(tt.fieldValues)
+			require.Equal(t, tt.wantFallback, fallback)
+		})
+	}
+}
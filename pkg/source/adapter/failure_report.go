@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+)
+
+const (
+	// FailureReportEventType is the CloudEvent type of the failure reports.
+	FailureReportEventType = "dev.knative.sources.redisstream.failurereport"
+
+	failureReportTimeout = 5 * time.Second
+
+	// maxFailureReportReasons bounds the sample of distinct failure reasons
+	// included in a report.
+	maxFailureReportReasons = 5
+)
+
+// failureReport is the data of the event summarizing the events that could
+// not be delivered to the sink within a window.
+type failureReport struct {
+	Stream        string    `json:"stream"`
+	Group         string    `json:"group"`
+	WindowStart   time.Time `json:"windowStart"`
+	Window        string    `json:"window"`
+	Threshold     int       `json:"threshold"`
+	Failures      int       `json:"failures"`
+	SampleReasons []string  `json:"sampleReasons"`
+}
+
+// failureReporter counts the events that could not be delivered to the sink
+// over fixed windows, and reports once per window when the count reaches the
+// threshold.
+type failureReporter struct {
+	client    cloudevents.Client
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	start    time.Time
+	failures int
+	reasons  []string
+	reported bool
+}
+
+// record counts a failure, returning the report to send when the threshold is
+// reached for the first time in the current window.
+func (r *failureReporter) record(reason string, now time.Time) *failureReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Sub(r.start) >= r.window {
+		r.start = now
+		r.failures = 0
+		r.reasons = nil
+		r.reported = false
+	}
+
+	r.failures++
+	if len(r.reasons) < maxFailureReportReasons && !contains(r.reasons, reason) {
+		r.reasons = append(r.reasons, reason)
+	}
+	if r.reported || r.failures < r.threshold {
+		return nil
+	}
+
+	r.reported = true
+	return &failureReport{
+		WindowStart:   r.start,
+		Window:        r.window.String(),
+		Threshold:     r.threshold,
+		Failures:      r.failures,
+		SampleReasons: append([]string(nil), r.reasons...),
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// useFailureReportSink creates the failure reporter sending reports to the
+// configured failure report sink through the given transport.
+func (a *Adapter) useFailureReportSink(transport http.RoundTripper) error {
+	if a.config.FailureReportSink == "" {
+		return nil
+	}
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(a.config.FailureReportSink), cehttp.WithRoundTripper(transport))
+	if err != nil {
+		return err
+	}
+	a.failures = &failureReporter{
+		client:    client,
+		threshold: a.config.FailureReportThreshold,
+		window:    a.config.FailureReportWindow,
+	}
+	return nil
+}
+
+// reportFailure counts an event that could not be delivered to the sink, and
+// sends a failure report in the background when too many events failed.
+func (a *Adapter) reportFailure(ctx context.Context, groupName string, result protocol.Result) {
+	if a.failures == nil {
+		return
+	}
+	report := a.failures.record(result.Error(), time.Now())
+	if report == nil {
+		return
+	}
+	report.Stream = a.config.Stream
+	report.Group = groupName
+
+	event := cloudevents.NewEvent()
+	event.SetID(fmt.Sprintf("%s-%s-%d", report.Stream, groupName, report.WindowStart.UnixMilli()))
+	event.SetType(FailureReportEventType)
+	event.SetSource(a.source)
+	event.SetTime(time.Now())
+	if err := event.SetData(cloudevents.ApplicationJSON, report); err != nil {
+		a.logger.Error("Cannot create failure report", zap.Error(err))
+		return
+	}
+
+	a.logger.Warn("Too many events could not be delivered, sending failure report",
+		zap.Int("failures", report.Failures), zap.String("window", report.Window))
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), failureReportTimeout)
+		defer cancel()
+		if result := a.failures.client.Send(ctx, event); !cloudevents.IsACK(result) {
+			a.logger.Error("Failed to send failure report", zap.Any("result", result))
+		}
+	}()
+}
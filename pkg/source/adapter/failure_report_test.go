@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFailureReporter(t *testing.T) {
+	start := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	r := &failureReporter{threshold: 3, window: time.Minute}
+
+	require.Nil(t, r.record("500: schema mismatch", start))
+	require.Nil(t, r.record("500: schema mismatch", start.Add(10*time.Second)))
+
+	report := r.record("400: missing field", start.Add(20*time.Second))
+	require.Equal(t, &failureReport{
+		WindowStart:   start,
+		Window:        "1m0s",
+		Threshold:     3,
+		Failures:      3,
+		SampleReasons: []string{"500: schema mismatch", "400: missing field"},
+	}, report)
+
+	// At most one report per window.
+	require.Nil(t, r.record("500: schema mismatch", start.Add(30*time.Second)))
+
+	// Failures are counted again in the next window.
+	next := start.Add(time.Minute)
+	require.Nil(t, r.record("500: schema mismatch", next))
+	require.Nil(t, r.record("500: schema mismatch", next.Add(time.Second)))
+	require.NotNil(t, r.record("500: schema mismatch", next.Add(2*time.Second)))
+}
+
+func TestFailureReporterSampleReasons(t *testing.T) {
+	r := &failureReporter{threshold: 10, window: time.Minute}
+	now := time.Now()
+
+	var report *failureReport
+	for i := 0; i < 10; i++ {
+		report = r.record(fmt.Sprintf("reason %d", i), now)
+	}
+	require.Len(t, report.SampleReasons, maxFailureReportReasons)
+}
+
+func TestProcessEntry_FailureReport(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("2-0"), entryReply("3-0"), entryReply("4-0")}}
+	client := &fakeClient{results: []protocol.Result{
+		errors.New("sink unavailable"),
+		protocol.ResultACK,
+		errors.New("sink unavailable"),
+		errors.New("sink unavailable"),
+	}}
+	reportClient := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{
+		logger:   zap.NewNop(),
+		client:   client,
+		source:   "test",
+		config:   &Config{Stream: "mystream"},
+		failures: &failureReporter{client: reportClient, threshold: 2, window: time.Hour},
+	}
+
+	for i := 0; i < 4; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+		a.background.Wait()
+	}
+
+	// A single report once the threshold is reached. Failed entries are left
+	// pending.
+	require.Equal(t, 1, reportClient.sent)
+	require.Equal(t, FailureReportEventType, reportClient.events[0].Type())
+	var report failureReport
+	require.NoError(t, reportClient.events[0].DataAs(&report))
+	require.Equal(t, "mystream", report.Stream)
+	require.Equal(t, "mygroup", report.Group)
+	require.Equal(t, 2, report.Failures)
+	require.Equal(t, []string{"sink unavailable"}, report.SampleReasons)
+	require.Equal(t, []string{"2-0"}, conn.acks)
+}
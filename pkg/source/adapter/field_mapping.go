@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// loadFieldMapping reads the extension attributes the fields of the entries
+// are mapped to from the FIELD_MAPPING_<i>_FIELD and
+// FIELD_MAPPING_<i>_EXTENSION environment variables.
+func loadFieldMapping() map[string]string {
+	var mapping map[string]string
+	for i := 0; ; i++ {
+		field, ok := os.LookupEnv(fmt.Sprintf("FIELD_MAPPING_%d_FIELD", i))
+		if !ok {
+			return mapping
+		}
+		if mapping == nil {
+			mapping = make(map[string]string)
+		}
+		mapping[field] = os.Getenv(fmt.Sprintf("FIELD_MAPPING_%d_EXTENSION", i))
+	}
+}
+
+// loadRequiredFields reads the mapped fields the entries must have from the
+// FIELD_MAPPING_<i>_REQUIRED environment variables.
+func loadRequiredFields() []string {
+	var required []string
+	for i := 0; ; i++ {
+		field, ok := os.LookupEnv(fmt.Sprintf("FIELD_MAPPING_%d_FIELD", i))
+		if !ok {
+			return required
+		}
+		if os.Getenv(fmt.Sprintf("FIELD_MAPPING_%d_REQUIRED", i)) == "true" {
+			required = append(required, field)
+		}
+	}
+}
+
+// missingField returns the first required field the entry does not have, if
+// any.
+func (a *Adapter) missingField(fieldValues []string) (string, bool) {
+	for _, field := range a.requiredFields {
+		found := false
+		for i := 0; i+1 < len(fieldValues); i += 2 {
+			if fieldValues[i] == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// dropMissingField acknowledges the entry of an event without a required
+// field instead of delivering it, after sending the event to the dead-letter
+// sink per OnMissingField. It returns false when the dead-letter sink did not
+// accept the event, leaving the entry pending.
+func (a *Adapter) dropMissingField(ctx context.Context, conn redis.Conn, streamName, groupName, consumerName string, event *cloudevents.Event, field, xreadID string, retries *retryState, isShuttingDown bool) (string, bool) {
+	if sourcesv1alpha1.MissingFieldPolicy(a.config.OnMissingField) == sourcesv1alpha1.MissingFieldDeadLetter {
+		if !a.sendToDeadLetterSink(ctx, event, fmt.Errorf("missing required field %q", field)) {
+			a.logger.Error("Cannot dead-letter message without a required field, leaving it pending", zap.String("id", event.ID()), zap.String("field", field))
+			return xreadID, false
+		}
+		a.logger.Info("Dead-lettered message without a required field", zap.String("consumerName", consumerName), zap.String("id", event.ID()), zap.String("field", field))
+	} else {
+		a.logger.Info("Dropping message without a required field", zap.String("consumerName", consumerName), zap.String("id", event.ID()), zap.String("field", field))
+	}
+	return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown), true
+}
+
+// mapFields sets the extension attributes of the event from the mapped fields
+// of the entry, and returns the other fields, which make the data of the
+// event.
+func (a *Adapter) mapFields(event *cloudevents.Event, fieldValues []string) []string {
+	if len(a.fieldMapping) == 0 {
+		return fieldValues
+	}
+	rest := make([]string, 0, len(fieldValues))
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		if extension, ok := a.fieldMapping[fieldValues[i]]; ok {
+			event.SetExtension(extension, fieldValues[i+1])
+			continue
+		}
+		rest = append(rest, fieldValues[i], fieldValues[i+1])
+	}
+	return rest
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestAdapter_FieldMapping(t *testing.T) {
+	t.Setenv("FIELD_MAPPING_0_FIELD", "tenant")
+	t.Setenv("FIELD_MAPPING_0_EXTENSION", "tenantid")
+	t.Setenv("FIELD_MAPPING_1_FIELD", "region")
+	t.Setenv("FIELD_MAPPING_1_EXTENSION", "region")
+	mapping := loadFieldMapping()
+	require.Equal(t, map[string]string{"tenant": "tenantid", "region": "region"}, mapping)
+
+	reply := []interface{}{
+		[]interface{}{[]byte("mystream"), []interface{}{
+			[]interface{}{[]byte("1-0"), []interface{}{
+				[]byte("tenant"), []byte("acme"),
+				[]byte("order"), []byte("42"),
+			}},
+		}},
+	}
+
+	a := &Adapter{config: &Config{}, fieldMapping: mapping}
+	event, err := a.toEvent(reply)
+	require.NoError(t, err)
+	require.Equal(t, "acme", event.Extensions()["tenantid"])
+	require.NotContains(t, event.Extensions(), "region")
+	require.JSONEq(t, `["order","42"]`, string(event.Data()))
+
+	// Without mapping, every field is in the data.
+	a = &Adapter{config: &Config{}}
+	event, err = a.toEvent(reply)
+	require.NoError(t, err)
+	require.NotContains(t, event.Extensions(), "tenantid")
+	require.JSONEq(t, `["tenant","acme","order","42"]`, string(event.Data()))
+}
+
+func TestAdapter_RequiredFields(t *testing.T) {
+	t.Setenv("FIELD_MAPPING_0_FIELD", "region")
+	t.Setenv("FIELD_MAPPING_0_EXTENSION", "region")
+	t.Setenv("FIELD_MAPPING_1_FIELD", "tenant")
+	t.Setenv("FIELD_MAPPING_1_EXTENSION", "tenantid")
+	t.Setenv("FIELD_MAPPING_1_REQUIRED", "true")
+	require.Equal(t, []string{"tenant"}, loadRequiredFields())
+
+	a := &Adapter{requiredFields: []string{"tenant"}}
+	field, missing := a.missingField([]string{"region", "eu", "order", "42"})
+	require.True(t, missing)
+	require.Equal(t, "tenant", field)
+	_, missing = a.missingField([]string{"tenant", "acme"})
+	require.False(t, missing)
+}
+
+func TestProcessEntry_MissingField(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      sourcesv1alpha1.MissingFieldPolicy
+		deadLetters *fakeClient
+		wantAcks    []string
+	}{{
+		name:     "drop",
+		wantAcks: []string{"1-0"},
+	}, {
+		name:        "dead-letter",
+		policy:      sourcesv1alpha1.MissingFieldDeadLetter,
+		deadLetters: &fakeClient{results: []protocol.Result{protocol.ResultACK}},
+		wantAcks:    []string{"1-0"},
+	}, {
+		name:        "dead-letter sink failure",
+		policy:      sourcesv1alpha1.MissingFieldDeadLetter,
+		deadLetters: &fakeClient{results: []protocol.Result{errors.New("dead-letter sink unavailable")}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The entry only has the field "field".
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			client := &fakeClient{}
+			a := &Adapter{
+				logger:         zap.NewNop(),
+				client:         client,
+				requiredFields: []string{"tenant"},
+				config:         &Config{OnMissingField: string(test.policy)},
+			}
+			if test.deadLetters != nil {
+				a.deadLetters = test.deadLetters
+			}
+
+			a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+
+			require.Zero(t, client.sent, "the event was delivered to the sink")
+			require.Equal(t, test.wantAcks, conn.acks)
+			if test.deadLetters != nil {
+				require.Equal(t, 1, test.deadLetters.sent)
+			}
+		})
+	}
+}
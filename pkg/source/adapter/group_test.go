@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// groupConn replies to XINFO GROUPS with the given reply, and records the
+// XGROUP commands.
+type groupConn struct {
+	redis.Conn
+	xinfo    interface{}
+	xinfoErr error
+	xgroup   [][]interface{}
+}
+
+func (c *groupConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	switch cmd {
+	case "XINFO":
+		return c.xinfo, c.xinfoErr
+	case "XGROUP":
+		c.xgroup = append(c.xgroup, args)
+	}
+	return "OK", nil
+}
+
+func groupReply(name string) []interface{} {
+	return []interface{}{
+		[]byte("name"), []byte(name),
+		[]byte("consumers"), int64(1),
+		[]byte("pending"), int64(0),
+		[]byte("last-delivered-id"), []byte("0-0"),
+	}
+}
+
+func TestEnsureGroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		startID    string
+		resumeIDs  sourcesv1alpha1.Cursors
+		xinfo      interface{}
+		xinfoErr   error
+		wantXGroup [][]interface{}
+	}{{
+		name:       "no stream",
+		startID:    "$",
+		xinfoErr:   redis.Error("ERR no such key"),
+		wantXGroup: [][]interface{}{{"CREATE", "mystream", "mygroup", "$", "MKSTREAM"}},
+	}, {
+		name:       "no stream with start ID",
+		startID:    "0",
+		xinfoErr:   redis.Error("ERR no such key"),
+		wantXGroup: [][]interface{}{{"CREATE", "mystream", "mygroup", "0", "MKSTREAM"}},
+	}, {
+		name:       "no group with start ID",
+		startID:    "1526919030474-55",
+		xinfo:      []interface{}{groupReply("othergroup")},
+		wantXGroup: [][]interface{}{{"CREATE", "mystream", "mygroup", "1526919030474-55"}},
+	}, {
+		name:       "no group with checkpointed cursor",
+		startID:    "$",
+		resumeIDs:  sourcesv1alpha1.Cursors{"mystream": {"mygroup": "1526919030474-56", "othergroup": "1526919030474-99"}},
+		xinfoErr:   redis.Error("ERR no such key"),
+		wantXGroup: [][]interface{}{{"CREATE", "mystream", "mygroup", "1526919030474-56", "MKSTREAM"}},
+	}, {
+		name:    "existing group keeps its position",
+		startID: "0",
+		xinfo:   []interface{}{groupReply("mygroup")},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &groupConn{xinfo: test.xinfo, xinfoErr: test.xinfoErr}
+			a := &Adapter{logger: zap.NewNop(), config: &Config{StartID: test.startID}, resumeIDs: test.resumeIDs}
+
+			require.NoError(t, a.ensureGroup(conn, "mystream", "mygroup"))
+			require.Equal(t, test.wantXGroup, conn.xgroup)
+		})
+	}
+}
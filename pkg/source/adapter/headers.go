@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// loadSinkHeaders reads the headers to add to sink requests from the
+// SINK_HEADER_<i>_NAME and SINK_HEADER_<i>_VALUE environment variables.
+// CloudEvents headers are ignored.
+func loadSinkHeaders() http.Header {
+	headers := make(http.Header)
+	for i := 0; ; i++ {
+		name, ok := os.LookupEnv(fmt.Sprintf("SINK_HEADER_%d_NAME", i))
+		if !ok {
+			break
+		}
+		if sourcesv1alpha1.IsReservedSinkHeader(name) {
+			continue
+		}
+		headers.Set(name, os.Getenv(fmt.Sprintf("SINK_HEADER_%d_VALUE", i)))
+	}
+	return headers
+}
+
+// withSinkHeaders returns a context adding the sink headers to the request
+// sending the event. With conditional requests, the If-None-Match header holds
+// the event ID as an entity tag, so that the sink recognizes redelivered events.
+func (a *Adapter) withSinkHeaders(ctx context.Context, event *cloudevents.Event) context.Context {
+	if len(a.sinkHeaders) == 0 && !a.config.ConditionalRequests {
+		return ctx
+	}
+	// The CloudEvents client writes the event headers into the given ones.
+	headers := a.sinkHeaders.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	if a.config.ConditionalRequests {
+		headers.Set("If-None-Match", strconv.Quote(event.ID()))
+	}
+	return cehttp.WithCustomHeader(ctx, headers)
+}
+
+// alreadyDelivered returns true when the sink, receiving a conditional
+// request, responded that it already has the event.
+func (a *Adapter) alreadyDelivered(result protocol.Result) bool {
+	if !a.config.ConditionalRequests {
+		return false
+	}
+	statusCode, ok := sinkStatusCode(result)
+	return ok && (statusCode == http.StatusNotModified || statusCode == http.StatusPreconditionFailed)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSinkHeaders(t *testing.T) {
+	t.Setenv("SINK_HEADER_0_NAME", "X-Api-Key")
+	t.Setenv("SINK_HEADER_0_VALUE", "secret")
+	t.Setenv("SINK_HEADER_1_NAME", "Ce-Id")
+	t.Setenv("SINK_HEADER_1_VALUE", "overridden")
+	t.Setenv("SINK_HEADER_2_NAME", "Content-Type")
+	t.Setenv("SINK_HEADER_2_VALUE", "text/plain")
+	t.Setenv("SINK_HEADER_3_NAME", "X-Tenant")
+	t.Setenv("SINK_HEADER_3_VALUE", "acme")
+
+	received := make(chan http.Header, 2)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL))
+	require.NoError(t, err)
+
+	a := &Adapter{client: client, config: &Config{}, sinkHeaders: loadSinkHeaders()}
+	require.Len(t, a.sinkHeaders, 2)
+
+	for _, id := range []string{"1-0", "2-0"} {
+		event := cloudevents.NewEvent()
+		event.SetID(id)
+		event.SetType(RedisStreamSourceEventType)
+		event.SetSource("test")
+		require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []string{"field", "value"}))
+		require.True(t, cloudevents.IsACK(a.client.Send(a.withSinkHeaders(context.Background(), &event), event)))
+
+		got := <-received
+		require.Equal(t, "secret", got.Get("X-Api-Key"))
+		require.Equal(t, "acme", got.Get("X-Tenant"))
+		require.Equal(t, []string{id}, got.Values("Ce-Id"))
+		require.Equal(t, []string{cloudevents.ApplicationJSON}, got.Values("Content-Type"))
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	for _, statusCode := range []int{http.StatusNotModified, http.StatusPreconditionFailed} {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			var conditions []string
+			seen := map[string]bool{}
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				etag := r.Header.Get("If-None-Match")
+				conditions = append(conditions, etag)
+				if seen[etag] {
+					w.WriteHeader(statusCode)
+					return
+				}
+				seen[etag] = true
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL))
+			require.NoError(t, err)
+
+			// 1-0 is redelivered, e.g. after a restart before it was acknowledged.
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("1-0"), entryReply("2-0")}}
+			a := &Adapter{logger: zap.NewNop(), client: client, source: "test", config: &Config{ConditionalRequests: true}}
+			retries := testRetryState()
+			for i := 0; i < 3; i++ {
+				a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", retries, false)
+			}
+
+			require.Equal(t, []string{`"1-0"`, `"1-0"`, `"2-0"`}, conditions)
+			require.Equal(t, []string{"1-0", "1-0", "2-0"}, conn.acks)
+			require.Zero(t, retries.sink.Failures())
+		})
+	}
+}
+
+func TestConditionalRequestsDisabled(t *testing.T) {
+	var conditions []string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditions = append(conditions, r.Header.Get("If-None-Match"))
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer sink.Close()
+
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL))
+	require.NoError(t, err)
+
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	a := &Adapter{logger: zap.NewNop(), client: client, source: "test", config: &Config{}}
+	retries := testRetryState()
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", retries, false)
+
+	require.Equal(t, []string{""}, conditions)
+	require.Equal(t, 1, retries.sink.Failures())
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/client/clientset/versioned"
+	sourcesclient "knative.dev/eventing-redis/pkg/source/client/injection/client"
+)
+
+// connectionReporter publishes whether Redis answers PING, err being nil when
+// it does.
+type connectionReporter interface {
+	reportConnection(ctx context.Context, err error) error
+}
+
+// sourceAnnotator reports whether Redis answers in annotations of the source,
+// which the controller reflects in its RedisConnected condition.
+type sourceAnnotator struct {
+	client    versioned.Interface
+	namespace string
+	name      string
+}
+
+func newSourceAnnotator(ctx context.Context, namespace, name string) *sourceAnnotator {
+	return &sourceAnnotator{client: sourcesclient.Get(ctx), namespace: namespace, name: name}
+}
+
+func (s *sourceAnnotator) reportConnection(ctx context.Context, err error) error {
+	// A null value removes the error annotation from the source.
+	var message interface{}
+	if err != nil {
+		message = err.Error()
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				sourcesv1alpha1.RedisConnectedAnnotation: strconv.FormatBool(err == nil),
+				sourcesv1alpha1.RedisErrorAnnotation:     message,
+			},
+		},
+	})
+	_, err = s.client.SourcesV1alpha1().RedisStreamSources(s.namespace).Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// reportLeaseHolder sets the lease holder annotation of the source to holder,
+// removing it when holder is empty.
+func (s *sourceAnnotator) reportLeaseHolder(ctx context.Context, holder string) error {
+	var value interface{}
+	if holder != "" {
+		value = holder
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				sourcesv1alpha1.LeaseHolderAnnotation: value,
+			},
+		},
+	})
+	_, err := s.client.SourcesV1alpha1().RedisStreamSources(s.namespace).Patch(ctx, s.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// ping sends PING to Redis.
+func ping(pool *redis.Pool) error {
+	conn := pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PING")
+	return err
+}
+
+// connectionState is the state of the connection to Redis last reported, by
+// the health check or by a consumer reconnecting to Redis.
+type connectionState struct {
+	mu       sync.Mutex
+	reported bool
+	last     string
+}
+
+// checkConnection sends PING to Redis now and then every interval until ctx is
+// done, reporting whether it answers.
+func (a *Adapter) checkConnection(ctx context.Context, pool *redis.Pool, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := ping(pool)
+		if err != nil {
+			a.logger.Warn("Redis does not answer PING", zap.Error(err))
+		}
+		a.updateConnection(ctx, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateConnection reports whether Redis answers when that changed since the
+// last report, so that the source is not updated every health check or every
+// attempt to reconnect.
+func (a *Adapter) updateConnection(ctx context.Context, err error) {
+	state := ""
+	if err != nil {
+		state = err.Error()
+	}
+	a.connectionState.mu.Lock()
+	defer a.connectionState.mu.Unlock()
+	if a.connectionState.reported && state == a.connectionState.last {
+		return
+	}
+	a.connectionState.reported = a.reportConnection(ctx, err)
+	a.connectionState.last = state
+}
+
+// reportConnection reports whether Redis answers, and returns whether the
+// report was published.
+func (a *Adapter) reportConnection(ctx context.Context, err error) bool {
+	if a.connection == nil {
+		return true
+	}
+	if err := a.connection.reportConnection(ctx, err); err != nil {
+		a.logger.Error("Cannot report whether Redis answers PING", zap.Error(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/client/clientset/versioned/fake"
+)
+
+// pingConn answers PING, unless err is set.
+type pingConn struct {
+	redis.Conn
+	err error
+}
+
+func (c *pingConn) Close() error { return nil }
+func (c *pingConn) Err() error   { return nil }
+func (c *pingConn) Do(string, ...interface{}) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return "PONG", nil
+}
+
+// recordingReporter records the errors reported.
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []error
+}
+
+func (r *recordingReporter) reportConnection(_ context.Context, err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, err)
+	return nil
+}
+
+func (r *recordingReporter) get() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.reports...)
+}
+
+func TestAdapter_CheckConnection(t *testing.T) {
+	refused := errors.New("dial tcp 10.0.0.1:6379: connect: connection refused")
+	var dials int
+	var mu sync.Mutex
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dials++
+		if dials == 1 {
+			return &pingConn{err: refused}, nil
+		}
+		return &pingConn{}, nil
+	}}
+
+	reporter := &recordingReporter{}
+	a := &Adapter{config: &Config{}, logger: zap.NewNop(), connection: reporter}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.checkConnection(ctx, pool, 10*time.Millisecond)
+	}()
+
+	// Redis refuses the first PING then answers the following ones, which
+	// are only reported once.
+	require.Eventually(t, func() bool { return len(reporter.get()) == 2 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	reports := reporter.get()
+	require.Len(t, reports, 2)
+	require.Equal(t, refused, reports[0])
+	require.NoError(t, reports[1])
+}
+
+func TestSourceAnnotator(t *testing.T) {
+	client := fake.NewSimpleClientset(&sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", Annotations: map[string]string{"owner": "team"}},
+	})
+	s := &sourceAnnotator{client: client, namespace: "ns", name: "source"}
+	ctx := context.Background()
+
+	require.NoError(t, s.reportConnection(ctx, errors.New("NOAUTH Authentication required.")))
+	source, err := client.SourcesV1alpha1().RedisStreamSources("ns").Get(ctx, "source", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"owner":                                  "team",
+		sourcesv1alpha1.RedisConnectedAnnotation: "false",
+		sourcesv1alpha1.RedisErrorAnnotation:     "NOAUTH Authentication required.",
+	}, source.Annotations)
+
+	require.NoError(t, s.reportConnection(ctx, nil))
+	source, err = client.SourcesV1alpha1().RedisStreamSources("ns").Get(ctx, "source", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"owner":                                  "team",
+		sourcesv1alpha1.RedisConnectedAnnotation: "true",
+	}, source.Annotations)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapter_JSONOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{{
+		name: "default",
+		want: `["html","\u003cb\u003eA \u0026 B\u003c/b\u003e"]`,
+	}, {
+		name:   "HTML escape disabled",
+		config: Config{JSONDisableHTMLEscape: true},
+		want:   `["html","<b>A & B</b>"]`,
+	}, {
+		name:   "indent",
+		config: Config{JSONIndent: true},
+		want:   "[\n  \"html\",\n  \"\\u003cb\\u003eA \\u0026 B\\u003c/b\\u003e\"\n]",
+	}, {
+		name:   "indent and HTML escape disabled",
+		config: Config{JSONDisableHTMLEscape: true, JSONIndent: true},
+		want:   "[\n  \"html\",\n  \"<b>A & B</b>\"\n]",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := &Adapter{config: &test.config}
+			reply := []interface{}{
+				[]interface{}{[]byte("mystream"), []interface{}{
+					[]interface{}{[]byte("1-0"), []interface{}{[]byte("html"), []byte("<b>A & B</b>")}},
+				}},
+			}
+
+			event, err := a.toEvent(reply)
+			require.NoError(t, err)
+			require.Equal(t, test.want, string(event.Data()))
+		})
+	}
+}
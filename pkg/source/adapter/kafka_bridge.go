@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// kafkaBridgeContentType is the content type of the requests producing records
+// with binary keys and values through the HTTP API of the Strimzi Kafka Bridge.
+const kafkaBridgeContentType = "application/vnd.kafka.binary.v2+json"
+
+// kafkaRecords is the body of the requests producing records to a topic.
+// Byte slices are encoded in base64, as the binary embedded format expects.
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key     []byte        `json:"key,omitempty"`
+	Value   []byte        `json:"value"`
+	Headers []kafkaHeader `json:"headers,omitempty"`
+}
+
+type kafkaHeader struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// kafkaOffsets is the body of the responses to the requests producing records.
+type kafkaOffsets struct {
+	Offsets []struct {
+		ErrorCode int    `json:"error_code,omitempty"`
+		Error     string `json:"error,omitempty"`
+	} `json:"offsets"`
+}
+
+// toKafkaRecord formats the event as a Kafka record in the binary content mode
+// of the CloudEvents Kafka protocol binding: the attributes are ce_ prefixed
+// headers, the data is the value, and the partitionkey extension is the key.
+func toKafkaRecord(event cloudevents.Event) (kafkaRecord, error) {
+	record := kafkaRecord{Value: event.Data()}
+
+	header := func(key, value string) {
+		record.Headers = append(record.Headers, kafkaHeader{Key: key, Value: []byte(value)})
+	}
+	header("ce_specversion", event.SpecVersion())
+	header("ce_id", event.ID())
+	header("ce_source", event.Source())
+	header("ce_type", event.Type())
+	if event.Subject() != "" {
+		header("ce_subject", event.Subject())
+	}
+	if event.DataSchema() != "" {
+		header("ce_dataschema", event.DataSchema())
+	}
+	if !event.Time().IsZero() {
+		header("ce_time", event.Time().Format(time.RFC3339Nano))
+	}
+	if event.DataContentType() != "" {
+		header("content-type", event.DataContentType())
+	}
+
+	extensions := event.Extensions()
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := types.Format(extensions[name])
+		if err != nil {
+			return kafkaRecord{}, fmt.Errorf("cannot format extension %q: %w", name, err)
+		}
+		header("ce_"+name, value)
+		if name == partitionKeyExtension {
+			record.Key = []byte(value)
+		}
+	}
+	return record, nil
+}
+
+// kafkaBridgeRoundTripper turns the requests sending events to the sink into
+// requests producing them as records to a topic, through the HTTP API of a
+// Kafka bridge. The sink is the address of the bridge.
+type kafkaBridgeRoundTripper struct {
+	http.RoundTripper
+	topic string
+}
+
+func (k *kafkaBridgeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	event, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	record, err := toKafkaRecord(*event)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{record}})
+	if err != nil {
+		return nil, err
+	}
+
+	u := *req.URL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/topics/" + k.topic
+	u.RawPath = ""
+	produce, err := http.NewRequestWithContext(req.Context(), http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	// Keep the other headers, e.g. the sink headers and the tracing ones.
+	for name, values := range req.Header {
+		if !sourcesv1alpha1.IsReservedSinkHeader(name) {
+			produce.Header[name] = values
+		}
+	}
+	produce.Header.Set("Content-Type", kafkaBridgeContentType)
+
+	resp, err := k.RoundTripper.RoundTrip(produce)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	return producedResponse(resp)
+}
+
+// producedResponse returns the response to the request producing a single
+// record, with the status of the record when the bridge failed to produce it.
+func producedResponse(resp *http.Response) (*http.Response, error) {
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	var offsets kafkaOffsets
+	if err := json.Unmarshal(b, &offsets); err != nil {
+		return nil, fmt.Errorf("cannot parse the Kafka bridge response: %w", err)
+	}
+	for _, offset := range offsets.Offsets {
+		if offset.ErrorCode != 0 {
+			resp.StatusCode = offset.ErrorCode
+			if resp.StatusCode < 400 || resp.StatusCode > 599 {
+				resp.StatusCode = http.StatusInternalServerError
+			}
+			resp.Status = fmt.Sprintf("%d %s", resp.StatusCode, offset.Error)
+		}
+	}
+	return resp, nil
+}
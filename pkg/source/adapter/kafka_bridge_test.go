@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+)
+
+func kafkaHeaders(record kafkaRecord) map[string]string {
+	headers := make(map[string]string, len(record.Headers))
+	for _, h := range record.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return headers
+}
+
+func TestToKafkaRecord(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1519073278252-0")
+	event.SetSource("/mysource")
+	event.SetType(RedisStreamSourceEventType)
+	event.SetTime(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+	event.SetExtension(partitionKeyExtension, "customer-1")
+	event.SetExtension("redisseq", 42)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []byte(`["field","value"]`)))
+
+	record, err := toKafkaRecord(event)
+	require.NoError(t, err)
+
+	require.Equal(t, "customer-1", string(record.Key))
+	require.Equal(t, `["field","value"]`, string(record.Value))
+	require.Equal(t, map[string]string{
+		"ce_specversion":  "1.0",
+		"ce_id":           "1519073278252-0",
+		"ce_source":       "/mysource",
+		"ce_type":         RedisStreamSourceEventType,
+		"ce_time":         "2020-10-01T12:00:00Z",
+		"ce_partitionkey": "customer-1",
+		"ce_redisseq":     "42",
+		"content-type":    cloudevents.ApplicationJSON,
+	}, kafkaHeaders(record))
+}
+
+func TestToKafkaRecordWithoutData(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1519073278252-0")
+	event.SetSource("/mysource")
+	event.SetType(RedisStreamSourceEventType)
+
+	record, err := toKafkaRecord(event)
+	require.NoError(t, err)
+
+	require.Nil(t, record.Key)
+	require.Nil(t, record.Value)
+	require.NotContains(t, kafkaHeaders(record), "content-type")
+}
+
+func TestKafkaBridgeRoundTripper(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantACK    bool
+		wantStatus int
+	}{{
+		name:       "produced",
+		response:   `{"offsets":[{"partition":0,"offset":7}]}`,
+		wantACK:    true,
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "record not produced",
+		response:   `{"offsets":[{"error_code":404,"error":"topic not found"}]}`,
+		wantStatus: http.StatusNotFound,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got kafkaRecords
+			var gotPath, gotContentType, gotAuthorization string
+			bridge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotContentType = r.Header.Get("Content-Type")
+				gotAuthorization = r.Header.Get("Authorization")
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+				_, _ = w.Write([]byte(test.response))
+			}))
+			defer bridge.Close()
+
+			client, err := cloudevents.NewClientHTTP(
+				cloudevents.WithTarget(bridge.URL),
+				cehttp.WithRoundTripper(&kafkaBridgeRoundTripper{RoundTripper: http.DefaultTransport, topic: "events"}),
+			)
+			require.NoError(t, err)
+
+			event := cloudevents.NewEvent()
+			event.SetID("1519073278252-0")
+			event.SetSource("/mysource")
+			event.SetType(RedisStreamSourceEventType)
+			require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []byte(`["field","value"]`)))
+
+			ctx := cehttp.WithCustomHeader(context.Background(), http.Header{"Authorization": {"Bearer token"}})
+			result := client.Send(ctx, event)
+
+			require.Equal(t, test.wantACK, cloudevents.IsACK(result), "result: %v", result)
+			status, ok := sinkStatusCode(result)
+			require.True(t, ok)
+			require.Equal(t, test.wantStatus, status)
+
+			require.Equal(t, "/topics/events", gotPath)
+			require.Equal(t, kafkaBridgeContentType, gotContentType)
+			require.Equal(t, "Bearer token", gotAuthorization)
+			require.Len(t, got.Records, 1)
+			require.Equal(t, `["field","value"]`, string(got.Records[0].Value))
+			require.Equal(t, "1519073278252-0", kafkaHeaders(got.Records[0])["ce_id"])
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+var (
+	// namespaceKey, sourceNameKey and streamKey tag the metrics of the
+	// consumer group with the source it belongs to and the stream it reads.
+	namespaceKey  = tag.MustNewKey("namespace")
+	sourceNameKey = tag.MustNewKey("source_name")
+	streamKey     = tag.MustNewKey("stream")
+)
+
+// groupLag is the state of the consumer group sampled by the adapter.
+type groupLag struct {
+	// lag is nil when Redis does not report it.
+	lag     *int64
+	pending int64
+}
+
+// sampleLag reads the lag of the consumer group with XINFO GROUPS, and its
+// pending entries with XPENDING.
+func sampleLag(conn redis.Conn, streamName, groupName string) (groupLag, error) {
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
+	if err != nil {
+		return groupLag{}, err
+	}
+	group, ok := groups[groupName]
+	if !ok {
+		return groupLag{}, fmt.Errorf("consumer group %q does not exist", groupName)
+	}
+
+	// The summary form replies with the number of pending entries first.
+	summary, err := redis.Values(conn.Do("XPENDING", streamName, groupName))
+	if err != nil {
+		return groupLag{}, err
+	}
+	if len(summary) == 0 {
+		return groupLag{}, errors.New("unexpected XPENDING reply")
+	}
+	pending, err := redis.Int64(summary[0], nil)
+	if err != nil {
+		return groupLag{}, err
+	}
+	return groupLag{lag: group.Lag, pending: pending}, nil
+}
+
+// sampleLagEvery records the lag and pending entries of the consumer group
+// every interval, until ctx is done.
+func (a *Adapter) sampleLagEvery(ctx context.Context, pool *redis.Pool, streamName, groupName string, interval time.Duration) {
+	ctx, err := a.withStreamTags(ctx, streamName)
+	if err != nil {
+		a.logger.Error("Cannot tag the consumer group lag", zap.Error(err))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn := pool.Get()
+			sample, err := sampleLag(conn, streamName, groupName)
+			conn.Close()
+			if err != nil {
+				a.logger.Warn("Cannot sample the consumer group lag", zap.Error(err))
+				continue
+			}
+			if sample.lag != nil {
+				metrics.Record(ctx, consumerLagM.M(*sample.lag))
+			}
+			metrics.Record(ctx, pendingCountM.M(sample.pending))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lagConn replies to XINFO GROUPS with the groups and to XPENDING with the
+// summary of the pending entries.
+type lagConn struct {
+	fakeConn
+	groups  []interface{}
+	pending int64
+}
+
+func (c *lagConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	switch cmd {
+	case "XINFO":
+		return c.groups, nil
+	case "XPENDING":
+		return []interface{}{c.pending, nil, nil, nil}, nil
+	}
+	return c.fakeConn.Do(cmd, args...)
+}
+
+func groupInfo(name string, lag interface{}) []interface{} {
+	return []interface{}{
+		[]byte("name"), []byte(name),
+		[]byte("consumers"), int64(1),
+		[]byte("pending"), int64(2),
+		[]byte("last-delivered-id"), []byte("1-0"),
+		[]byte("entries-read"), int64(1),
+		[]byte("lag"), lag,
+	}
+}
+
+func TestSampleLag(t *testing.T) {
+	conn := &lagConn{groups: []interface{}{groupInfo("other", int64(9)), groupInfo("mygroup", int64(5))}, pending: 2}
+
+	sample, err := sampleLag(conn, "mystream", "mygroup")
+	require.NoError(t, err)
+	require.NotNil(t, sample.lag)
+	require.Equal(t, int64(5), *sample.lag)
+	require.Equal(t, int64(2), sample.pending)
+}
+
+func TestSampleLag_Unknown(t *testing.T) {
+	conn := &lagConn{groups: []interface{}{groupInfo("mygroup", nil)}, pending: 2}
+
+	sample, err := sampleLag(conn, "mystream", "mygroup")
+	require.NoError(t, err)
+	require.Nil(t, sample.lag, "the lag is not reported when Redis cannot compute it")
+	require.Equal(t, int64(2), sample.pending)
+}
+
+func TestSampleLag_NoGroup(t *testing.T) {
+	conn := &lagConn{groups: []interface{}{groupInfo("other", int64(9))}}
+
+	_, err := sampleLag(conn, "mystream", "mygroup")
+	require.Error(t, err)
+}
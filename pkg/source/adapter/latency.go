@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+// commandKey tags the latencies of Redis commands with the command.
+var commandKey = tag.MustNewKey("command")
+
+// timedCommands are the commands the adapter sends to Redis, the only ones
+// tagged with their name to bound the cardinality of the latencies.
+var timedCommands = map[string]bool{
+	"XREADGROUP": true,
+	"XACK":       true,
+	"XAUTOCLAIM": true,
+	"XPENDING":   true,
+	"XCLAIM":     true,
+	"XINFO":      true,
+	"XGROUP":     true,
+	"XADD":       true,
+	"INCR":       true,
+	"SISMEMBER":  true,
+	"SADD":       true,
+}
+
+// timedConn observes the latency of the commands sent to Redis.
+type timedConn struct {
+	redis.Conn
+	observe func(command string, latency time.Duration)
+}
+
+func (c *timedConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	start := time.Now()
+	reply, err := c.Conn.Do(cmd, args...)
+	c.observe(commandName(cmd), time.Since(start))
+	return reply, err
+}
+
+// commandName returns the name the latencies of the command are tagged with.
+func commandName(cmd string) string {
+	cmd = strings.ToUpper(cmd)
+	if !timedCommands[cmd] {
+		return "OTHER"
+	}
+	return cmd
+}
+
+// recordCommandLatency records the latency of a Redis command. The latency
+// of XREADGROUP includes the time spent blocking for new entries.
+func recordCommandLatency(command string, latency time.Duration) {
+	ctx, err := tag.New(context.Background(), tag.Upsert(commandKey, command))
+	if err != nil {
+		return
+	}
+	metrics.Record(ctx, redisCommandLatencyM.M(float64(latency)/float64(time.Millisecond)))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTimedConn(t *testing.T) {
+	type observation struct {
+		command string
+		latency time.Duration
+	}
+	var observed []observation
+	conn := &timedConn{
+		Conn: &fakeConn{reads: []fakeReply{entryReply("1-0")}},
+		observe: func(command string, latency time.Duration) {
+			observed = append(observed, observation{command, latency})
+		},
+	}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	_, err := conn.Do("ping")
+	require.NoError(t, err)
+
+	var commands []string
+	for _, o := range observed {
+		commands = append(commands, o.command)
+		require.GreaterOrEqual(t, o.latency, time.Duration(0))
+	}
+	require.Equal(t, []string{"XREADGROUP", "XACK", "OTHER"}, commands)
+}
+
+func TestCommandName(t *testing.T) {
+	for cmd, want := range map[string]string{
+		"XREADGROUP": "XREADGROUP",
+		"xack":       "XACK",
+		"XAUTOCLAIM": "XAUTOCLAIM",
+		"XINFO":      "XINFO",
+		"CLIENT":     "OTHER",
+		"":           "OTHER",
+	} {
+		if got := commandName(cmd); got != want {
+			t.Errorf("commandName(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
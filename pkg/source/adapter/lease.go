@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+)
+
+// errLeaseLost is returned once the lease is lost while consuming: another pod
+// may be consuming the stream already, so the consumers are not drained.
+var errLeaseLost = errors.New("lost the lease of the exclusive consumer")
+
+// leaseReportTimeout bounds the time spent reporting the lease holder.
+const leaseReportTimeout = 10 * time.Second
+
+// leaseHolderReporter publishes the name of the pod holding the lease of an
+// exclusive consumer, holder being empty once it released it.
+type leaseHolderReporter interface {
+	reportLeaseHolder(ctx context.Context, holder string) error
+}
+
+// newLeaseLock returns the lease of the exclusive consumer, held by this pod.
+func (a *Adapter) newLeaseLock(ctx context.Context) resourcelock.Interface {
+	return &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: a.config.Namespace,
+			Name:      a.config.LeaseName,
+		},
+		Client:     kubeclient.Get(ctx).CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: a.config.PodName},
+	}
+}
+
+// whileLeading runs consume once the pod holds the lease, until ctx is done.
+// The lease is renewed while the consumers drain, and released after. It
+// returns errLeaseLost, without waiting for consume, when the lease cannot be
+// renewed in time.
+func (a *Adapter) whileLeading(ctx context.Context, lock resourcelock.Interface, consume func(context.Context) error) error {
+	// The lease outlives ctx, until the consumers are stopped.
+	electing, stopElecting := context.WithCancel(context.Background())
+	defer stopElecting()
+
+	var (
+		mu      sync.Mutex
+		leading bool
+	)
+	done := make(chan error, 1)
+	duration := a.config.LeaseDuration
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            a.config.LeaseName,
+		LeaseDuration:   duration,
+		RenewDeadline:   duration * 2 / 3,
+		RetryPeriod:     duration * 2 / 15,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				mu.Lock()
+				if ctx.Err() != nil { // shut down before holding the lease
+					mu.Unlock()
+					return
+				}
+				leading = true
+				mu.Unlock()
+
+				a.logger.Info("Holding the lease, starting the consumers", zap.String("lease", a.config.LeaseName))
+				a.reportLeaseHolder(electing, a.config.PodName)
+				err := consume(ctx)
+				a.reportLeaseHolder(electing, "")
+				done <- err
+			},
+			OnStoppedLeading: func() {
+				a.logger.Info("Not holding the lease anymore", zap.String("lease", a.config.LeaseName))
+			},
+		},
+	})
+	if err != nil {
+		a.logger.Error("Invalid lease configuration", zap.Error(err))
+		return err
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		a.logger.Info("Waiting for the lease", zap.String("lease", a.config.LeaseName))
+		elector.Run(electing)
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !leading {
+			stopElecting()
+		}
+	}()
+
+	select {
+	case err := <-done:
+		stopElecting() // releases the lease
+		<-stopped
+		return err
+	case <-stopped:
+		mu.Lock()
+		defer mu.Unlock()
+		if leading {
+			a.logger.Error("Lost the lease, stopping without draining the consumers", zap.String("lease", a.config.LeaseName))
+			return errLeaseLost
+		}
+		return nil
+	}
+}
+
+// reportLeaseHolder reports the pod holding the lease, if the adapter runs for
+// a source.
+func (a *Adapter) reportLeaseHolder(ctx context.Context, holder string) {
+	if a.leaseHolders == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, leaseReportTimeout)
+	defer cancel()
+	if err := a.leaseHolders.reportLeaseHolder(ctx, holder); err != nil {
+		a.logger.Warn("Cannot report the lease holder", zap.Error(err))
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/utils/pointer"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/client/clientset/versioned/fake"
+)
+
+func newLeasingAdapter(kubeClient kubernetes.Interface, sources *fake.Clientset, podName string) (*Adapter, resourcelock.Interface) {
+	a := &Adapter{
+		logger:       zap.NewNop(),
+		config:       &Config{PodName: podName, LeaseName: "adapter", LeaseDuration: time.Second},
+		leaseHolders: &sourceAnnotator{client: sources, namespace: "ns", name: "source"},
+	}
+	return a, &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: "ns", Name: "adapter"},
+		Client:     kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: podName},
+	}
+}
+
+// consumeUntilDone signals started, then consumes until ctx is done.
+func consumeUntilDone(started chan<- string, podName string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		started <- podName
+		<-ctx.Done()
+		return nil
+	}
+}
+
+func leaseHolderAnnotation(t *testing.T, sources *fake.Clientset) string {
+	t.Helper()
+	source, err := sources.SourcesV1alpha1().RedisStreamSources("ns").Get(context.Background(), "source", metav1.GetOptions{})
+	require.NoError(t, err)
+	return source.Annotations[sourcesv1alpha1.LeaseHolderAnnotation]
+}
+
+func TestAdapter_WhileLeading(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	sources := fake.NewSimpleClientset(&sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+	})
+	started := make(chan string, 2)
+
+	first, firstLock := newLeasingAdapter(kubeClient, sources, "adapter-0")
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() { firstDone <- first.whileLeading(firstCtx, firstLock, consumeUntilDone(started, "adapter-0")) }()
+	require.Equal(t, "adapter-0", <-started)
+	require.Eventually(t, func() bool { return leaseHolderAnnotation(t, sources) == "adapter-0" }, time.Second, 10*time.Millisecond)
+
+	// A replacement waits for the lease.
+	second, secondLock := newLeasingAdapter(kubeClient, sources, "adapter-1")
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	secondDone := make(chan error, 1)
+	go func() {
+		secondDone <- second.whileLeading(secondCtx, secondLock, consumeUntilDone(started, "adapter-1"))
+	}()
+	select {
+	case name := <-started:
+		t.Fatalf("%s consumes while adapter-0 holds the lease", name)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// The lease is released once the consumers are stopped, before it expires.
+	stopFirst()
+	require.NoError(t, <-firstDone)
+	select {
+	case name := <-started:
+		require.Equal(t, "adapter-1", name)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("adapter-1 does not consume once the lease is released")
+	}
+	require.Eventually(t, func() bool { return leaseHolderAnnotation(t, sources) == "adapter-1" }, time.Second, 10*time.Millisecond)
+
+	stopSecond()
+	require.NoError(t, <-secondDone)
+	require.Empty(t, leaseHolderAnnotation(t, sources))
+}
+
+func TestAdapter_WhileLeadingNotHeld(t *testing.T) {
+	// Another pod holds the lease, renewing it.
+	kubeClient := kubefake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "adapter"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       pointer.String("adapter-1"),
+			LeaseDurationSeconds: pointer.Int32(60),
+			AcquireTime:          &metav1.MicroTime{Time: time.Now()},
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	})
+	sources := fake.NewSimpleClientset(&sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+	})
+	a, lock := newLeasingAdapter(kubeClient, sources, "adapter-0")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	require.NoError(t, a.whileLeading(ctx, lock, func(context.Context) error {
+		t.Error("consuming without holding the lease")
+		return nil
+	}))
+}
+
+func TestAdapter_WhileLeadingLost(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	sources := fake.NewSimpleClientset(&sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+	})
+	a, lock := newLeasingAdapter(kubeClient, sources, "adapter-0")
+	started := make(chan string, 1)
+
+	done := make(chan error, 1)
+	go func() { done <- a.whileLeading(context.Background(), lock, consumeUntilDone(started, "adapter-0")) }()
+	<-started
+
+	// Another pod takes the lease over, which cannot be renewed anymore.
+	leases := kubeClient.CoordinationV1().Leases("ns")
+	lease, err := leases.Get(context.Background(), "adapter", metav1.GetOptions{})
+	require.NoError(t, err)
+	lease.Spec.HolderIdentity = pointer.String("adapter-1")
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(60)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+	_, err = leases.Update(context.Background(), lease, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, errLeaseLost)
+	case <-time.After(2 * time.Second):
+		t.Fatal("still consuming after losing the lease")
+	}
+}
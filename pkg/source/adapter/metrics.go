@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	// sequenceCounterLatencyM records the time taken to increment the
+	// sequence counter shared by the replicas of a source, which grows with
+	// the contention on the counter.
+	sequenceCounterLatencyM = stats.Float64(
+		"sequence_counter_latencies",
+		"The time spent incrementing the shared sequence counter",
+		stats.UnitMilliseconds,
+	)
+
+	// redisCommandLatencyM records the time taken by the commands sent to
+	// Redis, tagged with the command.
+	redisCommandLatencyM = stats.Float64(
+		"redis_command_latencies",
+		"The time spent sending commands to Redis and receiving their replies",
+		stats.UnitMilliseconds,
+	)
+
+	// auditFailureCountM counts the events that could not be delivered to
+	// the audit sink.
+	auditFailureCountM = stats.Int64(
+		"audit_failure_count",
+		"Number of events that could not be delivered to the audit sink",
+		stats.UnitDimensionless,
+	)
+
+	// deadLetterSinkFailureCountM counts the events that could not be
+	// delivered to the dead-letter sink.
+	deadLetterSinkFailureCountM = stats.Int64(
+		"dead_letter_sink_failure_count",
+		"Number of events that could not be delivered to the dead-letter sink",
+		stats.UnitDimensionless,
+	)
+
+	// emptyEntryCountM counts the entries read without fields, e.g. because
+	// they were deleted while pending.
+	emptyEntryCountM = stats.Int64(
+		"empty_entry_count",
+		"Number of entries read without fields",
+		stats.UnitDimensionless,
+	)
+
+	// reclaimedEntryCountM counts the idle pending entries claimed to be
+	// delivered again.
+	reclaimedEntryCountM = stats.Int64(
+		"reclaimed_entry_count",
+		"Number of idle pending entries reclaimed",
+		stats.UnitDimensionless,
+	)
+
+	// exhaustedEntryCountM counts the entries dead-lettered after being
+	// delivered the maximum number of times.
+	exhaustedEntryCountM = stats.Int64(
+		"exhausted_entry_count",
+		"Number of entries dead-lettered after too many delivery attempts",
+		stats.UnitDimensionless,
+	)
+
+	// consumerLagM records the number of entries of the stream not delivered
+	// to the consumer group yet, as last sampled.
+	consumerLagM = stats.Int64(
+		"redis_stream_consumer_lag",
+		"Number of entries of the stream not delivered to the consumer group yet",
+		stats.UnitDimensionless,
+	)
+
+	// pendingCountM records the number of entries delivered to the consumer
+	// group and not acknowledged yet, as last sampled.
+	pendingCountM = stats.Int64(
+		"redis_stream_pending_count",
+		"Number of entries delivered to the consumer group and not acknowledged yet",
+		stats.UnitDimensionless,
+	)
+
+	// entryReadCountM counts the entries read from the stream.
+	entryReadCountM = stats.Int64(
+		"redis_stream_entry_read_count",
+		"Number of entries read from the stream",
+		stats.UnitDimensionless,
+	)
+
+	// eventDeliveredCountM counts the events the sink accepted.
+	eventDeliveredCountM = stats.Int64(
+		"redis_stream_event_delivered_count",
+		"Number of events delivered to the sink",
+		stats.UnitDimensionless,
+	)
+
+	// deliveryFailureCountM counts the events the sink did not accept, after
+	// the retries.
+	deliveryFailureCountM = stats.Int64(
+		"redis_stream_delivery_failure_count",
+		"Number of events that could not be delivered to the sink",
+		stats.UnitDimensionless,
+	)
+
+	// eventTypeFallbackCountM counts the events built from entries without
+	// the configured type field, which get the fallback type.
+	eventTypeFallbackCountM = stats.Int64(
+		"event_type_fallback_total",
+		"Number of events built from entries without the type field",
+		stats.UnitDimensionless,
+	)
+
+	// schemaValidationFailureCountM counts the entries whose fields do not
+	// match the schema of the source.
+	schemaValidationFailureCountM = stats.Int64(
+		"schema_validation_failure_total",
+		"Number of entries not matching the schema",
+		stats.UnitDimensionless,
+	)
+
+	// ackCountM counts the delivered entries acknowledged.
+	ackCountM = stats.Int64(
+		"redis_stream_ack_count",
+		"Number of delivered entries acknowledged",
+		stats.UnitDimensionless,
+	)
+
+	// trimmedCountM counts the entries trimmed from the stream.
+	trimmedCountM = stats.Int64(
+		"redis_stream_trimmed_count",
+		"Number of entries trimmed from the stream",
+		stats.UnitDimensionless,
+	)
+
+	// ackRetryCountM counts the retries of acknowledging delivered entries.
+	ackRetryCountM = stats.Int64(
+		"ack_retry_count",
+		"Number of retries of acknowledging delivered entries",
+		stats.UnitDimensionless,
+	)
+)
+
+func init() {
+	if err := metrics.RegisterResourceView(&view.View{
+		Description: sequenceCounterLatencyM.Description(),
+		Measure:     sequenceCounterLatencyM,
+		Aggregation: view.Distribution(metrics.Buckets125(1, 1000)...),
+	}, &view.View{
+		Description: redisCommandLatencyM.Description(),
+		Measure:     redisCommandLatencyM,
+		Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...),
+		TagKeys:     []tag.Key{commandKey},
+	}, &view.View{
+		Description: auditFailureCountM.Description(),
+		Measure:     auditFailureCountM,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: deadLetterSinkFailureCountM.Description(),
+		Measure:     deadLetterSinkFailureCountM,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: emptyEntryCountM.Description(),
+		Measure:     emptyEntryCountM,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: ackRetryCountM.Description(),
+		Measure:     ackRetryCountM,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: reclaimedEntryCountM.Description(),
+		Measure:     reclaimedEntryCountM,
+		Aggregation: view.Sum(),
+	}, &view.View{
+		Description: exhaustedEntryCountM.Description(),
+		Measure:     exhaustedEntryCountM,
+		Aggregation: view.Count(),
+	}, &view.View{
+		Description: consumerLagM.Description(),
+		Measure:     consumerLagM,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: entryReadCountM.Description(),
+		Measure:     entryReadCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: eventDeliveredCountM.Description(),
+		Measure:     eventDeliveredCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: deliveryFailureCountM.Description(),
+		Measure:     deliveryFailureCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: ackCountM.Description(),
+		Measure:     ackCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: trimmedCountM.Description(),
+		Measure:     trimmedCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: eventTypeFallbackCountM.Description(),
+		Measure:     eventTypeFallbackCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: schemaValidationFailureCountM.Description(),
+		Measure:     schemaValidationFailureCountM,
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}, &view.View{
+		Description: pendingCountM.Description(),
+		Measure:     pendingCountM,
+		Aggregation: view.LastValue(),
+		TagKeys:     []tag.Key{namespaceKey, sourceNameKey, streamKey},
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// withStreamTags tags the metrics recorded with ctx with the source and the
+// stream read.
+func (a *Adapter) withStreamTags(ctx context.Context, streamName string) (context.Context, error) {
+	return tag.New(ctx,
+		tag.Upsert(namespaceKey, a.config.Namespace),
+		tag.Upsert(sourceNameKey, a.config.SourceName),
+		tag.Upsert(streamKey, streamName))
+}
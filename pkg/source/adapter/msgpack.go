@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+// msgPackContentType is the content type of MessagePack event data.
+const msgPackContentType = "application/vnd.msgpack"
+
+// marshalMsgPack serializes the field-value pairs of an entry to a MessagePack
+// array of strings, the shape of their JSON encoding. Values are kept as is,
+// even when they are not valid UTF-8.
+func marshalMsgPack(fieldValues []string) []byte {
+	size := 5
+	for _, s := range fieldValues {
+		size += 5 + len(s)
+	}
+	buf := make([]byte, 0, size)
+
+	n := len(fieldValues)
+	switch {
+	case n < 16:
+		buf = append(buf, 0x90|byte(n)) // fixarray
+	case n <= 0xffff:
+		buf = append(buf, 0xdc, byte(n>>8), byte(n)) // array 16
+	default:
+		buf = append(buf, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n)) // array 32
+	}
+
+	for _, s := range fieldValues {
+		n := len(s)
+		switch {
+		case n < 32:
+			buf = append(buf, 0xa0|byte(n)) // fixstr
+		case n <= 0xff:
+			buf = append(buf, 0xd9, byte(n)) // str 8
+		case n <= 0xffff:
+			buf = append(buf, 0xda, byte(n>>8), byte(n)) // str 16
+		default:
+			buf = append(buf, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n)) // str 32
+		}
+		buf = append(buf, s...)
+	}
+	return buf
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// unmarshalMsgPack decodes a MessagePack array of strings.
+func unmarshalMsgPack(data []byte) ([]string, error) {
+	next := func(n int) ([]byte, error) {
+		if len(data) < n {
+			return nil, errors.New("unexpected end of data")
+		}
+		b := data[:n]
+		data = data[n:]
+		return b, nil
+	}
+	length := func(b []byte) int {
+		n := 0
+		for _, c := range b {
+			n = n<<8 | int(c)
+		}
+		return n
+	}
+
+	h, err := next(1)
+	if err != nil {
+		return nil, err
+	}
+	var n int
+	switch {
+	case h[0]&0xf0 == 0x90:
+		n = int(h[0] & 0x0f)
+	case h[0] == 0xdc || h[0] == 0xdd:
+		b, err := next(map[byte]int{0xdc: 2, 0xdd: 4}[h[0]])
+		if err != nil {
+			return nil, err
+		}
+		n = length(b)
+	default:
+		return nil, errors.New("not an array")
+	}
+
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		h, err := next(1)
+		if err != nil {
+			return nil, err
+		}
+		var size int
+		switch {
+		case h[0]&0xe0 == 0xa0:
+			size = int(h[0] & 0x1f)
+		case h[0] >= 0xd9 && h[0] <= 0xdb:
+			b, err := next(map[byte]int{0xd9: 1, 0xda: 2, 0xdb: 4}[h[0]])
+			if err != nil {
+				return nil, err
+			}
+			size = length(b)
+		default:
+			return nil, errors.New("not a string")
+		}
+		s, err := next(size)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, string(s))
+	}
+	if len(data) > 0 {
+		return nil, errors.New("trailing data")
+	}
+	return values, nil
+}
+
+func TestMarshalMsgPack(t *testing.T) {
+	// ["a", "bc"]
+	require.Equal(t, []byte{0x92, 0xa1, 'a', 0xa2, 'b', 'c'}, marshalMsgPack([]string{"a", "bc"}))
+
+	many := make([]string, 70000)
+	for i := range many {
+		many[i] = "v"
+	}
+	tests := map[string][]string{
+		"empty":       {},
+		"fixstr":      {"field", "value"},
+		"str 8":       {"field", strings.Repeat("x", 200)},
+		"str 16":      {"field", strings.Repeat("x", 60000)},
+		"str 32":      {"field", strings.Repeat("x", 70000)},
+		"array 16":    many[:100],
+		"array 32":    many,
+		"invalid utf": {"field", "\xff\xfe"},
+	}
+	for n, fieldValues := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := unmarshalMsgPack(marshalMsgPack(fieldValues))
+			require.NoError(t, err)
+			require.Equal(t, fieldValues, got)
+		})
+	}
+}
+
+func TestAdapter_DataEncoding(t *testing.T) {
+	fieldValues := []string{"customer", "customer-1", "amount", "42"}
+	reply := []interface{}{
+		[]interface{}{[]byte("mystream"), []interface{}{
+			[]interface{}{[]byte("1-0"), []interface{}{[]byte("customer"), []byte("customer-1"), []byte("amount"), []byte("42")}},
+		}},
+	}
+
+	tests := []struct {
+		name            string
+		encoding        string
+		wantContentType string
+		unmarshal       func([]byte) ([]string, error)
+	}{{
+		name:            "default",
+		wantContentType: cloudevents.ApplicationJSON,
+	}, {
+		name:            "json",
+		encoding:        sourcesv1alpha1.DataEncodingJSON,
+		wantContentType: cloudevents.ApplicationJSON,
+	}, {
+		name:            "msgpack",
+		encoding:        sourcesv1alpha1.DataEncodingMsgPack,
+		wantContentType: msgPackContentType,
+		unmarshal:       unmarshalMsgPack,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := &Adapter{config: &Config{DataEncoding: test.encoding}}
+
+			event, err := a.toEvent(reply)
+			require.NoError(t, err)
+			require.Equal(t, test.wantContentType, event.DataContentType())
+
+			var got []string
+			if test.unmarshal != nil {
+				got, err = test.unmarshal(event.Data())
+			} else {
+				err = json.Unmarshal(event.Data(), &got)
+			}
+			require.NoError(t, err)
+			require.Equal(t, fieldValues, got)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// oidcRetryInterval is how often the OIDC token is exchanged again while
+	// it cannot be.
+	oidcRetryInterval = 10 * time.Second
+
+	// oidcRefreshInterval is how often the OIDC token is exchanged again when
+	// the security token service does not tell when the password expires.
+	oidcRefreshInterval = 5 * time.Minute
+)
+
+// exchangeOIDCToken exchanges the token of the service account of the pod for
+// a Redis password at the token endpoint of the security token service. It
+// returns the password and how long it is valid for, 0 when the token
+// endpoint does not tell.
+func (a *Adapter) exchangeOIDCToken(ctx context.Context, client *http.Client) (string, time.Duration, error) {
+	// The kubelet renews the token in the file before it expires.
+	token, err := os.ReadFile(a.config.RedisOIDCTokenFile)
+	if err != nil {
+		return "", 0, err
+	}
+	exchange := scan.OIDCExchange{
+		TokenURL: a.config.RedisOIDCTokenURL,
+		ClientID: a.config.RedisOIDCClientID,
+		Scope:    a.config.RedisOIDCScope,
+	}
+	return exchange.Exchange(ctx, client, string(token))
+}
+
+// refreshOIDCPassword exchanges the OIDC token for the Redis password, and
+// returns when to exchange it again: once three quarters of the lifetime of
+// the password elapsed, leaving time to retry before it expires. The last
+// password is kept when the exchange fails.
+func (a *Adapter) refreshOIDCPassword(ctx context.Context, client *http.Client) (time.Duration, error) {
+	password, lifetime, err := a.exchangeOIDCToken(ctx, client)
+	if err != nil {
+		return oidcRetryInterval, err
+	}
+	if a.passwords.set(password) {
+		// The first password is not a renewal.
+		if _, generation := a.passwords.get(); generation > 1 {
+			a.logger.Info("The Redis password was renewed, reconnecting")
+		}
+	}
+	if lifetime <= 0 {
+		return oidcRefreshInterval, nil
+	}
+	return lifetime * 3 / 4, nil
+}
+
+// useOIDCPassword exchanges the OIDC token for the first Redis password, when
+// the source authenticates with OIDC, and returns when to exchange it again.
+func (a *Adapter) useOIDCPassword(ctx context.Context, client *http.Client) (time.Duration, error) {
+	if a.config.RedisOIDCTokenURL == "" {
+		return 0, nil
+	}
+	a.passwords = &rotatingPassword{}
+	return a.refreshOIDCPassword(ctx, client)
+}
+
+// refreshOIDCPasswordEvery exchanges the OIDC token for the Redis password
+// again after each delay refreshOIDCPassword returns, starting with after,
+// until ctx is done.
+func (a *Adapter) refreshOIDCPasswordEvery(ctx context.Context, client *http.Client, after time.Duration) {
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			next, err := a.refreshOIDCPassword(ctx, client)
+			if err != nil {
+				a.logger.Warn("Cannot renew the Redis password, retrying", zap.Duration("after", next), zap.Error(err))
+			}
+			timer.Reset(next)
+		}
+	}
+}
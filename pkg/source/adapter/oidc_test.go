@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+func TestAdapter_RefreshOIDCPassword(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("service-account-token\n"), 0o600))
+
+	status, response := http.StatusOK, `{"access_token": "password-1", "expires_in": 3600}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, scan.JWTBearerAssertion, r.PostForm.Get("client_assertion_type"))
+		require.Equal(t, "service-account-token", r.PostForm.Get("client_assertion"))
+		require.Equal(t, "myclient", r.PostForm.Get("client_id"))
+		require.Equal(t, "redis", r.PostForm.Get("scope"))
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	a := &Adapter{
+		logger: zap.NewNop(),
+		config: &Config{
+			RedisOIDCTokenFile: tokenFile,
+			RedisOIDCTokenURL:  server.URL + "/token",
+			RedisOIDCClientID:  "myclient",
+			RedisOIDCScope:     "redis",
+		},
+	}
+
+	next, err := a.useOIDCPassword(context.Background(), server.Client())
+	require.NoError(t, err)
+	require.Equal(t, 45*time.Minute, next)
+	require.Equal(t, "password-1", a.redisPassword())
+
+	// The password is renewed.
+	response = `{"access_token": "password-2"}`
+	next, err = a.refreshOIDCPassword(context.Background(), server.Client())
+	require.NoError(t, err)
+	require.Equal(t, oidcRefreshInterval, next)
+	require.Equal(t, "password-2", a.redisPassword())
+
+	// The last password is kept while the token cannot be exchanged.
+	status, response = http.StatusUnauthorized, `{"error": "invalid_client"}`
+	next, err = a.refreshOIDCPassword(context.Background(), server.Client())
+	require.ErrorContains(t, err, "invalid_client")
+	require.Equal(t, oidcRetryInterval, next)
+	require.Equal(t, "password-2", a.redisPassword())
+}
+
+func TestAdapter_UseOIDCPassword_Disabled(t *testing.T) {
+	a := &Adapter{logger: zap.NewNop(), config: &Config{RedisPassword: "static"}}
+	_, err := a.useOIDCPassword(context.Background(), http.DefaultClient)
+	require.NoError(t, err)
+	require.Nil(t, a.passwords)
+	require.Equal(t, "static", a.redisPassword())
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// partitionKeyExtension is the extension attribute of the CloudEvents
+// partitioning extension.
+const partitionKeyExtension = "partitionkey"
+
+// setPartitionKey sets the partitionkey extension of the event from the
+// partition key field of the entry, when the entry has it.
+func (a *Adapter) setPartitionKey(event *cloudevents.Event, fieldValues []string) {
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		if fieldValues[i] != a.config.PartitionKeyField {
+			continue
+		}
+		key := fieldValues[i+1]
+		if a.config.PartitionKeyPartitions > 0 {
+			key = strconv.Itoa(partition(key, a.config.PartitionKeyPartitions))
+		}
+		event.SetExtension(partitionKeyExtension, key)
+		return
+	}
+}
+
+// partition hashes the key into one of n partitions. FNV-1a is stable across
+// releases and processes, so the same key always lands in the same partition.
+func partition(key string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
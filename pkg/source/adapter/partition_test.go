@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"strconv"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestPartition(t *testing.T) {
+	for _, key := range []string{"", "customer-1", "customer-2", "ünïcödé"} {
+		p := partition(key, 8)
+		if p < 0 || p >= 8 {
+			t.Errorf("partition(%q, 8) = %d, want within [0, 8)", key, p)
+		}
+		for i := 0; i < 10; i++ {
+			if got := partition(key, 8); got != p {
+				t.Fatalf("partition(%q, 8) = %d, then %d, want the same partition", key, p, got)
+			}
+		}
+	}
+
+	// Changing the hash would move keys between partitions on upgrade.
+	if got := partition("customer-1", 16); got != 7 {
+		t.Errorf("partition(customer-1, 16) = %d, want 4", got)
+	}
+}
+
+func TestPartitionDistribution(t *testing.T) {
+	const partitions, keys = 4, 4000
+	counts := make([]int, partitions)
+	for i := 0; i < keys; i++ {
+		counts[partition("key-"+strconv.Itoa(i), partitions)]++
+	}
+	for p, count := range counts {
+		if count < keys/partitions/2 || count > keys/partitions*2 {
+			t.Errorf("partition %d got %d of %d keys, want about %d", p, count, keys, keys/partitions)
+		}
+	}
+}
+
+func TestSetPartitionKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		partitions  int
+		fieldValues []string
+		want        interface{}
+	}{{
+		name:        "raw key",
+		fieldValues: []string{"data", "x", "customer", "customer-1"},
+		want:        "customer-1",
+	}, {
+		name:        "hashed key",
+		partitions:  16,
+		fieldValues: []string{"data", "x", "customer", "customer-1"},
+		want:        "7",
+	}, {
+		name:        "missing field",
+		partitions:  16,
+		fieldValues: []string{"data", "x"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := &Adapter{config: &Config{PartitionKeyField: "customer", PartitionKeyPartitions: test.partitions}}
+			event := cloudevents.NewEvent()
+			a.setPartitionKey(&event, test.fieldValues)
+
+			got, ok := event.Extensions()[partitionKeyExtension]
+			if test.want == nil {
+				if ok {
+					t.Errorf("partitionkey = %v, want none", got)
+				}
+				return
+			}
+			if got != test.want {
+				t.Errorf("partitionkey = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+)
+
+// errPasswordChanged drops the idle connections of the pool authenticated with
+// a password before it changed.
+var errPasswordChanged = errors.New("the Redis password changed")
+
+// rotatingPassword holds the Redis password when it changes while the receive
+// adapter runs: read from a file, e.g. written by the Vault agent, read again
+// when the file changes, or exchanged for the OIDC token again before it
+// expires.
+type rotatingPassword struct {
+	path       string // empty unless the password is read from a file
+	mu         sync.RWMutex
+	password   string
+	generation uint64 // incremented each time the password changes
+}
+
+// load reads the password from the file, returning true when it changed. The
+// trailing newline most tools write is not part of the password.
+func (p *rotatingPassword) load() (bool, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return false, err
+	}
+	return p.set(strings.TrimRight(string(b), "\r\n")), nil
+}
+
+// set replaces the password, returning true when it changed.
+func (p *rotatingPassword) set(password string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if password == p.password {
+		return false
+	}
+	p.password = password
+	p.generation++
+	return true
+}
+
+// get returns the password, and how many times it changed.
+func (p *rotatingPassword) get() (string, uint64) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.password, p.generation
+}
+
+// passwordConn is a connection authenticated with the password of a
+// generation of the rotating password.
+type passwordConn struct {
+	redis.Conn
+	generation uint64
+}
+
+// usePasswordFile reads the Redis password from its file, when the source has
+// one.
+func (a *Adapter) usePasswordFile() error {
+	if a.config.RedisPasswordFile == "" {
+		return nil
+	}
+	a.passwords = &rotatingPassword{path: a.config.RedisPasswordFile}
+	_, err := a.passwords.load()
+	return err
+}
+
+// watchPasswordFile reads the password file again each time it changes, until
+// ctx is done. The directory is watched rather than the file, as the file is
+// usually replaced instead of written to, e.g. by swapping the symbolic links
+// of a mounted volume.
+func (a *Adapter) watchPasswordFile(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(a.passwords.path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// The file can be missing for a moment while it is replaced.
+				changed, err := a.passwords.load()
+				if err != nil {
+					a.logger.Warn("Cannot read the Redis password file", zap.String("path", a.passwords.path), zap.Error(err))
+				} else if changed {
+					a.logger.Info("The Redis password changed, reconnecting", zap.String("path", a.passwords.path))
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				a.logger.Warn("Cannot watch the Redis password file", zap.String("path", a.passwords.path), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// redisPassword returns the password of the auth of the source, the rotating
+// one when it has one.
+func (a *Adapter) redisPassword() string {
+	if a.passwords == nil {
+		return a.config.RedisPassword
+	}
+	password, _ := a.passwords.get()
+	return password
+}
+
+// stalePassword returns true when the connection authenticated with a
+// password that changed since.
+func (a *Adapter) stalePassword(conn redis.Conn) bool {
+	pc, ok := conn.(*passwordConn)
+	if !ok {
+		return false
+	}
+	_, generation := a.passwords.get()
+	return pc.generation != generation
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdapter_WatchPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "redis-password")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+
+	a := &Adapter{logger: zap.NewNop(), config: &Config{RedisPasswordFile: path}}
+	require.NoError(t, a.usePasswordFile())
+	require.Equal(t, "old", a.redisPassword())
+
+	_, generation := a.passwords.get()
+	conn := &passwordConn{Conn: &fakeConn{}, generation: generation}
+	require.False(t, a.stalePassword(conn))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, a.watchPasswordFile(ctx))
+
+	// The file is replaced, as the Vault agent and the kubelet do.
+	rotated := filepath.Join(dir, ".redis-password.tmp")
+	require.NoError(t, os.WriteFile(rotated, []byte("new\n"), 0600))
+	require.NoError(t, os.Rename(rotated, path))
+
+	require.Eventually(t, func() bool { return a.redisPassword() == "new" }, 5*time.Second, 10*time.Millisecond)
+	require.True(t, a.stalePassword(conn))
+}
+
+func TestAdapter_UsePasswordFileMissing(t *testing.T) {
+	a := &Adapter{logger: zap.NewNop(), config: &Config{RedisPasswordFile: filepath.Join(t.TempDir(), "redis-password")}}
+	require.Error(t, a.usePasswordFile())
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pubsub implements the receive adapter of the RedisPubSubSource,
+// which sends the messages published to Redis channels as CloudEvents.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	redisParse "github.com/go-redis/redis/v8"
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// RedisPubSubSourceEventType is the type of the CloudEvents sent by the RedisPubSubSource.
+	RedisPubSubSourceEventType = "dev.knative.redis.pubsub.message"
+
+	// patternExtension is the pattern a message published to a channel
+	// matched, for the channels subscribed to with patterns.
+	patternExtension = "pattern"
+
+	// Extensions set on the events sent to the dead-letter sink, as Knative
+	// Eventing channels and brokers do.
+	errorDestExtension = "knativeerrordest" // the sink that did not accept the event
+	errorCodeExtension = "knativeerrorcode" // the status code of its last response, if any
+
+	// resubscribeDelay is how long to wait before subscribing again once the
+	// connection to Redis failed.
+	resubscribeDelay = time.Second
+)
+
+func NewEnvConfig() adapter.EnvConfigAccessor {
+	return &Config{}
+}
+
+type Adapter struct {
+	config      *Config
+	logger      *zap.Logger
+	client      cloudevents.Client
+	deadLetters cloudevents.Client // nil unless a dead-letter sink is configured
+	dial        func() (redis.Conn, error)
+}
+
+func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
+	config := processed.(*Config)
+
+	return &Adapter{
+		config: config,
+		logger: logging.FromContext(ctx).Desugar(),
+		client: ceClient,
+	}
+}
+
+// Start subscribes to the channels and patterns and sends the messages
+// published to them until ctx is done. The subscriptions are made again when
+// the connection to Redis fails: the messages published meanwhile are lost,
+// as Pub/Sub does not keep them.
+func (a *Adapter) Start(ctx context.Context) error {
+	opt, err := redisParse.ParseURL(a.config.Address)
+	if err != nil {
+		a.logger.Error("Invalid Redis address", zap.Error(err))
+		return err
+	}
+	a.dial = func() (redis.Conn, error) {
+		// No AUTH is sent without a password.
+		return redis.Dial("tcp", opt.Addr,
+			redis.DialUsername(opt.Username),
+			redis.DialPassword(opt.Password),
+			redis.DialDatabase(opt.DB),
+		)
+	}
+
+	if a.config.DeadLetterSink != "" {
+		client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(a.config.DeadLetterSink))
+		if err != nil {
+			a.logger.Error("Cannot create dead-letter sink client", zap.Error(err))
+			return err
+		}
+		a.deadLetters = client
+	}
+
+	a.logger.Info("Subscribing", zap.Strings("channels", a.config.Channels), zap.Strings("patterns", a.config.Patterns))
+	for {
+		err := a.subscribe(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		a.logger.Error("Lost the subscriptions, subscribing again", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// subscribe subscribes to the channels and patterns on a new connection, and
+// sends the messages received until the connection fails or ctx is done.
+func (a *Adapter) subscribe(ctx context.Context) error {
+	conn, err := a.dial()
+	if err != nil {
+		return err
+	}
+	psc := redis.PubSubConn{Conn: conn}
+	defer psc.Close()
+
+	// Closing the connection unblocks Receive once ctx is done.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			psc.Close()
+		case <-stop:
+		}
+	}()
+
+	if len(a.config.Channels) > 0 {
+		if err := psc.Subscribe(redis.Args{}.AddFlat(a.config.Channels)...); err != nil {
+			return err
+		}
+	}
+	if len(a.config.Patterns) > 0 {
+		if err := psc.PSubscribe(redis.Args{}.AddFlat(a.config.Patterns)...); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch m := psc.Receive().(type) {
+		case redis.Message:
+			a.send(ctx, m)
+		case redis.Subscription:
+			a.logger.Debug("Subscribed", zap.String("kind", m.Kind), zap.String("channel", m.Channel), zap.Int("count", m.Count))
+		case error:
+			return m
+		}
+	}
+}
+
+// send sends the message as a CloudEvent to the sink, and to the dead-letter
+// sink, if any, when the sink does not accept it. Messages neither accept are
+// dropped.
+func (a *Adapter) send(ctx context.Context, m redis.Message) {
+	event := newEvent(m)
+	result := a.client.Send(ctx, event)
+	if cloudevents.IsACK(result) {
+		return
+	}
+	a.logger.Error("Failed to send cloudevent", zap.String("channel", m.Channel), zap.Any("result", result))
+	if a.deadLetters == nil {
+		return
+	}
+
+	event.SetExtension(errorDestExtension, a.config.GetSink())
+	if statusCode, ok := sinkStatusCode(result); ok {
+		event.SetExtension(errorCodeExtension, statusCode)
+	}
+	if result := a.deadLetters.Send(ctx, event); !cloudevents.IsACK(result) {
+		a.logger.Error("Failed to send cloudevent to the dead-letter sink", zap.String("channel", m.Channel), zap.Any("result", result))
+	}
+}
+
+// newEvent returns the CloudEvent of a message, from the channel it was
+// published to. Its data is the payload of the message, as JSON when it is
+// valid JSON.
+func newEvent(m redis.Message) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetType(RedisPubSubSourceEventType)
+	event.SetSource(m.Channel)
+	event.SetTime(time.Now())
+	if m.Pattern != "" {
+		event.SetExtension(patternExtension, m.Pattern)
+	}
+	contentType := "application/octet-stream"
+	if json.Valid(m.Data) {
+		contentType = cloudevents.ApplicationJSON
+	}
+	_ = event.SetData(contentType, m.Data)
+	return event
+}
+
+// sinkStatusCode returns the status code of the last response of the sink
+// when sending an event, if any.
+func sinkStatusCode(result protocol.Result) (int, bool) {
+	var retries *cehttp.RetriesResult
+	if errors.As(result, &retries) {
+		result = retries.Result
+	}
+	var httpResult *cehttp.Result
+	if !errors.As(result, &httpResult) {
+		return 0, false
+	}
+	return httpResult.StatusCode, true
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+// fakeConn replies to the subscriptions, then receives the replies, then
+// fails as a closed connection.
+type fakeConn struct {
+	sent    [][]interface{}
+	replies []interface{}
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return nil }
+func (c *fakeConn) Do(string, ...interface{}) (interface{}, error) {
+	return nil, errors.New("unexpected command")
+}
+func (c *fakeConn) Send(cmd string, args ...interface{}) error {
+	c.sent = append(c.sent, append([]interface{}{cmd}, args...))
+	return nil
+}
+func (c *fakeConn) Flush() error { return nil }
+func (c *fakeConn) Receive() (interface{}, error) {
+	if len(c.replies) == 0 {
+		return nil, io.EOF
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
+type fakeClient struct {
+	results []protocol.Result
+	events  []cloudevents.Event
+}
+
+func (c *fakeClient) Send(_ context.Context, event cloudevents.Event) protocol.Result {
+	r := c.results[len(c.events)]
+	c.events = append(c.events, event)
+	return r
+}
+func (c *fakeClient) Request(context.Context, cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+	return nil, nil
+}
+func (c *fakeClient) StartReceiver(context.Context, interface{}) error { return nil }
+
+func message(channel, data string) interface{} {
+	return []interface{}{[]byte("message"), []byte(channel), []byte(data)}
+}
+
+func patternMessage(pattern, channel, data string) interface{} {
+	return []interface{}{[]byte("pmessage"), []byte(pattern), []byte(channel), []byte(data)}
+}
+
+func TestSubscribe(t *testing.T) {
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{[]byte("subscribe"), []byte("orders"), int64(1)},
+		[]interface{}{[]byte("psubscribe"), []byte("users.*"), int64(2)},
+		message("orders", `{"id":1}`),
+		patternMessage("users.*", "users.created", "alice"),
+	}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{
+		Channels: []string{"orders"},
+		Patterns: []string{"users.*"},
+	}, dial: func() (redis.Conn, error) { return conn, nil }}
+
+	err := a.subscribe(context.Background())
+
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, [][]interface{}{{"SUBSCRIBE", "orders"}, {"PSUBSCRIBE", "users.*"}}, conn.sent)
+	require.Len(t, client.events, 2)
+
+	require.Equal(t, RedisPubSubSourceEventType, client.events[0].Type())
+	require.Equal(t, "orders", client.events[0].Source())
+	require.Equal(t, cloudevents.ApplicationJSON, client.events[0].DataContentType())
+	require.Equal(t, `{"id":1}`, string(client.events[0].Data()))
+	require.NotContains(t, client.events[0].Extensions(), patternExtension)
+
+	require.Equal(t, "users.created", client.events[1].Source())
+	require.Equal(t, "users.*", client.events[1].Extensions()[patternExtension])
+	require.Equal(t, "application/octet-stream", client.events[1].DataContentType())
+	require.Equal(t, "alice", string(client.events[1].Data()))
+	require.NotEqual(t, client.events[0].ID(), client.events[1].ID())
+}
+
+func TestSend_DeadLetterSink(t *testing.T) {
+	rejected := cehttp.NewResult(http.StatusBadRequest, "invalid event")
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, rejected}}
+	deadLetters := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, deadLetters: deadLetters, config: &Config{
+		EnvConfig: adapter.EnvConfig{Sink: "http://sink.example.com"},
+	}}
+
+	a.send(context.Background(), redis.Message{Channel: "orders", Data: []byte("accepted")})
+	a.send(context.Background(), redis.Message{Channel: "orders", Data: []byte("rejected")})
+
+	// Only the message the sink did not accept is dead-lettered.
+	require.Len(t, deadLetters.events, 1)
+	require.Equal(t, "rejected", string(deadLetters.events[0].Data()))
+	require.Equal(t, "http://sink.example.com", deadLetters.events[0].Extensions()[errorDestExtension])
+	require.EqualValues(t, http.StatusBadRequest, deadLetters.events[0].Extensions()[errorCodeExtension])
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+// Config is the configuration of the RedisPubSubSource receive adapter.
+type Config struct {
+	adapter.EnvConfig
+
+	Address string `envconfig:"ADDRESS" required:"true"`
+
+	// Channels and glob-style patterns of channels subscribed to, see sourcesv1alpha1.RedisPubSubSourceSpec.
+	Channels []string `envconfig:"CHANNELS"`
+	Patterns []string `envconfig:"PATTERNS"`
+
+	// DeadLetterSink receives the events the sink does not accept, see sourcesv1alpha1.RedisPubSubSourceSpec.DeadLetterSink.
+	DeadLetterSink string `envconfig:"DEAD_LETTER_SINK"`
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+)
+
+// unblockInterval is how often a consumer blocked reading the stream until an
+// entry is added is unblocked once the adapter shuts down, until it stopped.
+const unblockInterval = time.Second
+
+// readCount returns the COUNT of XREADGROUP: how many entries are read at once.
+func (a *Adapter) readCount() int {
+	if a.config.ReadCount < 1 {
+		return 1
+	}
+	return a.config.ReadCount
+}
+
+// readBlock returns the BLOCK of XREADGROUP, in milliseconds: how long to wait
+// for entries to be added, 0 waiting until one is.
+func (a *Adapter) readBlock() int64 {
+	d := a.config.ReadBlockTimeout
+	if d <= 0 {
+		return 0
+	}
+	// Rounded up, not to block forever for less than a millisecond.
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
+
+// unblocker unblocks a consumer blocked reading the stream until an entry is
+// added, so that it sees the adapter shutting down.
+type unblocker struct {
+	id int64 // client ID of the connection of the consumer, 0 when unknown
+}
+
+// track records the client ID of the connection of the consumer, whenever it
+// connects to Redis.
+func (u *unblocker) track(conn redis.Conn) {
+	id, err := redis.Int64(conn.Do("CLIENT", "ID"))
+	if err != nil {
+		id = 0
+	}
+	atomic.StoreInt64(&u.id, id)
+}
+
+// unblockOnDone unblocks the consumer with CLIENT UNBLOCK once ctx is done, and
+// then every unblockInterval until stopped is closed, in case the consumer was
+// not blocked yet.
+func (a *Adapter) unblockOnDone(ctx context.Context, pool *redis.Pool, u *unblocker, stopped <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+	case <-stopped:
+		return
+	}
+	ticker := time.NewTicker(unblockInterval)
+	defer ticker.Stop()
+	for {
+		if id := atomic.LoadInt64(&u.id); id != 0 {
+			conn := pool.Get()
+			if _, err := conn.Do("CLIENT", "UNBLOCK", id); err != nil {
+				a.logger.Warn("Cannot unblock consumer", zap.Int64("clientID", id), zap.Error(err))
+			}
+			conn.Close()
+		}
+		select {
+		case <-stopped:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// readArgsConn records the arguments of XREADGROUP.
+type readArgsConn struct {
+	fakeConn
+	args []interface{}
+}
+
+func (c *readArgsConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if cmd == "XREADGROUP" {
+		c.args = args
+	}
+	return c.fakeConn.Do(cmd, args...)
+}
+
+func TestAdapter_ReadOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		wantCount int
+		wantBlock int64
+	}{{
+		name:      "defaults",
+		config:    Config{ReadCount: 1, ReadBlockTimeout: 5 * time.Second},
+		wantCount: 1,
+		wantBlock: 5000,
+	}, {
+		name:      "block until an entry is added",
+		config:    Config{ReadCount: 100},
+		wantCount: 100,
+		wantBlock: 0,
+	}, {
+		name:      "sub-millisecond block",
+		config:    Config{ReadBlockTimeout: 100 * time.Microsecond},
+		wantCount: 1,
+		wantBlock: 1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &readArgsConn{fakeConn: fakeConn{reads: []fakeReply{{}}}}
+			a := &Adapter{logger: zap.NewNop(), config: &tt.config}
+
+			xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+			require.Equal(t, scan.NewID, xreadID)
+			require.Equal(t, []interface{}{"GROUP", "mygroup", "consumer", "COUNT", tt.wantCount, "BLOCK", tt.wantBlock, "STREAMS", "mystream", ">"}, conn.args)
+		})
+	}
+}
+
+func TestProcessEntry_ReadCount(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0", "3-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{ReadCount: 3}}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, scan.NewID, xreadID)
+	require.Equal(t, []string{"1-0", "2-0", "3-0"}, conn.acks)
+}
+
+func TestProcessEntry_ReadCountStopsOnHold(t *testing.T) {
+	unavailable := cehttp.NewResult(http.StatusServiceUnavailable, "no healthy upstream")
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0", "3-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, unavailable}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{ReadCount: 3, HoldOnSinkUnavailable: true}}
+
+	// The entries after the held one are read again from the pending entries.
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, "0", xreadID)
+	require.Equal(t, 2, client.sent)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestProcessEntry_ReadCountPending(t *testing.T) {
+	rejected := cehttp.NewResult(http.StatusBadRequest, "invalid event")
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0", "2-0", "3-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, rejected, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{ReadCount: 3}}
+
+	// Reading the pending entries moves past the one left pending.
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", "0", testRetryState(), false)
+	require.Equal(t, "2-0", xreadID)
+	require.Equal(t, 3, client.sent)
+	require.Equal(t, []string{"1-0", "3-0"}, conn.acks)
+}
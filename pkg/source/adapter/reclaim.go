@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+)
+
+// reclaimCount is the number of pending entries checked by each XPENDING and
+// XAUTOCLAIM.
+const reclaimCount = 100
+
+// reclaimer hands the entries claimed by the adapter to its consumers in
+// turn. Claimed entries are added to the pending entries of a consumer, which
+// then reads them, like the pending entries it finds when it starts, so that
+// they are delivered the same way as any other entry.
+type reclaimer struct {
+	claimed []chan struct{}
+	next    int
+}
+
+func newReclaimer(numConsumers int) *reclaimer {
+	r := &reclaimer{claimed: make([]chan struct{}, numConsumers)}
+	for j := range r.claimed {
+		r.claimed[j] = make(chan struct{}, 1)
+	}
+	return r
+}
+
+// nextConsumer returns the index of the consumer the next entries are claimed for.
+func (r *reclaimer) nextConsumer() int {
+	j := r.next
+	r.next = (j + 1) % len(r.claimed)
+	return j
+}
+
+// notify tells the j-th consumer that entries were claimed for it.
+func (r *reclaimer) notify(j int) {
+	select {
+	case r.claimed[j] <- struct{}{}:
+	default: // already notified
+	}
+}
+
+// reclaimed returns whether entries were claimed for the j-th consumer since
+// it last checked.
+func (r *reclaimer) reclaimed(j int) bool {
+	select {
+	case <-r.claimed[j]:
+		return true
+	default:
+		return false
+	}
+}
+
+// reclaimInterval returns how often the pending entries are reclaimed.
+func (a *Adapter) reclaimInterval() time.Duration {
+	if a.config.ReclaimInterval > 0 {
+		return a.config.ReclaimInterval
+	}
+	return a.config.ReclaimMinIdleTime
+}
+
+// reclaimPending claims the idle pending entries every interval, for each of
+// the consumers of the adapter in turn, until ctx is done.
+func (a *Adapter) reclaimPending(ctx context.Context, pool *redis.Pool, streamName, groupName string, r *reclaimer) {
+	ticker := time.NewTicker(a.reclaimInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j := r.nextConsumer()
+			consumerName := a.consumerName(j)
+			conn := pool.Get()
+			n, err := a.reclaimOnce(conn, streamName, groupName, consumerName)
+			conn.Close()
+			if err != nil {
+				a.logger.Error("Cannot reclaim pending messages", zap.Error(err))
+			}
+			if n > 0 {
+				a.logger.Info("Reclaimed pending messages", zap.String("consumerName", consumerName), zap.Int("count", n))
+				metrics.Record(ctx, reclaimedEntryCountM.M(int64(n)))
+				r.notify(j)
+			}
+		}
+	}
+}
+
+// reclaimOnce dead-letters the idle pending entries delivered too many times,
+// if a maximum is set, then claims the other idle pending entries for the
+// consumer. It returns the number of entries claimed.
+func (a *Adapter) reclaimOnce(conn redis.Conn, streamName, groupName, consumerName string) (int, error) {
+	minIdle := a.config.ReclaimMinIdleTime.Milliseconds()
+
+	if a.config.ReclaimMaxDeliveryAttempts > 0 {
+		if err := a.deadLetterExhausted(conn, streamName, groupName, minIdle); err != nil {
+			return 0, err
+		}
+	}
+
+	// JUSTID leaves the delivery count unchanged: it is incremented when the
+	// consumer reads the entry.
+	claimed := 0
+	start := "0-0"
+	for {
+		reply, err := redis.Values(conn.Do("XAUTOCLAIM", streamName, groupName, consumerName, minIdle, start, "COUNT", reclaimCount, "JUSTID"))
+		if err != nil {
+			return claimed, err
+		}
+		if len(reply) < 2 {
+			return claimed, errors.New("unexpected XAUTOCLAIM reply")
+		}
+		if start, err = redis.String(reply[0], nil); err != nil {
+			return claimed, err
+		}
+		ids, err := redis.Values(reply[1], nil)
+		if err != nil {
+			return claimed, err
+		}
+		claimed += len(ids)
+		if start == "0-0" {
+			return claimed, nil
+		}
+	}
+}
+
+// deadLetterExhausted records the idle pending entries delivered at least the
+// maximum number of times in the dead-letter stream, and acknowledges them.
+func (a *Adapter) deadLetterExhausted(conn redis.Conn, streamName, groupName string, minIdle int64) error {
+	start := "-"
+	for {
+		pending, err := redis.Values(conn.Do("XPENDING", streamName, groupName, "IDLE", minIdle, start, "+", reclaimCount))
+		if err != nil {
+			return err
+		}
+		for _, p := range pending {
+			// Each pending entry is [id, consumer, idle time, delivery count].
+			fields, err := redis.Values(p, nil)
+			if err != nil || len(fields) != 4 {
+				return errors.New("unexpected XPENDING reply")
+			}
+			id, err := redis.String(fields[0], nil)
+			if err != nil {
+				return err
+			}
+			deliveries, err := redis.Int(fields[3], nil)
+			if err != nil {
+				return err
+			}
+			if deliveries < a.config.ReclaimMaxDeliveryAttempts {
+				continue
+			}
+			values, err := entryFields(conn, streamName, id)
+			if err != nil {
+				return err
+			}
+			if err := a.deadLetter(conn, streamName, groupName, id, deadLetterMaxDeliveryAttempts, deliveries, values); err != nil {
+				return err
+			}
+			if err := a.ack(conn, streamName, groupName, id); err != nil {
+				return err
+			}
+			a.logger.Warn("Dead-lettered message delivered too many times", zap.String("id", id), zap.Int("deliveries", deliveries))
+			metrics.Record(context.Background(), exhaustedEntryCountM.M(1))
+		}
+		if len(pending) < reclaimCount {
+			return nil
+		}
+		last, _ := redis.Values(pending[len(pending)-1], nil)
+		id, _ := redis.String(last[0], nil)
+		start = "(" + id
+	}
+}
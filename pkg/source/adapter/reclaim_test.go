@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// reclaimConn replies to XPENDING with the pending entries, to XRANGE with
+// the fields of the entries and to XAUTOCLAIM with pages of claimed IDs,
+// recording the consumers they are claimed for and the dead-letter records.
+type reclaimConn struct {
+	fakeConn
+	pending   []interface{}
+	entries   map[string][]string
+	claims    [][]string
+	claimedBy []string
+	records   [][]interface{}
+}
+
+func (c *reclaimConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	switch cmd {
+	case "XPENDING":
+		return c.pending, nil
+	case "XRANGE":
+		fields, ok := c.entries[args[1].(string)]
+		if !ok {
+			return []interface{}{}, nil
+		}
+		values := make([]interface{}, len(fields))
+		for i, f := range fields {
+			values[i] = []byte(f)
+		}
+		return []interface{}{[]interface{}{[]byte(args[1].(string)), values}}, nil
+	case "XADD":
+		c.records = append(c.records, args[2:])
+	case "XAUTOCLAIM":
+		c.claimedBy = append(c.claimedBy, args[2].(string))
+		ids := c.claims[0]
+		c.claims = c.claims[1:]
+		next := "0-0"
+		if len(c.claims) > 0 {
+			next = c.claims[0][0]
+		}
+		reply := make([]interface{}, len(ids))
+		for i, id := range ids {
+			reply[i] = []byte(id)
+		}
+		return []interface{}{[]byte(next), reply, []interface{}{}}, nil
+	}
+	return c.fakeConn.Do(cmd, args...)
+}
+
+func pendingEntry(id, consumer string, deliveries int64) []interface{} {
+	return []interface{}{[]byte(id), []byte(consumer), int64(60000), deliveries}
+}
+
+func TestReclaimOnce(t *testing.T) {
+	conn := &reclaimConn{claims: [][]string{{"1-0", "2-0"}, {"3-0"}}}
+	a := &Adapter{logger: zap.NewNop(), config: &Config{PodName: "pod-0", ReclaimMinIdleTime: time.Minute}}
+
+	n, err := a.reclaimOnce(conn, "mystream", "mygroup", "pod-0-1")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []string{"pod-0-1", "pod-0-1"}, conn.claimedBy)
+	require.Empty(t, conn.acks, "claimed entries must not be acknowledged before they are delivered")
+}
+
+func TestReclaimOnce_MaxDeliveryAttempts(t *testing.T) {
+	conn := &reclaimConn{
+		pending: []interface{}{
+			pendingEntry("1-0", "pod-1-0", 5),
+			pendingEntry("2-0", "pod-1-0", 2),
+			pendingEntry("3-0", "pod-1-1", 7),
+		},
+		entries: map[string][]string{"1-0": {"order", "42"}},
+		claims:  [][]string{{"2-0"}},
+	}
+	a := &Adapter{logger: zap.NewNop(), config: &Config{ReclaimMinIdleTime: time.Minute, ReclaimMaxDeliveryAttempts: 5}}
+
+	n, err := a.reclaimOnce(conn, "mystream", "mygroup", "pod-0-0")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"1-0", "3-0"}, conn.acks)
+	require.Equal(t, []string{"redisdeadletter:mystream", "redisdeadletter:mystream"}, conn.added)
+	require.Equal(t, [][]interface{}{{
+		"order", "42",
+		"knative-original-id", "1-0",
+		"knative-retry-count", 5,
+		"knative-stream", "mystream",
+		"knative-group", "mygroup",
+		"knative-reason", "MaxDeliveryAttempts",
+	}, {
+		// 3-0 was deleted while pending.
+		"knative-original-id", "3-0",
+		"knative-retry-count", 7,
+		"knative-stream", "mystream",
+		"knative-group", "mygroup",
+		"knative-reason", "MaxDeliveryAttempts",
+	}}, conn.records)
+}
+
+func TestReclaimOnce_DeadLetterStream(t *testing.T) {
+	conn := &reclaimConn{
+		pending: []interface{}{pendingEntry("1-0", "pod-1-0", 5)},
+		claims:  [][]string{{}},
+	}
+	a := &Adapter{logger: zap.NewNop(), config: &Config{ReclaimMinIdleTime: time.Minute, ReclaimMaxDeliveryAttempts: 5, DeadLetterStream: "mystream-dlq"}}
+
+	_, err := a.reclaimOnce(conn, "mystream", "mygroup", "pod-0-0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mystream-dlq"}, conn.added)
+}
+
+func TestReclaimer(t *testing.T) {
+	r := newReclaimer(2)
+
+	require.Equal(t, 0, r.nextConsumer())
+	require.Equal(t, 1, r.nextConsumer())
+	require.Equal(t, 0, r.nextConsumer())
+
+	require.False(t, r.reclaimed(1))
+	r.notify(1)
+	r.notify(1)
+	require.False(t, r.reclaimed(0))
+	require.True(t, r.reclaimed(1))
+	require.False(t, r.reclaimed(1), "notifications must not pile up")
+}
+
+func TestReclaimInterval(t *testing.T) {
+	a := &Adapter{config: &Config{ReclaimMinIdleTime: 5 * time.Minute}}
+	require.Equal(t, 5*time.Minute, a.reclaimInterval())
+
+	a.config.ReclaimInterval = time.Minute
+	require.Equal(t, time.Minute, a.reclaimInterval())
+}
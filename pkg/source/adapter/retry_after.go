@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBackoffDelay caps the delay requested by a sink before retrying.
+const maxBackoffDelay = 30 * time.Second
+
+// retryAfterRoundTripper retries the requests the sink responds to with 429
+// or 503 and a Retry-After header, after the requested delay, instead of
+// leaving them to the exponential backoff of the CloudEvents client.
+type retryAfterRoundTripper struct {
+	http.RoundTripper
+	maxRetries int
+	maxDelay   time.Duration
+}
+
+func (r *retryAfterRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := r.RoundTripper.RoundTrip(req)
+		if err != nil || retry >= r.maxRetries {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err // cannot resend the body
+		}
+
+		delay, ok := retryAfter(resp, time.Now())
+		if !ok {
+			return resp, err
+		}
+		if delay > r.maxDelay {
+			delay = r.maxDelay
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After header, in seconds
+// or as an HTTP date, of a 429 or 503 response.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
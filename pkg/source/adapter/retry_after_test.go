@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		want       time.Duration
+		wantOK     bool
+	}{{
+		name:       "seconds",
+		statusCode: http.StatusTooManyRequests,
+		retryAfter: "2",
+		want:       2 * time.Second,
+		wantOK:     true,
+	}, {
+		name:       "HTTP date",
+		statusCode: http.StatusServiceUnavailable,
+		retryAfter: now.Add(90 * time.Second).Format(http.TimeFormat),
+		want:       90 * time.Second,
+		wantOK:     true,
+	}, {
+		name:       "past HTTP date",
+		statusCode: http.StatusServiceUnavailable,
+		retryAfter: now.Add(-time.Minute).Format(http.TimeFormat),
+		wantOK:     true,
+	}, {
+		name:       "invalid",
+		statusCode: http.StatusTooManyRequests,
+		retryAfter: "soon",
+	}, {
+		name:       "negative",
+		statusCode: http.StatusTooManyRequests,
+		retryAfter: "-1",
+	}, {
+		name:       "missing",
+		statusCode: http.StatusTooManyRequests,
+	}, {
+		name:       "other status code",
+		statusCode: http.StatusInternalServerError,
+		retryAfter: "2",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: test.statusCode, Header: http.Header{}}
+			if test.retryAfter != "" {
+				resp.Header.Set("Retry-After", test.retryAfter)
+			}
+			got, ok := retryAfter(resp, now)
+			require.Equal(t, test.wantOK, ok)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestSinkRetryAfter(t *testing.T) {
+	tests := []struct {
+		name              string
+		respectRetryAfter bool
+		wantMin           time.Duration
+		wantMax           time.Duration
+	}{{
+		name:              "respected",
+		respectRetryAfter: true,
+		wantMin:           2 * time.Second,
+		wantMax:           3 * time.Second,
+	}, {
+		name:    "ignored",
+		wantMax: time.Second,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []time.Time
+			var bodies []string
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				requests = append(requests, time.Now())
+				if len(requests) == 1 {
+					w.Header().Set("Retry-After", "2")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			a := &Adapter{logger: zap.NewNop(), config: &Config{RespectRetryAfter: test.respectRetryAfter}}
+			client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL),
+				cehttp.WithRoundTripper(a.retryAfterRoundTripper(http.DefaultTransport)))
+			require.NoError(t, err)
+
+			event := cloudevents.NewEvent()
+			event.SetID("1-0")
+			event.SetType(RedisStreamSourceEventType)
+			event.SetSource("test")
+			require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []string{"field", "value"}))
+
+			ctx := cloudevents.ContextWithRetriesExponentialBackoff(context.Background(), retryWaitPeriod, retryNumTimes)
+			require.True(t, cloudevents.IsACK(client.Send(ctx, event)))
+
+			require.Len(t, requests, 2)
+			waited := requests[1].Sub(requests[0])
+			require.GreaterOrEqual(t, waited, test.wantMin)
+			require.Less(t, waited, test.wantMax)
+			require.Equal(t, bodies[0], bodies[1])
+		})
+	}
+}
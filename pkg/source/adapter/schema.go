@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/schema"
+)
+
+const (
+	// schemaFetchTimeout bounds the time spent downloading the schema, and
+	// the schemas it references.
+	schemaFetchTimeout = 10 * time.Second
+
+	// schemaRetryInterval is how often the schema is downloaded again while
+	// it cannot be, instead of the refresh interval.
+	schemaRetryInterval = 10 * time.Second
+)
+
+// schemaCache holds the last schema downloaded, shared by the consumers.
+type schemaCache struct {
+	mu     sync.RWMutex
+	schema *jsonschema.Schema // nil until downloaded, or once its URL answered 404
+}
+
+func (c *schemaCache) get() *jsonschema.Schema {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.schema
+}
+
+func (c *schemaCache) set(s *jsonschema.Schema) {
+	c.mu.Lock()
+	c.schema = s
+	c.mu.Unlock()
+}
+
+// fetchSchema downloads the schema into the cache, and returns when to
+// download it again. The last schema downloaded is kept when downloading it
+// fails, unless its URL answers 404.
+func (a *Adapter) fetchSchema(ctx context.Context, client *http.Client) time.Duration {
+	ctx, cancel := context.WithTimeout(ctx, schemaFetchTimeout)
+	defer cancel()
+	s, err := schema.Fetch(ctx, client, a.config.SchemaURL)
+	switch {
+	case err == nil:
+		a.schemas.set(s)
+		return a.config.SchemaRefreshInterval
+	case errors.Is(err, schema.ErrNotFound):
+		a.logger.Warn("Schema not found, entries are not validated until it is", zap.String("url", a.config.SchemaURL))
+		a.schemas.set(nil)
+	default:
+		a.logger.Warn("Cannot download the schema", zap.String("url", a.config.SchemaURL), zap.Error(err))
+	}
+	return schemaRetryInterval
+}
+
+// fetchSchemaEvery downloads the schema again after each delay fetchSchema
+// returns, starting with after, until ctx is done.
+func (a *Adapter) fetchSchemaEvery(ctx context.Context, client *http.Client, after time.Duration) {
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(a.fetchSchema(ctx, client))
+		}
+	}
+}
+
+// schemaVerdict is what happens to an entry validated against the schema.
+type schemaVerdict int
+
+const (
+	// schemaDeliver delivers the entry: it matches the schema, or it does not
+	// but schema violations are only logged.
+	schemaDeliver schemaVerdict = iota
+	// schemaReject sends the event of the entry to the dead-letter sink
+	// instead of the sink.
+	schemaReject
+	// schemaHold leaves the entry pending, as there is no schema to reject
+	// it on yet.
+	schemaHold
+)
+
+// checkSchema validates the fields of the entry of the event against the
+// schema, returning the violation when the event is rejected.
+func (a *Adapter) checkSchema(ctx context.Context, event *cloudevents.Event, fieldValues []string) (schemaVerdict, error) {
+	if a.schemas == nil {
+		return schemaDeliver, nil
+	}
+	reject := sourcesv1alpha1.SchemaValidationMode(a.config.SchemaValidation) == sourcesv1alpha1.SchemaValidationReject
+	s := a.schemas.get()
+	if s == nil {
+		if reject {
+			return schemaHold, nil
+		}
+		return schemaDeliver, nil
+	}
+
+	err := s.Validate(schema.Entry(fieldValues))
+	if err == nil {
+		return schemaDeliver, nil
+	}
+	metrics.Record(ctx, schemaValidationFailureCountM.M(1))
+	if reject {
+		return schemaReject, err
+	}
+	a.logger.Warn("Message does not match the schema, delivering it anyway", zap.String("id", event.ID()), zap.String("url", a.config.SchemaURL), zap.Error(err))
+	return schemaDeliver, nil
+}
+
+// rejectSchemaViolation acknowledges the entry of an event not matching the
+// schema instead of delivering it, after sending the event to the dead-letter
+// sink. It returns false when the dead-letter sink did not accept the event,
+// leaving the entry pending.
+func (a *Adapter) rejectSchemaViolation(ctx context.Context, conn redis.Conn, streamName, groupName, consumerName string, event *cloudevents.Event, violation error, xreadID string, retries *retryState, isShuttingDown bool) (string, bool) {
+	if !a.sendToDeadLetterSink(ctx, event, fmt.Errorf("schema validation failed: %v", violation)) {
+		a.logger.Error("Cannot dead-letter message not matching the schema, leaving it pending", zap.String("id", event.ID()), zap.Error(violation))
+		return xreadID, false
+	}
+	a.logger.Info("Dead-lettered message not matching the schema", zap.String("consumerName", consumerName), zap.String("id", event.ID()), zap.Error(violation))
+	return a.ackSkipped(conn, streamName, groupName, event.ID(), xreadID, retries, isShuttingDown), true
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// The entries of the fake connection have the field "field" only.
+const (
+	matchingSchema    = `{"type": "object", "required": ["field"]}`
+	notMatchingSchema = `{"type": "object", "required": ["order"]}`
+)
+
+func compileSchema(t *testing.T, doc string) *jsonschema.Schema {
+	t.Helper()
+	s, err := jsonschema.CompileString("schema.json", doc)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAdapter_FetchSchema(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(matchingSchema))
+	}))
+	defer server.Close()
+
+	a := &Adapter{
+		logger:  zap.NewNop(),
+		config:  &Config{SchemaURL: server.URL + "/order.json", SchemaRefreshInterval: time.Minute},
+		schemas: &schemaCache{},
+	}
+
+	require.Equal(t, time.Minute, a.fetchSchema(context.Background(), server.Client()))
+	fetched := a.schemas.get()
+	require.NotNil(t, fetched)
+
+	// The last schema is kept while it cannot be downloaded.
+	status = http.StatusServiceUnavailable
+	require.Equal(t, schemaRetryInterval, a.fetchSchema(context.Background(), server.Client()))
+	require.Same(t, fetched, a.schemas.get())
+
+	// The schema is dropped once it is not found.
+	status = http.StatusNotFound
+	require.Equal(t, schemaRetryInterval, a.fetchSchema(context.Background(), server.Client()))
+	require.Nil(t, a.schemas.get())
+}
+
+func TestProcessEntry_Schema(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        sourcesv1alpha1.SchemaValidationMode
+		schema      string
+		deadLetters *fakeClient
+		wantSent    int
+		wantAcks    []string
+	}{{
+		name:     "matching",
+		mode:     sourcesv1alpha1.SchemaValidationReject,
+		schema:   matchingSchema,
+		wantSent: 1,
+		wantAcks: []string{"1-0"},
+	}, {
+		name:     "not matching, warned",
+		mode:     sourcesv1alpha1.SchemaValidationWarn,
+		schema:   notMatchingSchema,
+		wantSent: 1,
+		wantAcks: []string{"1-0"},
+	}, {
+		name:     "no schema, warned",
+		mode:     sourcesv1alpha1.SchemaValidationWarn,
+		wantSent: 1,
+		wantAcks: []string{"1-0"},
+	}, {
+		name:        "not matching, rejected",
+		mode:        sourcesv1alpha1.SchemaValidationReject,
+		schema:      notMatchingSchema,
+		deadLetters: &fakeClient{results: []protocol.Result{protocol.ResultACK}},
+		wantAcks:    []string{"1-0"},
+	}, {
+		name:        "not matching, dead-letter sink failure",
+		mode:        sourcesv1alpha1.SchemaValidationReject,
+		schema:      notMatchingSchema,
+		deadLetters: &fakeClient{results: []protocol.Result{errors.New("dead-letter sink unavailable")}},
+	}, {
+		name:        "no schema, rejecting",
+		mode:        sourcesv1alpha1.SchemaValidationReject,
+		deadLetters: &fakeClient{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+			client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+			a := &Adapter{
+				logger:  zap.NewNop(),
+				client:  client,
+				config:  &Config{SchemaURL: "https://schemas.example.com/order.json", SchemaValidation: string(test.mode)},
+				schemas: &schemaCache{},
+			}
+			if test.schema != "" {
+				a.schemas.set(compileSchema(t, test.schema))
+			}
+			if test.deadLetters != nil {
+				a.deadLetters = test.deadLetters
+			}
+
+			a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+
+			require.Equal(t, test.wantSent, client.sent)
+			require.Equal(t, test.wantAcks, conn.acks)
+			if test.deadLetters != nil && test.deadLetters.results != nil {
+				require.Equal(t, 1, test.deadLetters.sent)
+				require.Contains(t, test.deadLetters.events[0].Extensions(), errorDataExtension)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	redisParse "github.com/go-redis/redis/v8"
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// discoveryTimeout bounds the connections to the sentinels asked where the
+// master is.
+const discoveryTimeout = 5 * time.Second
+
+// endpoint identifies Redis in the source of the events: its address, the
+// master monitored by the sentinels, or the cluster.
+func (c *Config) endpoint() string {
+	if c.SentinelMasterName != "" {
+		return sourcesv1alpha1.SentinelURL(c.SentinelMasterName, c.SentinelAddresses)
+	}
+	if len(c.ClusterAddresses) > 0 {
+		return sourcesv1alpha1.ClusterURL(c.ClusterAddresses)
+	}
+	return c.Address
+}
+
+// dialMaster connects to the master the sentinels know, so that new
+// connections follow the master when it is failed over.
+func (a *Adapter) dialMaster() (redis.Conn, error) {
+	addr, err := scan.MasterAddr(a.config.SentinelAddresses, a.config.SentinelMasterName, func(address string) (redis.Conn, error) {
+		return redis.Dial("tcp", address,
+			redis.DialConnectTimeout(discoveryTimeout),
+			redis.DialReadTimeout(discoveryTimeout),
+			redis.DialWriteTimeout(discoveryTimeout),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	conn, err := a.dial(&redisParse.Options{Addr: addr})
+	if err != nil {
+		return nil, err
+	}
+	if err := scan.CheckMaster(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &followingConn{Conn: conn, moved: isReadOnly}, nil
+}
+
+// followingConn is a connection to the node that was found to serve the
+// stream, which is broken once a reply tells that the stream moved to another
+// node, so that it is replaced by a connection to the new node: a master
+// demoted to a replica by a failover, or a cluster slot moved to another node.
+type followingConn struct {
+	redis.Conn
+	moved func(error) bool
+	err   error
+}
+
+func (c *followingConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(commandName, args...)
+	if c.moved(err) {
+		c.err = err
+	}
+	return reply, err
+}
+
+func (c *followingConn) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Conn.Err()
+}
+
+// isReadOnly returns whether Redis refused a write because it is a replica.
+func isReadOnly(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "READONLY")
+}
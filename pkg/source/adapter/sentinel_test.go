@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// replyConn replies to every command with err.
+type replyConn struct {
+	redis.Conn
+	err error
+}
+
+func (c *replyConn) Do(string, ...interface{}) (interface{}, error) { return nil, c.err }
+func (c *replyConn) Err() error                                     { return nil }
+
+func TestFollowingConn(t *testing.T) {
+	inner := &replyConn{err: redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")}
+	conn := &followingConn{Conn: inner, moved: isReadOnly}
+	_, err := conn.Do("XREADGROUP")
+	require.Error(t, err)
+	require.NoError(t, conn.Err())
+
+	// Demoted to a replica by a failover.
+	inner.err = redis.Error("READONLY You can't write against a read only replica.")
+	_, err = conn.Do("XACK")
+	require.Error(t, err)
+	require.Equal(t, err, conn.Err())
+
+	// The slot of the stream moved to another cluster node.
+	inner.err = redis.Error("MOVED 3999 127.0.0.1:6381")
+	conn = &followingConn{Conn: inner, moved: scan.IsMoved}
+	_, err = conn.Do("XREADGROUP")
+	require.Equal(t, err, conn.Err())
+}
+
+func TestConfig_Endpoint(t *testing.T) {
+	require.Equal(t, "redis://redis:6379", (&Config{Address: "redis://redis:6379"}).endpoint())
+	require.Equal(t, "redis+sentinel://sentinel-0:26379,sentinel-1:26379/mymaster", (&Config{
+		SentinelMasterName: "mymaster",
+		SentinelAddresses:  []string{"sentinel-0:26379", "sentinel-1:26379"},
+	}).endpoint())
+	require.Equal(t, "redis+cluster://redis-cluster-0:6379,redis-cluster-1:6379", (&Config{
+		ClusterAddresses: []string{"redis-cluster-0:6379", "redis-cluster-1:6379"},
+	}).endpoint())
+}
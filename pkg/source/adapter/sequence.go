@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"knative.dev/pkg/metrics"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// sequenceKey returns the key of the sequence counter shared by the
+// consumers of the group.
+func (a *Adapter) sequenceKey(groupName string) string {
+	return sourcesv1alpha1.SequenceKey(a.config.Stream, groupName)
+}
+
+// stampSequence sets the redisseq extension of the event to the next value
+// of the sequence counter. INCR is atomic, so values are strictly increasing
+// across all the consumers of the group, at the cost of a round trip to Redis
+// for every event.
+func (a *Adapter) stampSequence(ctx context.Context, conn redis.Conn, groupName string, event *cloudevents.Event) error {
+	start := time.Now()
+	seq, err := redis.Int64(conn.Do("INCR", a.sequenceKey(groupName)))
+	metrics.Record(ctx, sequenceCounterLatencyM.M(float64(time.Since(start))/float64(time.Millisecond)))
+	if err != nil {
+		return err
+	}
+	event.SetExtension(redisSeqExtension, seq)
+	return nil
+}
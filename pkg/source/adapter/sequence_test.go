@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProcessEntry_SequenceCounter(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0"), entryReply("2-0"), entryReply("3-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{Stream: "mystream", SequenceCounter: true}}
+
+	for i := 0; i < 3; i++ {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+
+	require.Len(t, client.events, 3)
+	for i, event := range client.events {
+		require.EqualValues(t, i+1, event.Extensions()[redisSeqExtension])
+	}
+	require.Equal(t, map[string]int64{"redisseq:mystream:mygroup": 3}, conn.counters)
+}
+
+func TestProcessEntry_SequenceCounterError(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}, incrErr: errors.New("connection reset")}
+	client := &fakeClient{}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{Stream: "mystream", SequenceCounter: true}}
+
+	xreadID := a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Equal(t, "0", xreadID)
+	require.Zero(t, client.sent)
+}
+
+func TestProcessEntry_NoSequenceCounter(t *testing.T) {
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{Stream: "mystream"}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	require.Len(t, client.events, 1)
+	require.NotContains(t, client.events[0].Extensions(), redisSeqExtension)
+	require.Nil(t, conn.counters)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// streamRoundTripper sends the body of the requests with an unknown length,
+// so that it is streamed to the sink as it is read, using the chunked transfer
+// encoding over HTTP/1.1, rather than being fully buffered beforehand.
+type streamRoundTripper struct {
+	http.RoundTripper
+}
+
+func (s *streamRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return s.RoundTripper.RoundTrip(req)
+	}
+
+	// The request must not be modified, see http.RoundTripper.
+	req = req.Clone(req.Context())
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+	return s.RoundTripper.RoundTrip(req)
+}
+
+// gzipStream returns a reader of the gzip compressed content of body, which
+// is compressed while being read instead of into an intermediate buffer.
+func gzipStream(body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer body.Close()
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, body)
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
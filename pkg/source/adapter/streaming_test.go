@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStreamRequestBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		http2    bool
+	}{{
+		name: "identity",
+	}, {
+		name:     "gzip",
+		encoding: "gzip",
+	}, {
+		name:  "http2",
+		http2: true,
+	}}
+
+	data := bytes.Repeat([]byte("large redis stream entry "), 1<<18) // ~6.5MB
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotContentLength int64
+			var gotTransferEncoding []string
+			var gotProto string
+			var got []byte
+			sink := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodOptions {
+					w.Header().Set("Accept-Encoding", "gzip")
+					return
+				}
+
+				gotContentLength = r.ContentLength
+				gotTransferEncoding = r.TransferEncoding
+				gotProto = r.Proto
+				var body io.Reader = r.Body
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					zr, err := gzip.NewReader(r.Body)
+					require.NoError(t, err)
+					body = zr
+				}
+				var err error
+				got, err = io.ReadAll(body)
+				require.NoError(t, err)
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			sink.EnableHTTP2 = test.http2
+			if test.http2 {
+				sink.StartTLS()
+			} else {
+				sink.Start()
+			}
+			defer sink.Close()
+
+			a := &Adapter{logger: zap.NewNop(), config: &Config{SinkContentEncoding: test.encoding, StreamRequestBody: true}}
+			transport := sink.Client().Transport
+			client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL),
+				cehttp.WithRoundTripper(a.encodingRoundTripper(context.Background(), &streamRoundTripper{RoundTripper: transport}, sink.URL)))
+			require.NoError(t, err)
+
+			event := cloudevents.NewEvent()
+			event.SetID("1-0")
+			event.SetType(RedisStreamSourceEventType)
+			event.SetSource("test")
+			require.NoError(t, event.SetData("application/octet-stream", data))
+
+			require.True(t, cloudevents.IsACK(client.Send(context.Background(), event)))
+			require.Equal(t, int64(-1), gotContentLength)
+			if test.http2 {
+				require.Equal(t, "HTTP/2.0", gotProto)
+			} else {
+				require.Equal(t, []string{"chunked"}, gotTransferEncoding)
+			}
+			require.Equal(t, data, got)
+		})
+	}
+}
+
+func TestStreamRequestBodyGzipRetry(t *testing.T) {
+	data := bytes.Repeat([]byte("large redis stream entry "), 1<<16)
+
+	attempts := 0
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		got, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	rt := &gzipRoundTripper{
+		RoundTripper: &retryAfterRoundTripper{RoundTripper: &streamRoundTripper{RoundTripper: http.DefaultTransport}, maxRetries: 1, maxDelay: maxBackoffDelay},
+		stream:       true,
+	}
+	req, err := http.NewRequest(http.MethodPost, sink.URL, bytes.NewReader(data))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, 2, attempts)
+}
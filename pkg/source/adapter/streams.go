@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// redisStreamExtension is the extension attribute holding the stream an event
+// was read from, set when the adapter reads several streams.
+const redisStreamExtension = "redisstream"
+
+// forStream returns an adapter reading the stream with the configuration and
+// clients of a. The state of the consumers of each stream, such as the
+// delivered entries to acknowledge, is kept apart.
+func (a *Adapter) forStream(ctx context.Context, stream string) *Adapter {
+	config := *a.config
+	config.Stream = stream
+	return &Adapter{
+		config:          &config,
+		logger:          logging.FromContext(ctx).Desugar().With(zap.String("stream", stream)),
+		client:          a.client,
+		source:          config.eventSource(stream),
+		deliveryWindow:  a.deliveryWindow,
+		sinkHeaders:     a.sinkHeaders,
+		additionalSinks: a.additionalSinks,
+		fieldMapping:    a.fieldMapping,
+		requiredFields:  a.requiredFields,
+		schemas:         a.schemas,
+		dedup:           a.dedup,
+		pool:            a.pool,
+		redisTLS:        a.redisTLS,
+		auditor:         a.auditor,
+		failures:        a.failures,
+		deadLetters:     a.deadLetters,
+		batches:         a.batches,
+		connection:      a.connection,
+		checkpoints:     a.checkpoints,
+		cursors:         a.cursors,
+		resumeIDs:       a.resumeIDs,
+		drains:          a.drains,
+		minID:           a.minID,
+	}
+}
+
+// runStreams reads the streams with numConsumers consumers, each reading all
+// of them with a single XREADGROUP, until ctx is done, then drains them. Each
+// stream keeps the state of its own, as run does for a single stream.
+func (a *Adapter) runStreams(ctx context.Context, pool *redis.Pool, conn redis.Conn, streams []string, numConsumers int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	readers := make([]*streamReader, 0, len(streams))
+	var err error
+	for _, stream := range streams {
+		s := a.forStream(ctx, stream)
+		var r *streamReader
+		if r, err = s.startReading(ctx, pool, conn, target{stream: stream, group: s.config.Group}, numConsumers); err != nil {
+			s.logger.Error("Cannot read stream", zap.Error(err))
+			cancel() // stops the streams already started
+			break
+		}
+		readers = append(readers, r)
+	}
+	if err == nil {
+		a.runConsumers(ctx, pool, readers, numConsumers)
+	}
+
+	for _, r := range readers {
+		if stopErr := r.stop(conn); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
+
+// processStreams reads the next entries of all the streams with a single
+// XREADGROUP, each stream from its own ID, and delivers the entries of each
+// stream in turn, like processEntry, or adds them to the batch of the stream,
+// like batcher.process. The streams share the consumer group. It returns the
+// IDs to read each stream from in the next iteration.
+func (a *Adapter) processStreams(conn redis.Conn, readers []*streamReader, batches []*batcher, consumerName string, xreadIDs []string, retries *retryState) []string {
+	next := append([]string(nil), xreadIDs...)
+
+	count, block := a.readCount(), a.readBlock()
+	if batches[0] != nil {
+		flushed := false
+		for k, b := range batches {
+			if len(b.events) > 0 && (b.full() || b.lingered()) {
+				r := readers[k]
+				next[k] = b.flush(r.ctx, conn, r.stream, r.group, consumerName, next[k], retries, false)
+				flushed = true
+			}
+		}
+		if flushed {
+			return next
+		}
+
+		// No batch gets more entries than it can take, or lingers too long.
+		count = a.config.BatchSize
+		for _, b := range batches {
+			if room := b.room(); room < count {
+				count = room
+			}
+			if wait := b.block(); block == 0 || (wait > 0 && wait < block) {
+				block = wait
+			}
+		}
+	}
+
+	args := []interface{}{"GROUP", readers[0].group, consumerName, "COUNT", count, "BLOCK", block, "STREAMS"}
+	for _, r := range readers {
+		args = append(args, r.stream)
+	}
+	for _, id := range next {
+		args = append(args, id)
+	}
+	reply, err := conn.Do("XREADGROUP", args...)
+	if err != nil {
+		a.readFailed(err, retries, false)
+		return next
+	}
+	retries.redis.Reset()
+	retries.resharding.Reset()
+	deliveredAt := time.Now()
+
+	items, err := scanStreamEntries(reply)
+	if err != nil {
+		a.logger.Error("Cannot convert reply", zap.Error(err))
+		time.Sleep(retries.redis.Next())
+		return next
+	}
+	for k, r := range readers {
+		if batches[k] != nil {
+			next[k] = batches[k].addItems(r.ctx, conn, r.stream, r.group, consumerName, items[r.stream], deliveredAt, next[k], retries, false)
+		} else {
+			next[k] = r.a.deliverEntries(r.ctx, conn, r.stream, r.group, consumerName, items[r.stream], deliveredAt, next[k], retries, false)
+		}
+	}
+	return next
+}
+
+// scanStreamEntries returns the entries read by XREADGROUP from several streams,
+// by stream. It is empty when timed out blocking.
+func scanStreamEntries(reply interface{}) (map[string][]scan.StreamItem, error) {
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, nil // nil when timed out blocking
+	}
+	elems, err := scan.ScanXReadReply(values, nil)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string][]scan.StreamItem, len(elems))
+	for _, elem := range elems {
+		items[elem.Name] = elem.Items
+	}
+	return items, nil
+}
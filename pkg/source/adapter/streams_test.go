@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdapter_ForStream(t *testing.T) {
+	t.Setenv("ADDRESS", "redis://redis:6379")
+	t.Setenv("STREAM", "orders")
+	t.Setenv("STREAMS", "orders,payments")
+	t.Setenv("GROUP", "mygroup")
+	t.Setenv("NAME", "source-abc-0")
+	t.Setenv("NUM_CONSUMERS", "1")
+	t.Setenv("TLS_CERTIFICATE", "")
+	config := &Config{}
+	require.NoError(t, envconfig.Process("", config))
+	require.Equal(t, []string{"orders", "payments"}, config.Streams)
+
+	a := &Adapter{config: config, acks: &ackSweeper{}}
+	payments := a.forStream(context.Background(), "payments")
+	require.Equal(t, "payments", payments.config.Stream)
+	require.Equal(t, "orders", a.config.Stream)
+	require.Equal(t, "redis://redis:6379/payments", payments.source)
+	require.Nil(t, payments.acks)
+
+	// Events carry the stream they were read from.
+	event, err := payments.toEvent(entryReply("1-0").reply)
+	require.NoError(t, err)
+	require.Equal(t, "payments", event.Extensions()[redisStreamExtension])
+
+	single := &Adapter{config: &Config{Stream: "orders"}}
+	event, err = single.toEvent(entryReply("1-0").reply)
+	require.NoError(t, err)
+	require.NotContains(t, event.Extensions(), redisStreamExtension)
+}
+
+// streamsConn records the XREADGROUP commands and the streams of the XACK ones.
+type streamsConn struct {
+	*fakeConn
+	xreads [][]interface{}
+	acked  []string
+}
+
+func (c *streamsConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	switch cmd {
+	case "XREADGROUP":
+		c.xreads = append(c.xreads, args)
+	case "XACK":
+		c.acked = append(c.acked, args[0].(string))
+	}
+	return c.fakeConn.Do(cmd, args...)
+}
+
+func TestAdapter_ProcessStreams(t *testing.T) {
+	entry := func(id string) []interface{} {
+		return []interface{}{[]byte(id), []interface{}{[]byte("field"), []byte("value")}}
+	}
+	conn := &streamsConn{fakeConn: &fakeConn{reads: []fakeReply{{reply: []interface{}{
+		[]interface{}{[]byte("orders"), []interface{}{entry("1-0")}},
+		[]interface{}{[]byte("payments"), []interface{}{entry("2-0"), entry("3-0")}},
+	}}, {}}}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{
+		Streams:   []string{"orders", "payments"},
+		Group:     "mygroup",
+		ReadCount: 10,
+	}}
+
+	ctx := context.Background()
+	var readers []*streamReader
+	for _, stream := range a.config.Streams {
+		readers = append(readers, &streamReader{a: a.forStream(ctx, stream), ctx: ctx, stream: stream, group: "mygroup"})
+	}
+	batches := make([]*batcher, len(readers))
+
+	// Both streams are read at once, each from its own ID.
+	next := a.processStreams(conn, readers, batches, "consumer", []string{"0", ">"}, testRetryState())
+	require.Equal(t, []string{"0", ">"}, next)
+	require.Equal(t, [][]interface{}{{"GROUP", "mygroup", "consumer", "COUNT", 10, "BLOCK", int64(0), "STREAMS", "orders", "payments", "0", ">"}}, conn.xreads)
+	require.Equal(t, []string{"1-0", "2-0", "3-0"}, conn.acks)
+	require.Equal(t, []string{"orders", "payments", "payments"}, conn.acked)
+	streams := make([]interface{}, 0, len(client.events))
+	for _, event := range client.events {
+		streams = append(streams, event.Extensions()[redisStreamExtension])
+	}
+	require.Equal(t, []interface{}{"orders", "payments", "payments"}, streams)
+
+	// Timed out blocking, the new entries are read next.
+	next = a.processStreams(conn, readers, batches, "consumer", next, testRetryState())
+	require.Equal(t, []string{">", ">"}, next)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+// targetPollInterval is how often the mounted target is checked for changes.
+// The kubelet itself only refreshes mounted ConfigMaps every minute or so.
+const targetPollInterval = 10 * time.Second
+
+// target is the stream read by the consumers and the group they read it with.
+// An empty group stands for the group owned by the pod.
+type target struct {
+	stream string
+	group  string
+}
+
+// readTarget returns the target set by the stream and group files of dir, as
+// mounted from the target ConfigMap, defaulting to fallback for the missing ones.
+func readTarget(dir string, fallback target) (target, error) {
+	t := fallback
+	for name, value := range map[string]*string{"stream": &t.stream, "group": &t.group} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fallback, err
+		}
+		if v := strings.TrimSpace(string(b)); v != "" {
+			*value = v
+		}
+	}
+	if t.stream == "" {
+		return fallback, fmt.Errorf("empty stream in %s", dir)
+	}
+	return t, nil
+}
+
+// watchTarget checks the target of dir every interval until ctx is done, and
+// sends it on the returned channel whenever it differs from the last one.
+func (a *Adapter) watchTarget(ctx context.Context, dir string, fallback, current target, interval time.Duration) <-chan target {
+	logger := a.logger
+	changes := make(chan target)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			t, err := readTarget(dir, fallback)
+			if err != nil {
+				logger.Warn("Cannot read stream target, keeping the current one", zap.Error(err))
+				continue
+			}
+			if t == current {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case changes <- t:
+				current = t
+			}
+		}
+	}()
+	return changes
+}
+
+// followTarget runs the consumers of the target with run until ctx is done.
+// When another target is received from changes, the context of run is
+// cancelled so that the consumers are drained, and run is called again with
+// the new target.
+func (a *Adapter) followTarget(ctx context.Context, t target, changes <-chan target, run func(context.Context, target) error) error {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		next := make(chan target, 1)
+		go func() {
+			select {
+			case <-runCtx.Done():
+			case t := <-changes:
+				next <- t
+				cancel()
+			}
+		}()
+
+		err := run(runCtx, t)
+		cancel()
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case t = <-next:
+			a.logger.Info("Redirecting consumers", zap.String("stream", t.stream), zap.String("group", t.group))
+		default:
+			// run returned on its own, e.g. the consumers could not connect.
+			return nil
+		}
+	}
+}
+
+// useTarget makes the events and logs refer to the stream of the target.
+func (a *Adapter) useTarget(ctx context.Context, t target) {
+	if t.stream == a.config.Stream {
+		return
+	}
+	a.config.Stream = t.stream
+	a.source = a.config.eventSource(t.stream)
+	a.logger = logging.FromContext(ctx).Desugar().With(zap.String("stream", t.stream))
+}
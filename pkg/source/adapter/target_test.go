@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReadTarget(t *testing.T) {
+	spec := target{stream: "mystream", group: "mygroup"}
+
+	dir := t.TempDir()
+	got, err := readTarget(dir, spec)
+	require.NoError(t, err)
+	require.Equal(t, spec, got)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stream"), []byte("mystream-green\n"), 0o644))
+	got, err = readTarget(dir, spec)
+	require.NoError(t, err)
+	require.Equal(t, target{stream: "mystream-green", group: "mygroup"}, got)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "group"), []byte("green"), 0o644))
+	got, err = readTarget(dir, spec)
+	require.NoError(t, err)
+	require.Equal(t, target{stream: "mystream-green", group: "green"}, got)
+
+	_, err = readTarget(dir, target{})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stream"), nil, 0o644))
+	_, err = readTarget(dir, target{})
+	require.Error(t, err)
+}
+
+func TestWatchTarget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spec := target{stream: "mystream"}
+	dir := t.TempDir()
+	a := &Adapter{logger: zap.NewNop(), config: &Config{}}
+	changes := a.watchTarget(ctx, dir, spec, spec, time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stream"), []byte("mystream-green"), 0o644))
+	select {
+	case got := <-changes:
+		require.Equal(t, target{stream: "mystream-green"}, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("target change not detected")
+	}
+
+	// Back to the spec once the key is removed.
+	require.NoError(t, os.Remove(filepath.Join(dir, "stream")))
+	select {
+	case got := <-changes:
+		require.Equal(t, spec, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("target change not detected")
+	}
+}
+
+func TestFollowTarget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blue := target{stream: "mystream-blue", group: "mygroup"}
+	green := target{stream: "mystream-green", group: "mygroup"}
+	changes := make(chan target)
+	started := make(chan target)
+	var drained []target
+
+	a := &Adapter{logger: zap.NewNop(), config: &Config{}}
+	done := make(chan error)
+	go func() {
+		done <- a.followTarget(ctx, blue, changes, func(ctx context.Context, t target) error {
+			started <- t
+			<-ctx.Done()
+			// The consumers of the previous target are drained before the
+			// next one is read.
+			drained = append(drained, t)
+			return nil
+		})
+	}()
+
+	require.Equal(t, blue, <-started)
+	changes <- green
+	require.Equal(t, green, <-started)
+	require.Equal(t, []target{blue}, drained)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, []target{blue, green}, drained)
+}
+
+func TestUseTarget(t *testing.T) {
+	a := &Adapter{logger: zap.NewNop(), config: &Config{Address: "redis:6379", Stream: "mystream"}}
+	a.source = "redis:6379/mystream"
+
+	a.useTarget(context.Background(), target{stream: "mystream-green", group: "green"})
+	require.Equal(t, "mystream-green", a.config.Stream)
+	require.Equal(t, "redis:6379/mystream-green", a.source)
+
+	event, err := a.toEvent(entryReply("1-0").reply)
+	require.NoError(t, err)
+	require.Equal(t, "redis:6379/mystream-green", event.Source())
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+// The fields of the entries, and the extensions of the events, carrying the
+// W3C trace context, as defined by the CloudEvents distributed tracing
+// extension.
+const (
+	traceparentAttribute = "traceparent"
+	tracestateAttribute  = "tracestate"
+)
+
+// entrySpanName is the name of the spans of the entries read from the stream.
+const entrySpanName = "redis.stream.entry"
+
+var traceContextFormat = &tracecontext.HTTPFormat{}
+
+// startEntrySpan starts the span of an entry read from the stream, continuing
+// the trace of its traceparent field, if any, and sets the trace context of the
+// span on the event. It returns the context of the span, and the function
+// ending it with the result of sending the event: only the first call ends it,
+// so that it can also be deferred for the entries never sent.
+func (a *Adapter) startEntrySpan(ctx context.Context, streamName string, event *cloudevents.Event, fieldValues []string) (context.Context, func(protocol.Result)) {
+	var opts []trace.StartOption
+	if rate := a.config.TracingSamplingRate; rate != nil {
+		// Sampled parents are sampled whatever the rate.
+		opts = append(opts, trace.WithSampler(trace.ProbabilitySampler(*rate)))
+	}
+	var span *trace.Span
+	if parent, ok := entryTraceContext(fieldValues); ok {
+		ctx, span = trace.StartSpanWithRemoteParent(ctx, entrySpanName, parent, opts...)
+	} else {
+		ctx, span = trace.StartSpan(ctx, entrySpanName, opts...)
+	}
+	span.AddAttributes(
+		trace.StringAttribute("messaging.system", "redis"),
+		trace.StringAttribute("messaging.destination", streamName),
+		trace.StringAttribute("messaging.message_id", event.ID()),
+		trace.StringAttribute("cloudevents.type", event.Type()),
+	)
+
+	traceparent, tracestate := traceContextFormat.SpanContextToHeaders(span.SpanContext())
+	event.SetExtension(traceparentAttribute, traceparent)
+	if tracestate != "" {
+		event.SetExtension(tracestateAttribute, tracestate)
+	}
+
+	var once sync.Once
+	return ctx, func(result protocol.Result) {
+		once.Do(func() {
+			if statusCode, ok := sinkStatusCode(result); ok {
+				span.AddAttributes(trace.Int64Attribute("http.status_code", int64(statusCode)))
+			}
+			if !cloudevents.IsACK(result) {
+				span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: result.Error()})
+			}
+			span.End()
+		})
+	}
+}
+
+// entryTraceContext returns the trace context of the traceparent and
+// tracestate fields of an entry, if it has a valid one.
+func entryTraceContext(fieldValues []string) (trace.SpanContext, bool) {
+	var traceparent, tracestate string
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		switch fieldValues[i] {
+		case traceparentAttribute:
+			traceparent = fieldValues[i+1]
+		case tracestateAttribute:
+			tracestate = fieldValues[i+1]
+		}
+	}
+	return traceContextFormat.SpanContextFromHeaders(traceparent, tracestate)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s *trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+func recordSpans(t *testing.T) *spanRecorder {
+	r := &spanRecorder{}
+	trace.RegisterExporter(r)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	t.Cleanup(func() {
+		trace.UnregisterExporter(r)
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(1e-4)})
+	})
+	return r
+}
+
+func tracedEntryReply(id, traceparent string) fakeReply {
+	return fakeReply{reply: []interface{}{
+		[]interface{}{[]byte("mystream"), []interface{}{
+			[]interface{}{[]byte(id), []interface{}{[]byte("field"), []byte("value"), []byte("traceparent"), []byte(traceparent)}},
+		}},
+	}}
+}
+
+func TestProcessEntry_ContinuesTrace(t *testing.T) {
+	spans := recordSpans(t)
+	conn := &fakeConn{reads: []fakeReply{tracedEntryReply("1-0", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+
+	require.Len(t, spans.spans, 1)
+	span := spans.spans[0]
+	require.Equal(t, entrySpanName, span.Name)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID.String())
+	require.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+	require.Equal(t, "1-0", span.Attributes["messaging.message_id"])
+	require.Equal(t, int32(trace.StatusCodeOK), span.Status.Code)
+
+	// The event carries the context of the span, for the sink to continue the trace.
+	traceparent, ok := client.events[0].Extensions()[traceparentAttribute].(string)
+	require.True(t, ok)
+	sc, ok := traceContextFormat.SpanContextFromHeaders(traceparent, "")
+	require.True(t, ok)
+	require.Equal(t, span.SpanContext, sc)
+	require.Equal(t, []string{"1-0"}, conn.acks)
+}
+
+func TestProcessEntry_StartsTrace(t *testing.T) {
+	spans := recordSpans(t)
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	rejected := cehttp.NewResult(http.StatusBadRequest, "invalid event")
+	client := &fakeClient{results: []protocol.Result{rejected}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), true)
+
+	require.Len(t, spans.spans, 1)
+	span := spans.spans[0]
+	require.Equal(t, trace.SpanID{}, span.ParentSpanID)
+	require.NotEqual(t, int32(trace.StatusCodeOK), span.Status.Code)
+	require.EqualValues(t, http.StatusBadRequest, span.Attributes["http.status_code"])
+
+	traceparent, _ := client.events[0].Extensions()[traceparentAttribute].(string)
+	sc, ok := traceContextFormat.SpanContextFromHeaders(traceparent, "")
+	require.True(t, ok)
+	require.Equal(t, span.SpanContext, sc)
+}
+
+func TestProcessEntry_SamplingRate(t *testing.T) {
+	spans := recordSpans(t)
+	never := 0.0
+	conn := &fakeConn{reads: []fakeReply{
+		entryReply("1-0"),
+		tracedEntryReply("2-0", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		tracedEntryReply("3-0", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"),
+	}}
+	client := &fakeClient{results: []protocol.Result{protocol.ResultACK, protocol.ResultACK, protocol.ResultACK}}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{TracingSamplingRate: &never}}
+
+	for range conn.reads {
+		a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+	}
+
+	// Only the entry whose trace is sampled upstream is sampled.
+	require.Len(t, spans.spans, 1)
+	require.Equal(t, "2-0", spans.spans[0].Attributes["messaging.message_id"])
+	require.Equal(t, 3, client.sent)
+}
+
+func TestProcessEntry_EndsSpanOfSkippedEntry(t *testing.T) {
+	spans := recordSpans(t)
+	conn := &fakeConn{reads: []fakeReply{entryReply("1-0")}}
+	client := &fakeClient{}
+	a := &Adapter{logger: zap.NewNop(), client: client, config: &Config{}, minID: &scan.StreamID{Ms: 2}}
+
+	a.processEntry(context.Background(), conn, "mystream", "mygroup", "consumer", ">", testRetryState(), false)
+
+	require.Len(t, spans.spans, 1)
+	require.Zero(t, client.sent)
+}
+
+func TestBatcher_EndsSpansWhenFlushed(t *testing.T) {
+	spans := recordSpans(t)
+	sink := &batchSink{status: http.StatusAccepted}
+	a := newBatchAdapter(t, sink, &Config{BatchSize: 2, BatchLinger: time.Hour})
+	conn := &fakeConn{reads: []fakeReply{entriesReply("1-0"), entriesReply("2-0")}}
+	b := a.newBatcher()
+
+	b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	require.Empty(t, spans.spans, "span ended before the batch was sent")
+
+	b.process(context.Background(), conn, "mystream", "mygroup", "consumer", scan.NewID, testRetryState(), false)
+	require.Len(t, spans.spans, 2)
+	require.Empty(t, b.spans)
+	for _, event := range sink.batches[0] {
+		require.NotEmpty(t, event[traceparentAttribute])
+	}
+}
+
+func TestEntryTraceContext(t *testing.T) {
+	_, ok := entryTraceContext([]string{"field", "value"})
+	require.False(t, ok)
+	_, ok = entryTraceContext([]string{"traceparent", "not a trace context"})
+	require.False(t, ok)
+	sc, ok := entryTraceContext([]string{"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "tracestate", "vendor=value"})
+	require.True(t, ok)
+	require.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	require.Equal(t, "vendor=value", sc.Tracestate.Entries()[0].Key+"="+sc.Tracestate.Entries()[0].Value)
+}
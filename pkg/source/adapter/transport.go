@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/plugin/ochttp"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/eventingtls"
+	"knative.dev/pkg/tracing/propagation/tracecontextb3"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+const (
+	maxIdleConns    = 100
+	idleConnTimeout = 90 * time.Second
+)
+
+// newSinkTransport returns the HTTP transport shared by all consumers to deliver
+// events to the sink, sized to keep enough idle connections for parallelism
+// concurrent deliveries. Unless disabled, HTTP/2 is negotiated with sinks
+// supporting it, multiplexing concurrent deliveries over a single connection.
+func newSinkTransport(parallelism int, disableHTTP2 bool) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = parallelism * 2
+	transport.IdleConnTimeout = idleConnTimeout
+	transport.DisableCompression = false
+
+	if disableHTTP2 {
+		// A non-nil, empty TLSNextProto map disables HTTP/2, and only HTTP/1.1
+		// is offered during the TLS handshake.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		transport.TLSClientConfig = &tls.Config{NextProtos: []string{"http/1.1"}}
+		return transport, nil
+	}
+
+	if err := http2.ConfigureTransport(transport); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// protocolLogger logs the protocol negotiated with the sink on the first response.
+type protocolLogger struct {
+	http.RoundTripper
+	logger *zap.Logger
+	once   sync.Once
+}
+
+func (p *protocolLogger) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.RoundTripper.RoundTrip(req)
+	if err == nil {
+		p.once.Do(func() {
+			p.logger.Info("Negotiated sink protocol", zap.String("protocol", resp.Proto))
+		})
+	}
+	return resp, err
+}
+
+// useSinkTransport rebuilds the CloudEvents client created by the adapter main
+// so that it sends events through the given transport.
+func (a *Adapter) useSinkTransport(ctx context.Context, transport *http.Transport) error {
+	cfg := adapter.GetClientConfig(ctx)
+	if cfg.Env == nil {
+		// Not started by the adapter main, keep the provided client.
+		return nil
+	}
+
+	if eventingtls.IsHttpsSink(cfg.Env.GetSink()) {
+		// Trust the sink CA certificates, as the client of the adapter main does.
+		clientConfig := eventingtls.NewDefaultClientConfig()
+		clientConfig.CACerts = cfg.Env.GetCACerts()
+		tlsConfig, err := eventingtls.GetTLSClientConfig(clientConfig)
+		if err != nil {
+			return err
+		}
+		if transport.TLSClientConfig != nil {
+			tlsConfig.NextProtos = transport.TLSClientConfig.NextProtos
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var sink http.RoundTripper = transport
+	if timeout := a.config.DeliveryTimeout; timeout > 0 {
+		sink = &timeoutRoundTripper{RoundTripper: sink, timeout: timeout}
+	}
+	if a.config.StreamRequestBody {
+		sink = &streamRoundTripper{RoundTripper: sink}
+	}
+
+	var base http.RoundTripper
+	if a.config.KafkaBridgeTopic != "" {
+		// The bridge produces records, the content encoding of the sink does not apply.
+		base = a.retryAfterRoundTripper(&kafkaBridgeRoundTripper{RoundTripper: sink, topic: a.config.KafkaBridgeTopic})
+	} else {
+		base = a.encodingRoundTripper(ctx, a.retryAfterRoundTripper(sink), cfg.Env.GetSink())
+	}
+	roundTripper := &ochttp.Transport{
+		Base:        &protocolLogger{RoundTripper: base, logger: a.logger},
+		Propagation: tracecontextb3.TraceContextEgress,
+	}
+	cfg.Options = append(cfg.Options, cehttp.WithRoundTripper(roundTripper))
+	if err := a.useBatchSink(roundTripper); err != nil {
+		return err
+	}
+	client, err := adapter.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	a.client = client
+	return nil
+}
+
+// encodingRoundTripper returns a round tripper applying the configured sink
+// content encoding to the requests sent through transport, when the sink
+// supports it.
+func (a *Adapter) encodingRoundTripper(ctx context.Context, transport http.RoundTripper, sink string) http.RoundTripper {
+	if a.config.SinkContentEncoding != sourcesv1alpha1.SinkContentEncodingGzip {
+		return transport
+	}
+
+	ok, err := sinkAcceptsGzip(ctx, transport, sink)
+	if err != nil {
+		a.logger.Warn("Cannot check whether the sink accepts gzip, sending uncompressed events", zap.Error(err))
+		return transport
+	}
+	if !ok {
+		a.logger.Info("Sink does not accept gzip, sending uncompressed events")
+		return transport
+	}
+	a.logger.Info("Sending gzip compressed events")
+	return &gzipRoundTripper{RoundTripper: transport, stream: a.config.StreamRequestBody}
+}
+
+// retryAfterRoundTripper returns a round tripper respecting the Retry-After
+// header of the sink responses, unless disabled.
+func (a *Adapter) retryAfterRoundTripper(transport http.RoundTripper) http.RoundTripper {
+	if !a.config.RespectRetryAfter {
+		return transport
+	}
+	return &retryAfterRoundTripper{RoundTripper: transport, maxRetries: retryNumTimes, maxDelay: maxBackoffDelay}
+}
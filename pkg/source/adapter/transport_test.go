@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+func TestNewSinkTransport(t *testing.T) {
+	transport, err := newSinkTransport(5, false)
+	require.NoError(t, err)
+	require.Equal(t, maxIdleConns, transport.MaxIdleConns)
+	require.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	require.Equal(t, idleConnTimeout, transport.IdleConnTimeout)
+	require.False(t, transport.DisableCompression)
+	require.Contains(t, transport.TLSNextProto, http2.NextProtoTLS)
+
+	transport, err = newSinkTransport(5, true)
+	require.NoError(t, err)
+	require.False(t, transport.ForceAttemptHTTP2)
+	require.NotNil(t, transport.TLSNextProto)
+	require.Empty(t, transport.TLSNextProto)
+}
+
+func TestSinkTransportNegotiatesHTTP2(t *testing.T) {
+	sink := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	sink.EnableHTTP2 = true
+	sink.TLS = &tls.Config{NextProtos: []string{http2.NextProtoTLS, "http/1.1"}}
+	sink.StartTLS()
+	defer sink.Close()
+
+	tests := []struct {
+		name         string
+		disableHTTP2 bool
+		wantProto    string
+	}{{
+		name:      "HTTP/2",
+		wantProto: "HTTP/2.0",
+	}, {
+		name:         "HTTP/2 disabled",
+		disableHTTP2: true,
+		wantProto:    "HTTP/1.1",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport, err := newSinkTransport(1, test.disableHTTP2)
+			require.NoError(t, err)
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+			transport.TLSClientConfig.RootCAs = sink.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+			resp, err := (&http.Client{Transport: transport}).Post(sink.URL, "application/json", nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, test.wantProto, resp.Proto)
+		})
+	}
+}
+
+func TestSinkTransportReusesConnections(t *testing.T) {
+	const (
+		parallelism = 4
+		messages    = 1000
+	)
+
+	var newConns int32
+	sink := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	sink.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	sink.Start()
+	defer sink.Close()
+
+	transport, err := newSinkTransport(parallelism, false)
+	require.NoError(t, err)
+	client, err := cloudevents.NewClientHTTP(cehttp.WithTarget(sink.URL), cehttp.WithRoundTripper(transport))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < messages; i += parallelism {
+				event := cloudevents.NewEvent()
+				event.SetID(strconv.Itoa(i))
+				event.SetType(RedisStreamSourceEventType)
+				event.SetSource("test")
+				if result := client.Send(context.Background(), event); !cloudevents.IsACK(result) {
+					t.Errorf("failed to send event %d: %v", i, result)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, int(atomic.LoadInt32(&newConns)), transport.MaxIdleConnsPerHost)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"knative.dev/pkg/metrics"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// trimInterval is how often the stream is trimmed, when entries were
+	// acknowledged since it was last trimmed.
+	trimInterval = 10 * time.Second
+
+	// maxTrimBatch bounds the entries read to find where to trim the
+	// stream, so that trimming a long backlog is spread over several runs.
+	maxTrimBatch = 1000
+)
+
+// trimmer records whether entries were acknowledged since the stream was last
+// trimmed.
+type trimmer struct {
+	acked int32
+}
+
+// ack records that entries were acknowledged.
+func (t *trimmer) ack() {
+	atomic.StoreInt32(&t.acked, 1)
+}
+
+// take returns whether entries were acknowledged since the last call.
+func (t *trimmer) take() bool {
+	return atomic.SwapInt32(&t.acked, 0) == 1
+}
+
+// trimEvery trims the stream every interval, once entries were acknowledged,
+// until ctx is done.
+func (a *Adapter) trimEvery(ctx context.Context, pool *redis.Pool, streamName string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !a.trims.take() {
+				continue
+			}
+			conn := pool.Get()
+			n, err := trimStream(conn, streamName, a.config.TrimMaxLen, a.config.TrimExact)
+			conn.Close()
+			if err != nil {
+				a.logger.Warn("Cannot trim the stream", zap.Error(err))
+				a.trims.ack() // try again next time
+				continue
+			}
+			if n > 0 {
+				a.logger.Debug("Trimmed the stream", zap.Int64("count", n))
+				metrics.Record(ctx, trimmedCountM.M(n))
+			}
+		}
+	}
+}
+
+// trimStream removes the oldest entries of the stream beyond maxLen, except
+// the entries still needed by a consumer group of the stream: pending, or not
+// delivered yet. It trims with XTRIM MINID rather than MAXLEN, so that the
+// entries added meanwhile do not move the cut past the entries still needed.
+// Without exact, the stream is trimmed approximately, leaving the entries of
+// partially trimmed nodes. It returns the number of entries removed.
+func trimStream(conn redis.Conn, streamName string, maxLen int64, exact bool) (int64, error) {
+	length, err := redis.Int64(conn.Do("XLEN", streamName))
+	if err != nil || length <= maxLen {
+		return 0, err
+	}
+	excess := length - maxLen
+	if excess > maxTrimBatch {
+		excess = maxTrimBatch
+	}
+
+	// The first entry kept follows the entries in excess.
+	entries, err := redis.Values(conn.Do("XRANGE", streamName, scan.MinID, scan.MaxID, "COUNT", excess+1))
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	entry, err := redis.Values(entries[len(entries)-1], nil)
+	if err != nil || len(entry) == 0 {
+		return 0, errors.New("unexpected XRANGE reply")
+	}
+	first, err := redis.String(entry[0], nil)
+	if err != nil {
+		return 0, err
+	}
+	cut, err := scan.ParseStreamID(first)
+	if err != nil {
+		return 0, err
+	}
+
+	needed, err := firstNeededID(conn, streamName)
+	if err != nil {
+		return 0, err
+	}
+	if needed != nil && needed.Less(cut) {
+		cut = *needed
+	}
+
+	threshold := "~"
+	if exact {
+		threshold = "="
+	}
+	return redis.Int64(conn.Do("XTRIM", streamName, "MINID", threshold, cut.String()))
+}
+
+// firstNeededID returns the ID of the first entry still needed by a consumer
+// group of the stream, the first one pending or after the last delivered one,
+// or nil when the stream has no group.
+func firstNeededID(conn redis.Conn, streamName string) (*scan.StreamID, error) {
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
+	if err != nil {
+		return nil, err
+	}
+	var first *scan.StreamID
+	for name, group := range groups {
+		last, err := scan.ParseStreamID(group.LastDeliveredId)
+		if err != nil {
+			return nil, err
+		}
+		needed := scan.StreamID{Ms: last.Ms, Seq: last.Seq + 1}
+		if group.Pending > 0 {
+			// The summary form replies with the smallest pending ID second.
+			summary, err := redis.Values(conn.Do("XPENDING", streamName, name))
+			if err != nil {
+				return nil, err
+			}
+			if len(summary) < 2 {
+				return nil, errors.New("unexpected XPENDING reply")
+			}
+			if summary[1] != nil {
+				smallest, err := redis.String(summary[1], nil)
+				if err != nil {
+					return nil, err
+				}
+				if needed, err = scan.ParseStreamID(smallest); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if first == nil || needed.Less(*first) {
+			n := needed
+			first = &n
+		}
+	}
+	return first, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"knative.dev/pkg/logging"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// trimJobComponent is the component reporting the events of the trim job.
+const trimJobComponent = "redis-stream-trim-job"
+
+// RunTrimJob trims the streams of the source once, following its trimming
+// policy, connecting to Redis the same way the receive adapter does. It is run
+// by the CronJob of the source, and reports the entries trimmed in a
+// StreamTrimmed event of the source.
+func RunTrimJob(ctx context.Context, config *Config, events typedcorev1.EventsGetter) error {
+	a := &Adapter{config: config, logger: logging.FromContext(ctx).Desugar()}
+	if err := a.useRedisTLS(); err != nil {
+		return fmt.Errorf("invalid Redis TLS configuration: %w", err)
+	}
+	if err := a.usePasswordFile(); err != nil {
+		return fmt.Errorf("cannot read the Redis password file: %w", err)
+	}
+	if _, err := a.useOIDCPassword(ctx, &http.Client{}); err != nil {
+		return fmt.Errorf("cannot exchange the OIDC token for the Redis password: %w", err)
+	}
+	pool := a.newPool(config.Address)
+	defer pool.Close()
+
+	streams := config.Streams
+	if len(streams) == 0 {
+		streams = []string{config.Stream}
+	}
+	var trimmed int64
+	for _, stream := range streams {
+		conn := pool.Get()
+		n, err := trimByPolicy(conn, stream, config.TrimStrategy, config.TrimThreshold, config.TrimApproximate)
+		conn.Close()
+		if err != nil {
+			return fmt.Errorf("cannot trim stream %q: %w", stream, err)
+		}
+		a.logger.Info("Trimmed the stream", zap.String("stream", stream), zap.Int64("count", n))
+		trimmed += n
+	}
+
+	if events != nil && config.SourceName != "" {
+		if err := a.reportTrimmed(ctx, events, streams, trimmed); err != nil {
+			a.logger.Warn("Cannot report the entries trimmed", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// trimByPolicy trims the stream with XTRIM, following the strategy and the
+// threshold of a trimming policy. It returns the number of entries removed.
+func trimByPolicy(conn redis.Conn, streamName, strategy, threshold string, approximate bool) (int64, error) {
+	modifier := "="
+	if approximate {
+		modifier = "~"
+	}
+	return redis.Int64(conn.Do("XTRIM", streamName, strategy, modifier, threshold))
+}
+
+// reportTrimmed creates the StreamTrimmed event of the source.
+func (a *Adapter) reportTrimmed(ctx context.Context, events typedcorev1.EventsGetter, streams []string, trimmed int64) error {
+	modifier := "="
+	if a.config.TrimApproximate {
+		modifier = "~"
+	}
+	now := metav1.NewTime(time.Now())
+	_, err := events.Events(a.config.Namespace).Create(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    a.config.Namespace,
+			GenerateName: a.config.SourceName + ".",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: sourcesv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "RedisStreamSource",
+			Namespace:  a.config.Namespace,
+			Name:       a.config.SourceName,
+			UID:        types.UID(a.config.SourceUID),
+		},
+		Reason:         "StreamTrimmed",
+		Message:        fmt.Sprintf("Trimmed %d entries of %s with XTRIM %s %s %s", trimmed, strings.Join(streams, ", "), a.config.TrimStrategy, modifier, a.config.TrimThreshold),
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: trimJobComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}, metav1.CreateOptions{})
+	return err
+}
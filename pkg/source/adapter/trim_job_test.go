@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTrimByPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		strategy    string
+		threshold   string
+		approximate bool
+		wantTrimmed []interface{}
+	}{{
+		name:        "max length",
+		strategy:    "MAXLEN",
+		threshold:   "1000",
+		wantTrimmed: []interface{}{"mystream", "MAXLEN", "=", "1000"},
+	}, {
+		name:        "approximate max length",
+		strategy:    "MAXLEN",
+		threshold:   "1000",
+		approximate: true,
+		wantTrimmed: []interface{}{"mystream", "MAXLEN", "~", "1000"},
+	}, {
+		name:        "minimum ID",
+		strategy:    "MINID",
+		threshold:   "1700000000000-0",
+		wantTrimmed: []interface{}{"mystream", "MINID", "=", "1700000000000-0"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &trimConn{}
+			n, err := trimByPolicy(conn, "mystream", test.strategy, test.threshold, test.approximate)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), n)
+			require.Equal(t, test.wantTrimmed, conn.trimmed)
+		})
+	}
+}
+
+func TestAdapter_ReportTrimmed(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	config := &Config{SourceName: "source", SourceUID: "1234", TrimStrategy: "MAXLEN", TrimThreshold: "1000", TrimApproximate: true}
+	config.Namespace = "ns"
+	a := &Adapter{config: config, logger: zap.NewNop()}
+
+	require.NoError(t, a.reportTrimmed(context.Background(), kubeClient.CoreV1(), []string{"mystream"}, 42))
+
+	events, err := kubeClient.CoreV1().Events("ns").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	event := events.Items[0]
+	require.Equal(t, "StreamTrimmed", event.Reason)
+	require.Equal(t, corev1.EventTypeNormal, event.Type)
+	require.Equal(t, "Trimmed 42 entries of mystream with XTRIM MAXLEN ~ 1000", event.Message)
+	require.Equal(t, corev1.ObjectReference{
+		APIVersion: "sources.knative.dev/v1alpha1",
+		Kind:       "RedisStreamSource",
+		Namespace:  "ns",
+		Name:       "source",
+		UID:        "1234",
+	}, event.InvolvedObject)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adapter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+)
+
+// trimConn holds a stream of entries 1-0 to <length>-0, read by groups whose
+// pending entries start at the given IDs, and records the XTRIM command.
+type trimConn struct {
+	redis.Conn
+	length  int64
+	groups  []trimGroup
+	trimmed []interface{}
+}
+
+type trimGroup struct {
+	name         string
+	lastID       string
+	firstPending string // empty without pending entries
+}
+
+func (c *trimConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	switch cmd {
+	case "XLEN":
+		return c.length, nil
+	case "XRANGE":
+		count := args[4].(int64)
+		var entries []interface{}
+		for i := int64(1); i <= count && i <= c.length; i++ {
+			entries = append(entries, []interface{}{[]byte(fmt.Sprintf("%d-0", i)), []interface{}{}})
+		}
+		return entries, nil
+	case "XINFO":
+		var groups []interface{}
+		for _, g := range c.groups {
+			pending := int64(0)
+			if g.firstPending != "" {
+				pending = 1
+			}
+			groups = append(groups, []interface{}{
+				[]byte("name"), []byte(g.name),
+				[]byte("consumers"), int64(1),
+				[]byte("pending"), pending,
+				[]byte("last-delivered-id"), []byte(g.lastID),
+			})
+		}
+		return groups, nil
+	case "XPENDING":
+		for _, g := range c.groups {
+			if g.name == args[1] {
+				return []interface{}{int64(1), []byte(g.firstPending), []byte(g.firstPending), nil}, nil
+			}
+		}
+	case "XTRIM":
+		c.trimmed = args
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("unexpected command %s", cmd)
+}
+
+func TestTrimStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		length      int64
+		exact       bool
+		groups      []trimGroup
+		wantTrimmed []interface{}
+	}{{
+		name:   "shorter than max length",
+		length: 10,
+		groups: []trimGroup{{name: "mygroup", lastID: "10-0"}},
+	}, {
+		name:        "consumed",
+		length:      15,
+		groups:      []trimGroup{{name: "mygroup", lastID: "15-0"}},
+		wantTrimmed: []interface{}{"mystream", "MINID", "~", "6-0"},
+	}, {
+		name:        "exact",
+		length:      15,
+		exact:       true,
+		groups:      []trimGroup{{name: "mygroup", lastID: "15-0"}},
+		wantTrimmed: []interface{}{"mystream", "MINID", "=", "6-0"},
+	}, {
+		name:   "pending for another group",
+		length: 15,
+		groups: []trimGroup{
+			{name: "mygroup", lastID: "15-0"},
+			{name: "other", lastID: "12-0", firstPending: "3-0"},
+		},
+		wantTrimmed: []interface{}{"mystream", "MINID", "~", "3-0"},
+	}, {
+		name:   "not delivered to another group",
+		length: 15,
+		groups: []trimGroup{
+			{name: "mygroup", lastID: "15-0"},
+			{name: "other", lastID: "2-0"},
+		},
+		wantTrimmed: []interface{}{"mystream", "MINID", "~", "2-1"},
+	}, {
+		name:        "long backlog",
+		length:      maxTrimBatch + 100,
+		groups:      []trimGroup{{name: "mygroup", lastID: fmt.Sprintf("%d-0", maxTrimBatch+100)}},
+		wantTrimmed: []interface{}{"mystream", "MINID", "~", fmt.Sprintf("%d-0", maxTrimBatch+1)},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &trimConn{length: tt.length, groups: tt.groups}
+			_, err := trimStream(conn, "mystream", 10, tt.exact)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTrimmed, conn.trimmed)
+		})
+	}
+}
+
+func TestTrimmer(t *testing.T) {
+	trims := &trimmer{}
+	require.False(t, trims.take())
+
+	trims.ack()
+	trims.ack()
+	require.True(t, trims.take())
+	require.False(t, trims.take(), "the acks are taken once")
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DeliveryWindow gates spec.deliveryWindow.
+	DeliveryWindow = "delivery-window"
+
+	// ProducerCallback gates spec.producerCallback.
+	ProducerCallback = "producer-callback"
+
+	// SinkContentEncoding gates spec.sinkContentEncoding.
+	SinkContentEncoding = "sink-content-encoding"
+
+	// KafkaBridge gates spec.kafkaBridge.
+	KafkaBridge = "kafka-bridge"
+)
+
+// Flag is a string value which can be either Enabled or Disabled.
+type Flag string
+
+const (
+	// Enabled allows sources to use an experimental feature.
+	Enabled Flag = "Enabled"
+	// Disabled rejects sources using an experimental feature.
+	Disabled Flag = "Disabled"
+)
+
+// Flags is a map containing the enabled/disabled flags of the experimental features.
+// Experimental features are enabled unless explicitly disabled, so that sources
+// using them keep working until an operator opts out.
+type Flags map[string]Flag
+
+// IsEnabled returns true if the feature is not disabled.
+func (f Flags) IsEnabled(featureName string) bool {
+	return f[featureName] != Disabled
+}
+
+// NewFlagsConfigFromMap creates a Flags from the supplied map.
+func NewFlagsConfigFromMap(data map[string]string) (Flags, error) {
+	flags := Flags{}
+	for k, v := range data {
+		if strings.HasPrefix(k, "_") {
+			// Ignore all the keys starting with _
+			continue
+		}
+		switch {
+		case strings.EqualFold(v, string(Enabled)):
+			flags[strings.TrimSpace(k)] = Enabled
+		case strings.EqualFold(v, string(Disabled)):
+			flags[strings.TrimSpace(k)] = Disabled
+		default:
+			return nil, fmt.Errorf("cannot parse the feature flag '%s' = '%s'", k, v)
+		}
+	}
+	return flags, nil
+}
+
+// NewFlagsConfigFromConfigMap creates a Flags from the supplied ConfigMap.
+func NewFlagsConfigFromConfigMap(config *corev1.ConfigMap) (Flags, error) {
+	return NewFlagsConfigFromMap(config.Data)
+}
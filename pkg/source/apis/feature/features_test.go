@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewFlagsConfigFromMap(t *testing.T) {
+	flags, err := NewFlagsConfigFromMap(map[string]string{
+		"_example":          "ignored",
+		DeliveryWindow:      "disabled",
+		ProducerCallback:    "Enabled",
+		SinkContentEncoding: "Disabled",
+	})
+	if err != nil {
+		t.Fatal("NewFlagsConfigFromMap() =", err)
+	}
+
+	if flags.IsEnabled(DeliveryWindow) {
+		t.Errorf("%s is enabled, want disabled", DeliveryWindow)
+	}
+	if !flags.IsEnabled(ProducerCallback) {
+		t.Errorf("%s is disabled, want enabled", ProducerCallback)
+	}
+	if flags.IsEnabled(SinkContentEncoding) {
+		t.Errorf("%s is enabled, want disabled", SinkContentEncoding)
+	}
+	if !flags.IsEnabled("unknown") {
+		t.Error("features missing from the config must be enabled")
+	}
+
+	if _, err := NewFlagsConfigFromMap(map[string]string{DeliveryWindow: "maybe"}); err == nil {
+		t.Error("NewFlagsConfigFromMap() = nil, want error for an invalid value")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if !FromContext(context.Background()).IsEnabled(ProducerCallback) {
+		t.Error("features must be enabled without flags in the context")
+	}
+
+	ctx := ToContext(context.Background(), Flags{ProducerCallback: Disabled})
+	if FromContext(ctx).IsEnabled(ProducerCallback) {
+		t.Errorf("%s is enabled, want disabled", ProducerCallback)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"context"
+
+	"knative.dev/pkg/configmap"
+)
+
+// FlagsConfigName is the name of the ConfigMap holding the experimental feature flags.
+const FlagsConfigName = "config-redis-features"
+
+type cfgKey struct{}
+
+// FromContext extracts the Flags from the provided context. All features are
+// enabled when no Flags are attached.
+func FromContext(ctx context.Context) Flags {
+	if flags, ok := ctx.Value(cfgKey{}).(Flags); ok {
+		return flags
+	}
+	return Flags{}
+}
+
+// ToContext attaches the provided Flags to the provided context.
+func ToContext(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, cfgKey{}, flags)
+}
+
+// Store is a typed wrapper around configmap.UntypedStore to handle the feature flags ConfigMap.
+type Store struct {
+	*configmap.UntypedStore
+}
+
+// NewStore creates a new store of Flags and optionally calls functions when the ConfigMap is updated.
+func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value interface{})) *Store {
+	return &Store{
+		UntypedStore: configmap.NewUntypedStore(
+			"redis-feature-flags",
+			logger,
+			configmap.Constructors{
+				FlagsConfigName: NewFlagsConfigFromConfigMap,
+			},
+			onAfterStore...,
+		),
+	}
+}
+
+// ToContext attaches the current Flags to the provided context.
+func (s *Store) ToContext(ctx context.Context) context.Context {
+	return ToContext(ctx, s.Load())
+}
+
+// Load returns the current Flags.
+func (s *Store) Load() Flags {
+	if flags, ok := s.UntypedLoad(FlagsConfigName).(Flags); ok {
+		return flags
+	}
+	return Flags{}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+)
+
+const timeOfDayLayout = "15:04"
+
+// parseTimeOfDay returns the offset from midnight of a HH:MM time of day.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse(timeOfDayLayout, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Location returns the time zone of the window.
+func (w *DeliveryWindow) Location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+// Contains reports whether now is within the window.
+func (w *DeliveryWindow) Contains(now time.Time) (bool, error) {
+	open, _, _, err := w.bounds(now)
+	return open, err
+}
+
+// Until returns how long to wait from now until the window is open, or
+// zero when now is within the window.
+func (w *DeliveryWindow) Until(now time.Time) (time.Duration, error) {
+	open, _, _, err := w.bounds(now)
+	if err != nil || open {
+		return 0, err
+	}
+	next, err := w.NextTransition(now)
+	if err != nil {
+		return 0, err
+	}
+	return next.Sub(now), nil
+}
+
+// NextTransition returns the next time the window opens or closes after now.
+func (w *DeliveryWindow) NextTransition(now time.Time) (time.Time, error) {
+	open, start, end, err := w.bounds(now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if open {
+		return end, nil
+	}
+	if now.Before(start) {
+		return start, nil
+	}
+	return start.AddDate(0, 0, 1), nil
+}
+
+// bounds reports whether now is within the window, along with the start and
+// end of the window occurrence relevant to now.
+func (w *DeliveryWindow) bounds(now time.Time) (bool, time.Time, time.Time, error) {
+	loc, err := w.Location()
+	if err != nil {
+		return false, time.Time{}, time.Time{}, err
+	}
+	from, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, err
+	}
+	to, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, err
+	}
+
+	now = now.In(loc)
+	// The start and end are times of the day on the wall clock, which are not
+	// the same durations after midnight on the days the clocks change.
+	at := func(offset time.Duration) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc)
+	}
+	start, end := at(from), at(to)
+
+	if from <= to {
+		return !now.Before(start) && now.Before(end), start, end, nil
+	}
+
+	// The window spans midnight.
+	if now.Before(end) {
+		// Still in the window opened the day before.
+		return true, start.AddDate(0, 0, -1), end, nil
+	}
+	if !now.Before(start) {
+		return true, start, end.AddDate(0, 0, 1), nil
+	}
+	return false, start, end, nil
+}
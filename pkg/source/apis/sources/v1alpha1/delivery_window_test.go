@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryWindow(t *testing.T) {
+	day := func(hour, min int) time.Time {
+		return time.Date(2020, 6, 15, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		window   DeliveryWindow
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{{
+		name:     "before business hours",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00"},
+		now:      day(8, 30),
+		wantOpen: false,
+		wantNext: day(9, 0),
+	}, {
+		name:     "during business hours",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00"},
+		now:      day(12, 0),
+		wantOpen: true,
+		wantNext: day(17, 0),
+	}, {
+		name:     "at window start",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00"},
+		now:      day(9, 0),
+		wantOpen: true,
+		wantNext: day(17, 0),
+	}, {
+		name:     "after business hours",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00"},
+		now:      day(17, 0),
+		wantOpen: false,
+		wantNext: day(9, 0).AddDate(0, 0, 1),
+	}, {
+		name:     "overnight window, before midnight",
+		window:   DeliveryWindow{Start: "22:00", End: "06:00"},
+		now:      day(23, 0),
+		wantOpen: true,
+		wantNext: day(6, 0).AddDate(0, 0, 1),
+	}, {
+		name:     "overnight window, after midnight",
+		window:   DeliveryWindow{Start: "22:00", End: "06:00"},
+		now:      day(2, 0),
+		wantOpen: true,
+		wantNext: day(6, 0),
+	}, {
+		name:     "overnight window, during the day",
+		window:   DeliveryWindow{Start: "22:00", End: "06:00"},
+		now:      day(12, 0),
+		wantOpen: false,
+		wantNext: day(22, 0),
+	}, {
+		name:     "timezone",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"},
+		now:      day(12, 0), // 08:00 in New York
+		wantOpen: false,
+		wantNext: day(13, 0),
+	}, {
+		name:     "timezone, clocks set forward",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"},
+		now:      time.Date(2020, 3, 8, 12, 0, 0, 0, time.UTC), // 08:00 EDT
+		wantOpen: false,
+		wantNext: time.Date(2020, 3, 8, 13, 0, 0, 0, time.UTC),
+	}, {
+		name:     "timezone, clocks set back",
+		window:   DeliveryWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"},
+		now:      time.Date(2020, 11, 1, 15, 0, 0, 0, time.UTC), // 10:00 EST
+		wantOpen: true,
+		wantNext: time.Date(2020, 11, 1, 22, 0, 0, 0, time.UTC),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			open, err := test.window.Contains(test.now)
+			if err != nil {
+				t.Fatalf("Contains() = %v", err)
+			}
+			if open != test.wantOpen {
+				t.Errorf("Contains() = %v, want %v", open, test.wantOpen)
+			}
+
+			next, err := test.window.NextTransition(test.now)
+			if err != nil {
+				t.Fatalf("NextTransition() = %v", err)
+			}
+			if !next.Equal(test.wantNext) {
+				t.Errorf("NextTransition() = %v, want %v", next, test.wantNext)
+			}
+
+			wait, err := test.window.Until(test.now)
+			if err != nil {
+				t.Fatalf("Until() = %v", err)
+			}
+			if open && wait != 0 {
+				t.Errorf("Until() = %v, want 0", wait)
+			}
+			if !open && wait != test.wantNext.Sub(test.now) {
+				t.Errorf("Until() = %v, want %v", wait, test.wantNext.Sub(test.now))
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "time"
+
+// DefaultFailureReportWindow is the duration over which failures are counted
+// when the failure report has no window.
+const DefaultFailureReportWindow = time.Minute
+
+// GetWindow returns the duration over which failures are counted.
+func (r *FailureReport) GetWindow() time.Duration {
+	if r.Window == nil {
+		return DefaultFailureReportWindow
+	}
+	return r.Window.Duration
+}
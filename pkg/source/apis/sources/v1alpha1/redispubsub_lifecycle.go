@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// RedisPubSubConditionReady has status True when the RedisPubSubSource is ready to send events.
+	RedisPubSubConditionReady = apis.ConditionReady
+
+	// RedisPubSubConditionSinkProvided has status True when the RedisPubSubSource has been configured with a sink target.
+	RedisPubSubConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// RedisPubSubConditionDeployed has status True when the RedisPubSubSource has had its statefulset created.
+	RedisPubSubConditionDeployed apis.ConditionType = "Deployed"
+
+	// RedisPubSubConditionDeadLetterSinkResolved has status True when the dead-letter sink of a
+	// RedisPubSubSource resolved to a URI, and False when it did not, in which case the sink is
+	// also marked as not provided. It is only set when a dead-letter sink is configured.
+	RedisPubSubConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
+)
+
+var redisPubSubCondSet = apis.NewLivingConditionSet(
+	RedisPubSubConditionSinkProvided,
+	RedisPubSubConditionDeployed,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*RedisPubSubSource) GetConditionSet() apis.ConditionSet {
+	return redisPubSubCondSet
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (s *RedisPubSubSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisPubSubSource")
+}
+
+// GetUntypedSpec returns the spec of the RedisPubSubSource.
+func (s *RedisPubSubSource) GetUntypedSpec() interface{} {
+	return s.Spec
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *RedisPubSubSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redisPubSubCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *RedisPubSubSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return redisPubSubCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *RedisPubSubSourceStatus) InitializeConditions() {
+	redisPubSubCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSink sets the condition that the source has a sink configured.
+func (s *RedisPubSubSourceStatus) MarkSink(uri *apis.URL) {
+	s.SinkURI = uri
+	if uri != nil {
+		redisPubSubCondSet.Manage(s).MarkTrue(RedisPubSubConditionSinkProvided)
+	} else {
+		redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty.")
+	}
+}
+
+// MarkNoSink sets the condition that the source does not have a sink configured.
+func (s *RedisPubSubSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// PropagateStatefulSetAvailability uses the availability of the provided StatefulSet to determine if
+// RedisPubSubConditionDeployed should be marked as true or false.
+func (s *RedisPubSubSourceStatus) PropagateStatefulSetAvailability(d *appsv1.StatefulSet) {
+	if d.Status.ReadyReplicas == *d.Spec.Replicas {
+		redisPubSubCondSet.Manage(s).MarkTrue(RedisPubSubConditionDeployed)
+	} else {
+		redisPubSubCondSet.Manage(s).MarkUnknown(RedisPubSubConditionDeployed, "StatefulSetUnavailable", "The StatefulSet '%s' is unavailable.", d.Name)
+	}
+}
+
+// MarkDeadLetterSinkResolved sets the condition that the dead-letter sink resolved to uri.
+func (s *RedisPubSubSourceStatus) MarkDeadLetterSinkResolved(uri *apis.URL) {
+	s.DeadLetterSinkURI = uri
+	redisPubSubCondSet.Manage(s).MarkTrue(RedisPubSubConditionDeadLetterSinkResolved)
+}
+
+// MarkDeadLetterSinkNotResolved sets the condition that the dead-letter sink cannot be resolved.
+func (s *RedisPubSubSourceStatus) MarkDeadLetterSinkNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	s.DeadLetterSinkURI = nil
+	redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
+// MarkNoDeadLetterSink removes the dead-letter sink condition and URI.
+func (s *RedisPubSubSourceStatus) MarkNoDeadLetterSink() {
+	s.DeadLetterSinkURI = nil
+	_ = redisPubSubCondSet.Manage(s).ClearCondition(RedisPubSubConditionDeadLetterSinkResolved)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *RedisPubSubSourceStatus) IsReady() bool {
+	return redisPubSubCondSet.Manage(s).IsHappy()
+}
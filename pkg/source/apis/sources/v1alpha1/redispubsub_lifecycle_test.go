@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var _ = duck.VerifyType(&RedisPubSubSource{}, &duckv1.Conditions{})
+
+func TestRedisPubSubSourceStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name string
+		s    func(s *RedisPubSubSourceStatus)
+		want bool
+	}{{
+		name: "initialized",
+		s:    func(s *RedisPubSubSourceStatus) {},
+	}, {
+		name: "mark sink",
+		s: func(s *RedisPubSubSourceStatus) {
+			s.MarkSink(apis.HTTP("example"))
+		},
+	}, {
+		name: "mark deployed",
+		s: func(s *RedisPubSubSourceStatus) {
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+		},
+	}, {
+		name: "mark sink and deployed",
+		s: func(s *RedisPubSubSourceStatus) {
+			s.MarkSink(apis.HTTP("example"))
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+		},
+		want: true,
+	}, {
+		name: "dead-letter sink not resolved",
+		s: func(s *RedisPubSubSourceStatus) {
+			s.MarkSink(apis.HTTP("example"))
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkDeadLetterSinkNotResolved("NotFound", "")
+			s.MarkNoSink("DeadLetterSinkNotFound", "")
+		},
+	}, {
+		name: "no sink",
+		s: func(s *RedisPubSubSourceStatus) {
+			s.MarkSink(nil)
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			test.s(s)
+			if got := s.IsReady(); got != test.want {
+				t.Errorf("IsReady() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedisPubSubSourceGetGroupVersionKind(t *testing.T) {
+	s := &RedisPubSubSource{}
+	if got, want := s.GetGroupVersionKind().Kind, "RedisPubSubSource"; got != want {
+		t.Errorf("GetGroupVersionKind().Kind = %q, want %q", got, want)
+	}
+	if got, want := s.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition = %v, want %v", got, want)
+	}
+}
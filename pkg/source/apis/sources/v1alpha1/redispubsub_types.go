@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// RedisPubSubSource is the Schema for the RedisPubSub API.
+type RedisPubSubSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisPubSubSourceSpec   `json:"spec,omitempty"`
+	Status RedisPubSubSourceStatus `json:"status,omitempty"`
+}
+
+var (
+	_ runtime.Object     = (*RedisPubSubSource)(nil)
+	_ kmeta.OwnerRefable = (*RedisPubSubSource)(nil)
+	_ apis.Validatable   = (*RedisPubSubSource)(nil)
+	_ apis.Defaultable   = (*RedisPubSubSource)(nil)
+	_ apis.HasSpec       = (*RedisPubSubSource)(nil)
+	_ duckv1.KRShaped    = (*RedisPubSubSource)(nil)
+)
+
+// RedisPubSubSourceSpec defines the desired state of the RedisPubSubSource.
+type RedisPubSubSourceSpec struct {
+	// inherits duck/v1 SourceSpec, which currently provides:
+	// * Sink - a reference to an object that will resolve to a domain name or
+	//   a URI directly to use as the sink.
+	// * CloudEventOverrides - defines overrides to control the output format
+	//   and modifications of the event sent to the sink.
+	duckv1.SourceSpec `json:",inline"`
+
+	// Address is the Redis TCP address
+	Address string `json:"address"`
+
+	// Channels are the names of the channels to subscribe to.
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// Patterns are the glob-style patterns of the channels to subscribe to,
+	// as with PSUBSCRIBE.
+	// +optional
+	Patterns []string `json:"patterns,omitempty"`
+
+	// DeadLetterSink, when set, receives the events the sink does not
+	// accept. Pub/Sub messages cannot be read again, so the events the
+	// dead-letter sink does not accept either are dropped.
+	// +optional
+	DeadLetterSink *duckv1.Destination `json:"deadLetterSink,omitempty"`
+}
+
+// RedisPubSubSourceStatus defines the observed state of RedisPubSubSource.
+type RedisPubSubSourceStatus struct {
+	// inherits duck/v1 SourceStatus, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last
+	//   processed by the controller.
+	// * Conditions - the latest available observations of a resource's current
+	//   state.
+	// * SinkURI - the current active sink URI that has been configured for the
+	//   Source.
+	duckv1.SourceStatus `json:",inline"`
+
+	// DeadLetterSinkURI is the resolved URI of the dead-letter sink, if any.
+	// +optional
+	DeadLetterSinkURI *apis.URL `json:"deadLetterSinkUri,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisPubSubSourceList contains a list of RedisPubSubSources.
+type RedisPubSubSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisPubSubSource `json:"items"`
+}
+
+// GetStatus retrieves the status of the RedisPubSubSource. Implements the KRShaped interface.
+func (p *RedisPubSubSource) GetStatus() *duckv1.Status {
+	return &p.Status.Status
+}
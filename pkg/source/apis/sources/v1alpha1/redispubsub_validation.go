@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the RedisPubSubSource. Status updates are not validated,
+// they leave the spec as is.
+func (s *RedisPubSubSource) Validate(ctx context.Context) *apis.FieldError {
+	if apis.IsInStatusUpdate(ctx) {
+		return nil
+	}
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate validates the RedisPubSubSourceSpec.
+func (s *RedisPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if s.Address == "" {
+		errs = errs.Also(apis.ErrMissingField("address"))
+	}
+
+	if len(s.Channels) == 0 && len(s.Patterns) == 0 {
+		errs = errs.Also(apis.ErrMissingOneOf("channels", "patterns"))
+	}
+	errs = errs.Also(validateSubscriptions(s.Channels, "channels"))
+	errs = errs.Also(validateSubscriptions(s.Patterns, "patterns"))
+
+	if s.DeadLetterSink != nil {
+		errs = errs.Also(s.DeadLetterSink.Validate(ctx).ViaField("deadLetterSink"))
+	}
+	return errs
+}
+
+// validateSubscriptions rejects the empty and repeated channels, or patterns,
+// in names, and those with a comma, which separates them in the environment of
+// the receive adapter.
+func validateSubscriptions(names []string, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case name == "":
+			errs = errs.Also(apis.ErrMissingField(path))
+		case strings.Contains(name, ","):
+			errs = errs.Also(apis.ErrInvalidValue(name, path, "must not contain a comma"))
+		case seen[name]:
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate %q", name), path))
+		}
+		seen[name] = true
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"knative.dev/pkg/apis"
+)
+
+func TestRedisPubSubSourceValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    RedisPubSubSourceSpec
+		wantErr bool
+	}{{
+		name:    "no channels or patterns",
+		spec:    RedisPubSubSourceSpec{Address: "redis://redis:6379"},
+		wantErr: true,
+	}, {
+		name:    "empty channels and patterns",
+		spec:    RedisPubSubSourceSpec{Address: "redis://redis:6379", Channels: []string{}, Patterns: []string{}},
+		wantErr: true,
+	}, {
+		name: "channels",
+		spec: RedisPubSubSourceSpec{Address: "redis://redis:6379", Channels: []string{"orders", "payments"}},
+	}, {
+		name: "patterns",
+		spec: RedisPubSubSourceSpec{Address: "redis://redis:6379", Patterns: []string{"orders.*"}},
+	}, {
+		name: "channels and patterns",
+		spec: RedisPubSubSourceSpec{Address: "redis://redis:6379", Channels: []string{"orders"}, Patterns: []string{"users.*"}},
+	}, {
+		name:    "no address",
+		spec:    RedisPubSubSourceSpec{Channels: []string{"orders"}},
+		wantErr: true,
+	}, {
+		name:    "empty channel",
+		spec:    RedisPubSubSourceSpec{Address: "redis://redis:6379", Channels: []string{"orders", ""}},
+		wantErr: true,
+	}, {
+		name:    "duplicate pattern",
+		spec:    RedisPubSubSourceSpec{Address: "redis://redis:6379", Patterns: []string{"users.*", "users.*"}},
+		wantErr: true,
+	}, {
+		name:    "channel with a comma",
+		spec:    RedisPubSubSourceSpec{Address: "redis://redis:6379", Channels: []string{"orders,payments"}},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := &RedisPubSubSource{Spec: test.spec}
+			err := source.Validate(context.Background()).Filter(apis.ErrorLevel)
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"path"
+	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the RedisAuth. A password is required, from a secret, a
+// file or OIDC, with or without a username, as Redis has no password-less ACL
+// authentication.
+func (a *RedisAuth) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	switch {
+	case a.OIDC != nil:
+		errs = a.OIDC.Validate(ctx).ViaField("oidc")
+		if a.PasswordSecretRef != nil {
+			errs = errs.Also(apis.ErrMultipleOneOf("oidc", "passwordSecretRef"))
+		}
+		if a.PasswordFile != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("oidc", "passwordFile"))
+		}
+	case a.PasswordSecretRef != nil && a.PasswordFile != "":
+		errs = apis.ErrMultipleOneOf("passwordSecretRef", "passwordFile")
+	case a.PasswordSecretRef != nil:
+		errs = validateSecretKeyRef(a.PasswordSecretRef, "passwordSecretRef")
+	case a.PasswordFile != "":
+		if !path.IsAbs(a.PasswordFile) {
+			errs = apis.ErrInvalidValue(a.PasswordFile, "passwordFile", "must be an absolute path")
+		}
+	default:
+		errs = apis.ErrMissingOneOf("passwordSecretRef", "passwordFile", "oidc")
+	}
+	if a.Username != "" && strings.IndexFunc(a.Username, unicode.IsSpace) >= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(a.Username, "username", "Redis ACL users cannot contain spaces"))
+	}
+	if a.UsernameSecretRef != nil {
+		if a.Username != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("username", "usernameSecretRef"))
+		}
+		errs = errs.Also(validateSecretKeyRef(a.UsernameSecretRef, "usernameSecretRef"))
+	}
+	return errs
+}
+
+// validateSecretKeyRef requires the secret key selector to name a secret and
+// a key.
+func validateSecretKeyRef(ref *corev1.SecretKeySelector, field string) *apis.FieldError {
+	if ref == nil {
+		return apis.ErrMissingField(field)
+	}
+	var errs *apis.FieldError
+	if ref.Name == "" {
+		errs = errs.Also(apis.ErrMissingField(field + ".name"))
+	}
+	if ref.Key == "" {
+		errs = errs.Also(apis.ErrMissingField(field + ".key"))
+	}
+	return errs
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DefaultMinReplicas is the minimum number of receive adapter replicas of
+	// autoscaled sources without one.
+	DefaultMinReplicas = 1
+
+	// DefaultLagThreshold is the lag each receive adapter replica of
+	// autoscaled sources without one is expected to keep up with.
+	DefaultLagThreshold = 100
+)
+
+// GetMinReplicas returns the minimum number of receive adapter replicas.
+func (a *Autoscaling) GetMinReplicas() int32 {
+	if a.MinReplicas == nil {
+		return DefaultMinReplicas
+	}
+	return *a.MinReplicas
+}
+
+// GetLagThreshold returns the lag each receive adapter replica is expected to
+// keep up with.
+func (a *Autoscaling) GetLagThreshold() int64 {
+	if a.LagThreshold == 0 {
+		return DefaultLagThreshold
+	}
+	return a.LagThreshold
+}
+
+// validateAutoscaling validates the replicas and lag threshold of an
+// autoscaled source, and that KEDA can read the lag of its consumer group.
+func (s *RedisStreamSourceSpec) validateAutoscaling() *apis.FieldError {
+	a := s.Autoscaling
+	if a == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if a.MaxReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(a.MaxReplicas, "autoscaling.maxReplicas", "must be at least 1"))
+	}
+	if min := a.GetMinReplicas(); min < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(min, "autoscaling.minReplicas", "must not be negative"))
+	} else if min > a.MaxReplicas {
+		errs = errs.Also(apis.ErrInvalidValue(min, "autoscaling.minReplicas", "must not exceed maxReplicas"))
+	}
+	if a.LagThreshold < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(a.LagThreshold, "autoscaling.lagThreshold", "must be positive"))
+	}
+
+	// Without a group, each replica reads with its own group.
+	if s.Group == "" {
+		errs = errs.Also(apis.ErrGeneric("autoscaling requires a group shared by the replicas", "autoscaling", "group"))
+	}
+	if s.ExclusiveConsumer {
+		errs = errs.Also(apis.ErrMultipleOneOf("autoscaling", "exclusiveConsumer"))
+	}
+	// KEDA reads the password from the environment of the receive adapter.
+	if s.Auth != nil && s.Auth.PasswordFile != "" {
+		errs = errs.Also(apis.ErrGeneric("autoscaling requires auth.passwordSecretRef instead of auth.passwordFile", "autoscaling", "auth.passwordFile"))
+	}
+	if s.GetOIDC() != nil {
+		errs = errs.Also(apis.ErrGeneric("autoscaling requires auth.passwordSecretRef instead of auth.oidc", "autoscaling", "auth.oidc"))
+	}
+	if u, err := url.Parse(s.Address); s.Auth == nil && err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			errs = errs.Also(apis.ErrGeneric("autoscaling requires auth instead of the credentials of the address", "autoscaling", "address"))
+		}
+	}
+	return errs
+}
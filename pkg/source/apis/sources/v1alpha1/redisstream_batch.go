@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// MaxBatchSize is the maximum number of entries delivered in a batch.
+	MaxBatchSize = 1000
+
+	// DefaultBatchLinger is how long a batch waits for more entries by default.
+	DefaultBatchLinger = time.Second
+
+	// MinBatchLinger is the shortest time a batch can wait for more entries.
+	// Shorter lingers deliver most batches partially filled under load,
+	// which defeats batching.
+	MinBatchLinger = 100 * time.Millisecond
+)
+
+// GetBatchLinger returns how long a batch waits for more entries before it
+// is delivered.
+func (s *RedisStreamSourceSpec) GetBatchLinger() time.Duration {
+	if s.BatchLinger == nil {
+		return DefaultBatchLinger
+	}
+	return s.BatchLinger.Duration
+}
+
+// validateBatch validates the batching options of the source. The entries of
+// a batch are delivered and acknowledged together, which excludes the options
+// delivering or acknowledging each event on its own.
+func (s *RedisStreamSourceSpec) validateBatch() *apis.FieldError {
+	var errs *apis.FieldError
+	if s.BatchSize < 0 || s.BatchSize > MaxBatchSize {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(s.BatchSize, 1, MaxBatchSize, "batchSize"))
+	}
+	if s.BatchMaxBytes < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.BatchMaxBytes, "batchMaxBytes", "must be positive"))
+	}
+	if s.BatchLinger != nil && s.BatchLinger.Duration < MinBatchLinger {
+		errs = errs.Also(apis.ErrInvalidValue(s.BatchLinger.Duration, "batchLinger", "must be at least "+MinBatchLinger.String()))
+	}
+
+	if s.BatchSize == 0 {
+		if s.BatchMaxBytes != 0 {
+			errs = errs.Also(apis.ErrGeneric("batchMaxBytes requires batchSize", "batchMaxBytes"))
+		}
+		if s.BatchLinger != nil {
+			errs = errs.Also(apis.ErrGeneric("batchLinger requires batchSize", "batchLinger"))
+		}
+		return errs
+	}
+
+	for _, f := range []struct {
+		field string
+		used  bool
+	}{
+		{field: "kafkaBridge", used: s.KafkaBridge != nil},
+		{field: "dedup", used: s.Dedup != nil},
+		{field: "sequenceCounter", used: s.SequenceCounter},
+		{field: "additionalSinks", used: len(s.AdditionalSinks) > 0},
+		{field: "conditionalRequests", used: s.ConditionalRequests},
+		{field: "disableAutoAck", used: s.DisableAutoAck},
+		{field: "deliveryDelay", used: s.DeliveryDelay != nil},
+	} {
+		if f.used {
+			errs = errs.Also(apis.ErrMultipleOneOf("batchSize", f.field))
+		}
+	}
+	return errs
+}
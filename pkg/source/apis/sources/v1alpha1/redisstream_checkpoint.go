@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"knative.dev/pkg/apis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// CursorsKey is the key of the cursor ConfigMap of a source holding the IDs of
+// the last entries acknowledged, checkpointed by the receive adapter: a JSON
+// object of the IDs by consumer group, by stream.
+const CursorsKey = "cursors"
+
+// Cursors are the IDs of the last entries acknowledged by consumer group, by
+// stream.
+type Cursors map[string]map[string]string
+
+// ParseCursors returns the cursors held by the data of a cursor ConfigMap.
+func ParseCursors(data map[string]string) (Cursors, error) {
+	cursors := Cursors{}
+	if doc, ok := data[CursorsKey]; ok && doc != "" {
+		if err := json.Unmarshal([]byte(doc), &cursors); err != nil {
+			return nil, err
+		}
+	}
+	return cursors, nil
+}
+
+// LastProcessedID returns the ID of the last entry of the stream acknowledged
+// by every consumer group reading it, or "" when none was checkpointed.
+func (c Cursors) LastProcessedID(stream string) string {
+	var last *scan.StreamID
+	for _, id := range c[stream] {
+		sid, err := scan.ParseStreamID(id)
+		if err != nil {
+			continue
+		}
+		if last == nil || sid.Less(*last) {
+			last = &sid
+		}
+	}
+	if last == nil {
+		return ""
+	}
+	return last.String()
+}
+
+// validateCheckpoint validates the checkpoint interval of the source.
+func (s *RedisStreamSourceSpec) validateCheckpoint() *apis.FieldError {
+	var errs *apis.FieldError
+	if s.CheckpointInterval < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.CheckpointInterval, "checkpointInterval", "must be positive"))
+	}
+	if s.CheckpointInterval != 0 && s.DisableAutoAck {
+		errs = errs.Also(apis.ErrMultipleOneOf("disableAutoAck", "checkpointInterval"))
+	}
+	return errs
+}
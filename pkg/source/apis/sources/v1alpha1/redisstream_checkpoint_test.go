@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestCursors_LastProcessedID(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    string
+		wantErr bool
+	}{{
+		name: "no cursors",
+	}, {
+		name: "empty",
+		data: map[string]string{CursorsKey: ""},
+	}, {
+		name: "one group",
+		data: map[string]string{CursorsKey: `{"mystream": {"mygroup": "1680000000000-1"}}`},
+		want: "1680000000000-1",
+	}, {
+		name: "lowest of the groups",
+		data: map[string]string{CursorsKey: `{"mystream": {"group-0": "1680000000000-1", "group-1": "1679999999999-7"}, "other": {"group-0": "1-0"}}`},
+		want: "1679999999999-7",
+	}, {
+		name: "other stream",
+		data: map[string]string{CursorsKey: `{"other": {"mygroup": "1-0"}}`},
+	}, {
+		name:    "invalid",
+		data:    map[string]string{CursorsKey: `["1-0"]`},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cursors, err := ParseCursors(test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseCursors() = %v, want error %v", err, test.wantErr)
+			}
+			if got := cursors.LastProcessedID("mystream"); got != test.want {
+				t.Errorf("LastProcessedID() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
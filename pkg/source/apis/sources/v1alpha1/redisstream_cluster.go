@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"knative.dev/pkg/apis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// Validate validates the RedisCluster.
+func (c *RedisCluster) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if len(c.Addresses) == 0 {
+		errs = errs.Also(apis.ErrMissingField("addresses"))
+	}
+	for i, address := range c.Addresses {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(address, "addresses", i))
+		}
+	}
+	return errs
+}
+
+// ClusterURL identifies the Redis cluster with the nodes at the given
+// addresses, e.g. in the source of the events.
+func ClusterURL(addresses []string) string {
+	return "redis+cluster://" + strings.Join(addresses, ",")
+}
+
+// validateClusterSlots rejects the keys used along with the stream that are
+// not in its hash slot, since the cluster node serving the stream cannot
+// serve them.
+func (s *RedisStreamSourceSpec) validateClusterSlots() *apis.FieldError {
+	stream := s.GetStream()
+	slot := scan.KeySlot(stream)
+	var errs *apis.FieldError
+	check := func(key, field string) {
+		if scan.KeySlot(key) != slot {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("key %q is not in the hash slot of stream %q, use a hash tag in the stream name, e.g. {%s}, so that they share it", key, stream, stream), field))
+		}
+	}
+	if s.Dedup != nil {
+		check(s.Dedup.GetKey(stream), "dedup.key")
+	}
+	if s.SequenceCounter {
+		check(SequenceKey(stream, s.Group), "sequenceCounter")
+	}
+	if s.DeadLettering() {
+		field := "deadLetterStream"
+		if s.DeadLetterStream == "" {
+			field = "onEmptyEntry"
+			if s.OnEmptyEntry != EmptyEntryDeadLetter {
+				field = "reclaim.maxDeliveryAttempts"
+			}
+		}
+		check(s.GetDeadLetterStream(), field)
+	}
+	return errs
+}
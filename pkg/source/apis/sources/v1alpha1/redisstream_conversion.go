@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+// ConvertTo implements apis.Convertible. v1alpha1 is the version
+// RedisStreamSources are stored as, v1beta1 converts to and from it.
+func (source *RedisStreamSource) ConvertTo(_ context.Context, sink apis.Convertible) error {
+	return fmt.Errorf("v1alpha1 does not convert to other versions, v1beta1 converts from it, got: %T", sink)
+}
+
+// ConvertFrom implements apis.Convertible. v1alpha1 is the version
+// RedisStreamSources are stored as, v1beta1 converts to and from it.
+func (sink *RedisStreamSource) ConvertFrom(_ context.Context, source apis.Convertible) error {
+	return fmt.Errorf("v1alpha1 does not convert from other versions, v1beta1 converts to it, got: %T", source)
+}
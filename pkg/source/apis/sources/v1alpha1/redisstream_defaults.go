@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// DefaultReadCount is how many entries each consumer reads at once when the
+// source sets neither ReadCount nor BatchSize.
+const DefaultReadCount int32 = 10
+
+// SetDefaults implements apis.Defaultable. The defaults are only applied
+// when the source is created. On update, the fields left empty keep their
+// previous value instead, so that editing a source created without them
+// never switches it to another consumer group or read count.
+func (s *RedisStreamSource) SetDefaults(ctx context.Context) {
+	switch {
+	case apis.IsInCreate(ctx):
+		s.Spec.SetDefaults(apis.WithinParent(ctx, s.ObjectMeta))
+	case apis.IsInUpdate(ctx):
+		if base, ok := apis.GetBaseline(ctx).(*RedisStreamSource); ok && base != nil {
+			s.Spec.carryOver(&base.Spec)
+		}
+	}
+}
+
+// SetDefaults defaults the consumer group to one named after the source, the
+// consumer groups to start reading after the entries added afterwards only,
+// and the number of entries read at once to DefaultReadCount. The fields set
+// explicitly are kept. The deprecated StartFrom and TrimStrategy are moved to
+// StartID and Trimming.
+func (s *RedisStreamSourceSpec) SetDefaults(ctx context.Context) {
+	if s.Group == "" {
+		s.Group = defaultGroup(apis.ParentMeta(ctx).Namespace, apis.ParentMeta(ctx).Name, s.NamespaceGroup)
+	}
+	s.moveStartFrom()
+	if s.StartID == "" && s.StartFrom == "" {
+		s.StartID = scan.LastID
+	}
+	s.moveTrimStrategy()
+	if s.ReadCount == 0 && s.BatchSize == 0 {
+		s.ReadCount = DefaultReadCount
+	}
+}
+
+// carryOver sets the fields of s left empty to their value in base, the
+// spec before the update.
+func (s *RedisStreamSourceSpec) carryOver(base *RedisStreamSourceSpec) {
+	if s.Group == "" {
+		s.Group = base.Group
+	}
+	s.moveStartFrom()
+	if s.StartID == "" && s.StartFrom == "" {
+		s.StartID = base.StartID
+	}
+	s.moveTrimStrategy()
+	if s.ReadCount == 0 && s.BatchSize == 0 {
+		s.ReadCount = base.ReadCount
+	}
+}
+
+// defaultGroup returns the consumer group of the source with the given
+// namespace and name, <namespace>/<name>, or <name> only when namespaceGroup
+// already prefixes it with the namespace. It is empty when the name of the
+// source is not known yet, e.g. while it is generated.
+func defaultGroup(namespace, name string, namespaceGroup bool) string {
+	switch {
+	case name == "":
+		return ""
+	case namespaceGroup:
+		return name
+	default:
+		return namespace + "/" + name
+	}
+}
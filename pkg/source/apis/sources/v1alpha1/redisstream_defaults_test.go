@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestRedisStreamSourceSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		spec RedisStreamSourceSpec
+		want RedisStreamSourceSpec
+	}{{
+		name: "defaults",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", ReadCount: DefaultReadCount},
+	}, {
+		name: "namespace group",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{NamespaceGroup: true},
+		want: RedisStreamSourceSpec{Group: "mysource", NamespaceGroup: true, StartID: "$", ReadCount: DefaultReadCount},
+	}, {
+		name: "generated name",
+		meta: metav1.ObjectMeta{Namespace: "default", GenerateName: "mysource-"},
+		want: RedisStreamSourceSpec{StartID: "$", ReadCount: DefaultReadCount},
+	}, {
+		name: "group set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{Group: "mygroup"},
+		want: RedisStreamSourceSpec{Group: "mygroup", StartID: "$", ReadCount: DefaultReadCount},
+	}, {
+		name: "start ID set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{StartID: "0"},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "0", ReadCount: DefaultReadCount},
+	}, {
+		name: "start from set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{StartFrom: StartFromEarliest},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: StartFromEarliest, ReadCount: DefaultReadCount},
+	}, {
+		name: "start from set on a defaulted source",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", StartFrom: StartFromEarliest, ReadCount: DefaultReadCount},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: StartFromEarliest, ReadCount: DefaultReadCount},
+	}, {
+		name: "start from set with start ID",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{Group: "default/mysource", StartID: "0", StartFrom: StartFromEarliest, ReadCount: DefaultReadCount},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "0", StartFrom: StartFromEarliest, ReadCount: DefaultReadCount},
+	}, {
+		name: "trim strategy set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{TrimStrategy: &TrimStrategy{MaxLen: 1000, Exact: true}},
+		want: RedisStreamSourceSpec{
+			Group:     "default/mysource",
+			StartID:   "$",
+			ReadCount: DefaultReadCount,
+			Trimming:  &Trimming{Strategy: TrimmingMaxLen, Threshold: "1000", OnAck: true},
+		},
+	}, {
+		name: "read count set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{ReadCount: 1},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", ReadCount: 1},
+	}, {
+		name: "batch size set",
+		meta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"},
+		spec: RedisStreamSourceSpec{BatchSize: 100},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", BatchSize: 100},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := &RedisStreamSource{ObjectMeta: test.meta, Spec: test.spec}
+			source.SetDefaults(apis.WithinCreate(context.Background()))
+			if diff := cmp.Diff(test.want, source.Spec); diff != "" {
+				t.Error("SetDefaults (-want, +got) =", diff)
+			}
+
+			// Defaulting twice changes nothing.
+			defaulted := source.DeepCopy()
+			defaulted.SetDefaults(apis.WithinCreate(context.Background()))
+			if diff := cmp.Diff(source.Spec, defaulted.Spec); diff != "" {
+				t.Error("SetDefaults again (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceSetDefaultsOnUpdate(t *testing.T) {
+	meta := metav1.ObjectMeta{Namespace: "default", Name: "mysource"}
+
+	tests := []struct {
+		name string
+		base RedisStreamSourceSpec
+		spec RedisStreamSourceSpec
+		want RedisStreamSourceSpec
+	}{{
+		name: "created without defaults",
+		base: RedisStreamSourceSpec{RedisConnection: RedisConnection{Address: "redis:6379"}},
+		spec: RedisStreamSourceSpec{RedisConnection: RedisConnection{Address: "redis:6380"}},
+		want: RedisStreamSourceSpec{RedisConnection: RedisConnection{Address: "redis:6380"}},
+	}, {
+		name: "defaults kept",
+		base: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", ReadCount: DefaultReadCount},
+		want: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", ReadCount: DefaultReadCount},
+	}, {
+		name: "explicit values kept",
+		base: RedisStreamSourceSpec{Group: "mygroup", StartID: "0", ReadCount: 50},
+		want: RedisStreamSourceSpec{Group: "mygroup", StartID: "0", ReadCount: 50},
+	}, {
+		name: "new values",
+		base: RedisStreamSourceSpec{Group: "default/mysource", StartID: "$", ReadCount: DefaultReadCount},
+		spec: RedisStreamSourceSpec{Group: "mygroup", StartID: "0", ReadCount: 50},
+		want: RedisStreamSourceSpec{Group: "mygroup", StartID: "0", ReadCount: 50},
+	}, {
+		name: "start from set",
+		base: RedisStreamSourceSpec{StartID: "$"},
+		spec: RedisStreamSourceSpec{StartID: "$", StartFrom: StartFromEarliest},
+		want: RedisStreamSourceSpec{StartID: StartFromEarliest},
+	}, {
+		name: "batch size set",
+		base: RedisStreamSourceSpec{ReadCount: DefaultReadCount},
+		spec: RedisStreamSourceSpec{BatchSize: 100},
+		want: RedisStreamSourceSpec{BatchSize: 100},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base := &RedisStreamSource{ObjectMeta: meta, Spec: test.base}
+			source := &RedisStreamSource{ObjectMeta: meta, Spec: test.spec}
+			source.SetDefaults(apis.WithinUpdate(context.Background(), base))
+			if diff := cmp.Diff(test.want, source.Spec); diff != "" {
+				t.Error("SetDefaults (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceSetDefaultsOutsideWebhook(t *testing.T) {
+	source := &RedisStreamSource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mysource"}}
+	source.SetDefaults(context.Background())
+	if diff := cmp.Diff(RedisStreamSourceSpec{}, source.Spec); diff != "" {
+		t.Error("SetDefaults (-want, +got) =", diff)
+	}
+}
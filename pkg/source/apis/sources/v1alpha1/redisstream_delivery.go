@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	"github.com/rickb777/date/period"
+	eventingfeature "knative.dev/eventing/pkg/apis/feature"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// GetDeadLetterSink returns the dead-letter sink of the source, set either by
+// DeadLetterSink or by Delivery, if any.
+func (s *RedisStreamSourceSpec) GetDeadLetterSink() *duckv1.Destination {
+	if s.DeadLetterSink != nil {
+		return s.DeadLetterSink
+	}
+	if s.Delivery != nil {
+		return s.Delivery.DeadLetterSink
+	}
+	return nil
+}
+
+// validateDelivery validates the delivery options of the source.
+func (s *RedisStreamSourceSpec) validateDelivery(ctx context.Context) *apis.FieldError {
+	if s.Delivery == nil {
+		return nil
+	}
+	// The receive adapter implements the timeout of each request, which
+	// Knative Eventing enables with a feature flag.
+	ctx = eventingfeature.ToContext(ctx, eventingfeature.Flags{eventingfeature.DeliveryTimeout: eventingfeature.Enabled})
+	errs := s.Delivery.Validate(ctx).ViaField("delivery")
+	if s.DeadLetterSink != nil && s.Delivery.DeadLetterSink != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("deadLetterSink", "delivery.deadLetterSink"))
+	}
+	return errs
+}
+
+// DeliveryDuration returns the duration of an ISO 8601 delivery option, such
+// as Delivery.BackoffDelay, which is zero when not set.
+func DeliveryDuration(iso *string) time.Duration {
+	if iso == nil {
+		return 0
+	}
+	p, err := period.Parse(*iso) // validated
+	if err != nil {
+		return 0
+	}
+	return p.DurationApprox()
+}
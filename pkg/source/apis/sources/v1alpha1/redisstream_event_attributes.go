@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// The variables replaced in EventSource.
+const (
+	// EventSourceStreamKeyVariable is replaced by the key of the stream the
+	// entry is read from.
+	EventSourceStreamKeyVariable = "{streamKey}"
+	// EventSourceNamespaceVariable is replaced by the namespace of the source.
+	EventSourceNamespaceVariable = "{namespace}"
+	// EventSourceNameVariable is replaced by the name of the source.
+	EventSourceNameVariable = "{name}"
+)
+
+// ExpandEventSource returns the event source with its variables replaced by
+// the stream key, namespace and name.
+func ExpandEventSource(eventSource, streamKey, namespace, name string) string {
+	return strings.NewReplacer(
+		EventSourceStreamKeyVariable, streamKey,
+		EventSourceNamespaceVariable, namespace,
+		EventSourceNameVariable, name,
+	).Replace(eventSource)
+}
+
+// validateEventAttributes rejects blank event types and sources, sources that
+// are not URI-references once their variables are replaced, and CloudEvents
+// overrides with invalid extension names.
+func (s *RedisStreamSourceSpec) validateEventAttributes(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if s.Type != "" && strings.TrimSpace(s.Type) == "" {
+		errs = errs.Also(apis.ErrInvalidValue(s.Type, "type", "must not be blank"))
+	}
+	if s.TypeFieldName != "" && strings.TrimSpace(s.TypeFieldName) == "" {
+		errs = errs.Also(apis.ErrInvalidValue(s.TypeFieldName, "typeFieldName", "must not be blank"))
+	}
+	if s.EventSource != "" {
+		if strings.TrimSpace(s.EventSource) == "" {
+			errs = errs.Also(apis.ErrInvalidValue(s.EventSource, "eventSource", "must not be blank"))
+		} else if _, err := url.Parse(ExpandEventSource(s.EventSource, "stream", "namespace", "name")); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.EventSource, "eventSource", "must be a URI-reference"))
+		}
+	}
+	return errs.Also(s.CloudEventOverrides.Validate(ctx).ViaField("ceOverrides"))
+}
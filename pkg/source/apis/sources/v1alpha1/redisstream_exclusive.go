@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// DefaultLeaseDuration is how long the lease of an exclusive consumer
+	// lasts without being renewed by default.
+	DefaultLeaseDuration = 15 * time.Second
+
+	// MinLeaseDuration is the shortest lease duration, which the receive
+	// adapter renews several times within.
+	MinLeaseDuration = time.Second
+)
+
+// GetLeaseDuration returns how long the lease of an exclusive consumer lasts
+// without being renewed.
+func (s *RedisStreamSourceSpec) GetLeaseDuration() time.Duration {
+	if s.LeaseDuration == nil {
+		return DefaultLeaseDuration
+	}
+	return s.LeaseDuration.Duration
+}
+
+// validateExclusiveConsumer validates that an exclusive consumer runs a
+// single receive adapter pod.
+func (s *RedisStreamSourceSpec) validateExclusiveConsumer() *apis.FieldError {
+	var errs *apis.FieldError
+	if !s.ExclusiveConsumer {
+		if s.LeaseDuration != nil {
+			errs = errs.Also(apis.ErrGeneric("leaseDuration requires exclusiveConsumer", "leaseDuration"))
+		}
+		return errs
+	}
+	if s.Consumers != nil && *s.Consumers > 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.Consumers, "consumers", "must be at most 1 with exclusiveConsumer"))
+	}
+	if s.Replicas != nil && *s.Replicas > 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.Replicas, "replicas", "must be at most 1 with exclusiveConsumer"))
+	}
+	if s.LeaseDuration != nil && s.LeaseDuration.Duration < MinLeaseDuration {
+		errs = errs.Also(apis.ErrInvalidValue(s.LeaseDuration.Duration, "leaseDuration", "must be at least 1s"))
+	}
+	return errs
+}
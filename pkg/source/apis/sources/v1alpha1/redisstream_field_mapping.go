@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"knative.dev/pkg/apis"
+)
+
+// extensionNameRegexp matches the names of CloudEvents attributes: lower-case
+// letters and digits, no longer than the 20 characters CloudEvents recommends.
+var extensionNameRegexp = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+// contextAttributes are the names of the attributes defined by the CloudEvents
+// specification, which the fields of the entries cannot be mapped to.
+var contextAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"subject":         true,
+	"time":            true,
+	"data":            true,
+}
+
+// validateFieldMapping rejects the extension names that are not valid
+// CloudEvents attribute names, fields mapped to the same extension, and
+// required fields that are not mapped.
+func (s *RedisStreamSourceSpec) validateFieldMapping() *apis.FieldError {
+	fields := make([]string, 0, len(s.FieldMapping))
+	for field := range s.FieldMapping {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields) // report duplicates on the same field every time
+
+	var errs *apis.FieldError
+	mapped := make(map[string]string, len(fields))
+	for _, field := range fields {
+		extension := s.FieldMapping[field]
+		switch {
+		case field == "":
+			errs = errs.Also(apis.ErrInvalidKeyName(field, "fieldMapping", "must not be empty"))
+		case !extensionNameRegexp.MatchString(extension):
+			errs = errs.Also(apis.ErrInvalidValue(extension, apis.CurrentField, "must be a CloudEvents attribute name of up to 20 lower-case letters or digits").ViaKey(field).ViaField("fieldMapping"))
+		case contextAttributes[extension]:
+			errs = errs.Also(apis.ErrInvalidValue(extension, apis.CurrentField, "must not be a CloudEvents context attribute").ViaKey(field).ViaField("fieldMapping"))
+		case mapped[extension] != "":
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("field %q is already mapped to extension %q", mapped[extension], extension)).ViaKey(field).ViaField("fieldMapping"))
+		}
+		mapped[extension] = field
+	}
+
+	required := make(map[string]bool, len(s.RequiredFields))
+	for i, field := range s.RequiredFields {
+		if _, ok := s.FieldMapping[field]; !ok {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("field %q is not mapped by fieldMapping", field)).ViaFieldIndex("requiredFields", i))
+		} else if required[field] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate %q", field)).ViaFieldIndex("requiredFields", i))
+		}
+		required[field] = true
+	}
+
+	switch s.OnMissingField {
+	case "", MissingFieldDrop:
+	case MissingFieldDeadLetter:
+		if s.DeadLetterSink == nil {
+			errs = errs.Also(apis.ErrGeneric("onMissingField DeadLetter requires a deadLetterSink", "onMissingField", "deadLetterSink"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.OnMissingField, "onMissingField"))
+	}
+	return errs
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// SourceLister returns the RedisStreamSources of all namespaces.
+type SourceLister func(ctx context.Context) ([]*RedisStreamSource, error)
+
+type sourceListerKey struct{}
+
+// WithSourceLister returns a context with which the sources are checked for
+// consumer group collisions, with the sources listed by lister, when
+// validating them.
+func WithSourceLister(ctx context.Context, lister SourceLister) context.Context {
+	return context.WithValue(ctx, sourceListerKey{}, lister)
+}
+
+func sourceListerFromContext(ctx context.Context) SourceLister {
+	lister, _ := ctx.Value(sourceListerKey{}).(SourceLister)
+	return lister
+}
+
+// ConsumerGroup returns the name of the consumer group read by the source,
+// prefixed with its namespace when NamespaceGroup is set. It is empty when
+// the group is automatically created by the receive adapter.
+func (s *RedisStreamSource) ConsumerGroup() string {
+	if s.Spec.Group == "" {
+		return ""
+	}
+	if s.Spec.NamespaceGroup {
+		return s.Namespace + "." + s.Spec.Group
+	}
+	return s.Spec.Group
+}
+
+// GroupCollisions returns the sources, among the given ones, from other
+// namespaces that read the same stream of the same Redis instance with the
+// same consumer group as s.
+func (s *RedisStreamSource) GroupCollisions(sources []*RedisStreamSource) []*RedisStreamSource {
+	group := s.ConsumerGroup()
+	if group == "" {
+		return nil
+	}
+
+	var collisions []*RedisStreamSource
+	for _, other := range sources {
+		if other.Namespace == s.Namespace || other.DeletionTimestamp != nil {
+			continue
+		}
+		if other.Spec.Endpoint() == s.Spec.Endpoint() && other.Spec.GetStream() == s.Spec.GetStream() && other.ConsumerGroup() == group {
+			collisions = append(collisions, other)
+		}
+	}
+	return collisions
+}
+
+// validateGroupCollision warns when sources in other namespaces read the same
+// stream with the same consumer group as s.
+func (s *RedisStreamSource) validateGroupCollision(ctx context.Context) *apis.FieldError {
+	lister := sourceListerFromContext(ctx)
+	if lister == nil {
+		return nil
+	}
+
+	sources, err := lister(ctx)
+	if err != nil {
+		return apis.ErrGeneric(fmt.Sprintf("cannot check the sources sharing the consumer group: %v", err), "group").At(apis.WarningLevel)
+	}
+	collisions := s.GroupCollisions(sources)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(collisions))
+	for _, other := range collisions {
+		names = append(names, other.Namespace+"/"+other.Name)
+	}
+	sort.Strings(names)
+	return apis.ErrGeneric(fmt.Sprintf("consumer group is shared with %s, entries are split between them; set namespaceGroup to use a group per namespace", strings.Join(names, ", ")), "group").At(apis.WarningLevel)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func groupSource(namespace, name, stream, group string, namespaceGroup bool) *RedisStreamSource {
+	return &RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc:6379"},
+			Stream:          stream,
+			Group:           group,
+			NamespaceGroup:  namespaceGroup,
+		},
+	}
+}
+
+func sentinelSource(namespace, name, masterName string) *RedisStreamSource {
+	source := groupSource(namespace, name, "mystream", "mygroup", false)
+	source.Spec.RedisConnection = RedisConnection{
+		Sentinel: &RedisSentinel{MasterName: masterName, Addresses: []string{"sentinel.redis.svc:26379"}},
+	}
+	return source
+}
+
+func TestRedisStreamSourceConsumerGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		source *RedisStreamSource
+		want   string
+	}{{
+		name:   "automatic group",
+		source: groupSource("ns1", "s", "mystream", "", true),
+		want:   "",
+	}, {
+		name:   "group",
+		source: groupSource("ns1", "s", "mystream", "mygroup", false),
+		want:   "mygroup",
+	}, {
+		name:   "namespaced group",
+		source: groupSource("ns1", "s", "mystream", "mygroup", true),
+		want:   "ns1.mygroup",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.source.ConsumerGroup(); got != test.want {
+				t.Errorf("ConsumerGroup() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceGroupCollisions(t *testing.T) {
+	source := groupSource("ns1", "s", "mystream", "mygroup", false)
+	deleted := groupSource("ns4", "deleted", "mystream", "mygroup", false)
+	deleted.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name    string
+		source  *RedisStreamSource
+		sources []*RedisStreamSource
+		want    []string
+	}{{
+		name:   "collision across namespaces",
+		source: source,
+		sources: []*RedisStreamSource{
+			source,
+			groupSource("ns1", "same-namespace", "mystream", "mygroup", false),
+			groupSource("ns2", "other-stream", "otherstream", "mygroup", false),
+			groupSource("ns2", "other-group", "mystream", "othergroup", false),
+			groupSource("ns2", "namespaced", "mystream", "mygroup", true),
+			groupSource("ns3", "collides", "mystream", "mygroup", false),
+			deleted,
+		},
+		want: []string{"ns3/collides"},
+	}, {
+		name:   "namespaced group",
+		source: groupSource("ns1", "s", "mystream", "mygroup", true),
+		sources: []*RedisStreamSource{
+			groupSource("ns2", "other", "mystream", "mygroup", false),
+			groupSource("ns2", "namespaced", "mystream", "mygroup", true),
+		},
+	}, {
+		name:   "automatic group",
+		source: groupSource("ns1", "s", "mystream", "", false),
+		sources: []*RedisStreamSource{
+			groupSource("ns2", "other", "mystream", "", false),
+		},
+	}, {
+		name:   "sentinel",
+		source: sentinelSource("ns1", "s", "mymaster"),
+		sources: []*RedisStreamSource{
+			groupSource("ns2", "address", "mystream", "mygroup", false),
+			sentinelSource("ns2", "other-master", "othermaster"),
+			sentinelSource("ns3", "collides", "mymaster"),
+		},
+		want: []string{"ns3/collides"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, other := range test.source.GroupCollisions(test.sources) {
+				got = append(got, other.Namespace+"/"+other.Name)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("GroupCollisions() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("GroupCollisions() = %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceStatusMarkGroupCollision(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+	s.PropagateStatefulSetAvailability(availableStatefulSet)
+
+	s.MarkGroupCollision([]string{"ns2/other"})
+	cond := s.GetCondition(RedisStreamConditionGroupCollision)
+	if cond == nil || cond.Status != corev1.ConditionTrue || cond.Severity != apis.ConditionSeverityWarning {
+		t.Errorf("GroupCollision condition = %v, want True with Warning severity", cond)
+	}
+	if !s.IsReady() {
+		t.Error("group collision must not affect readiness")
+	}
+
+	s.MarkNoGroupCollision()
+	if cond := s.GetCondition(RedisStreamConditionGroupCollision); cond != nil {
+		t.Errorf("GroupCollision condition = %v, want none", cond)
+	}
+}
+
+func TestRedisStreamSourceValidateGroupCollision(t *testing.T) {
+	sources := []*RedisStreamSource{
+		groupSource("ns2", "other", "mystream", "mygroup", false),
+		groupSource("ns3", "namespaced", "mystream", "mygroup", true),
+	}
+	ctx := WithSourceLister(context.Background(), func(context.Context) ([]*RedisStreamSource, error) {
+		return sources, nil
+	})
+
+	err := groupSource("ns1", "s", "mystream", "mygroup", false).validateGroupCollision(ctx)
+	if err == nil || err.Filter(apis.ErrorLevel) != nil || !strings.Contains(err.Error(), "ns2/other") {
+		t.Errorf("validateGroupCollision() = %v, want a warning about ns2/other", err)
+	}
+
+	if err := groupSource("ns1", "s", "mystream", "mygroup", true).validateGroupCollision(ctx); err != nil {
+		t.Errorf("validateGroupCollision() = %v, want no warning with a namespaced group", err)
+	}
+	if err := groupSource("ns1", "s", "mystream", "mygroup", false).validateGroupCollision(context.Background()); err != nil {
+		t.Errorf("validateGroupCollision() = %v, want no warning without lister", err)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// SequenceKey returns the key of the sequence counter shared by the consumers
+// of the group reading the stream.
+func SequenceKey(stream, group string) string {
+	return "redisseq:" + stream + ":" + group
+}
+
+// DeadLetterKey returns the default key of the stream the entries of the
+// stream are dead-lettered to, such as the entries without fields.
+func DeadLetterKey(stream string) string {
+	return "redisdeadletter:" + stream
+}
+
+// GetDeadLetterStream returns the key of the stream the entries are
+// dead-lettered to.
+func (s *RedisStreamSourceSpec) GetDeadLetterStream() string {
+	if s.DeadLetterStream != "" {
+		return s.DeadLetterStream
+	}
+	return DeadLetterKey(s.GetStream())
+}
+
+// DeadLettering returns whether entries may be dead-lettered.
+func (s *RedisStreamSourceSpec) DeadLettering() bool {
+	return s.OnEmptyEntry == EmptyEntryDeadLetter || (s.Reclaim != nil && s.Reclaim.MaxDeliveryAttempts > 0)
+}
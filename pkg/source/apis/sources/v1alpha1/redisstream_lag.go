@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "time"
+
+const (
+	// DefaultLagSampleInterval is how often the lag of the consumer group of
+	// the source is sampled when it has no lag sample interval.
+	DefaultLagSampleInterval = 30 * time.Second
+
+	// MinLagRefreshInterval is the shortest interval the controller reads the
+	// lag at, so that it does not load Redis with XINFO and XPENDING commands.
+	MinLagRefreshInterval = 10 * time.Second
+)
+
+// GetLagSampleInterval returns how often the receive adapter samples the lag
+// of the consumer group of the source.
+func (s *RedisStreamSourceSpec) GetLagSampleInterval() time.Duration {
+	if s.LagSampleInterval == nil {
+		return DefaultLagSampleInterval
+	}
+	return s.LagSampleInterval.Duration
+}
+
+// GetLagRefreshInterval returns how often the controller reads the lag of the
+// consumer group of the source into its status: every lag sample interval,
+// but not more often than MinLagRefreshInterval.
+func (s *RedisStreamSourceSpec) GetLagRefreshInterval() time.Duration {
+	if interval := s.GetLagSampleInterval(); interval > MinLagRefreshInterval {
+		return interval
+	}
+	return MinLagRefreshInterval
+}
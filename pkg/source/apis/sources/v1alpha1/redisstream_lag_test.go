@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLagRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        RedisStreamSourceSpec
+		wantSample  time.Duration
+		wantRefresh time.Duration
+	}{{
+		name:        "default",
+		wantSample:  30 * time.Second,
+		wantRefresh: 30 * time.Second,
+	}, {
+		name:        "sample interval",
+		spec:        RedisStreamSourceSpec{LagSampleInterval: &metav1.Duration{Duration: time.Minute}},
+		wantSample:  time.Minute,
+		wantRefresh: time.Minute,
+	}, {
+		name:        "sample interval shorter than the refresh minimum",
+		spec:        RedisStreamSourceSpec{LagSampleInterval: &metav1.Duration{Duration: time.Second}},
+		wantSample:  time.Second,
+		wantRefresh: 10 * time.Second,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.spec.GetLagSampleInterval(); got != test.wantSample {
+				t.Errorf("GetLagSampleInterval() = %v, want %v", got, test.wantSample)
+			}
+			if got := test.spec.GetLagRefreshInterval(); got != test.wantRefresh {
+				t.Errorf("GetLagRefreshInterval() = %v, want %v", got, test.wantRefresh)
+			}
+		})
+	}
+}
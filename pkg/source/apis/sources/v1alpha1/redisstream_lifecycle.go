@@ -17,7 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 )
@@ -31,6 +38,100 @@ const (
 
 	// RedisStreamConditionDeployed has status True when the RedisStreamSource has had it's statefulset created.
 	RedisStreamConditionDeployed apis.ConditionType = "Deployed"
+
+	// RedisStreamConditionWithinDeliveryWindow has status True when the current time is within the
+	// RedisStreamSource delivery window, and False when reading is paused until the window opens.
+	// It does not affect readiness.
+	RedisStreamConditionWithinDeliveryWindow apis.ConditionType = "WithinDeliveryWindow"
+
+	// RedisStreamSourceConditionGroupsReady has status True when all the consumer groups of the
+	// RedisStreamSource exist and have no stale pending entries.
+	RedisStreamSourceConditionGroupsReady apis.ConditionType = "GroupsReady"
+
+	// RedisStreamConditionGroupCollision is set, with status True and severity Warning, when
+	// the consumer group of the RedisStreamSource is also used by a RedisStreamSource in another
+	// namespace reading the same stream. It does not affect readiness.
+	RedisStreamConditionGroupCollision apis.ConditionType = "GroupCollision"
+
+	// RedisStreamConditionSinkAddressPending is set, with status True and severity Warning, when
+	// the sink reference of a RedisStreamSource holding entries on a pending sink address has
+	// no address. It does not affect readiness.
+	RedisStreamConditionSinkAddressPending apis.ConditionType = "SinkAddressPending"
+
+	// RedisStreamConditionClusterResharding is set, with status True and severity Warning, when
+	// the Redis cluster of a RedisStreamSource is moving slots and transiently refuses commands.
+	// It does not affect readiness.
+	RedisStreamConditionClusterResharding apis.ConditionType = "ClusterResharding"
+
+	// RedisStreamConditionTLSConfigured has status True when TLS can be negotiated with Redis using
+	// the TLS secret of the RedisStreamSource, and False when it cannot. It is only set when TLS is
+	// configured, and does not affect readiness.
+	RedisStreamConditionTLSConfigured apis.ConditionType = "TLSConfigured"
+
+	// RedisStreamConditionDeadLetterStreamReady has status True when the dead-letter stream of a
+	// RedisStreamSource can be written to, and False when its key holds another type. It is only
+	// set when entries may be dead-lettered, and does not affect readiness.
+	RedisStreamConditionDeadLetterStreamReady apis.ConditionType = "DeadLetterStreamReady"
+
+	// RedisStreamConditionDeadLetterSinkResolved has status True when the dead-letter sink of a
+	// RedisStreamSource resolved to a URI, and False when it did not, in which case the sink is
+	// also marked as not provided. It is only set when a dead-letter sink is configured.
+	RedisStreamConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
+
+	// RedisStreamConditionSchemaResolved has status True when the JSON Schema at the schema URL
+	// of a RedisStreamSource can be downloaded and compiled, and False when it cannot. It is only
+	// set when a schema URL is configured, and does not affect readiness.
+	RedisStreamConditionSchemaResolved apis.ConditionType = "SchemaResolved"
+
+	// RedisStreamConditionSourceAvailable has status True when the sentinels of a RedisStreamSource
+	// using Redis Sentinel reach the quorum needed to fail its master over, Unknown while they do not
+	// reach it yet, and False when they cannot be asked. It is only set when Sentinel is used, and
+	// does not affect readiness.
+	RedisStreamConditionSourceAvailable apis.ConditionType = "SourceAvailable"
+
+	// RedisStreamConditionClusterMode has status True when the node of the Redis cluster of a
+	// RedisStreamSource serving its stream is found and considers the cluster able to serve
+	// queries, and False otherwise. It is only set when Cluster is used, and does not affect
+	// readiness.
+	RedisStreamConditionClusterMode apis.ConditionType = "ClusterMode"
+
+	// RedisStreamConditionRedisConnected has status True when the receive adapter of a
+	// RedisStreamSource last got an answer to PING from Redis, False with the error of Redis when
+	// it did not, and Unknown until the adapter reports. It does not affect readiness.
+	RedisStreamConditionRedisConnected apis.ConditionType = "RedisConnected"
+
+	// RedisStreamConditionAutoscaled has status True when KEDA scales the receive adapter of a
+	// RedisStreamSource on the lag of its consumer group, and False when it cannot, e.g. when KEDA
+	// is not installed and the receive adapter runs a static number of replicas instead. It is
+	// only set when autoscaling is configured, and does not affect readiness.
+	RedisStreamConditionAutoscaled apis.ConditionType = "Autoscaled"
+
+	// RedisStreamConditionValidConfiguration has status True when the environment variables of a
+	// RedisStreamSource can be added to its receive adapter, and False when they override the
+	// variables the controller sets, in which case the receive adapter is not updated and the
+	// source is not ready. It is only set when environment variables are configured.
+	RedisStreamConditionValidConfiguration apis.ConditionType = "ValidConfiguration"
+
+	// ConsumerGroupMaxPendingIdle is the idle time after which a pending entry is considered
+	// stale, making its consumer group not ready.
+	ConsumerGroupMaxPendingIdle = 10 * time.Minute
+
+	// ConsumerGroupPendingAnnotation is the status annotation holding the number of entries
+	// delivered to the consumer group of a RedisStreamSource and not acknowledged yet.
+	ConsumerGroupPendingAnnotation = "consumerGroupPending"
+
+	// RedisConnectedAnnotation is the annotation the receive adapter sets on its RedisStreamSource
+	// to "true" when Redis answers PING, and to "false" when it does not.
+	RedisConnectedAnnotation = "redisstream.sources.knative.dev/redis-connected"
+
+	// RedisErrorAnnotation is the annotation the receive adapter sets on its RedisStreamSource to
+	// the error of the last PING that failed, and removes once Redis answers again.
+	RedisErrorAnnotation = "redisstream.sources.knative.dev/redis-error"
+
+	// LeaseHolderAnnotation is the annotation the receive adapter of an exclusive consumer sets
+	// on its RedisStreamSource to its pod name once it holds the lease, and removes once it
+	// releases it.
+	LeaseHolderAnnotation = "redisstream.sources.knative.dev/lease-holder"
 )
 
 var redisStreamCondSet = apis.NewLivingConditionSet(
@@ -102,11 +203,321 @@ func (s *RedisStreamSourceStatus) PropagateStatefulSetAvailability(d *appsv1.Sta
 	}
 }
 
+// PropagateStatefulSetWarmup is like PropagateStatefulSetAvailability, except
+// that RedisStreamConditionDeployed is marked as true only once all the replicas
+// of the provided StatefulSet have been ready for the warmup period. It returns
+// how long remains until the warmup period ends, or zero when it is over.
+func (s *RedisStreamSourceStatus) PropagateStatefulSetWarmup(d *appsv1.StatefulSet, warmup time.Duration, now time.Time) time.Duration {
+	deployed := s.GetCondition(RedisStreamConditionDeployed)
+	if warmup <= 0 || d.Status.ReadyReplicas != *d.Spec.Replicas || deployed.IsTrue() {
+		s.PropagateStatefulSetAvailability(d)
+		return 0
+	}
+
+	// The condition keeps its transition time while the replicas are warming up.
+	since := now
+	if deployed != nil && deployed.Reason == "WarmingUp" {
+		since = deployed.LastTransitionTime.Inner.Time
+	}
+	if remaining := since.Add(warmup).Sub(now); remaining > 0 {
+		redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionDeployed, "WarmingUp", "The StatefulSet '%s' is ready, waiting for it to stay ready for %s.", d.Name, warmup)
+		return remaining
+	}
+	s.PropagateStatefulSetAvailability(d)
+	return 0
+}
+
+// MarkWithinDeliveryWindow sets the condition that the source is within its delivery window.
+func (s *RedisStreamSourceStatus) MarkWithinDeliveryWindow(closes time.Time) {
+	redisStreamCondSet.Manage(s).MarkTrueWithReason(RedisStreamConditionWithinDeliveryWindow, "WithinDeliveryWindow", "Delivery window closes at %s.", closes.Format(time.RFC3339))
+}
+
+// MarkOutsideDeliveryWindow sets the condition that the source is paused until its delivery window opens.
+func (s *RedisStreamSourceStatus) MarkOutsideDeliveryWindow(opens time.Time) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionWithinDeliveryWindow, "OutsideDeliveryWindow", "Reading is paused until the delivery window opens at %s.", opens.Format(time.RFC3339))
+}
+
+// MarkNoDeliveryWindow removes the delivery window condition.
+func (s *RedisStreamSourceStatus) MarkNoDeliveryWindow() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionWithinDeliveryWindow)
+}
+
+// PropagateConsumerGroupStatuses sets the ConsumerGroupStatuses and RedisStreamSourceConditionGroupsReady
+// based on the observed consumer groups.
+func (s *RedisStreamSourceStatus) PropagateConsumerGroupStatuses(groups []ConsumerGroupInfo) {
+	s.ConsumerGroupStatuses = make([]ConsumerGroupStatus, len(groups))
+	allReady := true
+	// If there are no consumer groups, treat that as a False case.
+	if len(groups) == 0 {
+		allReady = false
+	}
+	for i, g := range groups {
+		s.ConsumerGroupStatuses[i] = ConsumerGroupStatus{
+			Stream:          g.Stream,
+			Group:           g.Group,
+			Lag:             g.Lag,
+			LastDeliveredID: g.LastDeliveredID,
+		}
+
+		switch {
+		case !g.Exists:
+			s.ConsumerGroupStatuses[i].ReadyCondition = consumerGroupCondition(corev1.ConditionFalse, "GroupNotFound",
+				fmt.Sprintf("Consumer group %q does not exist on stream %q", g.Group, g.Stream))
+			allReady = false
+		case g.Pending > 0 && g.OldestPendingIdle > ConsumerGroupMaxPendingIdle:
+			s.ConsumerGroupStatuses[i].ReadyCondition = consumerGroupCondition(corev1.ConditionFalse, "StalePendingEntries",
+				fmt.Sprintf("Oldest of %d pending entries has been idle for %s", g.Pending, g.OldestPendingIdle))
+			allReady = false
+		default:
+			s.ConsumerGroupStatuses[i].ReadyCondition = consumerGroupCondition(corev1.ConditionTrue, "", "")
+		}
+	}
+	if allReady {
+		redisStreamCondSet.Manage(s).MarkTrue(RedisStreamSourceConditionGroupsReady)
+	} else {
+		s.MarkConsumerGroupsNotReady("GroupsNotReady", "Consumer groups are not ready yet, or there are none")
+	}
+}
+
+// MarkConsumerGroupsNotReady sets the condition that the consumer groups are not ready.
+func (s *RedisStreamSourceStatus) MarkConsumerGroupsNotReady(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamSourceConditionGroupsReady, reason, messageFormat, messageA...)
+}
+
+func consumerGroupCondition(status corev1.ConditionStatus, reason, message string) apis.Condition {
+	return apis.Condition{
+		Type:               apis.ConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(time.Now())},
+	}
+}
+
+// MarkGroupCollision sets the warning condition that the consumer group is also used by the
+// given RedisStreamSources, as namespace/name.
+func (s *RedisStreamSourceStatus) MarkGroupCollision(sources []string) {
+	redisStreamCondSet.Manage(s).SetCondition(apis.Condition{
+		Type:     RedisStreamConditionGroupCollision,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   "GroupCollision",
+		Message:  fmt.Sprintf("Consumer group is shared with %s, entries are split between them. Set namespaceGroup to use a group per namespace.", strings.Join(sources, ", ")),
+	})
+}
+
+// MarkNoGroupCollision removes the group collision condition.
+func (s *RedisStreamSourceStatus) MarkNoGroupCollision() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionGroupCollision)
+}
+
+// MarkSinkAddressPending sets the warning condition that the sink reference has no address,
+// and that entries are held until it has one.
+func (s *RedisStreamSourceStatus) MarkSinkAddressPending(messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).SetCondition(apis.Condition{
+		Type:     RedisStreamConditionSinkAddressPending,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   "SinkAddressPending",
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
+
+// MarkNoSinkAddressPending removes the sink address pending condition.
+func (s *RedisStreamSourceStatus) MarkNoSinkAddressPending() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionSinkAddressPending)
+}
+
+// MarkClusterResharding sets the warning condition that the Redis cluster is resharding,
+// with the error it returned.
+func (s *RedisStreamSourceStatus) MarkClusterResharding(err error) {
+	redisStreamCondSet.Manage(s).SetCondition(apis.Condition{
+		Type:     RedisStreamConditionClusterResharding,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   "ClusterResharding",
+		Message:  fmt.Sprintf("Redis cluster is resharding, waiting for it to recover: %v", err),
+	})
+}
+
+// MarkNoClusterResharding removes the cluster resharding condition.
+func (s *RedisStreamSourceStatus) MarkNoClusterResharding() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionClusterResharding)
+}
+
+// MarkTLSConfigured sets the condition that TLS can be negotiated with Redis.
+func (s *RedisStreamSourceStatus) MarkTLSConfigured() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionTLSConfigured)
+}
+
+// MarkTLSNotConfigured sets the condition that TLS cannot be negotiated with Redis.
+func (s *RedisStreamSourceStatus) MarkTLSNotConfigured(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionTLSConfigured, reason, messageFormat, messageA...)
+}
+
+// MarkTLSUnknown sets the condition that whether TLS can be negotiated with Redis is unknown.
+func (s *RedisStreamSourceStatus) MarkTLSUnknown(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionTLSConfigured, reason, messageFormat, messageA...)
+}
+
+// MarkNoTLS removes the TLS condition.
+func (s *RedisStreamSourceStatus) MarkNoTLS() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionTLSConfigured)
+}
+
+// MarkAutoscaled sets the condition that KEDA scales the receive adapter.
+func (s *RedisStreamSourceStatus) MarkAutoscaled() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionAutoscaled)
+}
+
+// MarkNotAutoscaled sets the condition that KEDA does not scale the receive adapter.
+func (s *RedisStreamSourceStatus) MarkNotAutoscaled(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionAutoscaled, reason, messageFormat, messageA...)
+}
+
+// MarkNoAutoscaling removes the autoscaled condition.
+func (s *RedisStreamSourceStatus) MarkNoAutoscaling() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionAutoscaled)
+}
+
+// MarkValidConfiguration sets the condition that the environment variables of the source can be
+// added to its receive adapter.
+func (s *RedisStreamSourceStatus) MarkValidConfiguration() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionValidConfiguration)
+}
+
+// MarkInvalidConfiguration sets the condition that the environment variables of the source cannot
+// be added to its receive adapter, which is then not deployed.
+func (s *RedisStreamSourceStatus) MarkInvalidConfiguration(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionValidConfiguration, reason, messageFormat, messageA...)
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// MarkNoEnv removes the condition of the environment variables of the source.
+func (s *RedisStreamSourceStatus) MarkNoEnv() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionValidConfiguration)
+}
+
+// MarkDeadLetterStreamReady sets the condition that the dead-letter stream can be written to.
+func (s *RedisStreamSourceStatus) MarkDeadLetterStreamReady() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionDeadLetterStreamReady)
+}
+
+// MarkDeadLetterStreamNotReady sets the condition that the dead-letter stream cannot be written to.
+func (s *RedisStreamSourceStatus) MarkDeadLetterStreamNotReady(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionDeadLetterStreamReady, reason, messageFormat, messageA...)
+}
+
+// MarkDeadLetterStreamUnknown sets the condition that whether the dead-letter stream can be
+// written to is unknown.
+func (s *RedisStreamSourceStatus) MarkDeadLetterStreamUnknown(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionDeadLetterStreamReady, reason, messageFormat, messageA...)
+}
+
+// MarkNoDeadLetterStream removes the dead-letter stream condition.
+func (s *RedisStreamSourceStatus) MarkNoDeadLetterStream() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionDeadLetterStreamReady)
+}
+
+// MarkDeadLetterSinkResolved sets the condition that the dead-letter sink resolved to uri.
+func (s *RedisStreamSourceStatus) MarkDeadLetterSinkResolved(uri *apis.URL) {
+	s.DeadLetterSinkURI = uri
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionDeadLetterSinkResolved)
+}
+
+// MarkDeadLetterSinkNotResolved sets the condition that the dead-letter sink cannot be resolved.
+func (s *RedisStreamSourceStatus) MarkDeadLetterSinkNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	s.DeadLetterSinkURI = nil
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
+// MarkNoDeadLetterSink removes the dead-letter sink condition and URI.
+func (s *RedisStreamSourceStatus) MarkNoDeadLetterSink() {
+	s.DeadLetterSinkURI = nil
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionDeadLetterSinkResolved)
+}
+
+// MarkSchemaResolved sets the condition that the schema can be downloaded and compiled.
+func (s *RedisStreamSourceStatus) MarkSchemaResolved() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionSchemaResolved)
+}
+
+// MarkSchemaNotResolved sets the condition that the schema cannot be downloaded or compiled.
+func (s *RedisStreamSourceStatus) MarkSchemaNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionSchemaResolved, reason, messageFormat, messageA...)
+}
+
+// MarkNoSchema removes the schema resolved condition.
+func (s *RedisStreamSourceStatus) MarkNoSchema() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionSchemaResolved)
+}
+
+// MarkSourceAvailable sets the condition that the sentinels reach quorum.
+func (s *RedisStreamSourceStatus) MarkSourceAvailable() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionSourceAvailable)
+}
+
+// MarkSourceNotAvailable sets the condition that the sentinels cannot be asked whether they reach quorum.
+func (s *RedisStreamSourceStatus) MarkSourceNotAvailable(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionSourceAvailable, reason, messageFormat, messageA...)
+}
+
+// MarkSourceAvailableUnknown sets the condition that the sentinels do not reach quorum yet.
+func (s *RedisStreamSourceStatus) MarkSourceAvailableUnknown(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionSourceAvailable, reason, messageFormat, messageA...)
+}
+
+// MarkNoSentinel removes the source available condition.
+func (s *RedisStreamSourceStatus) MarkNoSentinel() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionSourceAvailable)
+}
+
+// MarkClusterJoined sets the condition that the Redis cluster serves the stream.
+func (s *RedisStreamSourceStatus) MarkClusterJoined() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionClusterMode)
+}
+
+// MarkClusterNotJoined sets the condition that the Redis cluster does not serve the stream.
+func (s *RedisStreamSourceStatus) MarkClusterNotJoined(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionClusterMode, reason, messageFormat, messageA...)
+}
+
+// MarkNoCluster removes the cluster mode condition.
+func (s *RedisStreamSourceStatus) MarkNoCluster() {
+	_ = redisStreamCondSet.Manage(s).ClearCondition(RedisStreamConditionClusterMode)
+}
+
+// PropagateRedisConnected sets the condition that the receive adapter reaches
+// Redis from the annotations it sets on the source.
+func (s *RedisStreamSourceStatus) PropagateRedisConnected(annotations map[string]string) {
+	switch annotations[RedisConnectedAnnotation] {
+	case "true":
+		redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionRedisConnected)
+	case "false":
+		redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionRedisConnected, "RedisUnreachable", "%s", annotations[RedisErrorAnnotation])
+	default:
+		redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionRedisConnected, "NotReported", "The receive adapter has not pinged Redis yet")
+	}
+}
+
+// PropagateLeaseHolder sets the lease holder of an exclusive consumer from the
+// annotations the receive adapter sets on the source.
+func (s *RedisStreamSourceStatus) PropagateLeaseHolder(annotations map[string]string) {
+	s.LeaseHolder = annotations[LeaseHolderAnnotation]
+}
+
 // IsReady returns true if the resource is ready overall.
 func (s *RedisStreamSourceStatus) IsReady() bool {
 	return redisStreamCondSet.Manage(s).IsHappy()
 }
 
+// IsReady returns true if the source is ready overall for the current
+// generation of its spec. The status of a previous generation is stale until
+// the controller observed the current one.
+func (s *RedisStreamSource) IsReady() bool {
+	return s.Generation == s.Status.ObservedGeneration && s.Status.IsReady()
+}
+
 // MarkNoRoleBinding sets the annotation that the source does not have a role binding
 func (s *RedisStreamSourceStatus) MarkNoRoleBinding(reason string) {
 	s.setAnnotation("roleBinding", reason)
@@ -127,6 +538,17 @@ func (s *RedisStreamSourceStatus) MarkServiceAccount() {
 	s.clearAnnotation("serviceAccount")
 }
 
+// MarkConsumerGroupPending sets the annotation with the number of entries
+// delivered to the consumer group and not acknowledged yet.
+func (s *RedisStreamSourceStatus) MarkConsumerGroupPending(pending int64) {
+	s.setAnnotation(ConsumerGroupPendingAnnotation, strconv.FormatInt(pending, 10))
+}
+
+// MarkNoConsumerGroupPending clears the pending entries annotation.
+func (s *RedisStreamSourceStatus) MarkNoConsumerGroupPending() {
+	s.clearAnnotation(ConsumerGroupPendingAnnotation)
+}
+
 func (s *RedisStreamSourceStatus) setAnnotation(name, value string) {
 	if s.Annotations == nil {
 		s.Annotations = make(map[string]string)
@@ -17,12 +17,15 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -195,3 +198,258 @@ func TestRedisStreamSourceStatusGetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestRedisStreamSourceStatusPropagateConsumerGroupStatuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		groups     []ConsumerGroupInfo
+		wantStatus corev1.ConditionStatus
+		wantGroups []corev1.ConditionStatus
+	}{{
+		name:       "empty",
+		groups:     []ConsumerGroupInfo{},
+		wantStatus: corev1.ConditionFalse,
+		wantGroups: []corev1.ConditionStatus{},
+	}, {
+		name: "all ready",
+		groups: []ConsumerGroupInfo{{
+			Stream: "orders",
+			Group:  "mygroup",
+			Exists: true,
+		}, {
+			Stream:            "payments",
+			Group:             "mygroup",
+			Exists:            true,
+			Pending:           3,
+			OldestPendingIdle: time.Second,
+		}},
+		wantStatus: corev1.ConditionTrue,
+		wantGroups: []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionTrue},
+	}, {
+		name: "partial failure",
+		groups: []ConsumerGroupInfo{{
+			Stream: "orders",
+			Group:  "mygroup",
+			Exists: true,
+		}, {
+			Stream: "payments",
+			Group:  "mygroup",
+		}, {
+			Stream:            "refunds",
+			Group:             "mygroup",
+			Exists:            true,
+			Pending:           1,
+			OldestPendingIdle: ConsumerGroupMaxPendingIdle + time.Minute,
+		}},
+		wantStatus: corev1.ConditionFalse,
+		wantGroups: []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionFalse},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.PropagateConsumerGroupStatuses(test.groups)
+
+			if got := s.GetCondition(RedisStreamSourceConditionGroupsReady).Status; got != test.wantStatus {
+				t.Errorf("GroupsReady = %v, want %v", got, test.wantStatus)
+			}
+			if len(s.ConsumerGroupStatuses) != len(test.wantGroups) {
+				t.Fatalf("len(ConsumerGroupStatuses) = %d, want %d", len(s.ConsumerGroupStatuses), len(test.wantGroups))
+			}
+			for i, want := range test.wantGroups {
+				got := s.ConsumerGroupStatuses[i]
+				if got.Stream != test.groups[i].Stream || got.Group != test.groups[i].Group {
+					t.Errorf("ConsumerGroupStatuses[%d] = %s/%s, want %s/%s", i, got.Stream, got.Group, test.groups[i].Stream, test.groups[i].Group)
+				}
+				if got.ReadyCondition.Status != want {
+					t.Errorf("ConsumerGroupStatuses[%d].ReadyCondition = %v, want %v", i, got.ReadyCondition.Status, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceIsReady(t *testing.T) {
+	s := &RedisStreamSource{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	s.Status.InitializeConditions()
+	s.Status.MarkSink("uri://example")
+	s.Status.PropagateStatefulSetAvailability(availableStatefulSet)
+
+	s.Status.ObservedGeneration = 1
+	if s.IsReady() {
+		t.Error("IsReady() = true, want the status of the previous generation to be stale")
+	}
+
+	s.Status.ObservedGeneration = 2
+	if !s.IsReady() {
+		t.Error("IsReady() = false, want true once the current generation is observed")
+	}
+}
+
+func TestRedisStreamSourceStatusMarkSinkAddressPending(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+	s.PropagateStatefulSetAvailability(availableStatefulSet)
+
+	s.MarkSinkAddressPending("address not set for %s", "mysink")
+	cond := s.GetCondition(RedisStreamConditionSinkAddressPending)
+	if cond == nil || cond.Status != corev1.ConditionTrue || cond.Severity != apis.ConditionSeverityWarning {
+		t.Errorf("SinkAddressPending condition = %v, want True with Warning severity", cond)
+	}
+	if cond != nil && cond.Message != "address not set for mysink" {
+		t.Errorf("SinkAddressPending message = %q, want %q", cond.Message, "address not set for mysink")
+	}
+	if !s.IsReady() {
+		t.Error("pending sink address must not affect readiness")
+	}
+
+	s.MarkNoSinkAddressPending()
+	if cond := s.GetCondition(RedisStreamConditionSinkAddressPending); cond != nil {
+		t.Errorf("SinkAddressPending condition = %v, want none", cond)
+	}
+}
+
+func TestRedisStreamSourceStatusMarkClusterResharding(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+	s.PropagateStatefulSetAvailability(availableStatefulSet)
+
+	s.MarkClusterResharding(errors.New("CLUSTERDOWN The cluster is down"))
+	cond := s.GetCondition(RedisStreamConditionClusterResharding)
+	if cond == nil || cond.Status != corev1.ConditionTrue || cond.Severity != apis.ConditionSeverityWarning {
+		t.Errorf("ClusterResharding condition = %v, want True with Warning severity", cond)
+	}
+	if !s.IsReady() {
+		t.Error("resharding must not affect readiness")
+	}
+
+	s.MarkNoClusterResharding()
+	if cond := s.GetCondition(RedisStreamConditionClusterResharding); cond != nil {
+		t.Errorf("ClusterResharding condition = %v, want none", cond)
+	}
+}
+
+func TestRedisStreamSourceStatusMarkDeadLetterSinkResolved(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+
+	uri := apis.HTTP("dls.default.svc")
+	s.MarkDeadLetterSinkResolved(uri)
+	if cond := s.GetCondition(RedisStreamConditionDeadLetterSinkResolved); cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Errorf("DeadLetterSinkResolved condition = %v, want True", cond)
+	}
+	if s.DeadLetterSinkURI != uri {
+		t.Errorf("DeadLetterSinkURI = %v, want %v", s.DeadLetterSinkURI, uri)
+	}
+
+	s.MarkDeadLetterSinkNotResolved("NotFound", "dead-letter sink %s not found", "dls")
+	if cond := s.GetCondition(RedisStreamConditionDeadLetterSinkResolved); cond == nil || cond.Status != corev1.ConditionFalse {
+		t.Errorf("DeadLetterSinkResolved condition = %v, want False", cond)
+	}
+	if s.DeadLetterSinkURI != nil {
+		t.Errorf("DeadLetterSinkURI = %v, want none", s.DeadLetterSinkURI)
+	}
+
+	s.MarkNoDeadLetterSink()
+	if cond := s.GetCondition(RedisStreamConditionDeadLetterSinkResolved); cond != nil {
+		t.Errorf("DeadLetterSinkResolved condition = %v, want none", cond)
+	}
+}
+
+func TestRedisStreamSourceStatusPropagateRedisConnected(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+	s.PropagateStatefulSetAvailability(availableStatefulSet)
+
+	s.PropagateRedisConnected(nil)
+	if cond := s.GetCondition(RedisStreamConditionRedisConnected); cond == nil || cond.Status != corev1.ConditionUnknown {
+		t.Errorf("RedisConnected condition = %v, want Unknown", cond)
+	}
+
+	s.PropagateRedisConnected(map[string]string{
+		RedisConnectedAnnotation: "false",
+		RedisErrorAnnotation:     "dial tcp 10.0.0.1:6379: connect: connection refused",
+	})
+	cond := s.GetCondition(RedisStreamConditionRedisConnected)
+	if cond == nil || cond.Status != corev1.ConditionFalse || cond.Reason != "RedisUnreachable" || cond.Message != "dial tcp 10.0.0.1:6379: connect: connection refused" {
+		t.Errorf("RedisConnected condition = %v, want False with the error of Redis", cond)
+	}
+	if !s.IsReady() {
+		t.Error("IsReady() = false, want the RedisConnected condition not to affect readiness")
+	}
+
+	s.PropagateRedisConnected(map[string]string{RedisConnectedAnnotation: "true"})
+	if cond := s.GetCondition(RedisStreamConditionRedisConnected); cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Errorf("RedisConnected condition = %v, want True", cond)
+	}
+}
+
+func TestRedisStreamSourceStatusPropagateStatefulSetWarmup(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+
+	unavailable := availableStatefulSet.DeepCopy()
+	unavailable.Status.ReadyReplicas = 0
+	if remaining := s.PropagateStatefulSetWarmup(unavailable, time.Minute, time.Now()); remaining != 0 {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, want 0 while the StatefulSet is unavailable", remaining)
+	}
+	if cond := s.GetCondition(RedisStreamConditionDeployed); cond.Reason != "StatefulSetUnavailable" {
+		t.Errorf("Deployed condition = %v, want StatefulSetUnavailable", cond)
+	}
+
+	// The replicas just became ready.
+	now := time.Now()
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, time.Minute, now); remaining != time.Minute {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, want %s", remaining, time.Minute)
+	}
+	cond := s.GetCondition(RedisStreamConditionDeployed)
+	if cond.Status != corev1.ConditionUnknown || cond.Reason != "WarmingUp" {
+		t.Errorf("Deployed condition = %v, want Unknown with reason WarmingUp", cond)
+	}
+	if s.IsReady() {
+		t.Error("source must not be ready while warming up")
+	}
+
+	// Later on, while still warming up.
+	since := cond.LastTransitionTime.Inner.Time
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, time.Minute, since.Add(40*time.Second)); remaining != 20*time.Second {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, want 20s", remaining)
+	}
+	if s.IsReady() {
+		t.Error("source must not be ready while warming up")
+	}
+
+	// Once the warmup period is over.
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, time.Minute, since.Add(time.Minute)); remaining != 0 {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, want 0", remaining)
+	}
+	if !s.IsReady() {
+		t.Errorf("source must be ready after warming up, conditions: %v", s.Conditions)
+	}
+
+	// Staying ready does not warm up again.
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, time.Minute, since.Add(2*time.Minute)); remaining != 0 || !s.IsReady() {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, ready = %t, want 0 and ready", remaining, s.IsReady())
+	}
+
+	// A replica that is not ready anymore starts over.
+	s.PropagateStatefulSetWarmup(unavailable, time.Minute, since.Add(3*time.Minute))
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, time.Minute, time.Now()); remaining != time.Minute || s.IsReady() {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, ready = %t, want %s and not ready", remaining, s.IsReady(), time.Minute)
+	}
+}
+
+func TestRedisStreamSourceStatusPropagateStatefulSetWithoutWarmup(t *testing.T) {
+	s := &RedisStreamSourceStatus{}
+	s.InitializeConditions()
+	s.MarkSink("uri://example")
+
+	if remaining := s.PropagateStatefulSetWarmup(availableStatefulSet, 0, time.Now()); remaining != 0 || !s.IsReady() {
+		t.Errorf("PropagateStatefulSetWarmup() = %s, ready = %t, want 0 and ready", remaining, s.IsReady())
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the RedisOIDC.
+func (o *RedisOIDC) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if o.ServiceAccountName != "" {
+		if msgs := validation.IsDNS1123Subdomain(o.ServiceAccountName); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(o.ServiceAccountName, "serviceAccountName", strings.Join(msgs, ", ")))
+		}
+	}
+	if o.Audience == "" {
+		errs = errs.Also(apis.ErrMissingField("audience"))
+	}
+	if o.TokenURL == "" {
+		errs = errs.Also(apis.ErrMissingField("tokenURL"))
+	} else if u, err := url.Parse(o.TokenURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = errs.Also(apis.ErrInvalidValue(o.TokenURL, "tokenURL", "must be an https URL"))
+	}
+	return errs
+}
+
+// GetOIDC returns the OIDC authentication of the source, nil when it has none.
+func (s *RedisStreamSourceSpec) GetOIDC() *RedisOIDC {
+	if s.Auth == nil {
+		return nil
+	}
+	return s.Auth.OIDC
+}
+
+// GetServiceAccountName returns the name of the existing service account the
+// receive adapter pods run as, from ServiceAccountName or the OIDC
+// authentication. It is empty when a service account is created for the
+// source.
+func (s *RedisStreamSourceSpec) GetServiceAccountName() string {
+	if s.ServiceAccountName != "" {
+		return s.ServiceAccountName
+	}
+	if oidc := s.GetOIDC(); oidc != nil {
+		return oidc.ServiceAccountName
+	}
+	return ""
+}
+
+// validateOIDC requires the service account of the OIDC authentication to be
+// the one the receive adapter pods run as.
+func (s *RedisStreamSourceSpec) validateOIDC() *apis.FieldError {
+	oidc := s.GetOIDC()
+	if oidc == nil || oidc.ServiceAccountName == "" || s.ServiceAccountName == "" || oidc.ServiceAccountName == s.ServiceAccountName {
+		return nil
+	}
+	return apis.ErrGeneric("auth.oidc.serviceAccountName must match serviceAccountName", "auth.oidc.serviceAccountName", "serviceAccountName")
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// GetInterval returns how often the pending entries are checked.
+func (r *Reclaim) GetInterval() time.Duration {
+	if r.Interval == nil {
+		return r.MinIdleTime.Duration
+	}
+	return r.Interval.Duration
+}
+
+// Validate validates the Reclaim.
+func (r *Reclaim) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if r.MinIdleTime.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(r.MinIdleTime.Duration, "minIdleTime", "must be positive"))
+	}
+	if r.Interval != nil && r.Interval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(r.Interval.Duration, "interval", "must be positive"))
+	}
+	if r.MaxDeliveryAttempts < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(r.MaxDeliveryAttempts, "maxDeliveryAttempts", "must be positive"))
+	}
+	return errs
+}
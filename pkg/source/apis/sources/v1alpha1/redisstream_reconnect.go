@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the ReconnectBackoff.
+func (b *ReconnectBackoff) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if b.InitialDelay != nil && b.InitialDelay.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(b.InitialDelay.Duration, "initialDelay", "must be positive"))
+	}
+	if b.MaxDelay != nil && b.MaxDelay.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(b.MaxDelay.Duration, "maxDelay", "must be positive"))
+	}
+	if b.InitialDelay != nil && b.MaxDelay != nil && b.InitialDelay.Duration > b.MaxDelay.Duration {
+		errs = errs.Also(apis.ErrInvalidValue(b.MaxDelay.Duration, "maxDelay", "must not be less than initialDelay"))
+	}
+	if b.Multiplier != "" {
+		if m, err := strconv.ParseFloat(b.Multiplier, 64); err != nil || m < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(b.Multiplier, "multiplier", "must be a number of at least 1"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "knative.dev/pkg/apis"
+
+// GetReplicas returns the number of receive adapter pods: Replicas, or else
+// Consumers, which predates it.
+func (s *RedisStreamSourceSpec) GetReplicas() *int32 {
+	if s.Replicas != nil {
+		return s.Replicas
+	}
+	return s.Consumers
+}
+
+// validateReplicas validates that the replicas of the receive adapter share a
+// consumer group, so that they split the entries instead of each delivering
+// all of them.
+func (s *RedisStreamSourceSpec) validateReplicas() *apis.FieldError {
+	if s.Replicas == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if *s.Replicas < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.Replicas, "replicas", "must not be negative"))
+	}
+	// Without a group, each replica reads with its own group.
+	if *s.Replicas > 1 && s.Group == "" {
+		errs = errs.Also(apis.ErrGeneric("more than 1 replica requires a group shared by the replicas", "replicas", "group"))
+	}
+	return errs
+}
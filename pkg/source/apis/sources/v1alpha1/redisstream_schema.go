@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// DefaultSchemaRefreshInterval is how often the receive adapter downloads the
+// schema again when the source has no schema refresh interval.
+const DefaultSchemaRefreshInterval = 5 * time.Minute
+
+// GetSchemaRefreshInterval returns how often the receive adapter downloads the
+// schema again.
+func (s *RedisStreamSourceSpec) GetSchemaRefreshInterval() time.Duration {
+	if s.SchemaRefreshInterval == nil {
+		return DefaultSchemaRefreshInterval
+	}
+	return s.SchemaRefreshInterval.Duration
+}
+
+// validateSchema validates the URL of the schema of the source and how the
+// entries not matching it are handled.
+func (s *RedisStreamSourceSpec) validateSchema() *apis.FieldError {
+	var errs *apis.FieldError
+	if s.SchemaURL != "" {
+		if u, err := url.Parse(s.SchemaURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = errs.Also(apis.ErrInvalidValue(s.SchemaURL, "schemaURL", "must be an http or https URL"))
+		}
+	}
+
+	switch s.SchemaValidation {
+	case "", SchemaValidationWarn:
+	case SchemaValidationReject:
+		if s.GetDeadLetterSink() == nil {
+			errs = errs.Also(apis.ErrGeneric("schemaValidation Reject requires a deadLetterSink", "schemaValidation", "deadLetterSink"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.SchemaValidation, "schemaValidation"))
+	}
+
+	if s.SchemaRefreshInterval != nil && s.SchemaRefreshInterval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.SchemaRefreshInterval.Duration, "schemaRefreshInterval", "must be positive"))
+	}
+
+	if s.SchemaURL == "" {
+		if s.SchemaValidation != "" {
+			errs = errs.Also(apis.ErrGeneric("schemaValidation requires schemaURL", "schemaValidation"))
+		}
+		if s.SchemaRefreshInterval != nil {
+			errs = errs.Also(apis.ErrGeneric("schemaRefreshInterval requires schemaURL", "schemaRefreshInterval"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the RedisSentinel.
+func (s *RedisSentinel) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if s.MasterName == "" {
+		errs = errs.Also(apis.ErrMissingField("masterName"))
+	}
+	if len(s.Addresses) == 0 {
+		errs = errs.Also(apis.ErrMissingField("addresses"))
+	}
+	for i, address := range s.Addresses {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(address, "addresses", i))
+		}
+	}
+	return errs
+}
+
+// SentinelURL identifies the master named masterName, monitored by the
+// sentinels at the given addresses, e.g. in the source of the events.
+func SentinelURL(masterName string, addresses []string) string {
+	return "redis+sentinel://" + strings.Join(addresses, ",") + "/" + masterName
+}
+
+// Endpoint identifies the Redis instance of the connection: its address, the
+// master monitored by its sentinels, or its cluster.
+func (c *RedisConnection) Endpoint() string {
+	if c.Sentinel != nil {
+		return SentinelURL(c.Sentinel.MasterName, c.Sentinel.Addresses)
+	}
+	if c.Cluster != nil {
+		return ClusterURL(c.Cluster.Addresses)
+	}
+	return c.Address
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// StartFromLatest is the StartID reading the entries added after the
+	// consumer group is created, like $.
+	StartFromLatest = "latest"
+	// StartFromEarliest is the StartID reading the whole stream.
+	StartFromEarliest = "earliest"
+)
+
+// GetStartID returns the ID the consumer groups created by the receive
+// adapter start reading after, as in XGROUP CREATE, from StartID or, for the
+// sources stored before it was merged into StartID, StartFrom. It is empty
+// when neither is set.
+func (s *RedisStreamSourceSpec) GetStartID() string {
+	start := s.StartID
+	if start == "" {
+		start = s.StartFrom
+	}
+	switch start {
+	case StartFromLatest:
+		return scan.LastID
+	case StartFromEarliest:
+		return "0-0"
+	default:
+		return start
+	}
+}
+
+// moveStartFrom moves the deprecated StartFrom to StartID, when StartID is
+// not set or only set to the default, e.g. when StartFrom is applied to a
+// source created without it.
+func (s *RedisStreamSourceSpec) moveStartFrom() {
+	if s.StartFrom != "" && (s.StartID == "" || s.StartID == scan.LastID) {
+		s.StartID, s.StartFrom = s.StartFrom, ""
+	}
+}
+
+// EffectiveStartID returns the ID the consumer groups created by the receive
+// adapter start reading after: the start ID of the spec, or $ for the entries
+// added afterwards only.
+func (s *RedisStreamSourceSpec) EffectiveStartID() string {
+	if id := s.GetStartID(); id != "" {
+		return id
+	}
+	return scan.LastID
+}
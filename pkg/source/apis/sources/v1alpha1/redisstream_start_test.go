@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestGetStartID(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          RedisStreamSourceSpec
+		want          string
+		wantEffective string
+	}{{
+		name:          "default",
+		wantEffective: "$",
+	}, {
+		name:          "start ID",
+		spec:          RedisStreamSourceSpec{StartID: "0"},
+		want:          "0",
+		wantEffective: "0",
+	}, {
+		name:          "latest",
+		spec:          RedisStreamSourceSpec{StartID: StartFromLatest},
+		want:          "$",
+		wantEffective: "$",
+	}, {
+		name:          "earliest",
+		spec:          RedisStreamSourceSpec{StartID: StartFromEarliest},
+		want:          "0-0",
+		wantEffective: "0-0",
+	}, {
+		name:          "entry ID",
+		spec:          RedisStreamSourceSpec{StartID: "1680000000000-0"},
+		want:          "1680000000000-0",
+		wantEffective: "1680000000000-0",
+	}, {
+		name:          "deprecated start from",
+		spec:          RedisStreamSourceSpec{StartFrom: StartFromEarliest},
+		want:          "0-0",
+		wantEffective: "0-0",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.spec.GetStartID(); got != test.want {
+				t.Errorf("GetStartID() = %q, want %q", got, test.want)
+			}
+			if got := test.spec.EffectiveStartID(); got != test.wantEffective {
+				t.Errorf("EffectiveStartID() = %q, want %q", got, test.wantEffective)
+			}
+		})
+	}
+}
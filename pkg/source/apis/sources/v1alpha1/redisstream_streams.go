@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// GetStreams returns the streams read by the source: Stream, when set,
+// followed by Streams, each once.
+func (s *RedisStreamSourceSpec) GetStreams() []string {
+	var streams []string
+	seen := make(map[string]bool, len(s.Streams)+1)
+	for _, stream := range append([]string{s.Stream}, s.Streams...) {
+		if stream == "" || seen[stream] {
+			continue
+		}
+		seen[stream] = true
+		streams = append(streams, stream)
+	}
+	return streams
+}
+
+// GetStream returns the first stream read by the source. The keys derived
+// from the name of the stream, such as the default dead-letter stream, and
+// the status of the consumer group refer to it.
+func (s *RedisStreamSourceSpec) GetStream() string {
+	if streams := s.GetStreams(); len(streams) > 0 {
+		return streams[0]
+	}
+	return ""
+}
+
+// validateStreams validates the streams read by the source, rejecting the
+// options that only support a single stream when several are read.
+func (s *RedisStreamSourceSpec) validateStreams() *apis.FieldError {
+	var errs *apis.FieldError
+	seen := make(map[string]bool, len(s.Streams))
+	for i, stream := range s.Streams {
+		switch {
+		case stream == "":
+			errs = errs.Also(apis.ErrInvalidArrayValue(stream, "streams", i))
+		case seen[stream]:
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate stream %q", stream), fmt.Sprintf("streams[%d]", i)))
+		}
+		seen[stream] = true
+	}
+
+	streams := s.GetStreams()
+	if len(streams) == 0 {
+		errs = errs.Also(apis.ErrMissingOneOf("stream", "streams"))
+	}
+	if len(streams) <= 1 {
+		return errs
+	}
+	if s.TargetConfigMap != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("streams", "targetConfigMap"))
+	}
+	if s.Dedup != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("streams", "dedup"))
+	}
+	if s.Cluster != nil {
+		slot := scan.KeySlot(streams[0])
+		for i, stream := range s.Streams {
+			if scan.KeySlot(stream) != slot {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("stream %q is not in the hash slot of stream %q, use the same hash tag in their names, e.g. {%s}, so that they share it", stream, streams[0], streams[0]), fmt.Sprintf("streams[%d]", i)))
+			}
+		}
+	}
+	return errs
+}
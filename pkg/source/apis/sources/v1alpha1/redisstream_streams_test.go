@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"knative.dev/pkg/apis"
+)
+
+func TestGetStreams(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       RedisStreamSourceSpec
+		want       []string
+		wantStream string
+	}{{
+		name: "none",
+	}, {
+		name:       "stream",
+		spec:       RedisStreamSourceSpec{Stream: "orders"},
+		want:       []string{"orders"},
+		wantStream: "orders",
+	}, {
+		name:       "streams",
+		spec:       RedisStreamSourceSpec{Streams: []string{"orders", "payments"}},
+		want:       []string{"orders", "payments"},
+		wantStream: "orders",
+	}, {
+		name:       "stream and streams",
+		spec:       RedisStreamSourceSpec{Stream: "orders", Streams: []string{"payments", "orders"}},
+		want:       []string{"orders", "payments"},
+		wantStream: "orders",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.spec.GetStreams(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("GetStreams() = %v, want %v", got, test.want)
+			}
+			if got := test.spec.GetStream(); got != test.wantStream {
+				t.Errorf("GetStream() = %q, want %q", got, test.wantStream)
+			}
+		})
+	}
+}
+
+func TestValidateStreams(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    RedisStreamSourceSpec
+		wantErr bool
+	}{{
+		name:    "no stream",
+		wantErr: true,
+	}, {
+		name:    "empty streams",
+		spec:    RedisStreamSourceSpec{Streams: []string{}},
+		wantErr: true,
+	}, {
+		name: "stream",
+		spec: RedisStreamSourceSpec{Stream: "orders"},
+	}, {
+		name: "streams",
+		spec: RedisStreamSourceSpec{Streams: []string{"events:{0}", "events:{1}"}},
+	}, {
+		name:    "duplicate streams",
+		spec:    RedisStreamSourceSpec{Streams: []string{"events:{0}", "events:{0}"}},
+		wantErr: true,
+	}, {
+		name:    "empty stream name",
+		spec:    RedisStreamSourceSpec{Streams: []string{"events:{0}", ""}},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := withAddress(test.spec)
+			err := spec.Validate(context.Background()).Filter(apis.ErrorLevel)
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Summary returns a one-line summary of the configuration and state of the
+// source, for triage with kubectl, e.g. "stream mystream, shared group
+// mygroup, 2 replicas, lag 12, last processed 1-0, warnings: GroupCollision".
+// The replicas are the ones running once the receive adapter is deployed, e.g.
+// as scaled by KEDA, and the ones desired before.
+func (s *RedisStreamSource) Summary() string {
+	parts := []string{"stream " + s.Spec.GetStream()}
+	if streams := s.Spec.GetStreams(); len(streams) > 1 {
+		parts[0] = "streams " + strings.Join(streams, " ")
+	}
+
+	if group := s.ConsumerGroup(); group != "" {
+		parts = append(parts, "shared group "+group)
+	} else {
+		parts = append(parts, "group per replica")
+	}
+
+	replicas := int32(1)
+	if r := s.Spec.GetReplicas(); r != nil {
+		replicas = *r
+	}
+	if s.Status.GetCondition(RedisStreamConditionDeployed).IsTrue() {
+		replicas = s.Status.Consumers
+	}
+	if replicas == 1 {
+		parts = append(parts, "1 replica")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d replicas", replicas))
+	}
+
+	if s.Status.Lag != nil {
+		parts = append(parts, fmt.Sprintf("lag %d", *s.Status.Lag))
+	}
+	if s.Status.LastProcessedID != "" {
+		parts = append(parts, "last processed "+s.Status.LastProcessedID)
+	}
+
+	if s.Spec.KafkaBridge != nil {
+		parts = append(parts, "to Kafka topic "+s.Spec.KafkaBridge.Topic)
+	}
+
+	if cond := s.Status.GetCondition(RedisStreamConditionWithinDeliveryWindow); cond != nil && cond.IsFalse() {
+		parts = append(parts, "paused until the delivery window opens")
+	}
+
+	var warnings []string
+	for _, cond := range s.Status.Conditions {
+		if cond.Severity == apis.ConditionSeverityWarning && cond.Status == corev1.ConditionTrue {
+			warnings = append(warnings, string(cond.Type))
+		}
+	}
+	if len(warnings) > 0 {
+		parts = append(parts, "warnings: "+strings.Join(warnings, ", "))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestRedisStreamSourceSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   RedisStreamSourceSpec
+		status func(*RedisStreamSourceStatus)
+		want   string
+	}{{
+		name: "group per replica",
+		spec: RedisStreamSourceSpec{Stream: "mystream"},
+		want: "stream mystream, group per replica, 1 replica",
+	}, {
+		name: "shared group",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup", NamespaceGroup: true, Consumers: pointer.Int32(3)},
+		want: "stream mystream, shared group ns.mygroup, 3 replicas",
+	}, {
+		name: "replicas",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup", Consumers: pointer.Int32(3), Replicas: pointer.Int32(5)},
+		want: "stream mystream, shared group mygroup, 5 replicas",
+	}, {
+		name: "running replicas",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup", Consumers: pointer.Int32(3)},
+		status: func(s *RedisStreamSourceStatus) {
+			s.PropagateStatefulSetAvailability(&appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: pointer.Int32(2)},
+				Status: appsv1.StatefulSetStatus{Replicas: 2, ReadyReplicas: 2},
+			})
+		},
+		want: "stream mystream, shared group mygroup, 2 replicas",
+	}, {
+		name: "scaled replicas",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup", Autoscaling: &Autoscaling{MaxReplicas: 10}},
+		status: func(s *RedisStreamSourceStatus) {
+			s.PropagateStatefulSetAvailability(&appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: pointer.Int32(4)},
+				Status: appsv1.StatefulSetStatus{Replicas: 4, ReadyReplicas: 4},
+			})
+		},
+		want: "stream mystream, shared group mygroup, 4 replicas",
+	}, {
+		name: "lag and last processed ID",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup"},
+		status: func(s *RedisStreamSourceStatus) {
+			s.Lag = pointer.Int64(12)
+			s.LastProcessedID = "1600000000000-0"
+		},
+		want: "stream mystream, shared group mygroup, 1 replica, lag 12, last processed 1600000000000-0",
+	}, {
+		name: "kafka bridge",
+		spec: RedisStreamSourceSpec{Stream: "mystream", KafkaBridge: &KafkaBridge{Topic: "events"}},
+		want: "stream mystream, group per replica, 1 replica, to Kafka topic events",
+	}, {
+		name: "warnings",
+		spec: RedisStreamSourceSpec{Stream: "mystream", Group: "mygroup"},
+		status: func(s *RedisStreamSourceStatus) {
+			s.MarkGroupCollision([]string{"other/source"})
+			s.MarkSinkAddressPending("no address")
+		},
+		want: "stream mystream, shared group mygroup, 1 replica, warnings: GroupCollision, SinkAddressPending",
+	}, {
+		name: "outside delivery window",
+		spec: RedisStreamSourceSpec{Stream: "mystream"},
+		status: func(s *RedisStreamSourceStatus) {
+			s.MarkOutsideDeliveryWindow(time.Date(2022, 3, 4, 8, 0, 0, 0, time.UTC))
+		},
+		want: "stream mystream, group per replica, 1 replica, paused until the delivery window opens",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+				Spec:       test.spec,
+			}
+			s.Status.InitializeConditions()
+			if test.status != nil {
+				test.status(&s.Status)
+			}
+			if got := s.Summary(); got != test.want {
+				t.Errorf("Summary() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
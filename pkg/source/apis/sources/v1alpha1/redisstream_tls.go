@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// RedisTLSCACertKey is the key of the TLS secret holding the CA certificate.
+	RedisTLSCACertKey = "ca.crt"
+	// RedisTLSCertKey is the key of the TLS secret holding the client certificate.
+	RedisTLSCertKey = corev1.TLSCertKey
+	// RedisTLSKeyKey is the key of the TLS secret holding the client key.
+	RedisTLSKeyKey = corev1.TLSPrivateKeyKey
+)
+
+// SecretGetter returns the secret with the given name in the given namespace.
+type SecretGetter func(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+
+type secretGetterKey struct{}
+
+// WithSecretGetter returns a context with which the TLS secrets of the sources
+// are checked, with getter, when validating them.
+func WithSecretGetter(ctx context.Context, getter SecretGetter) context.Context {
+	return context.WithValue(ctx, secretGetterKey{}, getter)
+}
+
+func secretGetterFromContext(ctx context.Context) SecretGetter {
+	getter, _ := ctx.Value(secretGetterKey{}).(SecretGetter)
+	return getter
+}
+
+// Validate validates the RedisTLS.
+func (t *RedisTLS) Validate(ctx context.Context) *apis.FieldError {
+	if t.SecretName == "" {
+		return apis.ErrMissingField("secretName")
+	}
+	return nil
+}
+
+// validateTLSSecret checks that the TLS secret of the source, when it exists,
+// has the expected keys. A missing secret is only a warning, as it may be
+// created after the source.
+func (s *RedisStreamSource) validateTLSSecret(ctx context.Context) *apis.FieldError {
+	getter := secretGetterFromContext(ctx)
+	if getter == nil || s.Spec.TLS == nil || s.Spec.TLS.SecretName == "" {
+		return nil
+	}
+
+	secret, err := getter(ctx, s.Namespace, s.Spec.TLS.SecretName)
+	if apierrors.IsNotFound(err) {
+		return apis.ErrGeneric(fmt.Sprintf("secret %q does not exist", s.Spec.TLS.SecretName), "secretName").At(apis.WarningLevel)
+	}
+	if err != nil {
+		return apis.ErrGeneric(fmt.Sprintf("cannot get secret %q: %v", s.Spec.TLS.SecretName, err), "secretName")
+	}
+	return ValidateTLSSecret(secret).ViaField("secretName")
+}
+
+// validateDialOptionsTLS rejects the TLS settings of dialOptions, which are
+// ignored: TLS is configured with tls instead. They are only rejected when set
+// on create, or changed on update, so that the sources created with them can
+// still be updated.
+func (s *RedisStreamSource) validateDialOptionsTLS(ctx context.Context) *apis.FieldError {
+	opts := s.Spec.Options
+	if opts == nil {
+		return nil
+	}
+	base := &RedisConnectionOptions{}
+	if apis.IsInUpdate(ctx) {
+		if source, ok := apis.GetBaseline(ctx).(*RedisStreamSource); ok && source != nil && source.Spec.Options != nil {
+			base = source.Spec.Options
+		}
+	}
+
+	var paths []string
+	if opts.UseTLS && !base.UseTLS {
+		paths = append(paths, "useTLS")
+	}
+	if opts.SkipVerify && !base.SkipVerify {
+		paths = append(paths, "skipVerify")
+	}
+	for _, value := range []struct {
+		path       string
+		set, based RedisSecretValueFromSource
+	}{{"cert", opts.Cert, base.Cert}, {"key", opts.Key, base.Key}, {"caCert", opts.CACert, base.CACert}} {
+		if value.set.SecretKeyRef != nil && !equality.Semantic.DeepEqual(value.set, value.based) {
+			paths = append(paths, value.path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return (&apis.FieldError{
+		Message: "The TLS dial options are deprecated and ignored",
+		Paths:   paths,
+		Details: "set tls.secretName to a secret holding the ca.crt, tls.crt and tls.key keys instead",
+	}).ViaField("dialOptions")
+}
+
+// ValidateTLSSecret checks that the secret has a CA certificate and, unless it
+// has neither, both a client certificate and key.
+func ValidateTLSSecret(secret *corev1.Secret) *apis.FieldError {
+	var errs *apis.FieldError
+	if len(secret.Data[RedisTLSCACertKey]) == 0 {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("secret %q has no %q key", secret.Name, RedisTLSCACertKey)))
+	}
+	hasCert, hasKey := len(secret.Data[RedisTLSCertKey]) > 0, len(secret.Data[RedisTLSKeyKey]) > 0
+	if hasCert != hasKey {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("secret %q must have both %q and %q keys, or neither", secret.Name, RedisTLSCertKey, RedisTLSKeyKey)))
+	}
+	return errs
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate validates the Tracing.
+func (t *Tracing) Validate(ctx context.Context) *apis.FieldError {
+	if t.SamplingRate == "" {
+		return nil
+	}
+	if rate, err := strconv.ParseFloat(t.SamplingRate, 64); err != nil || rate < 0 || rate > 1 {
+		return apis.ErrInvalidValue(t.SamplingRate, "samplingRate", "must be a number between 0 and 1")
+	}
+	return nil
+}
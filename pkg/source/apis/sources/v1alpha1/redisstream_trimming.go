@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/robfig/cron/v3"
+	"knative.dev/pkg/apis"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// DefaultTrimmingSchedule is the cron schedule the streams are trimmed on
+// when the trimming policy has no schedule.
+const DefaultTrimmingSchedule = "*/5 * * * *"
+
+// GetTrimming returns the trimming policy of the streams, from Trimming or,
+// for the sources stored before TrimStrategy was merged into it,
+// TrimStrategy. It is nil when the streams are not trimmed.
+func (s *RedisStreamSourceSpec) GetTrimming() *Trimming {
+	if s.Trimming != nil || s.TrimStrategy == nil {
+		return s.Trimming
+	}
+	return &Trimming{
+		Strategy:    TrimmingMaxLen,
+		Threshold:   strconv.FormatInt(s.TrimStrategy.MaxLen, 10),
+		Approximate: !s.TrimStrategy.Exact,
+		OnAck:       true,
+	}
+}
+
+// moveTrimStrategy moves the deprecated TrimStrategy to Trimming, when
+// Trimming is not set.
+func (s *RedisStreamSourceSpec) moveTrimStrategy() {
+	if s.TrimStrategy != nil && s.Trimming == nil {
+		s.Trimming, s.TrimStrategy = s.GetTrimming(), nil
+	}
+}
+
+// GetSchedule returns the cron schedule the streams are trimmed on.
+func (t *Trimming) GetSchedule() string {
+	if t.Schedule == "" {
+		return DefaultTrimmingSchedule
+	}
+	return t.Schedule
+}
+
+// Validate validates the trimming policy: its threshold is a number of entries
+// with MAXLEN, and an entry ID with MINID. Trimming on acknowledgement only
+// trims with MAXLEN, to a positive number of entries, and has no schedule.
+func (t *Trimming) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	switch t.Strategy {
+	case TrimmingMaxLen, TrimmingMinID:
+	case "":
+		errs = errs.Also(apis.ErrMissingField("strategy"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(t.Strategy, "strategy"))
+	}
+
+	switch {
+	case t.Threshold == "":
+		errs = errs.Also(apis.ErrMissingField("threshold"))
+	case t.Strategy == TrimmingMaxLen:
+		if n, err := strconv.ParseInt(t.Threshold, 10, 64); err != nil || n < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(t.Threshold, "threshold", "must be a number of entries with MAXLEN"))
+		}
+	case t.Strategy == TrimmingMinID:
+		if _, err := scan.ParseID(t.Threshold, scan.EntryPosition); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(t.Threshold, "threshold", err.Error()))
+		}
+	}
+
+	if t.Schedule != "" {
+		if _, err := cron.ParseStandard(t.Schedule); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(t.Schedule, "schedule", err.Error()))
+		}
+	}
+
+	if t.OnAck {
+		switch t.Strategy {
+		case TrimmingMaxLen:
+			if n, err := strconv.ParseInt(t.Threshold, 10, 64); err == nil && n == 0 {
+				errs = errs.Also(apis.ErrInvalidValue(t.Threshold, "threshold", "must be positive with onAck"))
+			}
+		case TrimmingMinID:
+			errs = errs.Also(apis.ErrInvalidValue(t.Strategy, "strategy", "must be MAXLEN with onAck"))
+		}
+		if t.Schedule != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("onAck", "schedule"))
+		}
+	}
+	return errs
+}
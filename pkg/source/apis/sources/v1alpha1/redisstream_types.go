@@ -17,9 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -43,10 +46,11 @@ type RedisStreamSource struct {
 var (
 	_ runtime.Object     = (*RedisStreamSource)(nil)
 	_ kmeta.OwnerRefable = (*RedisStreamSource)(nil)
-	//_ apis.Validatable   = (*RedisStreamSource)(nil)
-	//_ apis.Defaultable   = (*RedisStreamSource)(nil)
-	_ apis.HasSpec    = (*RedisStreamSource)(nil)
-	_ duckv1.KRShaped = (*RedisStreamSource)(nil)
+	_ apis.Validatable   = (*RedisStreamSource)(nil)
+	_ apis.Convertible   = (*RedisStreamSource)(nil)
+	_ apis.Defaultable   = (*RedisStreamSource)(nil)
+	_ apis.HasSpec       = (*RedisStreamSource)(nil)
+	_ duckv1.KRShaped    = (*RedisStreamSource)(nil)
 )
 
 // RedisStreamSourceSpec defines the desired state of the RedisStreamSource.
@@ -62,28 +66,826 @@ type RedisStreamSourceSpec struct {
 	// to a Redis instance
 	RedisConnection `json:",inline"`
 
-	// Stream is the name of the stream.
+	// TLS encrypts the connections of the receive adapter to Redis.
+	// +optional
+	TLS *RedisTLS `json:"tls,omitempty"`
+
+	// Auth authenticates the connections of the receive adapter to Redis,
+	// instead of the credentials of the Address URL.
+	// +optional
+	Auth *RedisAuth `json:"auth,omitempty"`
+
+	// Stream is the name of the stream. It is deprecated in favor of
+	// Streams, and kept as an alias for a single stream.
 	Stream string `json:"stream"`
 
+	// Streams are the names of the streams read with the same consumer
+	// group, e.g. the keys a topic is sharded across. Stream, when also set,
+	// is read first. At least one stream must be set.
+	// +optional
+	Streams []string `json:"streams,omitempty"`
+
 	// Group is the name of the consumer group associated to this source.
-	// When left empty, a group is automatically created for this source and
-	// deleted when this source is deleted.
+	// Defaults to <namespace>/<name> of the source, or <name> with
+	// NamespaceGroup. When left empty, a group is automatically created for
+	// each receive adapter pod and deleted with it.
 	// +optional
 	Group string `json:"group,omitempty"`
 
+	// NamespaceGroup prefixes Group with the namespace of this source, so
+	// that sources in different namespaces reading the same stream with the
+	// same group name never share the group, and steal each other's entries.
+	// +optional
+	NamespaceGroup bool `json:"namespaceGroup,omitempty"`
+
+	// TargetConfigMap names a ConfigMap, in the namespace of this source,
+	// whose stream and group keys override Stream and Group. Updating it
+	// redirects the running receive adapters to the new stream or group,
+	// after the entries delivered from the previous ones are acknowledged.
+	// +optional
+	TargetConfigMap *corev1.LocalObjectReference `json:"targetConfigMap,omitempty"`
+
 	// Number of desired consumers running in the consumer group. Defaults to 1.
 	//
 	// This is a pointer to distinguish between explicit
 	// zero and not specified.
 	// +optional
 	Consumers *int32 `json:"consumers,omitempty"`
+
+	// Replicas is the number of receive adapter pods reading the streams,
+	// sharing the consumer group: each entry is delivered by a single pod.
+	// It overrides Consumers, which also sets the number of pods. Defaults
+	// to Consumers, or 1. More than 1 replica requires a Group.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling scales the receive adapter with KEDA on the lag of the
+	// consumer group, between MinReplicas and MaxReplicas replicas. Without
+	// KEDA installed in the cluster, the receive adapter runs Replicas
+	// replicas instead.
+	// +optional
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty"`
+
+	// ExclusiveConsumer runs a single consumer at a time, e.g. for streams
+	// driving ordered state machines: the receive adapter is scaled to one
+	// pod running one consumer, which only reads the stream while it holds
+	// a Lease. A replacement of the pod, e.g. when it is evicted, waits for
+	// the lease to be released or to expire.
+	// +optional
+	ExclusiveConsumer bool `json:"exclusiveConsumer,omitempty"`
+
+	// LeaseDuration is how long the lease of an exclusive consumer lasts
+	// without being renewed: how long a replacement waits for a pod that
+	// stopped without releasing it. Defaults to 15s.
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// Resources are the compute resources of the receive adapter container,
+	// e.g. to raise its requests for a high-volume stream. Defaults to no
+	// requests or limits.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env are environment variables added to the receive adapter container,
+	// e.g. GOGC or GOMAXPROCS. They cannot override the variables the
+	// controller sets, nor start with K_ or REDIS_. The pods are replaced
+	// when a secret referenced by valueFrom.secretKeyRef changes, if it is
+	// labeled redisstream.sources.knative.dev/env-secret=true.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ServiceAccountName is the name of an existing service account the
+	// receive adapter pods run as, e.g. one bound to a workload identity.
+	// Defaults to a service account created for the source.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// NodeSelector restricts the receive adapter pods to the nodes with these
+	// labels, e.g. to the zone of the Redis instance.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let the receive adapter pods be scheduled on nodes with
+	// matching taints.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity constrains the nodes the receive adapter pods are scheduled
+	// on, and the pods they are scheduled with.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PodAnnotations are added to the receive adapter pods, e.g. to have the
+	// Vault agent injector write the file of auth.passwordFile.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// DeliveryWindow restricts reading from the stream to a daily time
+	// range. Outside of the window, entries accumulate in the stream and
+	// are read once the window opens again.
+	// +optional
+	DeliveryWindow *DeliveryWindow `json:"deliveryWindow,omitempty"`
+
+	// DeliveryDelay defers the delivery of entries until they are at least
+	// this old, computed from their ID, e.g. "30s" or "5m". Older entries
+	// are delivered immediately.
+	// +optional
+	DeliveryDelay *metav1.Duration `json:"deliveryDelay,omitempty"`
+
+	// DisableHTTP2 forces events to be delivered to the sink over HTTP/1.1.
+	// +optional
+	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
+
+	// StreamRequestBody streams the events to the sink with a request body of
+	// unknown length, sent with the chunked transfer encoding over HTTP/1.1,
+	// rather than with a fully buffered one. Combined with the gzip sink
+	// content encoding, events are compressed while being sent.
+	// +optional
+	StreamRequestBody bool `json:"streamRequestBody,omitempty"`
+
+	// MetricsPort is the port the receive adapter exposes Prometheus metrics
+	// on. Defaults to 9090.
+	// +optional
+	MetricsPort *int32 `json:"metricsPort,omitempty"`
+
+	// ProfilingPort is the port the receive adapter serves profiling data on,
+	// when profiling is enabled. Defaults to 8008.
+	// +optional
+	ProfilingPort *int32 `json:"profilingPort,omitempty"`
+
+	// SinkHeaders are HTTP headers added to every request sent to the sink.
+	// CloudEvents headers (ce-*) and Content-Type cannot be overridden.
+	// +optional
+	SinkHeaders map[string]string `json:"sinkHeaders,omitempty"`
+
+	// SinkHeadersFrom are HTTP headers added to every request sent to the sink,
+	// whose values are read from Kubernetes secrets.
+	// +optional
+	SinkHeadersFrom map[string]RedisSecretValueFromSource `json:"sinkHeadersFrom,omitempty"`
+
+	// SinkContentEncoding is the content encoding of the requests sent to the
+	// sink. The only supported encoding is "gzip", which is used only when the
+	// sink advertises it in the Accept-Encoding header of its response to an
+	// OPTIONS request. Defaults to no encoding.
+	// +optional
+	SinkContentEncoding string `json:"sinkContentEncoding,omitempty"`
+
+	// RespectRetryAfter makes the receive adapter wait for the delay requested
+	// by the Retry-After header of 429 and 503 sink responses, capped to 30
+	// seconds, before retrying. Defaults to true.
+	// +optional
+	RespectRetryAfter *bool `json:"respectRetryAfter,omitempty"`
+
+	// SequenceExtension sets the sequence CloudEvents extension attribute of
+	// the events to the ID of the entry they are built from, letting consumers
+	// detect gaps or reordering.
+	// +optional
+	SequenceExtension bool `json:"sequenceExtension,omitempty"`
+
+	// ProvenanceExtensions sets the redisadapterpod and redisadapternode
+	// CloudEvents extension attributes of the events to the names of the
+	// receive adapter pod and node that delivered them.
+	// +optional
+	ProvenanceExtensions bool `json:"provenanceExtensions,omitempty"`
+
+	// SequenceCounter sets the redisseq CloudEvents extension attribute of the
+	// events to a strictly increasing number, from a counter stored in Redis
+	// and shared by all the receive adapter replicas. Each event costs an
+	// extra round trip to Redis, serialized across replicas.
+	// +optional
+	SequenceCounter bool `json:"sequenceCounter,omitempty"`
+
+	// RedeliveredTypeSuffix, when set, is appended to the type of the events
+	// built from entries that are delivered again, such as the pending entries
+	// read after a restart, e.g. ".redelivered". The ID of the events is still
+	// the ID of their entry.
+	// +optional
+	RedeliveredTypeSuffix string `json:"redeliveredTypeSuffix,omitempty"`
+
+	// ProducerCallback, when set, confirms to producers that their entries
+	// have been delivered to the sink and acknowledged.
+	// +optional
+	ProducerCallback *ProducerCallback `json:"producerCallback,omitempty"`
+
+	// BinaryData, when set, uses the raw bytes of a field of the entries as
+	// the data of the events, instead of the JSON encoding of the entries.
+	// +optional
+	BinaryData *BinaryData `json:"binaryData,omitempty"`
+
+	// KafkaBridge, when set, produces the events as records to a Kafka topic,
+	// formatted per the CloudEvents Kafka protocol binding, through the HTTP
+	// API of a Kafka bridge such as the Strimzi Kafka Bridge. The sink is then
+	// the address of the bridge.
+	// +optional
+	KafkaBridge *KafkaBridge `json:"kafkaBridge,omitempty"`
+
+	// PartitionKey, when set, sets the partitionkey extension attribute of the
+	// events from a field of the entries, so that partitioned sinks keep the
+	// events with the same key together.
+	// +optional
+	PartitionKey *PartitionKey `json:"partitionKey,omitempty"`
+
+	// FieldMapping maps fields of the entries to CloudEvents extension
+	// attributes of the events, e.g. the tenant field to the tenantid
+	// extension, so that triggers can filter on them. The mapped fields are
+	// left out of the data of the events, made of the other fields.
+	// +optional
+	FieldMapping map[string]string `json:"fieldMapping,omitempty"`
+
+	// RequiredFields are fields of FieldMapping the entries must have. The
+	// events built from entries without one of them are not delivered to the
+	// sink, but handled per OnMissingField.
+	// +optional
+	RequiredFields []string `json:"requiredFields,omitempty"`
+
+	// OnMissingField defines what happens to the entries without one of the
+	// RequiredFields. Defaults to Drop.
+	// +optional
+	OnMissingField MissingFieldPolicy `json:"onMissingField,omitempty"`
+
+	// SchemaURL, when set, is the http or https URL of a JSON Schema the
+	// entries are validated against before delivery. Each entry is validated
+	// as a JSON object holding the value of each of its fields, as a string.
+	// +optional
+	SchemaURL string `json:"schemaURL,omitempty"`
+
+	// SchemaValidation defines what happens to the entries not matching the
+	// schema at SchemaURL. Defaults to Warn.
+	// +optional
+	SchemaValidation SchemaValidationMode `json:"schemaValidation,omitempty"`
+
+	// SchemaRefreshInterval is how often the receive adapter downloads the
+	// schema at SchemaURL again, e.g. "1m". Defaults to 5m.
+	// +optional
+	SchemaRefreshInterval *metav1.Duration `json:"schemaRefreshInterval,omitempty"`
+
+	// Type, when set, is the type of the events, instead of
+	// dev.knative.sources.redisstream.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// TypeFieldName, when set, names the field of the entries holding the
+	// type of their event, e.g. to emit order.created and order.cancelled
+	// events from the same stream. The events built from entries without
+	// that field, or with an empty value, have the type of the source.
+	// +optional
+	TypeFieldName string `json:"typeFieldName,omitempty"`
+
+	// EventSource, when set, is the source of the events, a URI-reference,
+	// instead of the address of Redis followed by the stream. The {streamKey},
+	// {namespace} and {name} variables are replaced by the key of the stream
+	// of the entries, and the namespace and name of this source, e.g.
+	// "/{namespace}/{name}/{streamKey}".
+	// +optional
+	EventSource string `json:"eventSource,omitempty"`
+
+	// ConditionalRequests sends events with the If-None-Match header set to
+	// their ID, quoted as an entity tag, so that sinks supporting conditional
+	// requests recognize redelivered events. 304 Not Modified and 412
+	// Precondition Failed responses are then treated as delivered.
+	// +optional
+	ConditionalRequests bool `json:"conditionalRequests,omitempty"`
+
+	// JSON defines how the entries are serialized to the JSON body of the
+	// events. Defaults to compact JSON with HTML characters escaped.
+	// +optional
+	JSON *JSONOptions `json:"json,omitempty"`
+
+	// DataEncoding is how the entries are serialized to the body of the
+	// events: "json", the default, or "msgpack", which is smaller and sent
+	// with the application/vnd.msgpack content type.
+	// +optional
+	DataEncoding string `json:"dataEncoding,omitempty"`
+
+	// Dedup, when set, skips the entries whose events were already emitted,
+	// according to a dedup store shared by the sources using it and kept
+	// across restarts.
+	// +optional
+	Dedup *Dedup `json:"dedup,omitempty"`
+
+	// StartID is where the consumer groups created by the receive adapter
+	// start reading from: "$" or "latest", the default, for the entries
+	// added afterwards only, "earliest" for the whole stream, or an entry ID,
+	// e.g. "0" for the whole stream too, to read the entries after it.
+	// Existing groups keep their position.
+	// +optional
+	StartID string `json:"startId,omitempty"`
+
+	// StartFrom is where the consumer groups created by the receive adapter
+	// start reading from, like StartID.
+	//
+	// Deprecated: set StartID, which takes the same values. The webhook moves
+	// StartFrom to StartID.
+	// +optional
+	StartFrom string `json:"startFrom,omitempty"`
+
+	// MinID is the ID of the first entry of the stream the source delivers.
+	// Entries before it are acknowledged and skipped, wherever the consumer
+	// group reads from, e.g. to never process the entries of a known-bad
+	// period again. The sequence number defaults to 0 when omitted.
+	// +optional
+	MinID string `json:"minId,omitempty"`
+
+	// AdditionalSinks are sinks the events are sent to along with Sink, each
+	// with its own delivery guarantee.
+	// +optional
+	AdditionalSinks []AdditionalSink `json:"additionalSinks,omitempty"`
+
+	// AuditSink, when set, receives a copy of every event once it was
+	// delivered to the sink and its entry acknowledged. Failing to deliver to the audit sink does not prevent acknowledging
+	// entries.
+	// +optional
+	AuditSink *duckv1.Destination `json:"auditSink,omitempty"`
+
+	// Delivery defines how the receive adapter retries delivering an event
+	// the sink does not accept, as for Knative Eventing subscriptions: the
+	// number of retries, their backoff policy and delay, the timeout of each
+	// request and the dead-letter sink. Defaults to 5 retries with an
+	// exponential backoff starting at 50ms, without timeout.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// DeadLetterSink, when set, receives the events the sink does not
+	// accept once the delivery retries are exhausted. Their entries are
+	// acknowledged once the dead-letter sink accepted them, and left pending
+	// when it did not.
+	// +optional
+	DeadLetterSink *duckv1.Destination `json:"deadLetterSink,omitempty"`
+
+	// FailureReport, when set, sends a single summary event when many events
+	// fail to be delivered to the sink within a time window, instead of
+	// leaving operators to notice each failure.
+	// +optional
+	FailureReport *FailureReport `json:"failureReport,omitempty"`
+
+	// OnSinkAddressPending defines what happens when the sink reference
+	// temporarily has no address, e.g. while its backing Deployment is down.
+	// Defaults to Fail.
+	// +optional
+	OnSinkAddressPending SinkAddressPendingPolicy `json:"onSinkAddressPending,omitempty"`
+
+	// OnEmptyEntry defines what happens to the entries without fields, such
+	// as pending entries deleted before they were delivered. Defaults to Skip.
+	// +optional
+	OnEmptyEntry EmptyEntryPolicy `json:"onEmptyEntry,omitempty"`
+
+	// DeadLetterStream is the key of the stream the entries are dead-lettered
+	// to, with OnEmptyEntry or Reclaim.MaxDeliveryAttempts. Defaults to
+	// redisdeadletter:<stream>.
+	// +optional
+	DeadLetterStream string `json:"deadLetterStream,omitempty"`
+
+	// WarmupPeriod is how long all the receive adapter replicas must stay
+	// ready before the source is marked as deployed, so that a replica
+	// failing right after it started, e.g. because it cannot read the stream,
+	// does not make the source flap between ready and not ready. Zero marks
+	// the source as deployed as soon as the replicas are ready. Defaults to 10s.
+	// +optional
+	WarmupPeriod *metav1.Duration `json:"warmupPeriod,omitempty"`
+
+	// DeleteGroupOnDelete destroys the consumer group in Redis when the source
+	// is deleted. It is opt-in since the group may be shared with other
+	// consumers of the stream. Groups created by the receive adapter when
+	// Group is empty are always destroyed.
+	// +optional
+	DeleteGroupOnDelete bool `json:"deleteGroupOnDelete,omitempty"`
+
+	// AckRetries is how many times acknowledging a delivered entry is retried
+	// before it is left pending, to be delivered again. Defaults to 3.
+	// +optional
+	AckRetries *int32 `json:"ackRetries,omitempty"`
+
+	// AckSweepInterval, when set, acknowledges the delivered entries together,
+	// with a single XACK every interval, instead of one XACK per entry, e.g.
+	// "1s". Entries delivered since the last sweep are delivered again if the
+	// receive adapter stops abruptly.
+	// +optional
+	AckSweepInterval *metav1.Duration `json:"ackSweepInterval,omitempty"`
+
+	// CheckpointInterval, when set, writes the ID of the last entry
+	// acknowledged to the <name>-cursor ConfigMap every CheckpointInterval
+	// entries acknowledged. A consumer group the receive adapter creates
+	// again, e.g. after the stream or the group was deleted, starts reading
+	// after that ID instead of StartID.
+	// +optional
+	CheckpointInterval int32 `json:"checkpointInterval,omitempty"`
+
+	// LagSampleInterval is how often the receive adapter samples the lag and
+	// pending entries of its consumer group, exported as metrics, and the
+	// controller reads the lag of the consumer group set with Group into the
+	// status, but not more often than every 10s, e.g. "1m". Defaults to 30s.
+	// +optional
+	LagSampleInterval *metav1.Duration `json:"lagSampleInterval,omitempty"`
+
+	// HealthCheckInterval is how often the receive adapter sends PING to
+	// Redis, reporting whether it answers in the RedisConnected condition,
+	// e.g. "10s". Defaults to 30s.
+	// +optional
+	HealthCheckInterval *metav1.Duration `json:"healthCheckInterval,omitempty"`
+
+	// TerminationGracePeriod is how long a receive adapter pod has to shut
+	// down once terminated, rounded up to the second, e.g. "1m". It stops
+	// reading new entries, completes the deliveries in flight, delivers its
+	// pending entries, and acknowledges and checkpoints them, within that
+	// period. Defaults to 30s.
+	// +optional
+	TerminationGracePeriod *metav1.Duration `json:"terminationGracePeriod,omitempty"`
+
+	// TrimStrategy, when set, trims the stream to about MaxLen entries once
+	// entries are acknowledged.
+	//
+	// Deprecated: set Trimming with OnAck. The webhook moves TrimStrategy to
+	// Trimming.
+	// +optional
+	TrimStrategy *TrimStrategy `json:"trimStrategy,omitempty"`
+
+	// Trimming, when set, trims the streams following a trimming policy, so
+	// that they do not grow unbounded: from a CronJob, even while the source
+	// is idle, or from the receive adapter once entries are acknowledged with
+	// OnAck. Trimming deletes entries: it is never done unless set.
+	// +optional
+	Trimming *Trimming `json:"trimming,omitempty"`
+
+	// ReconnectBackoff defines how the receive adapter backs off reconnecting
+	// to Redis when it cannot read from it. Defaults to an exponential backoff
+	// starting at 100ms, doubling up to 30s.
+	// +optional
+	ReconnectBackoff *ReconnectBackoff `json:"reconnectBackoff,omitempty"`
+
+	// Tracing defines how the traces of the entries are sampled. The spans of
+	// the entries continue the trace of their traceparent and tracestate
+	// fields, if any, and are exported per the config-tracing ConfigMap of
+	// Knative.
+	// +optional
+	Tracing *Tracing `json:"tracing,omitempty"`
+
+	// Reclaim, when set, periodically claims the entries left pending for too
+	// long by any consumer of the group, e.g. of a receive adapter pod that
+	// crashed, and delivers them again.
+	// +optional
+	Reclaim *Reclaim `json:"reclaim,omitempty"`
+
+	// DisableAutoAck leaves the delivered entries pending, for the sink to
+	// acknowledge them itself with XACK, using the stream, group and entry ID
+	// of the events. Entries skipped without being delivered are still
+	// acknowledged. Pending entries are delivered again when the receive
+	// adapter starts.
+	// +optional
+	DisableAutoAck bool `json:"disableAutoAck,omitempty"`
+
+	// ReadCount is how many entries each consumer reads from the stream at
+	// once, and then delivers in turn. Reading more entries at once saves
+	// round trips to Redis under high throughput. It does not apply with
+	// BatchSize, which reads the entries the batch has room for. Defaults to
+	// 10 without BatchSize.
+	// +optional
+	ReadCount int32 `json:"readCount,omitempty"`
+
+	// ReadBlockTimeout is how long each consumer waits for entries to be added
+	// to the stream before reading again, e.g. "1s". Zero waits until an entry
+	// is added. With BatchSize, it applies while the batch is empty only, the
+	// batch waiting no longer than BatchLinger once it has entries. Defaults
+	// to 5s.
+	// +optional
+	ReadBlockTimeout *metav1.Duration `json:"readBlockTimeout,omitempty"`
+
+	// BatchSize, when set, delivers up to BatchSize entries together, in a
+	// single request to the sink using the batched content mode of
+	// CloudEvents (application/cloudevents-batch+json). The entries of a batch
+	// are acknowledged once the sink accepts the whole batch.
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// BatchMaxBytes bounds the size of the body of a batch, in bytes. A batch
+	// holds at least one event, whatever its size. Defaults to no limit.
+	// +optional
+	BatchMaxBytes int64 `json:"batchMaxBytes,omitempty"`
+
+	// BatchLinger is how long a batch waits for more entries before it is
+	// delivered, even when it is not full, so that low-volume streams do not
+	// stall. It cannot be shorter than 100ms. Defaults to 1s.
+	// +optional
+	BatchLinger *metav1.Duration `json:"batchLinger,omitempty"`
+}
+
+// SinkAddressPendingPolicy defines what happens when the sink reference of a
+// RedisStreamSource has no address.
+type SinkAddressPendingPolicy string
+
+const (
+	// SinkAddressPendingFail marks the sink as not found and stops updating the
+	// receive adapter until the sink has an address again.
+	SinkAddressPendingFail SinkAddressPendingPolicy = "Fail"
+
+	// SinkAddressPendingHold keeps the last resolved address of the sink and
+	// holds the entries that cannot be delivered in the pending entries list
+	// of the consumer group, until the sink is reachable again.
+	SinkAddressPendingHold SinkAddressPendingPolicy = "Hold"
+)
+
+// EmptyEntryPolicy defines what happens to the entries without fields. Such
+// entries are always counted by the empty_entry_count metric.
+type EmptyEntryPolicy string
+
+const (
+	// EmptyEntrySkip acknowledges the entries without fields without
+	// delivering them.
+	EmptyEntrySkip EmptyEntryPolicy = "Skip"
+
+	// EmptyEntryEmit delivers the entries without fields as events like any
+	// other entry.
+	EmptyEntryEmit EmptyEntryPolicy = "Emit"
+
+	// EmptyEntryDeadLetter records the entries without fields in the
+	// dead-letter stream, then acknowledges them without delivering them.
+	EmptyEntryDeadLetter EmptyEntryPolicy = "DeadLetter"
+)
+
+// MissingFieldPolicy defines what happens to the entries without one of the
+// required fields of the field mapping.
+type MissingFieldPolicy string
+
+const (
+	// MissingFieldDrop acknowledges the entries without delivering them.
+	MissingFieldDrop MissingFieldPolicy = "Drop"
+
+	// MissingFieldDeadLetter sends the events of the entries to the
+	// dead-letter sink, then acknowledges them. The entries whose event the
+	// dead-letter sink does not accept are left pending.
+	MissingFieldDeadLetter MissingFieldPolicy = "DeadLetter"
+)
+
+// SchemaValidationMode defines what happens to the entries not matching the
+// schema of the source.
+type SchemaValidationMode string
+
+const (
+	// SchemaValidationWarn logs the entries not matching the schema, and
+	// delivers them anyway. The entries are delivered without being validated
+	// while the schema cannot be downloaded.
+	SchemaValidationWarn SchemaValidationMode = "Warn"
+
+	// SchemaValidationReject sends the events of the entries not matching the
+	// schema to the dead-letter sink instead of the sink, then acknowledges
+	// them. The entries whose event the dead-letter sink does not accept, and
+	// all the entries while the schema cannot be downloaded, are left pending.
+	SchemaValidationReject SchemaValidationMode = "Reject"
+)
+
+// ProducerCallback defines where delivery confirmations are posted. A
+// confirmation is a JSON object holding the entry ID, the event ID and the
+// time the entry was acknowledged.
+type ProducerCallback struct {
+	// URL is the URL confirmations are posted to.
+	// +optional
+	URL *apis.URL `json:"url,omitempty"`
+
+	// URLField is the name of the entry field holding the URL the confirmation
+	// of this entry is posted to. When the entry has no such field, the
+	// confirmation is posted to URL, if set.
+	// +optional
+	URLField string `json:"urlField,omitempty"`
+}
+
+// Reclaim defines when the pending entries of the consumer group are claimed
+// and delivered again.
+type Reclaim struct {
+	// MinIdleTime is how long an entry must stay pending since it was last
+	// delivered before it is reclaimed, e.g. "5m". It must be well above the
+	// time taken to deliver an entry, or entries being delivered are
+	// delivered twice.
+	MinIdleTime metav1.Duration `json:"minIdleTime"`
+
+	// Interval is how often the pending entries are checked. Defaults to
+	// MinIdleTime.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// MaxDeliveryAttempts, when set, is how many times an entry is delivered
+	// before it is recorded in the redisdeadletter:<stream> stream and
+	// acknowledged instead of being reclaimed again.
+	// +optional
+	MaxDeliveryAttempts int32 `json:"maxDeliveryAttempts,omitempty"`
+}
+
+// TrimStrategy defines how many entries the stream is trimmed to once
+// entries are acknowledged, like a Trimming with OnAck.
+type TrimStrategy struct {
+	// MaxLen is the number of entries the stream is trimmed to.
+	MaxLen int64 `json:"maxLen"`
+
+	// Exact trims the stream to exactly MaxLen entries, instead of about
+	// MaxLen entries, which lets Redis trim whole nodes of the stream only
+	// and is much more efficient.
+	// +optional
+	Exact bool `json:"exact,omitempty"`
+}
+
+// TrimmingStrategy is the XTRIM strategy of a trimming policy.
+type TrimmingStrategy string
+
+const (
+	// TrimmingMaxLen trims the stream to Threshold entries.
+	TrimmingMaxLen TrimmingStrategy = "MAXLEN"
+
+	// TrimmingMinID trims the entries whose ID is lower than Threshold.
+	TrimmingMinID TrimmingStrategy = "MINID"
+)
+
+// Trimming defines the policy the streams are trimmed with, with XTRIM.
+type Trimming struct {
+	// Strategy is MAXLEN, to trim the stream to Threshold entries, or MINID,
+	// to trim the entries whose ID is lower than Threshold.
+	Strategy TrimmingStrategy `json:"strategy"`
+
+	// Threshold is the number of entries kept with MAXLEN, or the ID of the
+	// first entry kept with MINID.
+	Threshold string `json:"threshold"`
+
+	// Approximate trims the stream with the ~ modifier, letting Redis trim
+	// whole nodes of the stream only, which is much more efficient.
+	// +optional
+	Approximate bool `json:"approximate,omitempty"`
+
+	// Schedule is the cron schedule the streams are trimmed on, e.g.
+	// "0 * * * *". Defaults to every 5 minutes.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// OnAck trims the streams from the receive adapter once entries are
+	// acknowledged, instead of on Schedule, keeping the entries still
+	// pending or not delivered yet for any consumer group of the stream. It
+	// requires MAXLEN.
+	// +optional
+	OnAck bool `json:"onAck,omitempty"`
+}
+
+// Autoscaling defines how KEDA scales the receive adapter on the lag of the
+// consumer group: the number of entries of the streams not delivered to the
+// group yet.
+type Autoscaling struct {
+	// MinReplicas is the minimum number of receive adapter replicas. Zero
+	// stops the receive adapter while the group has no lag. Defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of receive adapter replicas.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// LagThreshold is the lag each replica is expected to keep up with:
+	// KEDA runs about one replica per LagThreshold entries of lag. Defaults
+	// to 100.
+	// +optional
+	LagThreshold int64 `json:"lagThreshold,omitempty"`
+}
+
+// ReconnectBackoff defines the exponential backoff between the attempts of the
+// receive adapter to reconnect to Redis. A random jitter of up to half of each
+// delay is subtracted from it, so that the replicas do not reconnect at once.
+type ReconnectBackoff struct {
+	// InitialDelay is the delay before the first attempt, e.g. "100ms".
+	// Defaults to 100ms.
+	// +optional
+	InitialDelay *metav1.Duration `json:"initialDelay,omitempty"`
+
+	// MaxDelay caps the delay between attempts, e.g. "30s". Defaults to 30s.
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+
+	// Multiplier is the factor the delay grows by after each failed attempt,
+	// a decimal number of at least 1, e.g. "1.5". Defaults to 2.
+	// +optional
+	Multiplier string `json:"multiplier,omitempty"`
+}
+
+// Tracing defines how the traces of the entries are sampled.
+type Tracing struct {
+	// SamplingRate is the fraction of the traces started by the receive
+	// adapter that are sampled, a decimal number between 0 and 1, e.g. "0.1".
+	// The entries whose traceparent field is sampled are always sampled.
+	// Defaults to the sample-rate of the config-tracing ConfigMap.
+	// +optional
+	SamplingRate string `json:"samplingRate,omitempty"`
+}
+
+// BinaryData defines the field of the entries holding the data of the events,
+// e.g. protobuf or Avro payloads, which is delivered as is.
+type BinaryData struct {
+	// Field is the name of the field holding the data. Entries without this
+	// field are delivered as events without data.
+	Field string `json:"field"`
+
+	// ContentType is the content type of the data. Defaults to
+	// application/octet-stream.
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// AdditionalSink is a sink the events are sent to along with the sink of the
+// RedisStreamSource.
+type AdditionalSink struct {
+	duckv1.Destination `json:",inline"`
+
+	// Required sinks must get the events before their entries are
+	// acknowledged. Until they do, the entries stay in the pending entries
+	// list of the consumer group and are delivered again to all the sinks.
+	// Failing to deliver to the other sinks is only logged.
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// Dedup defines the dedup store holding the IDs of the events emitted.
+type Dedup struct {
+	// Key is the key of the Redis set, in the Redis instance of the source,
+	// holding the IDs of the events emitted. Sources using the same key skip
+	// the events emitted by each other, so it should only be shared by
+	// sources reading the same stream. Defaults to redisdedup:<stream>.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// KafkaBridge defines the Kafka topic the events are produced to.
+type KafkaBridge struct {
+	// Topic is the name of the Kafka topic.
+	Topic string `json:"topic"`
+}
+
+// PartitionKey defines how the partitionkey extension attribute of the events
+// is computed.
+type PartitionKey struct {
+	// Field is the name of the field holding the key. Entries without this
+	// field are delivered as events without partitionkey.
+	Field string `json:"field"`
+
+	// Partitions, when set, hashes the key into one of this number of
+	// partitions: the partitionkey is then a number between 0 and Partitions-1,
+	// the same for every event with the same key. Otherwise the partitionkey
+	// is the key itself.
+	// +optional
+	Partitions int32 `json:"partitions,omitempty"`
+}
+
+// FailureReport defines when and where reports of the events that could not be
+// delivered to the sink are sent.
+type FailureReport struct {
+	// Sink receives the failure reports.
+	Sink duckv1.Destination `json:"sink"`
+
+	// Threshold is the number of events that fail to be delivered within the
+	// window above which a report is sent. At most one report is sent per
+	// window.
+	Threshold int32 `json:"threshold"`
+
+	// Window is the duration over which failures are counted, e.g. "1m".
+	// Defaults to one minute.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+// JSONOptions defines how the entries are serialized to JSON.
+type JSONOptions struct {
+	// DisableHTMLEscape keeps <, > and & as is, instead of escaping them to
+	// \u003c, \u003e and \u0026.
+	// +optional
+	DisableHTMLEscape bool `json:"disableHTMLEscape,omitempty"`
+
+	// Indent emits JSON indented with two spaces, for sinks showing the body
+	// of the events to humans.
+	// +optional
+	Indent bool `json:"indent,omitempty"`
+}
+
+// DeliveryWindow defines a daily time-of-day range.
+type DeliveryWindow struct {
+	// Start is the time of day, in 24-hour HH:MM format, at which the window opens.
+	Start string `json:"start"`
+
+	// End is the time of day, in 24-hour HH:MM format, at which the window closes.
+	// When End is before Start, the window spans midnight.
+	End string `json:"end"`
+
+	// Timezone is the IANA name of the time zone Start and End are expressed
+	// in, for instance "Europe/Berlin". Defaults to UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // RedisConnection defines the address and options to connect to a Redis instance
 type RedisConnection struct {
-	// Address is the Redis TCP address
+	// Address is the Redis TCP address: host:port, or a redis:// or
+	// rediss:// URL. Required unless Sentinel or Cluster is set.
 	Address string `json:"address"`
 
+	// Sentinel connects to the Redis master through Redis Sentinel, instead
+	// of Address, following it when it is failed over.
+	// +optional
+	Sentinel *RedisSentinel `json:"sentinel,omitempty"`
+
+	// Cluster connects to the node of a Redis cluster serving the stream,
+	// instead of Address, following the slot of the stream when it moves.
+	// +optional
+	Cluster *RedisCluster `json:"cluster,omitempty"`
+
 	// Options are the connection options
 	// +optional
 	Options *RedisConnectionOptions `json:"dialOptions,omitempty"`
@@ -95,27 +897,138 @@ type RedisConnectionOptions struct {
 	// +optional
 	Password corev1.ObjectReference `json:"password,omitempty"`
 
-	// UseTLS indicates whether to use TLS or not
+	// UseTLS indicates whether to use TLS or not.
+	//
+	// Deprecated: it is ignored, set TLS on the source instead.
 	// +optional
 	UseTLS bool `json:"useTLS,omitempty"`
 
-	// SkipVerify indicates whether to skip TLS verification or not
+	// SkipVerify indicates whether to skip TLS verification or not.
+	//
+	// Deprecated: it is ignored, set TLS.InsecureSkipVerify instead.
 	// +optional
 	SkipVerify bool `json:"skipVerify,omitempty"`
 
 	// Cert is the Kubernetes secret containing the client certificate.
+	//
+	// Deprecated: it is ignored, set TLS.SecretName instead.
 	// +optional
 	Cert RedisSecretValueFromSource `json:"cert,omitempty"`
 
 	// Key is the Kubernetes secret containing the client key.
+	//
+	// Deprecated: it is ignored, set TLS.SecretName instead.
 	// +optional
 	Key RedisSecretValueFromSource `json:"key,omitempty"`
 
 	// CACert is the Kubernetes secret containing the server CA cert.
+	//
+	// Deprecated: it is ignored, set TLS.SecretName instead.
 	// +optional
 	CACert RedisSecretValueFromSource `json:"caCert,omitempty"`
 }
 
+// RedisSentinel defines the Redis Sentinel deployment monitoring the Redis
+// master.
+type RedisSentinel struct {
+	// MasterName is the name of the master monitored by the sentinels.
+	MasterName string `json:"masterName"`
+
+	// Addresses are the host:port addresses of the sentinels, asked in order
+	// for the address of the master.
+	Addresses []string `json:"addresses"`
+}
+
+// RedisCluster defines the Redis cluster holding the stream.
+type RedisCluster struct {
+	// Addresses are the host:port addresses of cluster nodes, asked in order
+	// for the node serving the stream.
+	Addresses []string `json:"addresses"`
+}
+
+// RedisTLS configures TLS for the connections to Redis.
+type RedisTLS struct {
+	// SecretName is the name of the secret, in the namespace of the source,
+	// holding the CA certificate verifying the Redis server in its ca.crt key
+	// and, for mutual TLS, the client certificate and key in its tls.crt and
+	// tls.key keys.
+	SecretName string `json:"secretName"`
+
+	// InsecureSkipVerify disables the verification of the certificate and
+	// host name of the Redis server. Only use it with self-signed
+	// certificates in development clusters.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// RedisAuth configures the credentials the receive adapter authenticates to
+// Redis with.
+type RedisAuth struct {
+	// Username is the Redis ACL user. When empty, the password authenticates
+	// the default user.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// UsernameSecretRef selects the key of the secret, in the namespace of
+	// the source, holding the Redis ACL user, instead of Username, e.g. the
+	// secret holding the password.
+	// +optional
+	UsernameSecretRef *corev1.SecretKeySelector `json:"usernameSecretRef,omitempty"`
+
+	// PasswordSecretRef selects the key of the secret, in the namespace of
+	// the source, holding the password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// PasswordFile is the absolute path of the file holding the password in
+	// the receive adapter pods, instead of PasswordSecretRef, e.g. written by
+	// the Vault agent injected through PodAnnotations. The receive adapter
+	// reads the file again when it changes, and reconnects with the new
+	// password.
+	// +optional
+	PasswordFile string `json:"passwordFile,omitempty"`
+
+	// OIDC authenticates with a short-lived password the receive adapter
+	// obtains from the security token service of the cloud provider, in
+	// exchange for the token of its service account, instead of a static
+	// password.
+	// +optional
+	OIDC *RedisOIDC `json:"oidc,omitempty"`
+}
+
+// RedisOIDC configures the exchange of the token of the service account of
+// the receive adapter for a Redis password, e.g. for Azure Cache for Redis
+// with Microsoft Entra authentication. The token, issued by Kubernetes for
+// Audience, is sent as a client assertion to the OAuth 2.0 token endpoint of
+// the security token service, and the access token it returns is the
+// password.
+type RedisOIDC struct {
+	// ServiceAccountName is the name of the existing service account the
+	// receive adapter pods run as, federated with the identity of the cloud
+	// provider allowed to access Redis. Defaults to the service account of
+	// the source.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Audience is the audience of the service account token, the one the
+	// security token service expects, e.g. api://AzureADTokenExchange.
+	Audience string `json:"audience"`
+
+	// TokenURL is the https URL of the OAuth 2.0 token endpoint of the
+	// security token service, e.g.
+	// https://login.microsoftonline.com/<tenant>/oauth2/v2.0/token.
+	TokenURL string `json:"tokenURL"`
+
+	// ClientID is the client ID of the identity of the cloud provider.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// Scope is the scope of the access token, e.g.
+	// https://redis.azure.com/.default.
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
 // RedisSecretValueFromSource represents the source of a secret value
 type RedisSecretValueFromSource struct {
 	// The Secret key to select from.
@@ -136,6 +1049,114 @@ type RedisStreamSourceStatus struct {
 	// Total number of consumers actually running in the consumer group.
 	// +optional
 	Consumers int32 `json:"consumers,omitempty"`
+
+	// Summary is a one-line summary of the configuration and state of the
+	// source: its stream, consumer group, running replicas, lag, last
+	// processed entry and active warnings.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
+	// AuditSinkURI is the resolved URI of the audit sink, if any.
+	// +optional
+	AuditSinkURI *apis.URL `json:"auditSinkUri,omitempty"`
+
+	// AdditionalSinkURIs are the resolved URIs of the additional sinks, in the
+	// same order.
+	// +optional
+	AdditionalSinkURIs []*apis.URL `json:"additionalSinkUris,omitempty"`
+
+	// DeadLetterSinkURI is the resolved URI of the dead-letter sink, if any.
+	// +optional
+	DeadLetterSinkURI *apis.URL `json:"deadLetterSinkUri,omitempty"`
+
+	// FailureReportSinkURI is the resolved URI of the failure report sink, if any.
+	// +optional
+	FailureReportSinkURI *apis.URL `json:"failureReportSinkUri,omitempty"`
+
+	// Lag is the number of entries of the streams not delivered to the
+	// consumer group yet, when last sampled by the controller. It is only set
+	// for consumer groups set with Group, on Redis 7.0 and later. The lag of
+	// each stream is in ConsumerGroupStatuses.
+	// +optional
+	Lag *int64 `json:"lag,omitempty"`
+
+	// LagObservedTime is when the controller last read Lag and
+	// ConsumerGroupStatuses from Redis.
+	// +optional
+	LagObservedTime *metav1.Time `json:"lagObservedTime,omitempty"`
+
+	// LeaseHolder is the name of the receive adapter pod consuming the
+	// stream of an exclusive consumer, as last reported by the pod.
+	// +optional
+	LeaseHolder string `json:"leaseHolder,omitempty"`
+
+	// StartID is the ID the consumer groups created by the receive adapter
+	// start reading after: $ for the entries added afterwards only, 0-0 for
+	// the whole stream, or an entry ID. Consumer groups that already exist
+	// keep their position.
+	// +optional
+	StartID string `json:"startId,omitempty"`
+
+	// LastProcessedID is the ID of the last entry of the first stream
+	// acknowledged by every consumer group reading it, as last checkpointed
+	// by the receive adapter. It is only set with CheckpointInterval.
+	// +optional
+	LastProcessedID string `json:"lastProcessedID,omitempty"`
+
+	// ConsumerGroupStatuses is an array of corresponding consumer group statuses,
+	// one per stream read by this source.
+	// +optional
+	ConsumerGroupStatuses []ConsumerGroupStatus `json:"consumerGroupStatuses,omitempty"`
+}
+
+// ConsumerGroupStatus represents the status of a consumer group.
+type ConsumerGroupStatus struct {
+	// Stream is the name of the stream the consumer group reads from.
+	Stream string `json:"stream"`
+
+	// Group is the name of the consumer group.
+	Group string `json:"group"`
+
+	// ReadyCondition indicates whether the consumer group is ready or not.
+	ReadyCondition apis.Condition `json:"ready"`
+
+	// Lag is the number of entries of the stream not delivered to the
+	// consumer group yet, on Redis 7.0 and later.
+	// +optional
+	Lag *int64 `json:"lag,omitempty"`
+
+	// LastDeliveredID is the ID of the last entry of the stream delivered to
+	// the consumer group.
+	// +optional
+	LastDeliveredID string `json:"lastDeliveredID,omitempty"`
+}
+
+// ConsumerGroupInfo describes a consumer group as observed in Redis.
+// +k8s:deepcopy-gen=false
+type ConsumerGroupInfo struct {
+	// Stream is the name of the stream the consumer group reads from.
+	Stream string
+
+	// Group is the name of the consumer group.
+	Group string
+
+	// Exists is true when the consumer group exists on the stream.
+	Exists bool
+
+	// Pending is the number of entries delivered to, but not acknowledged by,
+	// the consumers of the group.
+	Pending int64
+
+	// OldestPendingIdle is the time elapsed since the oldest pending entry
+	// was last delivered.
+	OldestPendingIdle time.Duration
+
+	// Lag is the number of entries not delivered to the group yet, nil when
+	// Redis does not report it.
+	Lag *int64
+
+	// LastDeliveredID is the ID of the last entry delivered to the group.
+	LastDeliveredID string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
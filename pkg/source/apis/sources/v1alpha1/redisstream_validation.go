@@ -0,0 +1,663 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/eventing-redis/pkg/source/apis/feature"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// SinkContentEncodingGzip compresses the requests sent to the sink with gzip.
+const SinkContentEncodingGzip = "gzip"
+
+// MaxAckRetries bounds the retries of acknowledging a delivered entry, which
+// block its consumer.
+const MaxAckRetries = 10
+
+const (
+	// DataEncodingJSON serializes the entries to JSON.
+	DataEncodingJSON = "json"
+	// DataEncodingMsgPack serializes the entries to MessagePack.
+	DataEncodingMsgPack = "msgpack"
+)
+
+const (
+	// DefaultMetricsPort is the port the receive adapter exposes metrics on by default.
+	DefaultMetricsPort int32 = 9090
+
+	// DefaultProfilingPort is the port the receive adapter serves profiling data on by default.
+	DefaultProfilingPort int32 = 8008
+)
+
+// IsReservedSinkHeader returns true for the HTTP headers set by the CloudEvents
+// HTTP binding, which cannot be overridden by sink headers.
+func IsReservedSinkHeader(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "ce-") || name == "content-type"
+}
+
+// Validate validates the RedisStreamSource. Common misconfigurations are
+// reported as warnings. Status updates are not validated, they leave the spec
+// as is.
+func (s *RedisStreamSource) Validate(ctx context.Context) *apis.FieldError {
+	if apis.IsInStatusUpdate(ctx) {
+		return nil
+	}
+	errs := s.Spec.Validate(ctx).Also(s.Spec.hints().At(apis.WarningLevel))
+	errs = errs.Also(s.validateGroupUpdate(ctx)).Also(s.validateGroupCollision(ctx))
+	errs = errs.Also(s.validateDialOptionsTLS(ctx))
+	return errs.Also(s.validateTLSSecret(ctx).ViaField("tls")).ViaField("spec")
+}
+
+// validateGroupUpdate rejects updates changing the consumer group of a source
+// reading with a named group. The receive adapter would start over with the
+// new group, leaving the old one and its pending entries behind.
+func (s *RedisStreamSource) validateGroupUpdate(ctx context.Context) *apis.FieldError {
+	if !apis.IsInUpdate(ctx) {
+		return nil
+	}
+	base, ok := apis.GetBaseline(ctx).(*RedisStreamSource)
+	if !ok || base == nil || base.ConsumerGroup() == "" {
+		return nil
+	}
+	if group := s.ConsumerGroup(); group != base.ConsumerGroup() {
+		return &apis.FieldError{
+			Message: "Immutable field changed",
+			Paths:   []string{"group"},
+			Details: fmt.Sprintf("the consumer group cannot be changed from %q to %q", base.ConsumerGroup(), group),
+		}
+	}
+	return nil
+}
+
+// Validate validates the RedisStreamSourceSpec.
+func (s *RedisStreamSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := s.validateFeatures(ctx)
+
+	if s.DeliveryWindow != nil {
+		errs = errs.Also(s.DeliveryWindow.Validate(ctx).ViaField("deliveryWindow"))
+	}
+
+	if s.TLS != nil {
+		errs = errs.Also(s.TLS.Validate(ctx).ViaField("tls"))
+	}
+
+	if s.Auth != nil {
+		errs = errs.Also(s.Auth.Validate(ctx).ViaField("auth"))
+	}
+
+	errs = errs.Also(s.validateAddress())
+	if s.Sentinel != nil {
+		errs = errs.Also(s.Sentinel.Validate(ctx).ViaField("sentinel"))
+		if s.Address != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("address", "sentinel"))
+		}
+	}
+
+	if s.Cluster != nil {
+		errs = errs.Also(s.Cluster.Validate(ctx).ViaField("cluster"))
+		if s.Address != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("address", "cluster"))
+		}
+		if s.Sentinel != nil {
+			errs = errs.Also(apis.ErrMultipleOneOf("sentinel", "cluster"))
+		}
+		errs = errs.Also(s.validateClusterSlots())
+	}
+
+	if s.TargetConfigMap != nil && s.TargetConfigMap.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("targetConfigMap.name"))
+	}
+
+	if s.DeliveryDelay != nil && s.DeliveryDelay.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.DeliveryDelay.Duration, "deliveryDelay", "must be positive"))
+	}
+
+	if s.AckRetries != nil && (*s.AckRetries < 0 || *s.AckRetries > MaxAckRetries) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.AckRetries, 0, MaxAckRetries, "ackRetries"))
+	}
+
+	if s.AckSweepInterval != nil && s.AckSweepInterval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.AckSweepInterval.Duration, "ackSweepInterval", "must be positive"))
+	}
+
+	if s.LagSampleInterval != nil && s.LagSampleInterval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.LagSampleInterval.Duration, "lagSampleInterval", "must be positive"))
+	}
+	if s.HealthCheckInterval != nil && s.HealthCheckInterval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.HealthCheckInterval.Duration, "healthCheckInterval", "must be positive"))
+	}
+	if s.TerminationGracePeriod != nil && s.TerminationGracePeriod.Duration < time.Second {
+		errs = errs.Also(apis.ErrInvalidValue(s.TerminationGracePeriod.Duration, "terminationGracePeriod", "must be at least 1s"))
+	}
+	if s.ReadCount < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.ReadCount, "readCount", "must not be negative"))
+	}
+	if s.ReadBlockTimeout != nil && s.ReadBlockTimeout.Duration < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.ReadBlockTimeout.Duration, "readBlockTimeout", "must not be negative"))
+	}
+
+	if s.TrimStrategy != nil && s.TrimStrategy.MaxLen <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.TrimStrategy.MaxLen, "trimStrategy.maxLen", "must be positive"))
+	}
+	if s.Trimming != nil {
+		errs = errs.Also(s.Trimming.Validate(ctx).ViaField("trimming"))
+		if s.TrimStrategy != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "trimStrategy is deprecated and cannot be set with trimming",
+				Paths:   []string{"trimStrategy"},
+				Details: "set only trimming, with onAck to trim once entries are acknowledged",
+			})
+		}
+	}
+	if s.ReconnectBackoff != nil {
+		errs = errs.Also(s.ReconnectBackoff.Validate(ctx).ViaField("reconnectBackoff"))
+	}
+	if s.Tracing != nil {
+		errs = errs.Also(s.Tracing.Validate(ctx).ViaField("tracing"))
+	}
+
+	if s.DisableAutoAck && s.AckSweepInterval != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("disableAutoAck", "ackSweepInterval"))
+	}
+
+	if s.Reclaim != nil {
+		errs = errs.Also(s.Reclaim.Validate(ctx).ViaField("reclaim"))
+	}
+
+	errs = errs.Also(s.validateBatch())
+
+	if s.WarmupPeriod != nil && s.WarmupPeriod.Duration < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.WarmupPeriod.Duration, "warmupPeriod", "must not be negative"))
+	}
+
+	errs = errs.Also(s.validatePorts())
+	errs = errs.Also(s.validateReplicas())
+	errs = errs.Also(s.validateExclusiveConsumer())
+	errs = errs.Also(s.validateAutoscaling())
+	errs = errs.Also(s.validateCheckpoint())
+	errs = errs.Also(s.validateResources())
+	errs = errs.Also(s.validateEnv())
+	errs = errs.Also(s.validateTolerations())
+	for _, err := range apivalidation.ValidateAnnotations(s.PodAnnotations, field.NewPath("podAnnotations")) {
+		errs = errs.Also(apis.ErrInvalidValue(err.BadValue, err.Field, err.Detail))
+	}
+
+	if s.ServiceAccountName != "" {
+		if msgs := validation.IsDNS1123Subdomain(s.ServiceAccountName); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(s.ServiceAccountName, "serviceAccountName", strings.Join(msgs, ", ")))
+		}
+	}
+	errs = errs.Also(s.validateOIDC())
+
+	for name := range s.SinkHeaders {
+		errs = errs.Also(validateSinkHeaderName(name).ViaKey(name).ViaField("sinkHeaders"))
+	}
+	for name, value := range s.SinkHeadersFrom {
+		errs = errs.Also(validateSinkHeaderName(name).ViaKey(name).ViaField("sinkHeadersFrom"))
+		if _, ok := s.SinkHeaders[name]; ok {
+			errs = errs.Also(apis.ErrMultipleOneOf("sinkHeaders["+name+"]", "sinkHeadersFrom["+name+"]"))
+		}
+		if value.SecretKeyRef == nil {
+			errs = errs.Also(apis.ErrMissingField("secretKeyRef").ViaKey(name).ViaField("sinkHeadersFrom"))
+		}
+	}
+
+	switch s.SinkContentEncoding {
+	case "", SinkContentEncodingGzip:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.SinkContentEncoding, "sinkContentEncoding"))
+	}
+
+	if s.ProducerCallback != nil {
+		errs = errs.Also(s.ProducerCallback.Validate(ctx).ViaField("producerCallback"))
+	}
+
+	errs = errs.Also(s.validateFieldMapping())
+	errs = errs.Also(s.validateSchema())
+	errs = errs.Also(s.validateEventAttributes(ctx))
+
+	if s.RedeliveredTypeSuffix != "" && !typeSuffixRegexp.MatchString(s.RedeliveredTypeSuffix) {
+		errs = errs.Also(apis.ErrInvalidValue(s.RedeliveredTypeSuffix, "redeliveredTypeSuffix"))
+	}
+
+	if s.StartID != "" && s.StartID != StartFromLatest && s.StartID != StartFromEarliest {
+		if _, err := scan.ParseID(s.StartID, scan.GroupStartPosition); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.StartID, "startId", `must be "latest", "earliest", "$" or an entry ID: `+err.Error()))
+		}
+	}
+
+	errs = errs.Also(s.validateStreams())
+
+	for _, stream := range s.GetStreams() {
+		if s.DeadLetterStream != "" && s.DeadLetterStream == stream {
+			errs = errs.Also(apis.ErrInvalidValue(s.DeadLetterStream, "deadLetterStream", "must not be a stream read"))
+		}
+	}
+
+	if s.StartFrom != "" {
+		if s.StartID != "" {
+			errs = errs.Also(&apis.FieldError{
+				Message: "startFrom is deprecated and cannot be set with startId",
+				Paths:   []string{"startFrom"},
+				Details: "set only startId, which takes the same values",
+			})
+		}
+		if s.StartFrom != StartFromLatest && s.StartFrom != StartFromEarliest {
+			if _, err := scan.ParseID(s.StartFrom, scan.EntryPosition); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(s.StartFrom, "startFrom", `must be "latest", "earliest" or an entry ID: `+err.Error()))
+			}
+		}
+	}
+
+	if s.MinID != "" {
+		if _, err := scan.ParseID(s.MinID, scan.EntryPosition); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.MinID, "minId", err.Error()))
+		}
+	}
+
+	if s.BinaryData != nil {
+		errs = errs.Also(s.BinaryData.Validate(ctx).ViaField("binaryData"))
+	}
+
+	switch s.DataEncoding {
+	case "", DataEncodingJSON:
+	case DataEncodingMsgPack:
+		if s.JSON != nil {
+			errs = errs.Also(apis.ErrMultipleOneOf("dataEncoding", "json"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.DataEncoding, "dataEncoding"))
+	}
+	if s.DataEncoding != "" && s.BinaryData != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("dataEncoding", "binaryData"))
+	}
+
+	if s.KafkaBridge != nil {
+		errs = errs.Also(s.KafkaBridge.Validate(ctx).ViaField("kafkaBridge"))
+		if s.SinkContentEncoding != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("kafkaBridge", "sinkContentEncoding"))
+		}
+	}
+
+	if s.PartitionKey != nil {
+		errs = errs.Also(s.PartitionKey.Validate(ctx).ViaField("partitionKey"))
+	}
+
+	if s.FailureReport != nil {
+		errs = errs.Also(s.FailureReport.Validate(ctx).ViaField("failureReport"))
+	}
+
+	for i, sink := range s.AdditionalSinks {
+		errs = errs.Also(sink.Validate(ctx).ViaFieldIndex("additionalSinks", i))
+	}
+
+	if s.DeadLetterSink != nil {
+		errs = errs.Also(s.DeadLetterSink.Validate(ctx).ViaField("deadLetterSink"))
+	}
+	errs = errs.Also(s.validateDelivery(ctx))
+	if s.AuditSink != nil {
+		errs = errs.Also(s.AuditSink.Validate(ctx).ViaField("auditSink"))
+	}
+
+	switch s.OnSinkAddressPending {
+	case "", SinkAddressPendingFail, SinkAddressPendingHold:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.OnSinkAddressPending, "onSinkAddressPending"))
+	}
+
+	switch s.OnEmptyEntry {
+	case "", EmptyEntrySkip, EmptyEntryEmit, EmptyEntryDeadLetter:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.OnEmptyEntry, "onEmptyEntry"))
+	}
+
+	return errs
+}
+
+// hints returns the common misconfigurations of the spec, which do not prevent
+// the source from working but likely do not do what was intended.
+func (s *RedisStreamSourceSpec) hints() *apis.FieldError {
+	var errs *apis.FieldError
+
+	if u, err := url.Parse(s.Address); err == nil && s.Address != "" {
+		if u.Port() == "6380" && u.Scheme == "redis" && s.TLS == nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "TLS is not enabled but port 6380 is typically the TLS port for Azure Cache for Redis",
+				Paths:   []string{"address"},
+			})
+		}
+	}
+
+	if s.Group == "" {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Group is empty; a consumer group is created per receive adapter pod and destroyed with the source. Set it explicitly to preserve state across renames",
+			Paths:   []string{"group"},
+		})
+		if start := s.GetStartID(); start != "" && start != scan.LastID {
+			path := "startId"
+			if s.StartFrom != "" {
+				path = "startFrom"
+			}
+			errs = errs.Also(&apis.FieldError{
+				Message: "StartID applies to the consumer groups created per receive adapter pod every time the pod starts, which reads the stream from StartID again",
+				Paths:   []string{path},
+			})
+		}
+		if s.DisableAutoAck {
+			errs = errs.Also(&apis.FieldError{
+				Message: "DisableAutoAck leaves the entries pending in the consumer groups created per receive adapter pod, which the sink cannot know and which are destroyed when the pod shuts down",
+				Paths:   []string{"disableAutoAck"},
+			})
+		}
+		if s.DeleteGroupOnDelete {
+			errs = errs.Also(&apis.FieldError{
+				Message: "DeleteGroupOnDelete has no effect when Group is empty, the consumer groups created by the receive adapter are always destroyed",
+				Paths:   []string{"deleteGroupOnDelete"},
+			})
+		}
+	}
+
+	if s.ReadCount > 0 && s.BatchSize > 0 {
+		errs = errs.Also(&apis.FieldError{
+			Message: "ReadCount has no effect with BatchSize, the consumers read the entries the batch has room for",
+			Paths:   []string{"readCount"},
+		})
+	}
+
+	return errs
+}
+
+// validateFeatures rejects the experimental fields whose feature is disabled.
+func (s *RedisStreamSourceSpec) validateFeatures(ctx context.Context) *apis.FieldError {
+	flags := feature.FromContext(ctx)
+
+	var errs *apis.FieldError
+	for _, f := range []struct {
+		name  string
+		field string
+		used  bool
+	}{
+		{name: feature.DeliveryWindow, field: "deliveryWindow", used: s.DeliveryWindow != nil},
+		{name: feature.ProducerCallback, field: "producerCallback", used: s.ProducerCallback != nil},
+		{name: feature.SinkContentEncoding, field: "sinkContentEncoding", used: s.SinkContentEncoding != ""},
+		{name: feature.KafkaBridge, field: "kafkaBridge", used: s.KafkaBridge != nil},
+	} {
+		if f.used && !flags.IsEnabled(f.name) {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("Disallowed field because the experimental feature '%s' is disabled in %s", f.name, feature.FlagsConfigName),
+				Paths:   []string{f.field},
+			})
+		}
+	}
+	return errs
+}
+
+// validatePorts validates that the metrics and profiling ports of the receive
+// adapter are valid and do not collide.
+func (s *RedisStreamSourceSpec) validatePorts() *apis.FieldError {
+	var errs *apis.FieldError
+	metricsPort, profilingPort := DefaultMetricsPort, DefaultProfilingPort
+	if s.MetricsPort != nil {
+		metricsPort = *s.MetricsPort
+		if len(validation.IsValidPortNum(int(metricsPort))) > 0 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(metricsPort, 1, 65535, "metricsPort"))
+		}
+	}
+	if s.ProfilingPort != nil {
+		profilingPort = *s.ProfilingPort
+		if len(validation.IsValidPortNum(int(profilingPort))) > 0 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(profilingPort, 1, 65535, "profilingPort"))
+		}
+	}
+	if metricsPort == profilingPort {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("metricsPort and profilingPort must be different, both are %d", metricsPort),
+			Paths:   []string{"metricsPort", "profilingPort"},
+		})
+	}
+	return errs
+}
+
+// validateTolerations rejects the tolerations the receive adapter
+// StatefulSet would be refused for.
+func (s *RedisStreamSourceSpec) validateTolerations() *apis.FieldError {
+	var errs *apis.FieldError
+	for i, toleration := range s.Tolerations {
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				errs = errs.Also(apis.ErrInvalidValue(toleration.Value, "value", "must be empty when operator is Exists").ViaIndex(i).ViaField("tolerations"))
+			}
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(toleration.Operator, "operator").ViaIndex(i).ViaField("tolerations"))
+		}
+		if toleration.Key == "" && toleration.Operator != corev1.TolerationOpExists {
+			errs = errs.Also(apis.ErrGeneric("an empty key requires operator Exists", "key").ViaIndex(i).ViaField("tolerations"))
+		}
+
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule:
+			if toleration.TolerationSeconds != nil {
+				errs = errs.Also(apis.ErrGeneric("tolerationSeconds requires effect NoExecute", "tolerationSeconds").ViaIndex(i).ViaField("tolerations"))
+			}
+		case corev1.TaintEffectNoExecute:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(toleration.Effect, "effect").ViaIndex(i).ViaField("tolerations"))
+		}
+	}
+	return errs
+}
+
+// validateAddress validates the address of Redis, required unless the source
+// connects through Redis Sentinel or to a Redis cluster. The credentials of an
+// address URL are not part of the error.
+func (s *RedisStreamSourceSpec) validateAddress() *apis.FieldError {
+	if s.Sentinel != nil || s.Cluster != nil {
+		return nil
+	}
+	if s.Address == "" {
+		return apis.ErrMissingOneOf("address", "sentinel", "cluster")
+	}
+	if _, err := scan.ParseAddress(s.Address); err != nil {
+		value := s.Address
+		if u, err := url.Parse(s.Address); err == nil {
+			value = u.Redacted()
+		}
+		details := err.Error()
+		if !strings.ContainsAny(s.Address, ":/.") {
+			details += ", set sentinel to connect through Redis Sentinel to the master of this name"
+		}
+		return apis.ErrInvalidValue(value, "address", details)
+	}
+	return nil
+}
+
+// validateResources rejects requests greater than their limit, which the
+// receive adapter pods would be refused for.
+func (s *RedisStreamSourceSpec) validateResources() *apis.FieldError {
+	names := make([]string, 0, len(s.Resources.Requests))
+	for name := range s.Resources.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var errs *apis.FieldError
+	for _, name := range names {
+		request := s.Resources.Requests[corev1.ResourceName(name)]
+		if limit, ok := s.Resources.Limits[corev1.ResourceName(name)]; ok && request.Cmp(limit) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(request.String(), apis.CurrentField,
+				fmt.Sprintf("must not be greater than the %s limit %s", name, limit.String())).ViaKey(name).ViaField("resources", "requests"))
+		}
+	}
+	return errs
+}
+
+// validateEnv validates the environment variables added to the receive
+// adapter container. Whether they override the variables the controller sets
+// is checked by the controller, which sets them.
+func (s *RedisStreamSourceSpec) validateEnv() *apis.FieldError {
+	var errs *apis.FieldError
+	seen := make(map[string]bool, len(s.Env))
+	for i, env := range s.Env {
+		var err *apis.FieldError
+		switch msgs := validation.IsEnvVarName(env.Name); {
+		case env.Name == "":
+			err = apis.ErrMissingField("name")
+		case len(msgs) > 0:
+			err = apis.ErrInvalidValue(env.Name, "name", strings.Join(msgs, ", "))
+		case seen[env.Name]:
+			err = apis.ErrGeneric(fmt.Sprintf("duplicate variable %q", env.Name), "name")
+		}
+		seen[env.Name] = true
+
+		if from := env.ValueFrom; from != nil {
+			if env.Value != "" {
+				err = err.Also(apis.ErrMultipleOneOf("value", "valueFrom"))
+			}
+			switch {
+			case from.SecretKeyRef != nil:
+				if from.SecretKeyRef.Name == "" {
+					err = err.Also(apis.ErrMissingField("valueFrom.secretKeyRef.name"))
+				}
+				if from.SecretKeyRef.Key == "" {
+					err = err.Also(apis.ErrMissingField("valueFrom.secretKeyRef.key"))
+				}
+			case from.ConfigMapKeyRef == nil && from.FieldRef == nil && from.ResourceFieldRef == nil:
+				err = err.Also(apis.ErrMissingOneOf("valueFrom.secretKeyRef", "valueFrom.configMapKeyRef", "valueFrom.fieldRef", "valueFrom.resourceFieldRef"))
+			}
+		}
+		errs = errs.Also(err.ViaFieldIndex("env", i))
+	}
+	return errs
+}
+
+func validateSinkHeaderName(name string) *apis.FieldError {
+	if msgs := validation.IsHTTPHeaderName(name); len(msgs) > 0 {
+		return apis.ErrInvalidKeyName(name, apis.CurrentField, msgs...)
+	}
+	if IsReservedSinkHeader(name) {
+		return apis.ErrInvalidKeyName(name, apis.CurrentField, "CloudEvents headers cannot be overridden")
+	}
+	return nil
+}
+
+// Validate validates the DeliveryWindow.
+func (w *DeliveryWindow) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(w.Start, "start", err.Error()))
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(w.End, "end", err.Error()))
+	}
+	if errs == nil && start == end {
+		errs = errs.Also(apis.ErrGeneric("start and end must differ", "start", "end"))
+	}
+
+	if _, err := w.Location(); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(w.Timezone, "timezone", err.Error()))
+	}
+
+	return errs
+}
+
+// Validate validates the ProducerCallback.
+func (c *ProducerCallback) Validate(ctx context.Context) *apis.FieldError {
+	if c.URL == nil && c.URLField == "" {
+		return apis.ErrMissingOneOf("url", "urlField")
+	}
+	if c.URL != nil && (c.URL.Host == "" || (c.URL.Scheme != "http" && c.URL.Scheme != "https")) {
+		return apis.ErrInvalidValue(c.URL.String(), "url")
+	}
+	return nil
+}
+
+// Validate validates the FailureReport.
+func (r *FailureReport) Validate(ctx context.Context) *apis.FieldError {
+	errs := r.Sink.Validate(ctx).ViaField("sink")
+	if r.Threshold < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(r.Threshold, "threshold", "must be at least 1"))
+	}
+	if r.Window != nil && r.Window.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(r.Window.Duration, "window", "must be positive"))
+	}
+	return errs
+}
+
+// Validate validates the BinaryData.
+func (b *BinaryData) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if b.Field == "" {
+		errs = errs.Also(apis.ErrMissingField("field"))
+	}
+	if b.ContentType != "" {
+		if _, _, err := mime.ParseMediaType(b.ContentType); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(b.ContentType, "contentType", err.Error()))
+		}
+	}
+	return errs
+}
+
+// typeSuffixRegexp matches the suffixes that keep event types made of
+// reverse-DNS names valid.
+var typeSuffixRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// kafkaTopicRegexp matches the valid names of Kafka topics.
+var kafkaTopicRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// Validate validates the KafkaBridge.
+func (k *KafkaBridge) Validate(ctx context.Context) *apis.FieldError {
+	if k.Topic == "" {
+		return apis.ErrMissingField("topic")
+	}
+	if !kafkaTopicRegexp.MatchString(k.Topic) || k.Topic == "." || k.Topic == ".." {
+		return apis.ErrInvalidValue(k.Topic, "topic", "must consist of at most 249 alphanumeric characters, '.', '_' or '-'")
+	}
+	return nil
+}
+
+// Validate validates the PartitionKey.
+func (k *PartitionKey) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if k.Field == "" {
+		errs = errs.Also(apis.ErrMissingField("field"))
+	}
+	if k.Partitions < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(k.Partitions, "partitions", "must be positive"))
+	}
+	return errs
+}
@@ -0,0 +1,1616 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/eventing-redis/pkg/source/apis/feature"
+)
+
+// withStream returns the spec reading mystream when it reads no stream, and
+// connecting to redis:6379 when it connects nowhere, so that test cases only
+// set the fields they validate.
+func withStream(spec RedisStreamSourceSpec) RedisStreamSourceSpec {
+	if spec.Stream == "" && len(spec.Streams) == 0 {
+		spec.Stream = "mystream"
+	}
+	return withAddress(spec)
+}
+
+// withAddress returns the spec connecting to redis:6379 when it has no
+// address, sentinel or cluster.
+func withAddress(spec RedisStreamSourceSpec) RedisStreamSourceSpec {
+	if spec.Address == "" && spec.Sentinel == nil && spec.Cluster == nil {
+		spec.Address = "redis:6379"
+	}
+	return spec
+}
+
+func TestRedisStreamSourceValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    RedisStreamSourceSpec
+		wantErr bool
+	}{{
+		name: "no delivery window",
+		spec: RedisStreamSourceSpec{},
+	}, {
+		name: "valid delivery window",
+		spec: RedisStreamSourceSpec{
+			DeliveryWindow: &DeliveryWindow{Start: "09:00", End: "17:30", Timezone: "Europe/Berlin"},
+		},
+	}, {
+		name: "invalid delivery window start",
+		spec: RedisStreamSourceSpec{
+			DeliveryWindow: &DeliveryWindow{Start: "9am", End: "17:00"},
+		},
+		wantErr: true,
+	}, {
+		name: "missing delivery window end",
+		spec: RedisStreamSourceSpec{
+			DeliveryWindow: &DeliveryWindow{Start: "09:00"},
+		},
+		wantErr: true,
+	}, {
+		name: "empty delivery window",
+		spec: RedisStreamSourceSpec{
+			DeliveryWindow: &DeliveryWindow{Start: "09:00", End: "09:00"},
+		},
+		wantErr: true,
+	}, {
+		name: "invalid delivery window timezone",
+		spec: RedisStreamSourceSpec{
+			DeliveryWindow: &DeliveryWindow{Start: "09:00", End: "17:00", Timezone: "Mars/Olympus_Mons"},
+		},
+		wantErr: true,
+	}, {
+		name: "sentinel",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel-0.sentinel:26379", "sentinel-1.sentinel:26379"}}},
+		},
+	}, {
+		name: "sentinel and address",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{
+				Address:  "redis://redis:6379",
+				Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}},
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "sentinel without master name",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Sentinel: &RedisSentinel{Addresses: []string{"sentinel:26379"}}},
+		},
+		wantErr: true,
+	}, {
+		name: "sentinel without addresses",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Sentinel: &RedisSentinel{MasterName: "mymaster"}},
+		},
+		wantErr: true,
+	}, {
+		name: "sentinel address without port",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel"}}},
+		},
+		wantErr: true,
+	}, {
+		name: "cluster",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster-0.redis-cluster:6379"}}},
+			Stream:          "mystream",
+		},
+	}, {
+		name: "cluster and address",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{
+				Address: "redis://redis:6379",
+				Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}},
+			},
+			Stream: "mystream",
+		},
+		wantErr: true,
+	}, {
+		name: "cluster and sentinel",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{
+				Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}},
+				Cluster:  &RedisCluster{Addresses: []string{"redis-cluster:6379"}},
+			},
+			Stream: "mystream",
+		},
+		wantErr: true,
+	}, {
+		name: "cluster without addresses",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Cluster: &RedisCluster{}},
+			Stream:          "mystream",
+		},
+		wantErr: true,
+	}, {
+		name: "cluster keys in another slot",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}}},
+			Stream:          "mystream",
+			Dedup:           &Dedup{},
+		},
+		wantErr: true,
+	}, {
+		name: "cluster keys sharing the hash tag of the stream",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}}},
+			Stream:          "{orders}",
+			Group:           "mygroup",
+			Dedup:           &Dedup{},
+			SequenceCounter: true,
+			OnEmptyEntry:    EmptyEntryDeadLetter,
+		},
+	}, {
+		name: "cluster dead-letter stream of reclaimed entries in another slot",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}}},
+			Stream:          "mystream",
+			Reclaim:         &Reclaim{MinIdleTime: metav1.Duration{Duration: time.Minute}, MaxDeliveryAttempts: 5},
+		},
+		wantErr: true,
+	}, {
+		name: "cluster dead-letter stream sharing the hash tag of the stream",
+		spec: RedisStreamSourceSpec{
+			RedisConnection:  RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}}},
+			Stream:           "{orders}",
+			Reclaim:          &Reclaim{MinIdleTime: metav1.Duration{Duration: time.Minute}, MaxDeliveryAttempts: 5},
+			DeadLetterStream: "{orders}:dlq",
+		},
+	}, {
+		name: "cluster dead-letter stream in another slot",
+		spec: RedisStreamSourceSpec{
+			RedisConnection:  RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-cluster:6379"}}},
+			Stream:           "{orders}",
+			OnEmptyEntry:     EmptyEntryDeadLetter,
+			DeadLetterStream: "orders-dlq",
+		},
+		wantErr: true,
+	}, {
+		name: "reclaim",
+		spec: RedisStreamSourceSpec{Reclaim: &Reclaim{
+			MinIdleTime:         metav1.Duration{Duration: 5 * time.Minute},
+			Interval:            &metav1.Duration{Duration: time.Minute},
+			MaxDeliveryAttempts: 5,
+		}},
+	}, {
+		name:    "reclaim without minimum idle time",
+		spec:    RedisStreamSourceSpec{Reclaim: &Reclaim{}},
+		wantErr: true,
+	}, {
+		name: "reclaim with empty interval",
+		spec: RedisStreamSourceSpec{Reclaim: &Reclaim{
+			MinIdleTime: metav1.Duration{Duration: time.Minute},
+			Interval:    &metav1.Duration{},
+		}},
+		wantErr: true,
+	}, {
+		name: "reclaim with negative max delivery attempts",
+		spec: RedisStreamSourceSpec{Reclaim: &Reclaim{
+			MinIdleTime:         metav1.Duration{Duration: time.Minute},
+			MaxDeliveryAttempts: -1,
+		}},
+		wantErr: true,
+	}, {
+		name: "metrics and profiling ports",
+		spec: RedisStreamSourceSpec{MetricsPort: pointer.Int32(19090), ProfilingPort: pointer.Int32(18008)},
+	}, {
+		name:    "invalid metrics port",
+		spec:    RedisStreamSourceSpec{MetricsPort: pointer.Int32(70000)},
+		wantErr: true,
+	}, {
+		name:    "metrics port colliding with the default profiling port",
+		spec:    RedisStreamSourceSpec{MetricsPort: pointer.Int32(DefaultProfilingPort)},
+		wantErr: true,
+	}, {
+		name:    "colliding ports",
+		spec:    RedisStreamSourceSpec{MetricsPort: pointer.Int32(9000), ProfilingPort: pointer.Int32(9000)},
+		wantErr: true,
+	}, {
+		name: "delivery delay",
+		spec: RedisStreamSourceSpec{DeliveryDelay: &metav1.Duration{Duration: 30 * time.Second}},
+	}, {
+		name:    "negative delivery delay",
+		spec:    RedisStreamSourceSpec{DeliveryDelay: &metav1.Duration{Duration: -time.Second}},
+		wantErr: true,
+	}, {
+		name: "producer callback URL",
+		spec: RedisStreamSourceSpec{
+			ProducerCallback: &ProducerCallback{URL: apis.HTTP("producer.default.svc")},
+		},
+	}, {
+		name: "producer callback URL field",
+		spec: RedisStreamSourceSpec{
+			ProducerCallback: &ProducerCallback{URLField: "callback"},
+		},
+	}, {
+		name: "gzip sink content encoding",
+		spec: RedisStreamSourceSpec{SinkContentEncoding: "gzip"},
+	}, {
+		name:    "unsupported sink content encoding",
+		spec:    RedisStreamSourceSpec{SinkContentEncoding: "br"},
+		wantErr: true,
+	}, {
+		name: "last entry start ID",
+		spec: RedisStreamSourceSpec{StartID: "$"},
+	}, {
+		name: "start ID",
+		spec: RedisStreamSourceSpec{StartID: "0"},
+	}, {
+		name:    "new entries start ID",
+		spec:    RedisStreamSourceSpec{StartID: ">"},
+		wantErr: true,
+	}, {
+		name: "start ID earliest",
+		spec: RedisStreamSourceSpec{StartID: StartFromEarliest},
+	}, {
+		name:    "invalid start ID",
+		spec:    RedisStreamSourceSpec{StartID: "yesterday"},
+		wantErr: true,
+	}, {
+		name: "start from earliest",
+		spec: RedisStreamSourceSpec{StartFrom: StartFromEarliest},
+	}, {
+		name: "start from entry ID",
+		spec: RedisStreamSourceSpec{StartFrom: "1680000000000-0"},
+	}, {
+		name:    "start from invalid ID",
+		spec:    RedisStreamSourceSpec{StartFrom: "yesterday"},
+		wantErr: true,
+	}, {
+		name:    "start from and start ID",
+		spec:    RedisStreamSourceSpec{StartFrom: StartFromLatest, StartID: "0"},
+		wantErr: true,
+	}, {
+		name: "auto ack disabled",
+		spec: RedisStreamSourceSpec{DisableAutoAck: true},
+	}, {
+		name: "auto ack disabled with ack sweep interval",
+		spec: RedisStreamSourceSpec{
+			DisableAutoAck:   true,
+			AckSweepInterval: &metav1.Duration{Duration: time.Second},
+		},
+		wantErr: true,
+	}, {
+		name: "minimum entry ID",
+		spec: RedisStreamSourceSpec{MinID: "1526919030474-55"},
+	}, {
+		name: "minimum entry ID without sequence number",
+		spec: RedisStreamSourceSpec{MinID: "1526919030474"},
+	}, {
+		name:    "invalid minimum entry ID",
+		spec:    RedisStreamSourceSpec{MinID: "1526919030474-"},
+		wantErr: true,
+	}, {
+		name:    "special minimum entry ID",
+		spec:    RedisStreamSourceSpec{MinID: "$"},
+		wantErr: true,
+	}, {
+		name: "no ack retries",
+		spec: RedisStreamSourceSpec{AckRetries: pointer.Int32(0)},
+	}, {
+		name:    "too many ack retries",
+		spec:    RedisStreamSourceSpec{AckRetries: pointer.Int32(MaxAckRetries + 1)},
+		wantErr: true,
+	}, {
+		name: "ack sweep interval",
+		spec: RedisStreamSourceSpec{AckSweepInterval: &metav1.Duration{Duration: time.Second}},
+	}, {
+		name:    "zero ack sweep interval",
+		spec:    RedisStreamSourceSpec{AckSweepInterval: &metav1.Duration{}},
+		wantErr: true,
+	}, {
+		name: "checkpoint interval",
+		spec: RedisStreamSourceSpec{CheckpointInterval: 100},
+	}, {
+		name:    "negative checkpoint interval",
+		spec:    RedisStreamSourceSpec{CheckpointInterval: -1},
+		wantErr: true,
+	}, {
+		name:    "checkpoint interval without auto ack",
+		spec:    RedisStreamSourceSpec{CheckpointInterval: 100, DisableAutoAck: true},
+		wantErr: true,
+	}, {
+		name: "lag sample interval",
+		spec: RedisStreamSourceSpec{LagSampleInterval: &metav1.Duration{Duration: 10 * time.Second}},
+	}, {
+		name:    "negative lag sample interval",
+		spec:    RedisStreamSourceSpec{LagSampleInterval: &metav1.Duration{Duration: -time.Second}},
+		wantErr: true,
+	}, {
+		name: "health check interval",
+		spec: RedisStreamSourceSpec{HealthCheckInterval: &metav1.Duration{Duration: 10 * time.Second}},
+	}, {
+		name: "termination grace period",
+		spec: RedisStreamSourceSpec{TerminationGracePeriod: &metav1.Duration{Duration: time.Minute}},
+	}, {
+		name:    "termination grace period too short",
+		spec:    RedisStreamSourceSpec{TerminationGracePeriod: &metav1.Duration{Duration: 500 * time.Millisecond}},
+		wantErr: true,
+	}, {
+		name:    "zero health check interval",
+		spec:    RedisStreamSourceSpec{HealthCheckInterval: &metav1.Duration{}},
+		wantErr: true,
+	}, {
+		name: "read options",
+		spec: RedisStreamSourceSpec{ReadCount: 10, ReadBlockTimeout: &metav1.Duration{Duration: time.Second}},
+	}, {
+		name: "read blocking until an entry is added",
+		spec: RedisStreamSourceSpec{ReadBlockTimeout: &metav1.Duration{}},
+	}, {
+		name:    "negative read count",
+		spec:    RedisStreamSourceSpec{ReadCount: -1},
+		wantErr: true,
+	}, {
+		name:    "negative read block timeout",
+		spec:    RedisStreamSourceSpec{ReadBlockTimeout: &metav1.Duration{Duration: -time.Second}},
+		wantErr: true,
+	}, {
+		name: "trim strategy",
+		spec: RedisStreamSourceSpec{TrimStrategy: &TrimStrategy{MaxLen: 10000}},
+	}, {
+		name:    "trim strategy without max length",
+		spec:    RedisStreamSourceSpec{TrimStrategy: &TrimStrategy{Exact: true}},
+		wantErr: true,
+	}, {
+		name: "trimming",
+		spec: RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "10000", Approximate: true, Schedule: "0 * * * *"}},
+	}, {
+		name: "trimming to a minimum ID",
+		spec: RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMinID, Threshold: "1700000000000-0"}},
+	}, {
+		name:    "trimming without threshold",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen}},
+		wantErr: true,
+	}, {
+		name:    "trimming to an ID with MAXLEN",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "1700000000000-0"}},
+		wantErr: true,
+	}, {
+		name:    "trimming to a special ID with MINID",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMinID, Threshold: "$"}},
+		wantErr: true,
+	}, {
+		name:    "trimming with unknown strategy",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: "MAXAGE", Threshold: "10"}},
+		wantErr: true,
+	}, {
+		name:    "trimming with invalid schedule",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "10", Schedule: "every hour"}},
+		wantErr: true,
+	}, {
+		name: "trimming on ack",
+		spec: RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "10000", Approximate: true, OnAck: true}},
+	}, {
+		name:    "trimming on ack to no entries",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "0", OnAck: true}},
+		wantErr: true,
+	}, {
+		name:    "trimming on ack to a minimum ID",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMinID, Threshold: "1700000000000-0", OnAck: true}},
+		wantErr: true,
+	}, {
+		name:    "trimming on ack with schedule",
+		spec:    RedisStreamSourceSpec{Trimming: &Trimming{Strategy: TrimmingMaxLen, Threshold: "10", OnAck: true, Schedule: "0 * * * *"}},
+		wantErr: true,
+	}, {
+		name: "trimming with trim strategy",
+		spec: RedisStreamSourceSpec{
+			TrimStrategy: &TrimStrategy{MaxLen: 10000},
+			Trimming:     &Trimming{Strategy: TrimmingMaxLen, Threshold: "10000"},
+		},
+		wantErr: true,
+	}, {
+		name: "reconnect backoff",
+		spec: RedisStreamSourceSpec{ReconnectBackoff: &ReconnectBackoff{
+			InitialDelay: &metav1.Duration{Duration: time.Second},
+			MaxDelay:     &metav1.Duration{Duration: time.Minute},
+			Multiplier:   "1.5",
+		}},
+	}, {
+		name:    "reconnect backoff initial delay above max delay",
+		spec:    RedisStreamSourceSpec{ReconnectBackoff: &ReconnectBackoff{InitialDelay: &metav1.Duration{Duration: time.Minute}, MaxDelay: &metav1.Duration{Duration: time.Second}}},
+		wantErr: true,
+	}, {
+		name:    "reconnect backoff multiplier below 1",
+		spec:    RedisStreamSourceSpec{ReconnectBackoff: &ReconnectBackoff{Multiplier: "0.5"}},
+		wantErr: true,
+	}, {
+		name:    "reconnect backoff multiplier not a number",
+		spec:    RedisStreamSourceSpec{ReconnectBackoff: &ReconnectBackoff{Multiplier: "twice"}},
+		wantErr: true,
+	}, {
+		name: "tracing",
+		spec: RedisStreamSourceSpec{Tracing: &Tracing{SamplingRate: "0.25"}},
+	}, {
+		name:    "tracing sampling rate above 1",
+		spec:    RedisStreamSourceSpec{Tracing: &Tracing{SamplingRate: "1.5"}},
+		wantErr: true,
+	}, {
+		name:    "tracing sampling rate not a number",
+		spec:    RedisStreamSourceSpec{Tracing: &Tracing{SamplingRate: "10%"}},
+		wantErr: true,
+	}, {
+		name: "batch",
+		spec: RedisStreamSourceSpec{BatchSize: 100, BatchMaxBytes: 1 << 20, BatchLinger: &metav1.Duration{Duration: 200 * time.Millisecond}},
+	}, {
+		name:    "batch size too large",
+		spec:    RedisStreamSourceSpec{BatchSize: MaxBatchSize + 1},
+		wantErr: true,
+	}, {
+		name:    "negative batch max bytes",
+		spec:    RedisStreamSourceSpec{BatchSize: 10, BatchMaxBytes: -1},
+		wantErr: true,
+	}, {
+		name:    "zero batch linger",
+		spec:    RedisStreamSourceSpec{BatchSize: 10, BatchLinger: &metav1.Duration{}},
+		wantErr: true,
+	}, {
+		name: "shortest batch linger",
+		spec: RedisStreamSourceSpec{BatchSize: 10, BatchLinger: &metav1.Duration{Duration: MinBatchLinger}},
+	}, {
+		name:    "batch linger too short",
+		spec:    RedisStreamSourceSpec{BatchSize: 10, BatchLinger: &metav1.Duration{Duration: 50 * time.Millisecond}},
+		wantErr: true,
+	}, {
+		name:    "batch linger without batch size",
+		spec:    RedisStreamSourceSpec{BatchLinger: &metav1.Duration{Duration: time.Second}},
+		wantErr: true,
+	}, {
+		name:    "batch with disableAutoAck",
+		spec:    RedisStreamSourceSpec{BatchSize: 10, DisableAutoAck: true},
+		wantErr: true,
+	}, {
+		name:    "batch with dedup",
+		spec:    RedisStreamSourceSpec{BatchSize: 10, Dedup: &Dedup{Key: "emitted"}},
+		wantErr: true,
+	}, {
+		name: "TLS",
+		spec: RedisStreamSourceSpec{TLS: &RedisTLS{SecretName: "redis-tls"}},
+	}, {
+		name:    "TLS without secret name",
+		spec:    RedisStreamSourceSpec{TLS: &RedisTLS{}},
+		wantErr: true,
+	}, {
+		name: "password auth",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+			Key:                  "password",
+		}}},
+	}, {
+		name: "ACL auth",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{Username: "knative", PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+			Key:                  "password",
+		}}},
+	}, {
+		name:    "username without password",
+		spec:    RedisStreamSourceSpec{Auth: &RedisAuth{Username: "knative"}},
+		wantErr: true,
+	}, {
+		name: "ACL user from a secret",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			UsernameSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "username"},
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+		}},
+	}, {
+		name: "ACL user secret without key",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			UsernameSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}},
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+		}},
+		wantErr: true,
+	}, {
+		name: "ACL user and ACL user secret",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			Username:          "knative",
+			UsernameSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "username"},
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+		}},
+		wantErr: true,
+	}, {
+		name: "autoscaling",
+		spec: RedisStreamSourceSpec{Group: "mygroup", Autoscaling: &Autoscaling{MinReplicas: pointer.Int32(0), MaxReplicas: 10, LagThreshold: 1000}},
+	}, {
+		name:    "autoscaling without group",
+		spec:    RedisStreamSourceSpec{Autoscaling: &Autoscaling{MaxReplicas: 10}},
+		wantErr: true,
+	}, {
+		name:    "autoscaling without maxReplicas",
+		spec:    RedisStreamSourceSpec{Group: "mygroup", Autoscaling: &Autoscaling{}},
+		wantErr: true,
+	}, {
+		name:    "autoscaling minReplicas above maxReplicas",
+		spec:    RedisStreamSourceSpec{Group: "mygroup", Autoscaling: &Autoscaling{MinReplicas: pointer.Int32(3), MaxReplicas: 2}},
+		wantErr: true,
+	}, {
+		name:    "autoscaling negative lagThreshold",
+		spec:    RedisStreamSourceSpec{Group: "mygroup", Autoscaling: &Autoscaling{MaxReplicas: 2, LagThreshold: -1}},
+		wantErr: true,
+	}, {
+		name:    "autoscaling exclusive consumer",
+		spec:    RedisStreamSourceSpec{Group: "mygroup", ExclusiveConsumer: true, Autoscaling: &Autoscaling{MaxReplicas: 2}},
+		wantErr: true,
+	}, {
+		name: "autoscaling with password file",
+		spec: RedisStreamSourceSpec{
+			Group:       "mygroup",
+			Auth:        &RedisAuth{PasswordFile: "/vault/secrets/redis-password"},
+			Autoscaling: &Autoscaling{MaxReplicas: 2},
+		},
+		wantErr: true,
+	}, {
+		name: "autoscaling with the password of the address",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://:s3cr3t@redis:6379"},
+			Group:           "mygroup",
+			Autoscaling:     &Autoscaling{MaxReplicas: 2},
+		},
+		wantErr: true,
+	}, {
+		name: "password file",
+		spec: RedisStreamSourceSpec{
+			Auth:           &RedisAuth{Username: "knative", PasswordFile: "/vault/secrets/redis-password"},
+			PodAnnotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+		},
+	}, {
+		name:    "relative password file",
+		spec:    RedisStreamSourceSpec{Auth: &RedisAuth{PasswordFile: "secrets/redis-password"}},
+		wantErr: true,
+	}, {
+		name: "password file and password secret",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+			PasswordFile:      "/vault/secrets/redis-password",
+		}},
+		wantErr: true,
+	}, {
+		name: "OIDC",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{Username: "knative", OIDC: &RedisOIDC{
+			ServiceAccountName: "redis-identity",
+			Audience:           "api://AzureADTokenExchange",
+			TokenURL:           "https://login.microsoftonline.com/tenant/oauth2/v2.0/token",
+			Scope:              "https://redis.azure.com/.default",
+		}}},
+	}, {
+		name: "OIDC and password secret",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+			OIDC:              &RedisOIDC{Audience: "api://AzureADTokenExchange", TokenURL: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token"},
+		}},
+		wantErr: true,
+	}, {
+		name: "OIDC and password file",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			PasswordFile: "/vault/secrets/redis-password",
+			OIDC:         &RedisOIDC{Audience: "api://AzureADTokenExchange", TokenURL: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token"},
+		}},
+		wantErr: true,
+	}, {
+		name:    "OIDC without audience",
+		spec:    RedisStreamSourceSpec{Auth: &RedisAuth{OIDC: &RedisOIDC{TokenURL: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token"}}},
+		wantErr: true,
+	}, {
+		name:    "OIDC with an http token URL",
+		spec:    RedisStreamSourceSpec{Auth: &RedisAuth{OIDC: &RedisOIDC{Audience: "api://AzureADTokenExchange", TokenURL: "http://sts.example.com/token"}}},
+		wantErr: true,
+	}, {
+		name: "OIDC with another service account",
+		spec: RedisStreamSourceSpec{
+			ServiceAccountName: "adapter",
+			Auth: &RedisAuth{OIDC: &RedisOIDC{
+				ServiceAccountName: "redis-identity",
+				Audience:           "api://AzureADTokenExchange",
+				TokenURL:           "https://login.microsoftonline.com/tenant/oauth2/v2.0/token",
+			}},
+		},
+		wantErr: true,
+	}, {
+		name: "autoscaling with OIDC",
+		spec: RedisStreamSourceSpec{
+			Group:       "mygroup",
+			Auth:        &RedisAuth{OIDC: &RedisOIDC{Audience: "api://AzureADTokenExchange", TokenURL: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token"}},
+			Autoscaling: &Autoscaling{MaxReplicas: 2},
+		},
+		wantErr: true,
+	}, {
+		name:    "invalid pod annotation",
+		spec:    RedisStreamSourceSpec{PodAnnotations: map[string]string{"vault.hashicorp.com/agent inject": "true"}},
+		wantErr: true,
+	}, {
+		name: "ACL user with a space",
+		spec: RedisStreamSourceSpec{Auth: &RedisAuth{
+			Username:          "knative events",
+			PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"},
+		}},
+		wantErr: true,
+	}, {
+		name:    "password secret without key",
+		spec:    RedisStreamSourceSpec{Auth: &RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}}}},
+		wantErr: true,
+	}, {
+		name: "target ConfigMap",
+		spec: RedisStreamSourceSpec{TargetConfigMap: &corev1.LocalObjectReference{Name: "redis-target"}},
+	}, {
+		name:    "target ConfigMap without name",
+		spec:    RedisStreamSourceSpec{TargetConfigMap: &corev1.LocalObjectReference{}},
+		wantErr: true,
+	}, {
+		name: "no warmup period",
+		spec: RedisStreamSourceSpec{WarmupPeriod: &metav1.Duration{}},
+	}, {
+		name:    "negative warmup period",
+		spec:    RedisStreamSourceSpec{WarmupPeriod: &metav1.Duration{Duration: -time.Second}},
+		wantErr: true,
+	}, {
+		name: "additional sinks",
+		spec: RedisStreamSourceSpec{AdditionalSinks: []AdditionalSink{
+			{Destination: duckv1.Destination{URI: apis.HTTP("primary.example.com")}, Required: true},
+			{Destination: duckv1.Destination{URI: apis.HTTP("metrics.example.com")}},
+		}},
+	}, {
+		name: "additional sink without destination",
+		spec: RedisStreamSourceSpec{AdditionalSinks: []AdditionalSink{
+			{Destination: duckv1.Destination{URI: apis.HTTP("primary.example.com")}},
+			{Required: true},
+		}},
+		wantErr: true,
+	}, {
+		name: "kafka bridge",
+		spec: RedisStreamSourceSpec{KafkaBridge: &KafkaBridge{Topic: "redis.events_v1"}},
+	}, {
+		name:    "kafka bridge without topic",
+		spec:    RedisStreamSourceSpec{KafkaBridge: &KafkaBridge{}},
+		wantErr: true,
+	}, {
+		name:    "kafka bridge with invalid topic",
+		spec:    RedisStreamSourceSpec{KafkaBridge: &KafkaBridge{Topic: "redis/events"}},
+		wantErr: true,
+	}, {
+		name:    "kafka bridge with sink content encoding",
+		spec:    RedisStreamSourceSpec{KafkaBridge: &KafkaBridge{Topic: "events"}, SinkContentEncoding: SinkContentEncodingGzip},
+		wantErr: true,
+	}, {
+		name: "redelivered type suffix",
+		spec: RedisStreamSourceSpec{RedeliveredTypeSuffix: ".redelivered"},
+	}, {
+		name:    "invalid redelivered type suffix",
+		spec:    RedisStreamSourceSpec{RedeliveredTypeSuffix: " redelivered/v1"},
+		wantErr: true,
+	}, {
+		name: "partition key",
+		spec: RedisStreamSourceSpec{PartitionKey: &PartitionKey{Field: "customer"}},
+	}, {
+		name: "hashed partition key",
+		spec: RedisStreamSourceSpec{PartitionKey: &PartitionKey{Field: "customer", Partitions: 12}},
+	}, {
+		name:    "partition key without field",
+		spec:    RedisStreamSourceSpec{PartitionKey: &PartitionKey{Partitions: 12}},
+		wantErr: true,
+	}, {
+		name:    "negative partitions",
+		spec:    RedisStreamSourceSpec{PartitionKey: &PartitionKey{Field: "customer", Partitions: -1}},
+		wantErr: true,
+	}, {
+		name: "msgpack data encoding",
+		spec: RedisStreamSourceSpec{DataEncoding: DataEncodingMsgPack},
+	}, {
+		name:    "unknown data encoding",
+		spec:    RedisStreamSourceSpec{DataEncoding: "xml"},
+		wantErr: true,
+	}, {
+		name:    "msgpack data encoding with json options",
+		spec:    RedisStreamSourceSpec{DataEncoding: DataEncodingMsgPack, JSON: &JSONOptions{Indent: true}},
+		wantErr: true,
+	}, {
+		name:    "data encoding with binary data",
+		spec:    RedisStreamSourceSpec{DataEncoding: DataEncodingJSON, BinaryData: &BinaryData{Field: "payload"}},
+		wantErr: true,
+	}, {
+		name: "binary data",
+		spec: RedisStreamSourceSpec{BinaryData: &BinaryData{Field: "payload", ContentType: "application/protobuf"}},
+	}, {
+		name:    "binary data without field",
+		spec:    RedisStreamSourceSpec{BinaryData: &BinaryData{}},
+		wantErr: true,
+	}, {
+		name:    "binary data with invalid content type",
+		spec:    RedisStreamSourceSpec{BinaryData: &BinaryData{Field: "payload", ContentType: "application/"}},
+		wantErr: true,
+	}, {
+		name: "failure report",
+		spec: RedisStreamSourceSpec{FailureReport: &FailureReport{
+			Sink:      duckv1.Destination{URI: apis.HTTP("alerts.default.svc")},
+			Threshold: 10,
+			Window:    &metav1.Duration{Duration: 5 * time.Minute},
+		}},
+	}, {
+		name: "failure report without sink and threshold",
+		spec: RedisStreamSourceSpec{FailureReport: &FailureReport{
+			Window: &metav1.Duration{Duration: time.Minute},
+		}},
+		wantErr: true,
+	}, {
+		name: "failure report with empty window",
+		spec: RedisStreamSourceSpec{FailureReport: &FailureReport{
+			Sink:      duckv1.Destination{URI: apis.HTTP("alerts.default.svc")},
+			Threshold: 10,
+			Window:    &metav1.Duration{},
+		}},
+		wantErr: true,
+	}, {
+		name: "audit sink",
+		spec: RedisStreamSourceSpec{AuditSink: &duckv1.Destination{URI: apis.HTTP("audit.default.svc")}},
+	}, {
+		name:    "empty audit sink",
+		spec:    RedisStreamSourceSpec{AuditSink: &duckv1.Destination{}},
+		wantErr: true,
+	}, {
+		name: "dead-letter sink",
+		spec: RedisStreamSourceSpec{DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.default.svc")}},
+	}, {
+		name:    "empty dead-letter sink",
+		spec:    RedisStreamSourceSpec{DeadLetterSink: &duckv1.Destination{}},
+		wantErr: true,
+	}, {
+		name: "delivery",
+		spec: RedisStreamSourceSpec{Delivery: &eventingduckv1.DeliverySpec{
+			Retry:         pointer.Int32(3),
+			BackoffPolicy: (*eventingduckv1.BackoffPolicyType)(pointer.String(string(eventingduckv1.BackoffPolicyLinear))),
+			BackoffDelay:  pointer.String("PT0.2S"),
+			Timeout:       pointer.String("PT10S"),
+		}},
+	}, {
+		name:    "negative delivery retry",
+		spec:    RedisStreamSourceSpec{Delivery: &eventingduckv1.DeliverySpec{Retry: pointer.Int32(-1)}},
+		wantErr: true,
+	}, {
+		name:    "invalid delivery backoff delay",
+		spec:    RedisStreamSourceSpec{Delivery: &eventingduckv1.DeliverySpec{BackoffDelay: pointer.String("200ms")}},
+		wantErr: true,
+	}, {
+		name:    "delivery retry after max",
+		spec:    RedisStreamSourceSpec{Delivery: &eventingduckv1.DeliverySpec{RetryAfterMax: pointer.String("PT30S")}},
+		wantErr: true,
+	}, {
+		name: "delivery dead-letter sink",
+		spec: RedisStreamSourceSpec{Delivery: &eventingduckv1.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.default.svc")}}},
+	}, {
+		name: "dead-letter sink and delivery dead-letter sink",
+		spec: RedisStreamSourceSpec{
+			DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.default.svc")},
+			Delivery:       &eventingduckv1.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.default.svc")}},
+		},
+		wantErr: true,
+	}, {
+		name: "field mapping",
+		spec: RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid", "Region-Code": "region"}},
+	}, {
+		name:    "field mapped to an invalid extension name",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenant-id"}},
+		wantErr: true,
+	}, {
+		name:    "field mapped to a too long extension name",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantidentifierofevent"}},
+		wantErr: true,
+	}, {
+		name:    "field mapped to a context attribute",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"kind": "type"}},
+		wantErr: true,
+	}, {
+		name:    "fields mapped to the same extension",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid", "customer": "tenantid"}},
+		wantErr: true,
+	}, {
+		name:    "empty field mapped",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"": "tenantid"}},
+		wantErr: true,
+	}, {
+		name: "required fields dropped",
+		spec: RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid"}, RequiredFields: []string{"tenant"}, OnMissingField: MissingFieldDrop},
+	}, {
+		name: "required fields dead-lettered",
+		spec: RedisStreamSourceSpec{
+			FieldMapping:   map[string]string{"tenant": "tenantid"},
+			RequiredFields: []string{"tenant"},
+			OnMissingField: MissingFieldDeadLetter,
+			DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.default.svc")},
+		},
+	}, {
+		name:    "required field not mapped",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid"}, RequiredFields: []string{"region"}},
+		wantErr: true,
+	}, {
+		name:    "duplicate required field",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid"}, RequiredFields: []string{"tenant", "tenant"}},
+		wantErr: true,
+	}, {
+		name:    "missing fields dead-lettered without dead-letter sink",
+		spec:    RedisStreamSourceSpec{FieldMapping: map[string]string{"tenant": "tenantid"}, RequiredFields: []string{"tenant"}, OnMissingField: MissingFieldDeadLetter},
+		wantErr: true,
+	}, {
+		name:    "invalid missing field policy",
+		spec:    RedisStreamSourceSpec{OnMissingField: "Ignore"},
+		wantErr: true,
+	}, {
+		name: "resources",
+		spec: RedisStreamSourceSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+	}, {
+		name: "env",
+		spec: RedisStreamSourceSpec{
+			Env: []corev1.EnvVar{{Name: "GOGC", Value: "50"}, {
+				Name: "API_KEY",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+					Key:                  "apiKey",
+				}},
+			}},
+		},
+	}, {
+		name:    "env without name",
+		spec:    RedisStreamSourceSpec{Env: []corev1.EnvVar{{Value: "50"}}},
+		wantErr: true,
+	}, {
+		name:    "env with invalid name",
+		spec:    RedisStreamSourceSpec{Env: []corev1.EnvVar{{Name: "1GOGC", Value: "50"}}},
+		wantErr: true,
+	}, {
+		name:    "duplicate env",
+		spec:    RedisStreamSourceSpec{Env: []corev1.EnvVar{{Name: "GOGC", Value: "50"}, {Name: "GOGC", Value: "100"}}},
+		wantErr: true,
+	}, {
+		name: "env with value and valueFrom",
+		spec: RedisStreamSourceSpec{Env: []corev1.EnvVar{{
+			Name:      "GOMAXPROCS",
+			Value:     "2",
+			ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.cpu"}},
+		}}},
+		wantErr: true,
+	}, {
+		name: "env from secret without key",
+		spec: RedisStreamSourceSpec{Env: []corev1.EnvVar{{
+			Name: "API_KEY",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+			}},
+		}}},
+		wantErr: true,
+	}, {
+		name: "resource requests greater than limits",
+		spec: RedisStreamSourceSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "exclusive consumer",
+		spec: RedisStreamSourceSpec{
+			ExclusiveConsumer: true,
+			Consumers:         pointer.Int32(1),
+			LeaseDuration:     &metav1.Duration{Duration: 30 * time.Second},
+		},
+	}, {
+		name: "exclusive consumer with several consumers",
+		spec: RedisStreamSourceSpec{
+			ExclusiveConsumer: true,
+			Consumers:         pointer.Int32(2),
+		},
+		wantErr: true,
+	}, {
+		name: "exclusive consumer with several replicas",
+		spec: RedisStreamSourceSpec{
+			ExclusiveConsumer: true,
+			Group:             "mygroup",
+			Replicas:          pointer.Int32(2),
+		},
+		wantErr: true,
+	}, {
+		name: "replicas sharing a group",
+		spec: RedisStreamSourceSpec{Group: "mygroup", Replicas: pointer.Int32(3)},
+	}, {
+		name:    "replicas without a group",
+		spec:    RedisStreamSourceSpec{Replicas: pointer.Int32(3)},
+		wantErr: true,
+	}, {
+		name: "single replica without a group",
+		spec: RedisStreamSourceSpec{Replicas: pointer.Int32(1)},
+	}, {
+		name:    "negative replicas",
+		spec:    RedisStreamSourceSpec{Group: "mygroup", Replicas: pointer.Int32(-1)},
+		wantErr: true,
+	}, {
+		name: "lease duration too short",
+		spec: RedisStreamSourceSpec{
+			ExclusiveConsumer: true,
+			LeaseDuration:     &metav1.Duration{Duration: 500 * time.Millisecond},
+		},
+		wantErr: true,
+	}, {
+		name:    "lease duration without exclusive consumer",
+		spec:    RedisStreamSourceSpec{LeaseDuration: &metav1.Duration{Duration: 30 * time.Second}},
+		wantErr: true,
+	}, {
+		name: "service account",
+		spec: RedisStreamSourceSpec{ServiceAccountName: "workload-identity"},
+	}, {
+		name:    "invalid service account",
+		spec:    RedisStreamSourceSpec{ServiceAccountName: "Workload_Identity"},
+		wantErr: true,
+	}, {
+		name: "scheduling",
+		spec: RedisStreamSourceSpec{
+			NodeSelector: map[string]string{"topology.kubernetes.io/zone": "eu-west-1a"},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "redis", Effect: corev1.TaintEffectNoSchedule},
+				{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: pointer.Int64(300)},
+			},
+			Affinity: &corev1.Affinity{},
+		},
+	}, {
+		name: "toleration with value and operator Exists",
+		spec: RedisStreamSourceSpec{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "redis"}},
+		},
+		wantErr: true,
+	}, {
+		name: "toleration without key and operator Equal",
+		spec: RedisStreamSourceSpec{
+			Tolerations: []corev1.Toleration{{Value: "redis"}},
+		},
+		wantErr: true,
+	}, {
+		name: "toleration seconds without effect NoExecute",
+		spec: RedisStreamSourceSpec{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: pointer.Int64(300)}},
+		},
+		wantErr: true,
+	}, {
+		name: "toleration with unknown effect",
+		spec: RedisStreamSourceSpec{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Effect: "NoRun"}},
+		},
+		wantErr: true,
+	}, {
+		name: "schema",
+		spec: RedisStreamSourceSpec{
+			SchemaURL:             "https://schemas.example.com/order.json",
+			SchemaValidation:      SchemaValidationWarn,
+			SchemaRefreshInterval: &metav1.Duration{Duration: time.Minute},
+		},
+	}, {
+		name: "schema violations rejected",
+		spec: RedisStreamSourceSpec{
+			SchemaURL:        "http://schemas.default.svc/order.json",
+			SchemaValidation: SchemaValidationReject,
+			DeadLetterSink:   &duckv1.Destination{URI: apis.HTTP("dls.default.svc")},
+		},
+	}, {
+		name:    "schema violations rejected without dead-letter sink",
+		spec:    RedisStreamSourceSpec{SchemaURL: "https://schemas.example.com/order.json", SchemaValidation: SchemaValidationReject},
+		wantErr: true,
+	}, {
+		name:    "schema URL not http",
+		spec:    RedisStreamSourceSpec{SchemaURL: "file:///etc/schemas/order.json"},
+		wantErr: true,
+	}, {
+		name:    "relative schema URL",
+		spec:    RedisStreamSourceSpec{SchemaURL: "/order.json"},
+		wantErr: true,
+	}, {
+		name:    "invalid schema validation mode",
+		spec:    RedisStreamSourceSpec{SchemaURL: "https://schemas.example.com/order.json", SchemaValidation: "Drop"},
+		wantErr: true,
+	}, {
+		name:    "schema validation without schema URL",
+		spec:    RedisStreamSourceSpec{SchemaValidation: SchemaValidationWarn},
+		wantErr: true,
+	}, {
+		name:    "non-positive schema refresh interval",
+		spec:    RedisStreamSourceSpec{SchemaURL: "https://schemas.example.com/order.json", SchemaRefreshInterval: &metav1.Duration{}},
+		wantErr: true,
+	}, {
+		name: "event type and source",
+		spec: RedisStreamSourceSpec{Type: "com.example.order", TypeFieldName: "kind", EventSource: "/orders/eu"},
+	}, {
+		name:    "blank event type",
+		spec:    RedisStreamSourceSpec{Type: "  "},
+		wantErr: true,
+	}, {
+		name:    "blank type field",
+		spec:    RedisStreamSourceSpec{TypeFieldName: " "},
+		wantErr: true,
+	}, {
+		name:    "event source not a URI-reference",
+		spec:    RedisStreamSourceSpec{EventSource: "%zz"},
+		wantErr: true,
+	}, {
+		name: "event source with variables",
+		spec: RedisStreamSourceSpec{EventSource: "https://{namespace}.example.com/{name}/{streamKey}"},
+	}, {
+		name: "CloudEvents overrides",
+		spec: RedisStreamSourceSpec{SourceSpec: duckv1.SourceSpec{
+			CloudEventOverrides: &duckv1.CloudEventOverrides{Extensions: map[string]string{"tenant": "acme"}},
+		}},
+	}, {
+		name: "CloudEvents overrides with an invalid extension name",
+		spec: RedisStreamSourceSpec{SourceSpec: duckv1.SourceSpec{
+			CloudEventOverrides: &duckv1.CloudEventOverrides{Extensions: map[string]string{"tenant-id": "acme"}},
+		}},
+		wantErr: true,
+	}, {
+		name: "hold on sink address pending",
+		spec: RedisStreamSourceSpec{OnSinkAddressPending: SinkAddressPendingHold},
+	}, {
+		name:    "unsupported sink address pending policy",
+		spec:    RedisStreamSourceSpec{OnSinkAddressPending: "Drop"},
+		wantErr: true,
+	}, {
+		name: "dead-letter empty entries",
+		spec: RedisStreamSourceSpec{OnEmptyEntry: EmptyEntryDeadLetter},
+	}, {
+		name: "dead-letter stream",
+		spec: RedisStreamSourceSpec{Stream: "orders", OnEmptyEntry: EmptyEntryDeadLetter, DeadLetterStream: "orders-dlq"},
+	}, {
+		name:    "dead-letter stream is the stream",
+		spec:    RedisStreamSourceSpec{Stream: "orders", OnEmptyEntry: EmptyEntryDeadLetter, DeadLetterStream: "orders"},
+		wantErr: true,
+	}, {
+		name:    "dead-letter stream is one of the streams",
+		spec:    RedisStreamSourceSpec{Stream: "orders", Streams: []string{"payments"}, OnEmptyEntry: EmptyEntryDeadLetter, DeadLetterStream: "payments"},
+		wantErr: true,
+	}, {
+		name: "several streams",
+		spec: RedisStreamSourceSpec{Stream: "orders", Streams: []string{"payments", "refunds"}},
+	}, {
+		name: "streams without stream",
+		spec: RedisStreamSourceSpec{Streams: []string{"orders", "payments"}},
+	}, {
+		name:    "empty stream in streams",
+		spec:    RedisStreamSourceSpec{Streams: []string{"orders", ""}},
+		wantErr: true,
+	}, {
+		name:    "duplicate streams",
+		spec:    RedisStreamSourceSpec{Streams: []string{"orders", "orders"}},
+		wantErr: true,
+	}, {
+		name:    "several streams with target ConfigMap",
+		spec:    RedisStreamSourceSpec{Streams: []string{"orders", "payments"}, TargetConfigMap: &corev1.LocalObjectReference{Name: "target"}},
+		wantErr: true,
+	}, {
+		name:    "several streams with dedup",
+		spec:    RedisStreamSourceSpec{Streams: []string{"orders", "payments"}, Dedup: &Dedup{}},
+		wantErr: true,
+	}, {
+		name: "several streams in the same cluster slot",
+		spec: RedisStreamSourceSpec{RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-0:6379"}}}, Streams: []string{"{shop}orders", "{shop}payments"}},
+	}, {
+		name:    "several streams in different cluster slots",
+		spec:    RedisStreamSourceSpec{RedisConnection: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-0:6379"}}}, Streams: []string{"orders", "payments"}},
+		wantErr: true,
+	}, {
+		name:    "unsupported empty entry policy",
+		spec:    RedisStreamSourceSpec{OnEmptyEntry: "Drop"},
+		wantErr: true,
+	}, {
+		name: "empty producer callback",
+		spec: RedisStreamSourceSpec{
+			ProducerCallback: &ProducerCallback{},
+		},
+		wantErr: true,
+	}, {
+		name: "relative producer callback URL",
+		spec: RedisStreamSourceSpec{
+			ProducerCallback: &ProducerCallback{URL: &apis.URL{Path: "/confirm"}},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &RedisStreamSource{Spec: withStream(test.spec)}
+			err := src.Validate(context.Background()).Filter(apis.ErrorLevel)
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		conn    RedisConnection
+		wantErr string
+	}{{
+		name: "host and port",
+		conn: RedisConnection{Address: "redis.redis.svc.cluster.local:6379"},
+	}, {
+		name: "URL",
+		conn: RedisConnection{Address: "rediss://:s3cr3t@mycache.redis.cache.windows.net:6380/1"},
+	}, {
+		name: "URL without port",
+		conn: RedisConnection{Address: "redis://redis"},
+	}, {
+		name:    "missing",
+		wantErr: "expected exactly one, got neither: spec.address, spec.cluster, spec.sentinel",
+	}, {
+		name:    "missing port",
+		conn:    RedisConnection{Address: "redis.redis.svc.cluster.local"},
+		wantErr: "invalid value: redis.redis.svc.cluster.local: spec.address",
+	}, {
+		name:    "invalid port",
+		conn:    RedisConnection{Address: "redis:70000"},
+		wantErr: `invalid port "70000"`,
+	}, {
+		name:    "invalid scheme",
+		conn:    RedisConnection{Address: "http://redis:6379"},
+		wantErr: `invalid URL scheme "http", must be redis or rediss`,
+	}, {
+		name:    "invalid URL, password redacted",
+		conn:    RedisConnection{Address: "redis://:s3cr3t@redis:6379/db"},
+		wantErr: "invalid value: redis://:xxxxx@redis:6379/db: spec.address",
+	}, {
+		name:    "sentinel master name",
+		conn:    RedisConnection{Address: "mymaster"},
+		wantErr: "set sentinel to connect through Redis Sentinel",
+	}, {
+		name:    "sentinel and cluster",
+		conn:    RedisConnection{Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}}, Cluster: &RedisCluster{Addresses: []string{"redis-0:6379"}}},
+		wantErr: "expected exactly one, got both: spec.cluster, spec.sentinel",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &RedisStreamSource{Spec: RedisStreamSourceSpec{RedisConnection: test.conn, Stream: "mystream"}}
+			err := src.Validate(context.Background()).Filter(apis.ErrorLevel)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, test.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "s3cr3t") {
+				t.Errorf("Validate() = %v, want the password redacted", err)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateSinkHeaders(t *testing.T) {
+	secret := RedisSecretValueFromSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "gateway"},
+			Key:                  "apiKey",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    RedisStreamSourceSpec
+		wantErr bool
+	}{{
+		name: "valid headers",
+		spec: RedisStreamSourceSpec{
+			SinkHeaders:     map[string]string{"X-Tenant": "acme"},
+			SinkHeadersFrom: map[string]RedisSecretValueFromSource{"X-Api-Key": secret},
+		},
+	}, {
+		name: "invalid header name",
+		spec: RedisStreamSourceSpec{
+			SinkHeaders: map[string]string{"X Tenant": "acme"},
+		},
+		wantErr: true,
+	}, {
+		name: "reserved CloudEvents header",
+		spec: RedisStreamSourceSpec{
+			SinkHeaders: map[string]string{"Ce-Type": "overridden"},
+		},
+		wantErr: true,
+	}, {
+		name: "reserved content type header",
+		spec: RedisStreamSourceSpec{
+			SinkHeadersFrom: map[string]RedisSecretValueFromSource{"content-type": secret},
+		},
+		wantErr: true,
+	}, {
+		name: "header set twice",
+		spec: RedisStreamSourceSpec{
+			SinkHeaders:     map[string]string{"X-Api-Key": "key"},
+			SinkHeadersFrom: map[string]RedisSecretValueFromSource{"X-Api-Key": secret},
+		},
+		wantErr: true,
+	}, {
+		name: "missing secret key ref",
+		spec: RedisStreamSourceSpec{
+			SinkHeadersFrom: map[string]RedisSecretValueFromSource{"X-Api-Key": {}},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &RedisStreamSource{Spec: withStream(test.spec)}
+			err := src.Validate(context.Background()).Filter(apis.ErrorLevel)
+			if got := err != nil; got != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateFeatures(t *testing.T) {
+	spec := RedisStreamSourceSpec{
+		RedisConnection:     RedisConnection{Address: "redis:6379"},
+		Stream:              "mystream",
+		DeliveryWindow:      &DeliveryWindow{Start: "09:00", End: "17:00"},
+		ProducerCallback:    &ProducerCallback{URLField: "callback"},
+		SinkContentEncoding: SinkContentEncodingGzip,
+	}
+
+	tests := []struct {
+		name      string
+		flags     feature.Flags
+		wantPaths []string
+	}{{
+		name: "default flags",
+	}, {
+		name: "enabled features",
+		flags: feature.Flags{
+			feature.DeliveryWindow:      feature.Enabled,
+			feature.ProducerCallback:    feature.Enabled,
+			feature.SinkContentEncoding: feature.Enabled,
+		},
+	}, {
+		name: "disabled features",
+		flags: feature.Flags{
+			feature.ProducerCallback:    feature.Disabled,
+			feature.SinkContentEncoding: feature.Disabled,
+		},
+		wantPaths: []string{"spec.producerCallback", "spec.sinkContentEncoding"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.flags != nil {
+				ctx = feature.ToContext(ctx, test.flags)
+			}
+			src := &RedisStreamSource{Spec: spec}
+			err := src.Validate(ctx).Filter(apis.ErrorLevel)
+			if len(test.wantPaths) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want errors on %v", test.wantPaths)
+			}
+			for _, path := range test.wantPaths {
+				if !strings.Contains(err.Error(), path) {
+					t.Errorf("Validate() = %v, want error on %s", err, path)
+				}
+			}
+			if strings.Contains(err.Error(), "spec.deliveryWindow") {
+				t.Errorf("Validate() = %v, want no error on spec.deliveryWindow", err)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateKafkaBridgeFeature(t *testing.T) {
+	src := &RedisStreamSource{Spec: withStream(RedisStreamSourceSpec{KafkaBridge: &KafkaBridge{Topic: "events"}})}
+
+	ctx := feature.ToContext(context.Background(), feature.Flags{feature.KafkaBridge: feature.Disabled})
+	if err := src.Validate(ctx).Filter(apis.ErrorLevel); err == nil || !strings.Contains(err.Error(), "spec.kafkaBridge") {
+		t.Errorf("Validate() = %v, want error on spec.kafkaBridge", err)
+	}
+
+	ctx = feature.ToContext(context.Background(), feature.Flags{feature.KafkaBridge: feature.Enabled})
+	if err := src.Validate(ctx).Filter(apis.ErrorLevel); err != nil {
+		t.Errorf("Validate() = %v, want no error", err)
+	}
+}
+
+func TestRedisStreamSourceValidateHints(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      RedisStreamSourceSpec
+		wantHints []string
+	}{{
+		name: "no hints",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+			Group:           "mygroup",
+		},
+	}, {
+		name: "TLS port without TLS",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://mycache.redis.cache.windows.net:6380"},
+			Group:           "mygroup",
+		},
+		wantHints: []string{"port 6380 is typically the TLS port"},
+	}, {
+		name: "TLS port with TLS secret",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://mycache.redis.cache.windows.net:6380"},
+			TLS:             &RedisTLS{SecretName: "redis-tls"},
+			Group:           "mygroup",
+		},
+	}, {
+		name: "TLS port with rediss",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "rediss://mycache.redis.cache.windows.net:6380"},
+			Group:           "mygroup",
+		},
+	}, {
+		name: "empty group",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+		},
+		wantHints: []string{"Group is empty"},
+	}, {
+		name: "start ID with empty group",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+			StartID:         "0",
+		},
+		wantHints: []string{"Group is empty", "reads the stream from StartID again"},
+	}, {
+		name: "auto ack disabled with empty group",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+			DisableAutoAck:  true,
+		},
+		wantHints: []string{"Group is empty", "DisableAutoAck leaves the entries pending"},
+	}, {
+		name: "delete group on delete with empty group",
+		spec: RedisStreamSourceSpec{
+			RedisConnection:     RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+			DeleteGroupOnDelete: true,
+		},
+		wantHints: []string{"Group is empty", "DeleteGroupOnDelete has no effect"},
+	}, {
+		name: "read count with batches",
+		spec: RedisStreamSourceSpec{
+			RedisConnection: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379"},
+			Group:           "mygroup",
+			ReadCount:       10,
+			BatchSize:       100,
+		},
+		wantHints: []string{"ReadCount has no effect with BatchSize"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &RedisStreamSource{Spec: withStream(test.spec)}
+			result := src.Validate(context.Background())
+			if errs := result.Filter(apis.ErrorLevel); errs != nil {
+				t.Errorf("Validate() = %v, hints must not be errors", errs)
+			}
+
+			warnings := result.Filter(apis.WarningLevel)
+			if got := len(warnings.WrappedErrors()); got != len(test.wantHints) {
+				t.Fatalf("Validate() warnings = %v, want %d hints", warnings, len(test.wantHints))
+			}
+			for _, hint := range test.wantHints {
+				if !strings.Contains(warnings.Error(), hint) {
+					t.Errorf("Validate() warnings = %v, want %q", warnings, hint)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateTLSSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string][]byte
+		notFound    bool
+		wantErr     string
+		wantWarning string
+	}{{
+		name: "CA certificate",
+		data: map[string][]byte{"ca.crt": []byte("ca")},
+	}, {
+		name: "mutual TLS",
+		data: map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}, {
+		name:    "missing CA certificate",
+		data:    map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		wantErr: `has no "ca.crt" key`,
+	}, {
+		name:    "client certificate without key",
+		data:    map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("cert")},
+		wantErr: `must have both "tls.crt" and "tls.key" keys`,
+	}, {
+		name:        "secret not found",
+		notFound:    true,
+		wantWarning: `secret "redis-tls" does not exist`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithSecretGetter(context.Background(), func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+				if namespace != "ns" || name != "redis-tls" || test.notFound {
+					return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+				}
+				return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}, Data: test.data}, nil
+			})
+
+			src := &RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+				Spec: RedisStreamSourceSpec{
+					RedisConnection: RedisConnection{Address: "rediss://redis.redis.svc.cluster.local:6379"},
+					Stream:          "mystream",
+					Group:           "mygroup",
+					TLS:             &RedisTLS{SecretName: "redis-tls"},
+				},
+			}
+			result := src.Validate(ctx)
+
+			errs := result.Filter(apis.ErrorLevel)
+			if test.wantErr == "" && errs != nil {
+				t.Errorf("Validate() = %v, want no error", errs)
+			} else if test.wantErr != "" && (errs == nil || !strings.Contains(errs.Error(), test.wantErr)) {
+				t.Errorf("Validate() = %v, want %q", errs, test.wantErr)
+			}
+			if warnings := result.Filter(apis.WarningLevel); test.wantWarning != "" && (warnings == nil || !strings.Contains(warnings.Error(), test.wantWarning)) {
+				t.Errorf("Validate() warnings = %v, want %q", warnings, test.wantWarning)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateGroupUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    RedisStreamSourceSpec
+		spec    RedisStreamSourceSpec
+		wantErr bool
+	}{{
+		name: "same group",
+		base: RedisStreamSourceSpec{Group: "mygroup"},
+		spec: RedisStreamSourceSpec{Group: "mygroup"},
+	}, {
+		name: "group set on a source without group",
+		spec: RedisStreamSourceSpec{Group: "mygroup"},
+	}, {
+		name:    "group changed",
+		base:    RedisStreamSourceSpec{Group: "mygroup"},
+		spec:    RedisStreamSourceSpec{Group: "othergroup"},
+		wantErr: true,
+	}, {
+		name:    "group removed",
+		base:    RedisStreamSourceSpec{Group: "mygroup"},
+		wantErr: true,
+	}, {
+		name:    "namespace group set",
+		base:    RedisStreamSourceSpec{Group: "mygroup"},
+		spec:    RedisStreamSourceSpec{Group: "mygroup", NamespaceGroup: true},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := metav1.ObjectMeta{Namespace: "ns", Name: "source"}
+			base := &RedisStreamSource{ObjectMeta: meta, Spec: test.base}
+			src := &RedisStreamSource{ObjectMeta: meta, Spec: test.spec}
+			err := src.validateGroupUpdate(apis.WithinUpdate(context.Background(), base))
+			if test.wantErr != (err != nil) {
+				t.Errorf("validateGroupUpdate() = %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceValidateStatusUpdate(t *testing.T) {
+	src := &RedisStreamSource{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"}}
+
+	if err := src.Validate(apis.WithinSubResourceUpdate(context.Background(), src, "status")); err != nil {
+		t.Errorf("Validate() = %v, want no error in a status update", err)
+	}
+	if err := src.Validate(apis.WithinUpdate(context.Background(), src)); err == nil {
+		t.Error("Validate() = nil, want the errors of the spec")
+	}
+}
+
+func TestRedisStreamSourceValidateDialOptionsTLS(t *testing.T) {
+	caCert := RedisSecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"},
+		Key:                  "ca.crt",
+	}}
+
+	tests := []struct {
+		name      string
+		base      *RedisConnectionOptions
+		opts      *RedisConnectionOptions
+		wantPaths []string
+	}{{
+		name: "no dial options",
+	}, {
+		name: "password only",
+		opts: &RedisConnectionOptions{Password: corev1.ObjectReference{Name: "redis-password"}},
+	}, {
+		name:      "TLS options on create",
+		opts:      &RedisConnectionOptions{UseTLS: true, SkipVerify: true, CACert: caCert},
+		wantPaths: []string{"spec.dialOptions.caCert", "spec.dialOptions.skipVerify", "spec.dialOptions.useTLS"},
+	}, {
+		name: "TLS options kept on update",
+		base: &RedisConnectionOptions{UseTLS: true, CACert: caCert},
+		opts: &RedisConnectionOptions{UseTLS: true, CACert: caCert},
+	}, {
+		name:      "TLS options added on update",
+		base:      &RedisConnectionOptions{UseTLS: true},
+		opts:      &RedisConnectionOptions{UseTLS: true, CACert: caCert},
+		wantPaths: []string{"spec.dialOptions.caCert"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := metav1.ObjectMeta{Namespace: "ns", Name: "source"}
+			ctx := apis.WithinCreate(context.Background())
+			if test.base != nil {
+				base := &RedisStreamSource{ObjectMeta: meta}
+				base.Spec.Options = test.base
+				ctx = apis.WithinUpdate(context.Background(), base)
+			}
+			src := &RedisStreamSource{ObjectMeta: meta}
+			src.Spec.Options = test.opts
+
+			err := src.validateDialOptionsTLS(ctx).ViaField("spec")
+			var got []string
+			if err != nil {
+				got = err.Paths
+				sort.Strings(got)
+			}
+			if !cmp.Equal(got, test.wantPaths) {
+				t.Errorf("validateDialOptionsTLS() paths = %v, want %v", got, test.wantPaths)
+			}
+		})
+	}
+}
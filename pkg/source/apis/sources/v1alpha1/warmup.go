@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "time"
+
+// DefaultWarmupPeriod is how long all the receive adapter replicas must be
+// ready before the source is deployed when the source has no warmup period.
+const DefaultWarmupPeriod = 10 * time.Second
+
+// GetWarmupPeriod returns how long all the receive adapter replicas must be
+// ready before the source is deployed.
+func (s *RedisStreamSourceSpec) GetWarmupPeriod() time.Duration {
+	if s.WarmupPeriod == nil {
+		return DefaultWarmupPeriod
+	}
+	return s.WarmupPeriod.Duration
+}
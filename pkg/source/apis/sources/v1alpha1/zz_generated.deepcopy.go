@@ -22,13 +22,358 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apisduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	apis "knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalSink) DeepCopyInto(out *AdditionalSink) {
+	*out = *in
+	in.Destination.DeepCopyInto(&out.Destination)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalSink.
+func (in *AdditionalSink) DeepCopy() *AdditionalSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Autoscaling) DeepCopyInto(out *Autoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Autoscaling.
+func (in *Autoscaling) DeepCopy() *Autoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Autoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BinaryData) DeepCopyInto(out *BinaryData) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BinaryData.
+func (in *BinaryData) DeepCopy() *BinaryData {
+	if in == nil {
+		return nil
+	}
+	out := new(BinaryData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerGroupStatus) DeepCopyInto(out *ConsumerGroupStatus) {
+	*out = *in
+	in.ReadyCondition.DeepCopyInto(&out.ReadyCondition)
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerGroupStatus.
+func (in *ConsumerGroupStatus) DeepCopy() *ConsumerGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Cursors) DeepCopyInto(out *Cursors) {
+	{
+		in := &in
+		*out = make(Cursors, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cursors.
+func (in Cursors) DeepCopy() Cursors {
+	if in == nil {
+		return nil
+	}
+	out := new(Cursors)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dedup) DeepCopyInto(out *Dedup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dedup.
+func (in *Dedup) DeepCopy() *Dedup {
+	if in == nil {
+		return nil
+	}
+	out := new(Dedup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryWindow) DeepCopyInto(out *DeliveryWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryWindow.
+func (in *DeliveryWindow) DeepCopy() *DeliveryWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureReport) DeepCopyInto(out *FailureReport) {
+	*out = *in
+	in.Sink.DeepCopyInto(&out.Sink)
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureReport.
+func (in *FailureReport) DeepCopy() *FailureReport {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONOptions) DeepCopyInto(out *JSONOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONOptions.
+func (in *JSONOptions) DeepCopy() *JSONOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaBridge) DeepCopyInto(out *KafkaBridge) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaBridge.
+func (in *KafkaBridge) DeepCopy() *KafkaBridge {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaBridge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionKey) DeepCopyInto(out *PartitionKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionKey.
+func (in *PartitionKey) DeepCopy() *PartitionKey {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProducerCallback) DeepCopyInto(out *ProducerCallback) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProducerCallback.
+func (in *ProducerCallback) DeepCopy() *ProducerCallback {
+	if in == nil {
+		return nil
+	}
+	out := new(ProducerCallback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Reclaim) DeepCopyInto(out *Reclaim) {
+	*out = *in
+	out.MinIdleTime = in.MinIdleTime
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reclaim.
+func (in *Reclaim) DeepCopy() *Reclaim {
+	if in == nil {
+		return nil
+	}
+	out := new(Reclaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconnectBackoff) DeepCopyInto(out *ReconnectBackoff) {
+	*out = *in
+	if in.InitialDelay != nil {
+		in, out := &in.InitialDelay, &out.InitialDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconnectBackoff.
+func (in *ReconnectBackoff) DeepCopy() *ReconnectBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconnectBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisAuth) DeepCopyInto(out *RedisAuth) {
+	*out = *in
+	if in.UsernameSecretRef != nil {
+		in, out := &in.UsernameSecretRef, &out.UsernameSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(RedisOIDC)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisAuth.
+func (in *RedisAuth) DeepCopy() *RedisAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisCluster) DeepCopyInto(out *RedisCluster) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisCluster.
+func (in *RedisCluster) DeepCopy() *RedisCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisConnection) DeepCopyInto(out *RedisConnection) {
 	*out = *in
+	if in.Sentinel != nil {
+		in, out := &in.Sentinel, &out.Sentinel
+		*out = new(RedisSentinel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(RedisCluster)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Options != nil {
 		in, out := &in.Options, &out.Options
 		*out = new(RedisConnectionOptions)
@@ -67,12 +412,143 @@ func (in *RedisConnectionOptions) DeepCopy() *RedisConnectionOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisOIDC) DeepCopyInto(out *RedisOIDC) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisOIDC.
+func (in *RedisOIDC) DeepCopy() *RedisOIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisOIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSource) DeepCopyInto(out *RedisPubSubSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSource.
+func (in *RedisPubSubSource) DeepCopy() *RedisPubSubSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisPubSubSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSourceList) DeepCopyInto(out *RedisPubSubSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisPubSubSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceList.
+func (in *RedisPubSubSourceList) DeepCopy() *RedisPubSubSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisPubSubSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSourceSpec) DeepCopyInto(out *RedisPubSubSourceSpec) {
+	*out = *in
+	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceSpec.
+func (in *RedisPubSubSourceSpec) DeepCopy() *RedisPubSubSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSourceStatus) DeepCopyInto(out *RedisPubSubSourceStatus) {
+	*out = *in
+	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	if in.DeadLetterSinkURI != nil {
+		in, out := &in.DeadLetterSinkURI, &out.DeadLetterSinkURI
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceStatus.
+func (in *RedisPubSubSourceStatus) DeepCopy() *RedisPubSubSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSecretValueFromSource) DeepCopyInto(out *RedisSecretValueFromSource) {
 	*out = *in
 	if in.SecretKeyRef != nil {
 		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(v1.SecretKeySelector)
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 	return
@@ -88,6 +564,27 @@ func (in *RedisSecretValueFromSource) DeepCopy() *RedisSecretValueFromSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSentinel) DeepCopyInto(out *RedisSentinel) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSentinel.
+func (in *RedisSentinel) DeepCopy() *RedisSentinel {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSentinel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisStreamSource) DeepCopyInto(out *RedisStreamSource) {
 	*out = *in
@@ -154,11 +651,258 @@ func (in *RedisStreamSourceSpec) DeepCopyInto(out *RedisStreamSourceSpec) {
 	*out = *in
 	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
 	in.RedisConnection.DeepCopyInto(&out.RedisConnection)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RedisTLS)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(RedisAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetConfigMap != nil {
+		in, out := &in.TargetConfigMap, &out.TargetConfigMap
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.Consumers != nil {
 		in, out := &in.Consumers, &out.Consumers
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeliveryWindow != nil {
+		in, out := &in.DeliveryWindow, &out.DeliveryWindow
+		*out = new(DeliveryWindow)
+		**out = **in
+	}
+	if in.DeliveryDelay != nil {
+		in, out := &in.DeliveryDelay, &out.DeliveryDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MetricsPort != nil {
+		in, out := &in.MetricsPort, &out.MetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProfilingPort != nil {
+		in, out := &in.ProfilingPort, &out.ProfilingPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SinkHeaders != nil {
+		in, out := &in.SinkHeaders, &out.SinkHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SinkHeadersFrom != nil {
+		in, out := &in.SinkHeadersFrom, &out.SinkHeadersFrom
+		*out = make(map[string]RedisSecretValueFromSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RespectRetryAfter != nil {
+		in, out := &in.RespectRetryAfter, &out.RespectRetryAfter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProducerCallback != nil {
+		in, out := &in.ProducerCallback, &out.ProducerCallback
+		*out = new(ProducerCallback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BinaryData != nil {
+		in, out := &in.BinaryData, &out.BinaryData
+		*out = new(BinaryData)
+		**out = **in
+	}
+	if in.KafkaBridge != nil {
+		in, out := &in.KafkaBridge, &out.KafkaBridge
+		*out = new(KafkaBridge)
+		**out = **in
+	}
+	if in.PartitionKey != nil {
+		in, out := &in.PartitionKey, &out.PartitionKey
+		*out = new(PartitionKey)
+		**out = **in
+	}
+	if in.FieldMapping != nil {
+		in, out := &in.FieldMapping, &out.FieldMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequiredFields != nil {
+		in, out := &in.RequiredFields, &out.RequiredFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaRefreshInterval != nil {
+		in, out := &in.SchemaRefreshInterval, &out.SchemaRefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = new(JSONOptions)
+		**out = **in
+	}
+	if in.Dedup != nil {
+		in, out := &in.Dedup, &out.Dedup
+		*out = new(Dedup)
+		**out = **in
+	}
+	if in.AdditionalSinks != nil {
+		in, out := &in.AdditionalSinks, &out.AdditionalSinks
+		*out = make([]AdditionalSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AuditSink != nil {
+		in, out := &in.AuditSink, &out.AuditSink
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(apisduckv1.DeliverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReport != nil {
+		in, out := &in.FailureReport, &out.FailureReport
+		*out = new(FailureReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmupPeriod != nil {
+		in, out := &in.WarmupPeriod, &out.WarmupPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AckRetries != nil {
+		in, out := &in.AckRetries, &out.AckRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AckSweepInterval != nil {
+		in, out := &in.AckSweepInterval, &out.AckSweepInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LagSampleInterval != nil {
+		in, out := &in.LagSampleInterval, &out.LagSampleInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckInterval != nil {
+		in, out := &in.HealthCheckInterval, &out.HealthCheckInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TerminationGracePeriod != nil {
+		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TrimStrategy != nil {
+		in, out := &in.TrimStrategy, &out.TrimStrategy
+		*out = new(TrimStrategy)
+		**out = **in
+	}
+	if in.Trimming != nil {
+		in, out := &in.Trimming, &out.Trimming
+		*out = new(Trimming)
+		**out = **in
+	}
+	if in.ReconnectBackoff != nil {
+		in, out := &in.ReconnectBackoff, &out.ReconnectBackoff
+		*out = new(ReconnectBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(Tracing)
+		**out = **in
+	}
+	if in.Reclaim != nil {
+		in, out := &in.Reclaim, &out.Reclaim
+		*out = new(Reclaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadBlockTimeout != nil {
+		in, out := &in.ReadBlockTimeout, &out.ReadBlockTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BatchLinger != nil {
+		in, out := &in.BatchLinger, &out.BatchLinger
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -176,6 +920,48 @@ func (in *RedisStreamSourceSpec) DeepCopy() *RedisStreamSourceSpec {
 func (in *RedisStreamSourceStatus) DeepCopyInto(out *RedisStreamSourceStatus) {
 	*out = *in
 	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	if in.AuditSinkURI != nil {
+		in, out := &in.AuditSinkURI, &out.AuditSinkURI
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalSinkURIs != nil {
+		in, out := &in.AdditionalSinkURIs, &out.AdditionalSinkURIs
+		*out = make([]*apis.URL, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(apis.URL)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.DeadLetterSinkURI != nil {
+		in, out := &in.DeadLetterSinkURI, &out.DeadLetterSinkURI
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReportSinkURI != nil {
+		in, out := &in.FailureReportSinkURI, &out.FailureReportSinkURI
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LagObservedTime != nil {
+		in, out := &in.LagObservedTime, &out.LagObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ConsumerGroupStatuses != nil {
+		in, out := &in.ConsumerGroupStatuses, &out.ConsumerGroupStatuses
+		*out = make([]ConsumerGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -188,3 +974,67 @@ func (in *RedisStreamSourceStatus) DeepCopy() *RedisStreamSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisTLS) DeepCopyInto(out *RedisTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisTLS.
+func (in *RedisTLS) DeepCopy() *RedisTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tracing) DeepCopyInto(out *Tracing) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
+	}
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrimStrategy) DeepCopyInto(out *TrimStrategy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrimStrategy.
+func (in *TrimStrategy) DeepCopy() *TrimStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(TrimStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Trimming) DeepCopyInto(out *Trimming) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Trimming.
+func (in *Trimming) DeepCopy() *Trimming {
+	if in == nil {
+		return nil
+	}
+	out := new(Trimming)
+	in.DeepCopyInto(out)
+	return out
+}
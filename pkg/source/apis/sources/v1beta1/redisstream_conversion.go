@@ -0,0 +1,245 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// ConvertTo implements apis.Convertible, converting the source to v1alpha1,
+// the version RedisStreamSources are stored as.
+func (source *RedisStreamSource) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	switch sink := to.(type) {
+	case *v1alpha1.RedisStreamSource:
+		sink.ObjectMeta = source.ObjectMeta
+		source.Spec.ConvertTo(ctx, &sink.Spec)
+		sink.Status = source.Status
+		return nil
+	default:
+		return fmt.Errorf("unknown version, got: %T", sink)
+	}
+}
+
+// ConvertTo converts the spec to the v1alpha1 spec, moving the connection
+// settings back to the top level of the spec. The deprecated v1alpha1 fields
+// dropped from v1beta1 are left empty.
+func (source *RedisStreamSourceSpec) ConvertTo(_ context.Context, sink *v1alpha1.RedisStreamSourceSpec) {
+	sink.SourceSpec = source.SourceSpec
+	sink.Address = source.Connection.Address
+	sink.Sentinel = source.Connection.Sentinel
+	sink.Cluster = source.Connection.Cluster
+	sink.Options = nil
+	if opts := source.Connection.Options; opts != nil {
+		sink.Options = &v1alpha1.RedisConnectionOptions{Password: opts.Password}
+	}
+	sink.TLS = source.Connection.TLS
+	sink.Auth = source.Connection.Auth
+	sink.Stream = source.Stream
+	sink.Streams = source.Streams
+	sink.Group = source.Group
+	sink.NamespaceGroup = source.NamespaceGroup
+	sink.TargetConfigMap = source.TargetConfigMap
+	sink.Consumers = nil
+	sink.Replicas = source.Replicas
+	sink.Autoscaling = source.Autoscaling
+	sink.ExclusiveConsumer = source.ExclusiveConsumer
+	sink.LeaseDuration = source.LeaseDuration
+	sink.Resources = source.Resources
+	sink.Env = source.Env
+	sink.ServiceAccountName = source.ServiceAccountName
+	sink.NodeSelector = source.NodeSelector
+	sink.Tolerations = source.Tolerations
+	sink.Affinity = source.Affinity
+	sink.PodAnnotations = source.PodAnnotations
+	sink.DeliveryWindow = source.DeliveryWindow
+	sink.DeliveryDelay = source.DeliveryDelay
+	sink.DisableHTTP2 = source.DisableHTTP2
+	sink.StreamRequestBody = source.StreamRequestBody
+	sink.MetricsPort = source.MetricsPort
+	sink.ProfilingPort = source.ProfilingPort
+	sink.SinkHeaders = source.SinkHeaders
+	sink.SinkHeadersFrom = source.SinkHeadersFrom
+	sink.SinkContentEncoding = source.SinkContentEncoding
+	sink.RespectRetryAfter = source.RespectRetryAfter
+	sink.SequenceExtension = source.SequenceExtension
+	sink.ProvenanceExtensions = source.ProvenanceExtensions
+	sink.SequenceCounter = source.SequenceCounter
+	sink.RedeliveredTypeSuffix = source.RedeliveredTypeSuffix
+	sink.ProducerCallback = source.ProducerCallback
+	sink.BinaryData = source.BinaryData
+	sink.KafkaBridge = source.KafkaBridge
+	sink.PartitionKey = source.PartitionKey
+	sink.FieldMapping = source.FieldMapping
+	sink.RequiredFields = source.RequiredFields
+	sink.OnMissingField = source.OnMissingField
+	sink.SchemaURL = source.SchemaURL
+	sink.SchemaValidation = source.SchemaValidation
+	sink.SchemaRefreshInterval = source.SchemaRefreshInterval
+	sink.Type = source.Type
+	sink.TypeFieldName = source.TypeFieldName
+	sink.EventSource = source.EventSource
+	sink.ConditionalRequests = source.ConditionalRequests
+	sink.JSON = source.JSON
+	sink.DataEncoding = source.DataEncoding
+	sink.Dedup = source.Dedup
+	sink.StartID = source.StartID
+	sink.StartFrom = ""
+	sink.MinID = source.MinID
+	sink.AdditionalSinks = source.AdditionalSinks
+	sink.AuditSink = source.AuditSink
+	sink.Delivery = source.Delivery
+	sink.DeadLetterSink = source.DeadLetterSink
+	sink.FailureReport = source.FailureReport
+	sink.OnSinkAddressPending = source.OnSinkAddressPending
+	sink.OnEmptyEntry = source.OnEmptyEntry
+	sink.DeadLetterStream = source.DeadLetterStream
+	sink.WarmupPeriod = source.WarmupPeriod
+	sink.DeleteGroupOnDelete = source.DeleteGroupOnDelete
+	sink.AckRetries = source.AckRetries
+	sink.AckSweepInterval = source.AckSweepInterval
+	sink.CheckpointInterval = source.CheckpointInterval
+	sink.LagSampleInterval = source.LagSampleInterval
+	sink.HealthCheckInterval = source.HealthCheckInterval
+	sink.TerminationGracePeriod = source.TerminationGracePeriod
+	sink.TrimStrategy = nil
+	sink.Trimming = source.Trimming
+	sink.ReconnectBackoff = source.ReconnectBackoff
+	sink.Tracing = source.Tracing
+	sink.Reclaim = source.Reclaim
+	sink.DisableAutoAck = source.DisableAutoAck
+	sink.ReadCount = source.ReadCount
+	sink.ReadBlockTimeout = source.ReadBlockTimeout
+	sink.BatchSize = source.BatchSize
+	sink.BatchMaxBytes = source.BatchMaxBytes
+	sink.BatchLinger = source.BatchLinger
+}
+
+// ConvertFrom implements apis.Convertible, converting the source from
+// v1alpha1, the version RedisStreamSources are stored as.
+func (sink *RedisStreamSource) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	switch source := from.(type) {
+	case *v1alpha1.RedisStreamSource:
+		sink.ObjectMeta = source.ObjectMeta
+		sink.Spec.ConvertFrom(ctx, &source.Spec)
+		sink.Status = source.Status
+		return nil
+	default:
+		return fmt.Errorf("unknown version, got: %T", source)
+	}
+}
+
+// ConvertFrom converts the spec from the v1alpha1 spec, gathering its
+// connection settings under Connection. The deprecated v1alpha1 fields are
+// converted to the fields they duplicate: Consumers to Replicas, StartFrom to
+// StartID and TrimStrategy to Trimming, unless those are set. The TLS dial
+// options, which are ignored, are dropped.
+func (sink *RedisStreamSourceSpec) ConvertFrom(_ context.Context, source *v1alpha1.RedisStreamSourceSpec) {
+	sink.SourceSpec = source.SourceSpec
+	sink.Connection = RedisConnection{
+		Address:  source.Address,
+		Sentinel: source.Sentinel,
+		Cluster:  source.Cluster,
+		TLS:      source.TLS,
+		Auth:     source.Auth,
+	}
+	if opts := source.Options; opts != nil {
+		sink.Connection.Options = &RedisConnectionOptions{Password: opts.Password}
+	}
+	sink.Stream = source.Stream
+	sink.Streams = source.Streams
+	sink.Group = source.Group
+	sink.NamespaceGroup = source.NamespaceGroup
+	sink.TargetConfigMap = source.TargetConfigMap
+	sink.Replicas = source.GetReplicas()
+	sink.Autoscaling = source.Autoscaling
+	sink.ExclusiveConsumer = source.ExclusiveConsumer
+	sink.LeaseDuration = source.LeaseDuration
+	sink.Resources = source.Resources
+	sink.Env = source.Env
+	sink.ServiceAccountName = source.ServiceAccountName
+	sink.NodeSelector = source.NodeSelector
+	sink.Tolerations = source.Tolerations
+	sink.Affinity = source.Affinity
+	sink.PodAnnotations = source.PodAnnotations
+	sink.DeliveryWindow = source.DeliveryWindow
+	sink.DeliveryDelay = source.DeliveryDelay
+	sink.DisableHTTP2 = source.DisableHTTP2
+	sink.StreamRequestBody = source.StreamRequestBody
+	sink.MetricsPort = source.MetricsPort
+	sink.ProfilingPort = source.ProfilingPort
+	sink.SinkHeaders = source.SinkHeaders
+	sink.SinkHeadersFrom = source.SinkHeadersFrom
+	sink.SinkContentEncoding = source.SinkContentEncoding
+	sink.RespectRetryAfter = source.RespectRetryAfter
+	sink.SequenceExtension = source.SequenceExtension
+	sink.ProvenanceExtensions = source.ProvenanceExtensions
+	sink.SequenceCounter = source.SequenceCounter
+	sink.RedeliveredTypeSuffix = source.RedeliveredTypeSuffix
+	sink.ProducerCallback = source.ProducerCallback
+	sink.BinaryData = source.BinaryData
+	sink.KafkaBridge = source.KafkaBridge
+	sink.PartitionKey = source.PartitionKey
+	sink.FieldMapping = source.FieldMapping
+	sink.RequiredFields = source.RequiredFields
+	sink.OnMissingField = source.OnMissingField
+	sink.SchemaURL = source.SchemaURL
+	sink.SchemaValidation = source.SchemaValidation
+	sink.SchemaRefreshInterval = source.SchemaRefreshInterval
+	sink.Type = source.Type
+	sink.TypeFieldName = source.TypeFieldName
+	sink.EventSource = source.EventSource
+	sink.ConditionalRequests = source.ConditionalRequests
+	sink.JSON = source.JSON
+	sink.DataEncoding = source.DataEncoding
+	sink.Dedup = source.Dedup
+	sink.StartID = source.StartID
+	if sink.StartID == "" {
+		sink.StartID = source.StartFrom
+	}
+	sink.MinID = source.MinID
+	sink.AdditionalSinks = source.AdditionalSinks
+	sink.AuditSink = source.AuditSink
+	sink.Delivery = source.Delivery
+	sink.DeadLetterSink = source.DeadLetterSink
+	sink.FailureReport = source.FailureReport
+	sink.OnSinkAddressPending = source.OnSinkAddressPending
+	sink.OnEmptyEntry = source.OnEmptyEntry
+	sink.DeadLetterStream = source.DeadLetterStream
+	sink.WarmupPeriod = source.WarmupPeriod
+	sink.DeleteGroupOnDelete = source.DeleteGroupOnDelete
+	sink.AckRetries = source.AckRetries
+	sink.AckSweepInterval = source.AckSweepInterval
+	sink.CheckpointInterval = source.CheckpointInterval
+	sink.LagSampleInterval = source.LagSampleInterval
+	sink.HealthCheckInterval = source.HealthCheckInterval
+	sink.TerminationGracePeriod = source.TerminationGracePeriod
+	sink.Trimming = source.GetTrimming()
+	sink.ReconnectBackoff = source.ReconnectBackoff
+	sink.Tracing = source.Tracing
+	sink.Reclaim = source.Reclaim
+	sink.DisableAutoAck = source.DisableAutoAck
+	sink.ReadCount = source.ReadCount
+	sink.ReadBlockTimeout = source.ReadBlockTimeout
+	sink.BatchSize = source.BatchSize
+	sink.BatchMaxBytes = source.BatchMaxBytes
+	sink.BatchLinger = source.BatchLinger
+}
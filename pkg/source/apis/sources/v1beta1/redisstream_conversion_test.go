@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestRedisStreamSourceConversionRoundTrip(t *testing.T) {
+	f := fuzz.NewWithSeed(1).NilChance(0.2).NumElements(1, 2).Funcs(
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+		func(u *apis.URL, c fuzz.Continue) {
+			*u = apis.URL{Scheme: "https", Host: c.RandString()}
+		},
+		func(tm *metav1.Time, c fuzz.Continue) {
+			*tm = metav1.Unix(c.Int63n(1<<32), 0)
+		},
+	)
+
+	// v1beta1 has no deprecated fields, so that it converts to v1alpha1 and
+	// back without loss.
+	for i := 0; i < 100; i++ {
+		want := &RedisStreamSource{}
+		f.Fuzz(&want.ObjectMeta)
+		f.Fuzz(&want.Spec)
+		f.Fuzz(&want.Status.Consumers)
+		f.Fuzz(&want.Status.LastProcessedID)
+
+		alpha := &v1alpha1.RedisStreamSource{}
+		if err := want.ConvertTo(context.Background(), alpha); err != nil {
+			t.Fatal("ConvertTo() =", err)
+		}
+		got := &RedisStreamSource{}
+		if err := got.ConvertFrom(context.Background(), alpha); err != nil {
+			t.Fatal("ConvertFrom() =", err)
+		}
+		if !equality.Semantic.DeepEqual(want, got) {
+			t.Fatalf("Round trip of %+v = %+v", want.Spec, got.Spec)
+		}
+	}
+}
+
+func TestRedisStreamSourceConversionDeprecatedFields(t *testing.T) {
+	tests := []struct {
+		name string
+		spec v1alpha1.RedisStreamSourceSpec
+		want RedisStreamSourceSpec
+	}{{
+		name: "consumers",
+		spec: v1alpha1.RedisStreamSourceSpec{Consumers: pointer.Int32(3)},
+		want: RedisStreamSourceSpec{Replicas: pointer.Int32(3)},
+	}, {
+		name: "consumers and replicas",
+		spec: v1alpha1.RedisStreamSourceSpec{Consumers: pointer.Int32(3), Replicas: pointer.Int32(2)},
+		want: RedisStreamSourceSpec{Replicas: pointer.Int32(2)},
+	}, {
+		name: "start from",
+		spec: v1alpha1.RedisStreamSourceSpec{StartFrom: v1alpha1.StartFromEarliest},
+		want: RedisStreamSourceSpec{StartID: v1alpha1.StartFromEarliest},
+	}, {
+		name: "start from and start ID",
+		spec: v1alpha1.RedisStreamSourceSpec{StartID: "0", StartFrom: v1alpha1.StartFromEarliest},
+		want: RedisStreamSourceSpec{StartID: "0"},
+	}, {
+		name: "trim strategy",
+		spec: v1alpha1.RedisStreamSourceSpec{TrimStrategy: &v1alpha1.TrimStrategy{MaxLen: 1000}},
+		want: RedisStreamSourceSpec{Trimming: &v1alpha1.Trimming{Strategy: v1alpha1.TrimmingMaxLen, Threshold: "1000", Approximate: true, OnAck: true}},
+	}, {
+		name: "TLS dial options",
+		spec: v1alpha1.RedisStreamSourceSpec{RedisConnection: v1alpha1.RedisConnection{Options: &v1alpha1.RedisConnectionOptions{
+			Password:   corev1.ObjectReference{Name: "redis-password"},
+			UseTLS:     true,
+			SkipVerify: true,
+		}}},
+		want: RedisStreamSourceSpec{Connection: RedisConnection{Options: &RedisConnectionOptions{
+			Password: corev1.ObjectReference{Name: "redis-password"},
+		}}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			beta := &RedisStreamSource{}
+			if err := beta.ConvertFrom(context.Background(), &v1alpha1.RedisStreamSource{Spec: test.spec}); err != nil {
+				t.Fatal("ConvertFrom() =", err)
+			}
+			if diff := cmp.Diff(test.want, beta.Spec); diff != "" {
+				t.Error("ConvertFrom (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestRedisStreamSourceConversionConnection(t *testing.T) {
+	alpha := &v1alpha1.RedisStreamSource{}
+	if err := json.Unmarshal([]byte(`{"spec":{"address":"redis://redis.redis.svc:6379","sentinel":{"masterName":"mymaster","addresses":["sentinel:26379"]},"tls":{"secretName":"redis-tls"},"auth":{"username":"adapter"},"stream":"mystream"}}`), alpha); err != nil {
+		t.Fatal(err)
+	}
+
+	beta := &RedisStreamSource{}
+	if err := beta.ConvertFrom(context.Background(), alpha); err != nil {
+		t.Fatal("ConvertFrom() =", err)
+	}
+	got, err := json.Marshal(beta.Spec.Connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"address":"redis://redis.redis.svc:6379","sentinel":{"masterName":"mymaster","addresses":["sentinel:26379"]},"tls":{"secretName":"redis-tls"},"auth":{"username":"adapter"}}`
+	if string(got) != want {
+		t.Errorf("connection = %s, want %s", got, want)
+	}
+	if beta.Spec.Stream != "mystream" {
+		t.Errorf("stream = %q, want mystream", beta.Spec.Stream)
+	}
+}
+
+func TestRedisStreamSourceConversionUnknownVersion(t *testing.T) {
+	beta := &RedisStreamSource{}
+	if err := beta.ConvertTo(context.Background(), &RedisStreamSource{}); err == nil {
+		t.Error("ConvertTo(v1beta1) = nil, want error")
+	}
+	if err := beta.ConvertFrom(context.Background(), &RedisStreamSource{}); err == nil {
+		t.Error("ConvertFrom(v1beta1) = nil, want error")
+	}
+}
@@ -0,0 +1,592 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisStreamSource is the Schema for the RedisStream API.
+//
+// It differs from the v1alpha1 RedisStreamSource, the version it is stored
+// as, in its connection settings, grouped under Connection, and in dropping
+// the deprecated fields of v1alpha1 duplicating other fields: Consumers,
+// StartFrom, TrimStrategy and the TLS dial options. The types of the other
+// settings are shared with v1alpha1.
+type RedisStreamSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisStreamSourceSpec            `json:"spec,omitempty"`
+	Status v1alpha1.RedisStreamSourceStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that RedisStreamSource should be implementing.
+var (
+	_ runtime.Object     = (*RedisStreamSource)(nil)
+	_ kmeta.OwnerRefable = (*RedisStreamSource)(nil)
+	_ apis.Convertible   = (*RedisStreamSource)(nil)
+)
+
+// RedisStreamSourceSpec defines the desired state of the RedisStreamSource.
+type RedisStreamSourceSpec struct {
+	// inherits duck/v1 SourceSpec, which currently provides:
+	// * Sink - a reference to an object that will resolve to a domain name or
+	//   a URI directly to use as the sink.
+	// * CloudEventOverrides - defines overrides to control the output format
+	//   and modifications of the event sent to the sink.
+	duckv1.SourceSpec `json:",inline"`
+
+	// Connection is how the receive adapter connects and authenticates to
+	// Redis.
+	Connection RedisConnection `json:"connection"`
+
+	// Stream is the name of the stream. It is deprecated in favor of
+	// Streams, and kept as an alias for a single stream.
+	Stream string `json:"stream"`
+
+	// Streams are the names of the streams read with the same consumer
+	// group, e.g. the keys a topic is sharded across. Stream, when also set,
+	// is read first. At least one stream must be set.
+	// +optional
+	Streams []string `json:"streams,omitempty"`
+
+	// Group is the name of the consumer group associated to this source.
+	// Defaults to <namespace>/<name> of the source, or <name> with
+	// NamespaceGroup. When left empty, a group is automatically created for
+	// each receive adapter pod and deleted with it.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// NamespaceGroup prefixes Group with the namespace of this source, so
+	// that sources in different namespaces reading the same stream with the
+	// same group name never share the group, and steal each other's entries.
+	// +optional
+	NamespaceGroup bool `json:"namespaceGroup,omitempty"`
+
+	// TargetConfigMap names a ConfigMap, in the namespace of this source,
+	// whose stream and group keys override Stream and Group. Updating it
+	// redirects the running receive adapters to the new stream or group,
+	// after the entries delivered from the previous ones are acknowledged.
+	// +optional
+	TargetConfigMap *corev1.LocalObjectReference `json:"targetConfigMap,omitempty"`
+
+	// Replicas is the number of receive adapter pods reading the streams,
+	// sharing the consumer group: each entry is delivered by a single pod.
+	// Defaults to 1. More than 1 replica requires a Group.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling scales the receive adapter with KEDA on the lag of the
+	// consumer group, between MinReplicas and MaxReplicas replicas. Without
+	// KEDA installed in the cluster, the receive adapter runs Replicas
+	// replicas instead.
+	// +optional
+	Autoscaling *v1alpha1.Autoscaling `json:"autoscaling,omitempty"`
+
+	// ExclusiveConsumer runs a single consumer at a time, e.g. for streams
+	// driving ordered state machines: the receive adapter is scaled to one
+	// pod running one consumer, which only reads the stream while it holds
+	// a Lease. A replacement of the pod, e.g. when it is evicted, waits for
+	// the lease to be released or to expire.
+	// +optional
+	ExclusiveConsumer bool `json:"exclusiveConsumer,omitempty"`
+
+	// LeaseDuration is how long the lease of an exclusive consumer lasts
+	// without being renewed: how long a replacement waits for a pod that
+	// stopped without releasing it. Defaults to 15s.
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// Resources are the compute resources of the receive adapter container,
+	// e.g. to raise its requests for a high-volume stream. Defaults to no
+	// requests or limits.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env are environment variables added to the receive adapter container,
+	// e.g. GOGC or GOMAXPROCS. They cannot override the variables the
+	// controller sets, nor start with K_ or REDIS_. The pods are replaced
+	// when a secret referenced by valueFrom.secretKeyRef changes, if it is
+	// labeled redisstream.sources.knative.dev/env-secret=true.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ServiceAccountName is the name of an existing service account the
+	// receive adapter pods run as, e.g. one bound to a workload identity.
+	// Defaults to a service account created for the source.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// NodeSelector restricts the receive adapter pods to the nodes with these
+	// labels, e.g. to the zone of the Redis instance.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let the receive adapter pods be scheduled on nodes with
+	// matching taints.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity constrains the nodes the receive adapter pods are scheduled
+	// on, and the pods they are scheduled with.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PodAnnotations are added to the receive adapter pods, e.g. to have the
+	// Vault agent injector write the file of auth.passwordFile.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// DeliveryWindow restricts reading from the stream to a daily time
+	// range. Outside of the window, entries accumulate in the stream and
+	// are read once the window opens again.
+	// +optional
+	DeliveryWindow *v1alpha1.DeliveryWindow `json:"deliveryWindow,omitempty"`
+
+	// DeliveryDelay defers the delivery of entries until they are at least
+	// this old, computed from their ID, e.g. "30s" or "5m". Older entries
+	// are delivered immediately.
+	// +optional
+	DeliveryDelay *metav1.Duration `json:"deliveryDelay,omitempty"`
+
+	// DisableHTTP2 forces events to be delivered to the sink over HTTP/1.1.
+	// +optional
+	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
+
+	// StreamRequestBody streams the events to the sink with a request body of
+	// unknown length, sent with the chunked transfer encoding over HTTP/1.1,
+	// rather than with a fully buffered one. Combined with the gzip sink
+	// content encoding, events are compressed while being sent.
+	// +optional
+	StreamRequestBody bool `json:"streamRequestBody,omitempty"`
+
+	// MetricsPort is the port the receive adapter exposes Prometheus metrics
+	// on. Defaults to 9090.
+	// +optional
+	MetricsPort *int32 `json:"metricsPort,omitempty"`
+
+	// ProfilingPort is the port the receive adapter serves profiling data on,
+	// when profiling is enabled. Defaults to 8008.
+	// +optional
+	ProfilingPort *int32 `json:"profilingPort,omitempty"`
+
+	// SinkHeaders are HTTP headers added to every request sent to the sink.
+	// CloudEvents headers (ce-*) and Content-Type cannot be overridden.
+	// +optional
+	SinkHeaders map[string]string `json:"sinkHeaders,omitempty"`
+
+	// SinkHeadersFrom are HTTP headers added to every request sent to the sink,
+	// whose values are read from Kubernetes secrets.
+	// +optional
+	SinkHeadersFrom map[string]v1alpha1.RedisSecretValueFromSource `json:"sinkHeadersFrom,omitempty"`
+
+	// SinkContentEncoding is the content encoding of the requests sent to the
+	// sink. The only supported encoding is "gzip", which is used only when the
+	// sink advertises it in the Accept-Encoding header of its response to an
+	// OPTIONS request. Defaults to no encoding.
+	// +optional
+	SinkContentEncoding string `json:"sinkContentEncoding,omitempty"`
+
+	// RespectRetryAfter makes the receive adapter wait for the delay requested
+	// by the Retry-After header of 429 and 503 sink responses, capped to 30
+	// seconds, before retrying. Defaults to true.
+	// +optional
+	RespectRetryAfter *bool `json:"respectRetryAfter,omitempty"`
+
+	// SequenceExtension sets the sequence CloudEvents extension attribute of
+	// the events to the ID of the entry they are built from, letting consumers
+	// detect gaps or reordering.
+	// +optional
+	SequenceExtension bool `json:"sequenceExtension,omitempty"`
+
+	// ProvenanceExtensions sets the redisadapterpod and redisadapternode
+	// CloudEvents extension attributes of the events to the names of the
+	// receive adapter pod and node that delivered them.
+	// +optional
+	ProvenanceExtensions bool `json:"provenanceExtensions,omitempty"`
+
+	// SequenceCounter sets the redisseq CloudEvents extension attribute of the
+	// events to a strictly increasing number, from a counter stored in Redis
+	// and shared by all the receive adapter replicas. Each event costs an
+	// extra round trip to Redis, serialized across replicas.
+	// +optional
+	SequenceCounter bool `json:"sequenceCounter,omitempty"`
+
+	// RedeliveredTypeSuffix, when set, is appended to the type of the events
+	// built from entries that are delivered again, such as the pending entries
+	// read after a restart, e.g. ".redelivered". The ID of the events is still
+	// the ID of their entry.
+	// +optional
+	RedeliveredTypeSuffix string `json:"redeliveredTypeSuffix,omitempty"`
+
+	// ProducerCallback, when set, confirms to producers that their entries
+	// have been delivered to the sink and acknowledged.
+	// +optional
+	ProducerCallback *v1alpha1.ProducerCallback `json:"producerCallback,omitempty"`
+
+	// BinaryData, when set, uses the raw bytes of a field of the entries as
+	// the data of the events, instead of the JSON encoding of the entries.
+	// +optional
+	BinaryData *v1alpha1.BinaryData `json:"binaryData,omitempty"`
+
+	// KafkaBridge, when set, produces the events as records to a Kafka topic,
+	// formatted per the CloudEvents Kafka protocol binding, through the HTTP
+	// API of a Kafka bridge such as the Strimzi Kafka Bridge. The sink is then
+	// the address of the bridge.
+	// +optional
+	KafkaBridge *v1alpha1.KafkaBridge `json:"kafkaBridge,omitempty"`
+
+	// PartitionKey, when set, sets the partitionkey extension attribute of the
+	// events from a field of the entries, so that partitioned sinks keep the
+	// events with the same key together.
+	// +optional
+	PartitionKey *v1alpha1.PartitionKey `json:"partitionKey,omitempty"`
+
+	// FieldMapping maps fields of the entries to CloudEvents extension
+	// attributes of the events, e.g. the tenant field to the tenantid
+	// extension, so that triggers can filter on them. The mapped fields are
+	// left out of the data of the events, made of the other fields.
+	// +optional
+	FieldMapping map[string]string `json:"fieldMapping,omitempty"`
+
+	// RequiredFields are fields of FieldMapping the entries must have. The
+	// events built from entries without one of them are not delivered to the
+	// sink, but handled per OnMissingField.
+	// +optional
+	RequiredFields []string `json:"requiredFields,omitempty"`
+
+	// OnMissingField defines what happens to the entries without one of the
+	// RequiredFields. Defaults to Drop.
+	// +optional
+	OnMissingField v1alpha1.MissingFieldPolicy `json:"onMissingField,omitempty"`
+
+	// SchemaURL, when set, is the http or https URL of a JSON Schema the
+	// entries are validated against before delivery. Each entry is validated
+	// as a JSON object holding the value of each of its fields, as a string.
+	// +optional
+	SchemaURL string `json:"schemaURL,omitempty"`
+
+	// SchemaValidation defines what happens to the entries not matching the
+	// schema at SchemaURL. Defaults to Warn.
+	// +optional
+	SchemaValidation v1alpha1.SchemaValidationMode `json:"schemaValidation,omitempty"`
+
+	// SchemaRefreshInterval is how often the receive adapter downloads the
+	// schema at SchemaURL again, e.g. "1m". Defaults to 5m.
+	// +optional
+	SchemaRefreshInterval *metav1.Duration `json:"schemaRefreshInterval,omitempty"`
+
+	// Type, when set, is the type of the events, instead of
+	// dev.knative.sources.redisstream.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// TypeFieldName, when set, names the field of the entries holding the
+	// type of their event, e.g. to emit order.created and order.cancelled
+	// events from the same stream. The events built from entries without
+	// that field, or with an empty value, have the type of the source.
+	// +optional
+	TypeFieldName string `json:"typeFieldName,omitempty"`
+
+	// EventSource, when set, is the source of the events, a URI-reference,
+	// instead of the address of Redis followed by the stream. The {streamKey},
+	// {namespace} and {name} variables are replaced by the key of the stream
+	// of the entries, and the namespace and name of this source, e.g.
+	// "/{namespace}/{name}/{streamKey}".
+	// +optional
+	EventSource string `json:"eventSource,omitempty"`
+
+	// ConditionalRequests sends events with the If-None-Match header set to
+	// their ID, quoted as an entity tag, so that sinks supporting conditional
+	// requests recognize redelivered events. 304 Not Modified and 412
+	// Precondition Failed responses are then treated as delivered.
+	// +optional
+	ConditionalRequests bool `json:"conditionalRequests,omitempty"`
+
+	// JSON defines how the entries are serialized to the JSON body of the
+	// events. Defaults to compact JSON with HTML characters escaped.
+	// +optional
+	JSON *v1alpha1.JSONOptions `json:"json,omitempty"`
+
+	// DataEncoding is how the entries are serialized to the body of the
+	// events: "json", the default, or "msgpack", which is smaller and sent
+	// with the application/vnd.msgpack content type.
+	// +optional
+	DataEncoding string `json:"dataEncoding,omitempty"`
+
+	// Dedup, when set, skips the entries whose events were already emitted,
+	// according to a dedup store shared by the sources using it and kept
+	// across restarts.
+	// +optional
+	Dedup *v1alpha1.Dedup `json:"dedup,omitempty"`
+
+	// StartID is where the consumer groups created by the receive adapter
+	// start reading from: "$" or "latest", the default, for the entries
+	// added afterwards only, "earliest" for the whole stream, or an entry ID,
+	// e.g. "0" for the whole stream too, to read the entries after it.
+	// Existing groups keep their position.
+	// +optional
+	StartID string `json:"startId,omitempty"`
+
+	// MinID is the ID of the first entry of the stream the source delivers.
+	// Entries before it are acknowledged and skipped, wherever the consumer
+	// group reads from, e.g. to never process the entries of a known-bad
+	// period again. The sequence number defaults to 0 when omitted.
+	// +optional
+	MinID string `json:"minId,omitempty"`
+
+	// AdditionalSinks are sinks the events are sent to along with Sink, each
+	// with its own delivery guarantee.
+	// +optional
+	AdditionalSinks []v1alpha1.AdditionalSink `json:"additionalSinks,omitempty"`
+
+	// AuditSink, when set, receives a copy of every event once it was
+	// delivered to the sink and its entry acknowledged. Failing to deliver to the audit sink does not prevent acknowledging
+	// entries.
+	// +optional
+	AuditSink *duckv1.Destination `json:"auditSink,omitempty"`
+
+	// Delivery defines how the receive adapter retries delivering an event
+	// the sink does not accept, as for Knative Eventing subscriptions: the
+	// number of retries, their backoff policy and delay, the timeout of each
+	// request and the dead-letter sink. Defaults to 5 retries with an
+	// exponential backoff starting at 50ms, without timeout.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// DeadLetterSink, when set, receives the events the sink does not
+	// accept once the delivery retries are exhausted. Their entries are
+	// acknowledged once the dead-letter sink accepted them, and left pending
+	// when it did not.
+	// +optional
+	DeadLetterSink *duckv1.Destination `json:"deadLetterSink,omitempty"`
+
+	// FailureReport, when set, sends a single summary event when many events
+	// fail to be delivered to the sink within a time window, instead of
+	// leaving operators to notice each failure.
+	// +optional
+	FailureReport *v1alpha1.FailureReport `json:"failureReport,omitempty"`
+
+	// OnSinkAddressPending defines what happens when the sink reference
+	// temporarily has no address, e.g. while its backing Deployment is down.
+	// Defaults to Fail.
+	// +optional
+	OnSinkAddressPending v1alpha1.SinkAddressPendingPolicy `json:"onSinkAddressPending,omitempty"`
+
+	// OnEmptyEntry defines what happens to the entries without fields, such
+	// as pending entries deleted before they were delivered. Defaults to Skip.
+	// +optional
+	OnEmptyEntry v1alpha1.EmptyEntryPolicy `json:"onEmptyEntry,omitempty"`
+
+	// DeadLetterStream is the key of the stream the entries are dead-lettered
+	// to, with OnEmptyEntry or Reclaim.MaxDeliveryAttempts. Defaults to
+	// redisdeadletter:<stream>.
+	// +optional
+	DeadLetterStream string `json:"deadLetterStream,omitempty"`
+
+	// WarmupPeriod is how long all the receive adapter replicas must stay
+	// ready before the source is marked as deployed, so that a replica
+	// failing right after it started, e.g. because it cannot read the stream,
+	// does not make the source flap between ready and not ready. Zero marks
+	// the source as deployed as soon as the replicas are ready. Defaults to 10s.
+	// +optional
+	WarmupPeriod *metav1.Duration `json:"warmupPeriod,omitempty"`
+
+	// DeleteGroupOnDelete destroys the consumer group in Redis when the source
+	// is deleted. It is opt-in since the group may be shared with other
+	// consumers of the stream. Groups created by the receive adapter when
+	// Group is empty are always destroyed.
+	// +optional
+	DeleteGroupOnDelete bool `json:"deleteGroupOnDelete,omitempty"`
+
+	// AckRetries is how many times acknowledging a delivered entry is retried
+	// before it is left pending, to be delivered again. Defaults to 3.
+	// +optional
+	AckRetries *int32 `json:"ackRetries,omitempty"`
+
+	// AckSweepInterval, when set, acknowledges the delivered entries together,
+	// with a single XACK every interval, instead of one XACK per entry, e.g.
+	// "1s". Entries delivered since the last sweep are delivered again if the
+	// receive adapter stops abruptly.
+	// +optional
+	AckSweepInterval *metav1.Duration `json:"ackSweepInterval,omitempty"`
+
+	// CheckpointInterval, when set, writes the ID of the last entry
+	// acknowledged to the <name>-cursor ConfigMap every CheckpointInterval
+	// entries acknowledged. A consumer group the receive adapter creates
+	// again, e.g. after the stream or the group was deleted, starts reading
+	// after that ID instead of StartID.
+	// +optional
+	CheckpointInterval int32 `json:"checkpointInterval,omitempty"`
+
+	// LagSampleInterval is how often the receive adapter samples the lag and
+	// pending entries of its consumer group, exported as metrics, and the
+	// controller reads the lag of the consumer group set with Group into the
+	// status, but not more often than every 10s, e.g. "1m". Defaults to 30s.
+	// +optional
+	LagSampleInterval *metav1.Duration `json:"lagSampleInterval,omitempty"`
+
+	// HealthCheckInterval is how often the receive adapter sends PING to
+	// Redis, reporting whether it answers in the RedisConnected condition,
+	// e.g. "10s". Defaults to 30s.
+	// +optional
+	HealthCheckInterval *metav1.Duration `json:"healthCheckInterval,omitempty"`
+
+	// TerminationGracePeriod is how long a receive adapter pod has to shut
+	// down once terminated, rounded up to the second, e.g. "1m". It stops
+	// reading new entries, completes the deliveries in flight, delivers its
+	// pending entries, and acknowledges and checkpoints them, within that
+	// period. Defaults to 30s.
+	// +optional
+	TerminationGracePeriod *metav1.Duration `json:"terminationGracePeriod,omitempty"`
+
+	// Trimming, when set, trims the streams following a trimming policy, so
+	// that they do not grow unbounded: from a CronJob, even while the source
+	// is idle, or from the receive adapter once entries are acknowledged with
+	// OnAck. Trimming deletes entries: it is never done unless set.
+	// +optional
+	Trimming *v1alpha1.Trimming `json:"trimming,omitempty"`
+
+	// ReconnectBackoff defines how the receive adapter backs off reconnecting
+	// to Redis when it cannot read from it. Defaults to an exponential backoff
+	// starting at 100ms, doubling up to 30s.
+	// +optional
+	ReconnectBackoff *v1alpha1.ReconnectBackoff `json:"reconnectBackoff,omitempty"`
+
+	// Tracing defines how the traces of the entries are sampled. The spans of
+	// the entries continue the trace of their traceparent and tracestate
+	// fields, if any, and are exported per the config-tracing ConfigMap of
+	// Knative.
+	// +optional
+	Tracing *v1alpha1.Tracing `json:"tracing,omitempty"`
+
+	// Reclaim, when set, periodically claims the entries left pending for too
+	// long by any consumer of the group, e.g. of a receive adapter pod that
+	// crashed, and delivers them again.
+	// +optional
+	Reclaim *v1alpha1.Reclaim `json:"reclaim,omitempty"`
+
+	// DisableAutoAck leaves the delivered entries pending, for the sink to
+	// acknowledge them itself with XACK, using the stream, group and entry ID
+	// of the events. Entries skipped without being delivered are still
+	// acknowledged. Pending entries are delivered again when the receive
+	// adapter starts.
+	// +optional
+	DisableAutoAck bool `json:"disableAutoAck,omitempty"`
+
+	// ReadCount is how many entries each consumer reads from the stream at
+	// once, and then delivers in turn. Reading more entries at once saves
+	// round trips to Redis under high throughput. It does not apply with
+	// BatchSize, which reads the entries the batch has room for. Defaults to
+	// 10 without BatchSize.
+	// +optional
+	ReadCount int32 `json:"readCount,omitempty"`
+
+	// ReadBlockTimeout is how long each consumer waits for entries to be added
+	// to the stream before reading again, e.g. "1s". Zero waits until an entry
+	// is added. With BatchSize, it applies while the batch is empty only, the
+	// batch waiting no longer than BatchLinger once it has entries. Defaults
+	// to 5s.
+	// +optional
+	ReadBlockTimeout *metav1.Duration `json:"readBlockTimeout,omitempty"`
+
+	// BatchSize, when set, delivers up to BatchSize entries together, in a
+	// single request to the sink using the batched content mode of
+	// CloudEvents (application/cloudevents-batch+json). The entries of a batch
+	// are acknowledged once the sink accepts the whole batch.
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// BatchMaxBytes bounds the size of the body of a batch, in bytes. A batch
+	// holds at least one event, whatever its size. Defaults to no limit.
+	// +optional
+	BatchMaxBytes int64 `json:"batchMaxBytes,omitempty"`
+
+	// BatchLinger is how long a batch waits for more entries before it is
+	// delivered, even when it is not full, so that low-volume streams do not
+	// stall. It cannot be shorter than 100ms. Defaults to 1s.
+	// +optional
+	BatchLinger *metav1.Duration `json:"batchLinger,omitempty"`
+}
+
+// RedisConnection defines how to connect and authenticate to Redis. It
+// gathers the address, sentinel, cluster, dialOptions, tls and auth fields of
+// the v1alpha1 spec.
+type RedisConnection struct {
+	// Address is the Redis TCP address: host:port, or a redis:// or
+	// rediss:// URL. Required unless Sentinel or Cluster is set.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Sentinel connects to the Redis master through Redis Sentinel, instead
+	// of Address, following it when it is failed over.
+	// +optional
+	Sentinel *v1alpha1.RedisSentinel `json:"sentinel,omitempty"`
+
+	// Cluster connects to the node of a Redis cluster serving the stream,
+	// instead of Address, following the slot of the stream when it moves.
+	// +optional
+	Cluster *v1alpha1.RedisCluster `json:"cluster,omitempty"`
+
+	// Options are the connection options.
+	// +optional
+	Options *RedisConnectionOptions `json:"dialOptions,omitempty"`
+
+	// TLS encrypts the connections of the receive adapter to Redis.
+	// +optional
+	TLS *v1alpha1.RedisTLS `json:"tls,omitempty"`
+
+	// Auth authenticates the connections of the receive adapter to Redis,
+	// instead of the credentials of the Address URL.
+	// +optional
+	Auth *v1alpha1.RedisAuth `json:"auth,omitempty"`
+}
+
+// RedisConnectionOptions are the options of the connections to Redis. Unlike
+// the v1alpha1 dial options, they have no TLS settings: TLS is set with the
+// TLS field of RedisConnection.
+type RedisConnectionOptions struct {
+	// Password to use for connecting to Redis
+	// +optional
+	Password corev1.ObjectReference `json:"password,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisStreamSourceList contains a list of RedisStreamSources.
+type RedisStreamSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisStreamSource `json:"items"`
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (s *RedisStreamSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisStreamSource")
+}
@@ -0,0 +1,400 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	apisduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisConnection) DeepCopyInto(out *RedisConnection) {
+	*out = *in
+	if in.Sentinel != nil {
+		in, out := &in.Sentinel, &out.Sentinel
+		*out = new(v1alpha1.RedisSentinel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(v1alpha1.RedisCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(RedisConnectionOptions)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(v1alpha1.RedisTLS)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(v1alpha1.RedisAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConnection.
+func (in *RedisConnection) DeepCopy() *RedisConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisConnectionOptions) DeepCopyInto(out *RedisConnectionOptions) {
+	*out = *in
+	out.Password = in.Password
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConnectionOptions.
+func (in *RedisConnectionOptions) DeepCopy() *RedisConnectionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisConnectionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamSource) DeepCopyInto(out *RedisStreamSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamSource.
+func (in *RedisStreamSource) DeepCopy() *RedisStreamSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisStreamSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamSourceList) DeepCopyInto(out *RedisStreamSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisStreamSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamSourceList.
+func (in *RedisStreamSourceList) DeepCopy() *RedisStreamSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisStreamSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamSourceSpec) DeepCopyInto(out *RedisStreamSourceSpec) {
+	*out = *in
+	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
+	in.Connection.DeepCopyInto(&out.Connection)
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetConfigMap != nil {
+		in, out := &in.TargetConfigMap, &out.TargetConfigMap
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(v1alpha1.Autoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeliveryWindow != nil {
+		in, out := &in.DeliveryWindow, &out.DeliveryWindow
+		*out = new(v1alpha1.DeliveryWindow)
+		**out = **in
+	}
+	if in.DeliveryDelay != nil {
+		in, out := &in.DeliveryDelay, &out.DeliveryDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MetricsPort != nil {
+		in, out := &in.MetricsPort, &out.MetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProfilingPort != nil {
+		in, out := &in.ProfilingPort, &out.ProfilingPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SinkHeaders != nil {
+		in, out := &in.SinkHeaders, &out.SinkHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SinkHeadersFrom != nil {
+		in, out := &in.SinkHeadersFrom, &out.SinkHeadersFrom
+		*out = make(map[string]v1alpha1.RedisSecretValueFromSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RespectRetryAfter != nil {
+		in, out := &in.RespectRetryAfter, &out.RespectRetryAfter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProducerCallback != nil {
+		in, out := &in.ProducerCallback, &out.ProducerCallback
+		*out = new(v1alpha1.ProducerCallback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BinaryData != nil {
+		in, out := &in.BinaryData, &out.BinaryData
+		*out = new(v1alpha1.BinaryData)
+		**out = **in
+	}
+	if in.KafkaBridge != nil {
+		in, out := &in.KafkaBridge, &out.KafkaBridge
+		*out = new(v1alpha1.KafkaBridge)
+		**out = **in
+	}
+	if in.PartitionKey != nil {
+		in, out := &in.PartitionKey, &out.PartitionKey
+		*out = new(v1alpha1.PartitionKey)
+		**out = **in
+	}
+	if in.FieldMapping != nil {
+		in, out := &in.FieldMapping, &out.FieldMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequiredFields != nil {
+		in, out := &in.RequiredFields, &out.RequiredFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaRefreshInterval != nil {
+		in, out := &in.SchemaRefreshInterval, &out.SchemaRefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = new(v1alpha1.JSONOptions)
+		**out = **in
+	}
+	if in.Dedup != nil {
+		in, out := &in.Dedup, &out.Dedup
+		*out = new(v1alpha1.Dedup)
+		**out = **in
+	}
+	if in.AdditionalSinks != nil {
+		in, out := &in.AdditionalSinks, &out.AdditionalSinks
+		*out = make([]v1alpha1.AdditionalSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AuditSink != nil {
+		in, out := &in.AuditSink, &out.AuditSink
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(apisduckv1.DeliverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReport != nil {
+		in, out := &in.FailureReport, &out.FailureReport
+		*out = new(v1alpha1.FailureReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmupPeriod != nil {
+		in, out := &in.WarmupPeriod, &out.WarmupPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.AckRetries != nil {
+		in, out := &in.AckRetries, &out.AckRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AckSweepInterval != nil {
+		in, out := &in.AckSweepInterval, &out.AckSweepInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LagSampleInterval != nil {
+		in, out := &in.LagSampleInterval, &out.LagSampleInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckInterval != nil {
+		in, out := &in.HealthCheckInterval, &out.HealthCheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TerminationGracePeriod != nil {
+		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Trimming != nil {
+		in, out := &in.Trimming, &out.Trimming
+		*out = new(v1alpha1.Trimming)
+		**out = **in
+	}
+	if in.ReconnectBackoff != nil {
+		in, out := &in.ReconnectBackoff, &out.ReconnectBackoff
+		*out = new(v1alpha1.ReconnectBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(v1alpha1.Tracing)
+		**out = **in
+	}
+	if in.Reclaim != nil {
+		in, out := &in.Reclaim, &out.Reclaim
+		*out = new(v1alpha1.Reclaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadBlockTimeout != nil {
+		in, out := &in.ReadBlockTimeout, &out.ReadBlockTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.BatchLinger != nil {
+		in, out := &in.BatchLinger, &out.BatchLinger
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamSourceSpec.
+func (in *RedisStreamSourceSpec) DeepCopy() *RedisStreamSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
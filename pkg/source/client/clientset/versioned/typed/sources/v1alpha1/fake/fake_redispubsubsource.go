@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// FakeRedisPubSubSources implements RedisPubSubSourceInterface
+type FakeRedisPubSubSources struct {
+	Fake *FakeSourcesV1alpha1
+	ns   string
+}
+
+var redispubsubsourcesResource = v1alpha1.SchemeGroupVersion.WithResource("redispubsubsources")
+
+var redispubsubsourcesKind = v1alpha1.SchemeGroupVersion.WithKind("RedisPubSubSource")
+
+// Get takes name of the redisPubSubSource, and returns the corresponding redisPubSubSource object, and an error if there is any.
+func (c *FakeRedisPubSubSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(redispubsubsourcesResource, c.ns, name), &v1alpha1.RedisPubSubSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), err
+}
+
+// List takes label and field selectors, and returns the list of RedisPubSubSources that match those selectors.
+func (c *FakeRedisPubSubSources) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RedisPubSubSourceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(redispubsubsourcesResource, redispubsubsourcesKind, c.ns, opts), &v1alpha1.RedisPubSubSourceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RedisPubSubSourceList{ListMeta: obj.(*v1alpha1.RedisPubSubSourceList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RedisPubSubSourceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested redisPubSubSources.
+func (c *FakeRedisPubSubSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(redispubsubsourcesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a redisPubSubSource and creates it.  Returns the server's representation of the redisPubSubSource, and an error, if there is any.
+func (c *FakeRedisPubSubSources) Create(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.CreateOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(redispubsubsourcesResource, c.ns, redisPubSubSource), &v1alpha1.RedisPubSubSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), err
+}
+
+// Update takes the representation of a redisPubSubSource and updates it. Returns the server's representation of the redisPubSubSource, and an error, if there is any.
+func (c *FakeRedisPubSubSources) Update(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(redispubsubsourcesResource, c.ns, redisPubSubSource), &v1alpha1.RedisPubSubSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRedisPubSubSources) UpdateStatus(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (*v1alpha1.RedisPubSubSource, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(redispubsubsourcesResource, "status", c.ns, redisPubSubSource), &v1alpha1.RedisPubSubSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), err
+}
+
+// Delete takes name of the redisPubSubSource and deletes it. Returns an error if one occurs.
+func (c *FakeRedisPubSubSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(redispubsubsourcesResource, c.ns, name, opts), &v1alpha1.RedisPubSubSource{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRedisPubSubSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(redispubsubsourcesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RedisPubSubSourceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched redisPubSubSource.
+func (c *FakeRedisPubSubSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RedisPubSubSource, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(redispubsubsourcesResource, c.ns, name, pt, data, subresources...), &v1alpha1.RedisPubSubSource{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), err
+}
@@ -28,6 +28,10 @@ type FakeSourcesV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSourcesV1alpha1) RedisPubSubSources(namespace string) v1alpha1.RedisPubSubSourceInterface {
+	return &FakeRedisPubSubSources{c, namespace}
+}
+
 func (c *FakeSourcesV1alpha1) RedisStreamSources(namespace string) v1alpha1.RedisStreamSourceInterface {
 	return &FakeRedisStreamSources{c, namespace}
 }
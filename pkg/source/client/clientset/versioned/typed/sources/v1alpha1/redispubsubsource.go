@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scheme "knative.dev/eventing-redis/pkg/source/client/clientset/versioned/scheme"
+)
+
+// RedisPubSubSourcesGetter has a method to return a RedisPubSubSourceInterface.
+// A group's client should implement this interface.
+type RedisPubSubSourcesGetter interface {
+	RedisPubSubSources(namespace string) RedisPubSubSourceInterface
+}
+
+// RedisPubSubSourceInterface has methods to work with RedisPubSubSource resources.
+type RedisPubSubSourceInterface interface {
+	Create(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.CreateOptions) (*v1alpha1.RedisPubSubSource, error)
+	Update(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (*v1alpha1.RedisPubSubSource, error)
+	UpdateStatus(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (*v1alpha1.RedisPubSubSource, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.RedisPubSubSource, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RedisPubSubSourceList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RedisPubSubSource, err error)
+	RedisPubSubSourceExpansion
+}
+
+// redisPubSubSources implements RedisPubSubSourceInterface
+type redisPubSubSources struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRedisPubSubSources returns a RedisPubSubSources
+func newRedisPubSubSources(c *SourcesV1alpha1Client, namespace string) *redisPubSubSources {
+	return &redisPubSubSources{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the redisPubSubSource, and returns the corresponding redisPubSubSource object, and an error if there is any.
+func (c *redisPubSubSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	result = &v1alpha1.RedisPubSubSource{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RedisPubSubSources that match those selectors.
+func (c *redisPubSubSources) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RedisPubSubSourceList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RedisPubSubSourceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested redisPubSubSources.
+func (c *redisPubSubSources) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a redisPubSubSource and creates it.  Returns the server's representation of the redisPubSubSource, and an error, if there is any.
+func (c *redisPubSubSources) Create(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.CreateOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	result = &v1alpha1.RedisPubSubSource{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisPubSubSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a redisPubSubSource and updates it. Returns the server's representation of the redisPubSubSource, and an error, if there is any.
+func (c *redisPubSubSources) Update(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	result = &v1alpha1.RedisPubSubSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		Name(redisPubSubSource.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisPubSubSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *redisPubSubSources) UpdateStatus(ctx context.Context, redisPubSubSource *v1alpha1.RedisPubSubSource, opts v1.UpdateOptions) (result *v1alpha1.RedisPubSubSource, err error) {
+	result = &v1alpha1.RedisPubSubSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		Name(redisPubSubSource.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisPubSubSource).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the redisPubSubSource and deletes it. Returns an error if one occurs.
+func (c *redisPubSubSources) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *redisPubSubSources) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched redisPubSubSource.
+func (c *redisPubSubSources) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RedisPubSubSource, err error) {
+	result = &v1alpha1.RedisPubSubSource{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("redispubsubsources").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -28,6 +28,7 @@ import (
 
 type SourcesV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	RedisPubSubSourcesGetter
 	RedisStreamSourcesGetter
 }
 
@@ -36,6 +37,10 @@ type SourcesV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SourcesV1alpha1Client) RedisPubSubSources(namespace string) RedisPubSubSourceInterface {
+	return newRedisPubSubSources(c, namespace)
+}
+
 func (c *SourcesV1alpha1Client) RedisStreamSources(namespace string) RedisStreamSourceInterface {
 	return newRedisStreamSources(c, namespace)
 }
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// RedisPubSubSources returns a RedisPubSubSourceInformer.
+	RedisPubSubSources() RedisPubSubSourceInformer
 	// RedisStreamSources returns a RedisStreamSourceInformer.
 	RedisStreamSources() RedisStreamSourceInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// RedisPubSubSources returns a RedisPubSubSourceInformer.
+func (v *version) RedisPubSubSources() RedisPubSubSourceInformer {
+	return &redisPubSubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // RedisStreamSources returns a RedisStreamSourceInformer.
 func (v *version) RedisStreamSources() RedisStreamSourceInformer {
 	return &redisStreamSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubsource
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	pubsubsourcereconciler "knative.dev/eventing-redis/pkg/source/client/injection/reconciler/sources/v1alpha1/redispubsubsource"
+	"knative.dev/eventing-redis/pkg/source/reconciler"
+	"knative.dev/eventing-redis/pkg/source/reconciler/pubsubsource/resources"
+)
+
+const component = "redispubsubsource"
+
+func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
+}
+
+// Reconciler reconciles a pubsubsource object
+type Reconciler struct {
+	ssr                 *reconciler.StatefulSetReconciler
+	receiveAdapterImage string
+	sinkResolver        *resolver.URIResolver
+	configs             reconcilersource.ConfigAccessor
+}
+
+// Check that our Reconciler implements ReconcileKind.
+var _ pubsubsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.RedisPubSubSource) pkgreconciler.Event {
+	sinkURI, dest, err := r.resolveDestination(ctx, source, &source.Spec.Sink)
+	if err != nil {
+		source.Status.MarkNoSink("NotFound", "")
+		return newWarningSinkNotFound(dest)
+	}
+	source.Status.MarkSink(sinkURI)
+
+	deadLetterSinkURI, event := r.resolveDeadLetterSink(ctx, source)
+	if event != nil {
+		return event
+	}
+
+	expectedStatefulSet := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinkURI.String(), deadLetterSinkURI)
+	if r.configs != nil {
+		// The receive adapter follows the logging, metrics and tracing
+		// configuration of the controller.
+		container := &expectedStatefulSet.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, r.configs.ToEnvVars()...)
+	}
+	ra, event := r.ssr.ReconcileStatefulSet(ctx, source, expectedStatefulSet)
+	if ra == nil {
+		return event
+	}
+	source.Status.PropagateStatefulSetAvailability(ra)
+	return nil
+}
+
+// resolveDeadLetterSink resolves the dead-letter sink of the source, if any, to
+// the URI passed to the receive adapter.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, source *sourcesv1alpha1.RedisPubSubSource) (string, pkgreconciler.Event) {
+	if source.Spec.DeadLetterSink == nil {
+		source.Status.MarkNoDeadLetterSink()
+		return "", nil
+	}
+
+	uri, dest, err := r.resolveDestination(ctx, source, source.Spec.DeadLetterSink)
+	if err != nil {
+		source.Status.MarkDeadLetterSinkNotResolved("NotFound", "Dead-letter sink not found: %v", err)
+		source.Status.MarkNoSink("DeadLetterSinkNotFound", "Dead-letter sink not found: %v", err)
+		return "", newWarningSinkNotFound(dest)
+	}
+	source.Status.MarkDeadLetterSinkResolved(uri)
+	return uri.String(), nil
+}
+
+// resolveDestination resolves a destination of the source, referencing
+// objects in the namespace of the source by default.
+func (r *Reconciler) resolveDestination(ctx context.Context, source *sourcesv1alpha1.RedisPubSubSource, dest *duckv1.Destination) (*apis.URL, *duckv1.Destination, error) {
+	dest = dest.DeepCopy()
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = source.GetNamespace()
+	}
+	uri, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, source)
+	return uri, dest, err
+}
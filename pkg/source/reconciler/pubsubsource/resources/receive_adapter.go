@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func AdapterName(source *sourcesv1alpha1.RedisPubSubSource) string {
+	return kmeta.ChildName(fmt.Sprintf("redispubsub-%s-", source.Name), string(source.UID))
+}
+
+// MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter StatefulSet for
+// RedisPubSub Sources. It has a single replica: every subscriber receives all the messages
+// published to its channels, so more replicas would send each of them more than once.
+func MakeReceiveAdapter(source *sourcesv1alpha1.RedisPubSubSource, image string, sinkURI string, deadLetterSinkURI string) *appsv1.StatefulSet {
+	labels := Labels(source.Name)
+	env := []corev1.EnvVar{{
+		Name:  "ADDRESS",
+		Value: source.Spec.Address,
+	}, {
+		Name:  "K_SINK",
+		Value: sinkURI,
+	}, {
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}, {
+		Name: "NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+
+	if len(source.Spec.Channels) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "CHANNELS",
+			Value: strings.Join(source.Spec.Channels, ","),
+		})
+	}
+	if len(source.Spec.Patterns) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "PATTERNS",
+			Value: strings.Join(source.Spec.Patterns, ","),
+		})
+	}
+
+	if deadLetterSinkURI != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "DEAD_LETTER_SINK",
+			Value: deadLetterSinkURI,
+		})
+	}
+
+	if overrides := source.Spec.CloudEventOverrides; overrides != nil {
+		// The CloudEvents client of the adapter sets the extensions of the overrides.
+		value, _ := json.Marshal(overrides) // maps of strings always marshal
+		env = append(env, corev1.EnvVar{
+			Name:  "K_CE_OVERRIDES",
+			Value: string(value),
+		})
+	}
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      AdapterName(source),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Replicas: pointer.Int32(1),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "receive-adapter",
+							Image: image,
+							Env:   env,
+						},
+					},
+				},
+			},
+		},
+	}
+}
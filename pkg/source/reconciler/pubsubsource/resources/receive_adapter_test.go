@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestMakeReceiveAdapter(t *testing.T) {
+	src := &v1alpha1.RedisPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisPubSubSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				CloudEventOverrides: &duckv1.CloudEventOverrides{
+					Extensions: map[string]string{"team": "orders"},
+				},
+			},
+			Address:  "redis://redis:6379",
+			Channels: []string{"orders", "payments"},
+			Patterns: []string{"users.*"},
+		},
+	}
+
+	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "dead-letter-uri")
+
+	if got.Namespace != "source-namespace" || got.Name != AdapterName(src) {
+		t.Errorf("StatefulSet = %s/%s, want source-namespace/%s", got.Namespace, got.Name, AdapterName(src))
+	}
+	if got.Spec.Replicas == nil || *got.Spec.Replicas != 1 {
+		t.Errorf("Replicas = %v, want 1", got.Spec.Replicas)
+	}
+	if len(got.OwnerReferences) != 1 || got.OwnerReferences[0].Kind != "RedisPubSubSource" {
+		t.Errorf("OwnerReferences = %v, want the source", got.OwnerReferences)
+	}
+
+	container := got.Spec.Template.Spec.Containers[0]
+	if container.Image != "test-image" {
+		t.Errorf("Image = %q, want test-image", container.Image)
+	}
+	env := make(map[string]string, len(container.Env))
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"ADDRESS":          "redis://redis:6379",
+		"K_SINK":           "sink-uri",
+		"CHANNELS":         "orders,payments",
+		"PATTERNS":         "users.*",
+		"DEAD_LETTER_SINK": "dead-letter-uri",
+		"K_CE_OVERRIDES":   `{"extensions":{"team":"orders"}}`,
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterChannelsOnly(t *testing.T) {
+	src := &v1alpha1.RedisPubSubSource{
+		Spec: v1alpha1.RedisPubSubSourceSpec{
+			Address:  "redis://redis:6379",
+			Channels: []string{"orders"},
+		},
+	}
+
+	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "")
+
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		switch e.Name {
+		case "PATTERNS", "DEAD_LETTER_SINK", "K_CE_OVERRIDES":
+			t.Errorf("Unexpected env %s = %q", e.Name, e.Value)
+		}
+	}
+}
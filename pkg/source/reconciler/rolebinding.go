@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -42,6 +43,12 @@ func newRoleBindingFailed(namespace, name string, err error) pkgreconciler.Event
 	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "RoleBindingFailed", "failed to create role binding: \"%s/%s\", %w", namespace, name, err)
 }
 
+// newRoleBindingUpdated makes a new reconciler event with event type Normal, and
+// reason RoleBindingUpdated.
+func newRoleBindingUpdated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "RoleBindingUpdated", "updated role binding: \"%s/%s\"", namespace, name)
+}
+
 type RoleBindingReconciler struct {
 	KubeClientSet kubernetes.Interface
 }
@@ -59,6 +66,14 @@ func (r *RoleBindingReconciler) ReconcileRoleBinding(ctx context.Context, owner
 	} else if !metav1.IsControlledBy(rb, owner.GetObjectMeta()) {
 		return nil, fmt.Errorf("deployment %q is not owned by %s %q",
 			rb.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
+	} else if !equality.Semantic.DeepEqual(expected.Subjects, rb.Subjects) {
+		// The role is immutable, but the subjects follow the service account
+		// of the receive adapter.
+		rb.Subjects = expected.Subjects
+		if rb, err = r.KubeClientSet.RbacV1().RoleBindings(expected.Namespace).Update(ctx, rb, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("error updating role binding %q: %v", expected.Name, err)
+		}
+		return rb, newRoleBindingUpdated(expected.Namespace, expected.Name)
 	} else {
 		logging.FromContext(ctx).Debugw("Reusing existing role binding", zap.Any("roleBinding", rb))
 	}
@@ -16,89 +16,9 @@ limitations under the License.
 package reconciler
 
 import (
-	"context"
-	"fmt"
-
-	"k8s.io/apimachinery/pkg/api/equality"
-
-	"go.uber.org/zap"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"knative.dev/pkg/kmeta"
-	"knative.dev/pkg/logging"
-	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/eventing-redis/pkg/reconciler"
 )
 
-// newStatefulSetCreated makes a new reconciler event with event type Normal, and
-// reason StatefulSetCreated.
-func newStatefulSetCreated(namespace, name string) pkgreconciler.Event {
-	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "StatefulSetCreated", "created statefulset: \"%s/%s\"", namespace, name)
-}
-
-// newStatefulSetFailed makes a new reconciler event with event type Warning, and
-// reason StatefulSetFailed.
-func newStatefulSetFailed(namespace, name string, err error) pkgreconciler.Event {
-	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "StatefulSetFailed", "failed to create statefulset: \"%s/%s\", %w", namespace, name, err)
-}
-
-// newStatefulSetUpdated makes a new reconciler event with event type Normal, and
-// reason StatefulSetUpdated.
-func newStatefulSetUpdated(namespace, name string) pkgreconciler.Event {
-	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "StatefulSetUpdated", "updated statefulset: \"%s/%s\"", namespace, name)
-}
-
-// statefulsetScaled makes a new reconciler event with event type Normal, and
-// reason StatefulSetScaled
-func statefulsetScaled(namespace, name string) pkgreconciler.Event {
-	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "StatefulSetScaled", "scaled statefulset: \"%s/%s\"", namespace, name)
-}
-
-type StatefulSetReconciler struct {
-	KubeClientSet kubernetes.Interface
-}
-
-func (r *StatefulSetReconciler) ReconcileStatefulSet(ctx context.Context, owner kmeta.OwnerRefable, expected *appsv1.StatefulSet) (*appsv1.StatefulSet, pkgreconciler.Event) {
-	namespace := owner.GetObjectMeta().GetNamespace()
-	ra, err := r.KubeClientSet.AppsV1().StatefulSets(namespace).Get(ctx, expected.Name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		ra, err = r.KubeClientSet.AppsV1().StatefulSets(namespace).Create(ctx, expected, metav1.CreateOptions{})
-		if err != nil {
-			return nil, newStatefulSetFailed(expected.Namespace, expected.Name, err)
-		}
-		return ra, newStatefulSetCreated(ra.Namespace, ra.Name)
-	} else if err != nil {
-		return nil, fmt.Errorf("error getting statefulset %q: %v", expected.Name, err)
-	} else if !metav1.IsControlledBy(ra, owner.GetObjectMeta()) {
-		return nil, fmt.Errorf("statefulset %q is not owned by %s %q",
-			ra.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
-	} else if r.podSpecChanged(expected.Spec.Template.Spec, ra.Spec.Template.Spec) {
-		if ra, err = r.KubeClientSet.AppsV1().StatefulSets(namespace).Update(ctx, ra, metav1.UpdateOptions{}); err != nil {
-			return ra, err
-		}
-		return ra, newStatefulSetUpdated(ra.Namespace, ra.Name)
-	} else if deref(ra.Spec.Replicas) != deref(expected.Spec.Replicas) {
-		ra.Spec.Replicas = expected.Spec.Replicas
-		if ra, err = r.KubeClientSet.AppsV1().StatefulSets(namespace).Update(ctx, ra, metav1.UpdateOptions{}); err != nil {
-			return ra, err
-		}
-		return ra, statefulsetScaled(ra.Namespace, ra.Name)
-	} else {
-		logging.FromContext(ctx).Debugw("Reusing existing receive adapter", zap.Any("receiveAdapter", ra))
-	}
-	return ra, nil
-}
-
-// Returns false if an update is needed.
-func (r *StatefulSetReconciler) podSpecChanged(expected corev1.PodSpec, now corev1.PodSpec) bool {
-	return !equality.Semantic.DeepDerivative(expected, now)
-}
-
-func deref(i *int32) int32 {
-	if i == nil {
-		return 1
-	}
-	return *i
-}
+// StatefulSetReconciler reconciles the StatefulSet of the receive adapter. It
+// is the StatefulSet reconciler shared with the channel dispatcher.
+type StatefulSetReconciler = reconciler.StatefulSetReconciler
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func newWarningAutoscalingFailed(name string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "AutoscalingFailed", "Failed to reconcile the KEDA objects %q: %v", name, err)
+}
+
+// reconcileAutoscaling creates or updates the KEDA ScaledObject scaling the
+// receive adapter of an autoscaled source, and its TriggerAuthentication. The
+// replicas of expected, the receive adapter StatefulSet, are then those KEDA
+// set, so that they are not reset. Without KEDA installed, the receive adapter
+// runs the replicas of expected instead. The KEDA objects of the sources no
+// longer autoscaled are deleted.
+func (r *Reconciler) reconcileAutoscaling(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, expected *appsv1.StatefulSet) pkgreconciler.Event {
+	name := resources.AdapterName(source)
+	if source.Spec.Autoscaling == nil {
+		// Only the sources that were autoscaled can have KEDA objects.
+		if source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionAutoscaled) != nil {
+			for _, gvr := range []schema.GroupVersionResource{resources.ScaledObjectGVR, resources.TriggerAuthenticationGVR} {
+				if err := r.deleteKEDAObject(ctx, source, gvr, name); err != nil {
+					return newWarningAutoscalingFailed(name, err)
+				}
+			}
+		}
+		source.Status.MarkNoAutoscaling()
+		return nil
+	}
+
+	installed, err := r.kedaInstalled()
+	if err != nil {
+		return newWarningAutoscalingFailed(name, err)
+	}
+	if !installed {
+		source.Status.MarkNotAutoscaled("KEDANotInstalled", "KEDA is not installed, the receive adapter runs %d replicas", pointer.Int32Deref(expected.Spec.Replicas, 1))
+		return nil
+	}
+
+	if auth := resources.MakeTriggerAuthentication(source); auth != nil {
+		err = r.applyKEDAObject(ctx, source, resources.TriggerAuthenticationGVR, auth)
+	} else {
+		err = r.deleteKEDAObject(ctx, source, resources.TriggerAuthenticationGVR, name)
+	}
+	if err == nil {
+		err = r.applyKEDAObject(ctx, source, resources.ScaledObjectGVR, resources.MakeScaledObject(source))
+	}
+	if err != nil {
+		source.Status.MarkNotAutoscaled("ScaledObjectFailed", "%v", err)
+		return newWarningAutoscalingFailed(name, err)
+	}
+
+	current, err := r.kubeClientSet.AppsV1().StatefulSets(source.Namespace).Get(ctx, expected.Name, metav1.GetOptions{})
+	if err == nil {
+		expected.Spec.Replicas = current.Spec.Replicas
+	} else if !apierrors.IsNotFound(err) {
+		return newWarningAutoscalingFailed(name, err)
+	}
+	source.Status.MarkAutoscaled()
+	return nil
+}
+
+// kedaInstalled returns true when the KEDA ScaledObjects are served.
+func (r *Reconciler) kedaInstalled() (bool, error) {
+	list, err := r.kubeClientSet.Discovery().ServerResourcesForGroupVersion(resources.ScaledObjectGVR.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range list.APIResources {
+		if resource.Name == resources.ScaledObjectGVR.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applyKEDAObject creates the KEDA object, or updates its spec when it
+// changed.
+func (r *Reconciler) applyKEDAObject(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, gvr schema.GroupVersionResource, expected *unstructured.Unstructured) error {
+	client := r.dynamicClientSet.Resource(gvr).Namespace(source.Namespace)
+	existing, err := client.Get(ctx, expected.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+	case err != nil:
+	case !metav1.IsControlledBy(existing, source):
+		err = fmt.Errorf("%s %q is not owned by %s %q", expected.GetKind(), expected.GetName(), source.GetGroupVersionKind().Kind, source.Name)
+	case !equality.Semantic.DeepDerivative(expected.Object["spec"], existing.Object["spec"]):
+		existing.Object["spec"] = expected.Object["spec"]
+		_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// deleteKEDAObject deletes the KEDA object owned by the source, if any.
+func (r *Reconciler) deleteKEDAObject(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, gvr schema.GroupVersionResource, name string) error {
+	client := r.dynamicClientSet.Resource(gvr).Namespace(source.Namespace)
+	existing, err := client.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil || !metav1.IsControlledBy(existing, source) {
+		return err
+	}
+	if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+// fakeDynamicClient keeps the objects of a single namespace, by resource and
+// name.
+type fakeDynamicClient struct {
+	objects map[schema.GroupVersionResource]map[string]*unstructured.Unstructured
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	if c.objects[gvr] == nil {
+		c.objects[gvr] = make(map[string]*unstructured.Unstructured)
+	}
+	return &fakeDynamicResource{objects: c.objects[gvr], gvr: gvr}
+}
+
+// fakeDynamicResource implements the methods of the resources the reconciler
+// uses, and panics on the others.
+type fakeDynamicResource struct {
+	dynamic.NamespaceableResourceInterface
+	objects map[string]*unstructured.Unstructured
+	gvr     schema.GroupVersionResource
+}
+
+func (r *fakeDynamicResource) Namespace(string) dynamic.ResourceInterface {
+	return r
+}
+
+func (r *fakeDynamicResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	obj, ok := r.objects[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeDynamicResource) Create(_ context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	if _, ok := r.objects[obj.GetName()]; ok {
+		return nil, apierrors.NewAlreadyExists(r.gvr.GroupResource(), obj.GetName())
+	}
+	r.objects[obj.GetName()] = obj.DeepCopy()
+	return obj, nil
+}
+
+func (r *fakeDynamicResource) Update(_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.objects[obj.GetName()] = obj.DeepCopy()
+	return obj, nil
+}
+
+func (r *fakeDynamicResource) Delete(_ context.Context, name string, _ metav1.DeleteOptions, _ ...string) error {
+	if _, ok := r.objects[name]; !ok {
+		return apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	delete(r.objects, name)
+	return nil
+}
+
+func TestReconcileAutoscaling(t *testing.T) {
+	newSource := func(autoscaling *sourcesv1alpha1.Autoscaling) *sourcesv1alpha1.RedisStreamSource {
+		return &sourcesv1alpha1.RedisStreamSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "1234"},
+			Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+				RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+				Stream:          "mystream",
+				Group:           "mygroup",
+				Auth: &sourcesv1alpha1.RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+					Key:                  "password",
+				}},
+				Autoscaling: autoscaling,
+			},
+		}
+	}
+	autoscaling := &sourcesv1alpha1.Autoscaling{MaxReplicas: 10}
+
+	tests := []struct {
+		name          string
+		autoscaling   *sourcesv1alpha1.Autoscaling
+		kedaInstalled bool
+		wasScaled     bool                       // the source had the autoscaled condition
+		existing      *unstructured.Unstructured // ScaledObject
+		wantCondition corev1.ConditionStatus     // empty when the condition is not set
+		wantMax       int64                      // zero when the ScaledObject does not exist
+		wantReplicas  int32
+	}{{
+		name:         "not autoscaled",
+		wantReplicas: 1,
+	}, {
+		name:          "KEDA not installed",
+		autoscaling:   autoscaling,
+		wantCondition: corev1.ConditionFalse,
+		wantReplicas:  1,
+	}, {
+		name:          "created",
+		autoscaling:   autoscaling,
+		kedaInstalled: true,
+		wantCondition: corev1.ConditionTrue,
+		wantMax:       10,
+		wantReplicas:  4,
+	}, {
+		name:          "updated",
+		autoscaling:   autoscaling,
+		kedaInstalled: true,
+		existing:      resources.MakeScaledObject(newSource(&sourcesv1alpha1.Autoscaling{MaxReplicas: 2})),
+		wantCondition: corev1.ConditionTrue,
+		wantMax:       10,
+		wantReplicas:  4,
+	}, {
+		name:          "no longer autoscaled",
+		kedaInstalled: true,
+		wasScaled:     true,
+		existing:      resources.MakeScaledObject(newSource(autoscaling)),
+		wantReplicas:  1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := newSource(test.autoscaling)
+			expected := resources.MakeReceiveAdapter(source, "test-image", resources.SinkURIs{Sink: "sink-uri"}, "1", "")
+
+			// KEDA scaled the receive adapter to 4 replicas.
+			current := expected.DeepCopy()
+			current.Spec.Replicas = pointer.Int32(4)
+			kubeClient := kubefake.NewSimpleClientset(current)
+			if test.kedaInstalled {
+				kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+					GroupVersion: "keda.sh/v1alpha1",
+					APIResources: []metav1.APIResource{{Name: "scaledobjects"}, {Name: "triggerauthentications"}},
+				}}
+			}
+			dynamicClient := &fakeDynamicClient{objects: make(map[schema.GroupVersionResource]map[string]*unstructured.Unstructured)}
+			if test.existing != nil {
+				dynamicClient.Resource(resources.ScaledObjectGVR).Create(context.Background(), test.existing, metav1.CreateOptions{})
+			}
+			if test.wasScaled {
+				source.Status.MarkAutoscaled()
+			}
+			r := &Reconciler{kubeClientSet: kubeClient, dynamicClientSet: dynamicClient}
+
+			if event := r.reconcileAutoscaling(context.Background(), source, expected); event != nil {
+				t.Fatal("reconcileAutoscaling() =", event)
+			}
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionAutoscaled)
+			switch {
+			case test.wantCondition == "" && cond != nil:
+				t.Errorf("Autoscaled = %+v, want none", cond)
+			case test.wantCondition != "" && cond == nil:
+				t.Error("Autoscaled condition not set")
+			case cond != nil && cond.Status != test.wantCondition:
+				t.Errorf("Autoscaled = %s %q, want %s", cond.Status, cond.Reason, test.wantCondition)
+			}
+			if got := pointer.Int32Deref(expected.Spec.Replicas, 1); got != test.wantReplicas {
+				t.Errorf("replicas = %d, want %d", got, test.wantReplicas)
+			}
+
+			scaledObject, err := dynamicClient.Resource(resources.ScaledObjectGVR).Get(context.Background(), resources.AdapterName(source), metav1.GetOptions{})
+			if test.wantMax == 0 {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("ScaledObject = %v, %v, want none", scaledObject, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Get ScaledObject =", err)
+			}
+			if got, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "maxReplicaCount"); got != test.wantMax {
+				t.Errorf("maxReplicaCount = %d, want %d", got, test.wantMax)
+			}
+			if _, err := dynamicClient.Resource(resources.TriggerAuthenticationGVR).Get(context.Background(), resources.AdapterName(source), metav1.GetOptions{}); err != nil {
+				t.Error("Get TriggerAuthentication =", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// clusterClient asks the nodes of the Redis cluster of a source.
+type clusterClient interface {
+	// StreamNode returns the host:port address of the node serving the
+	// stream.
+	StreamNode(ctx context.Context, cluster *sourcesv1alpha1.RedisCluster, stream string, creds redisCredentials) (string, error)
+	// CheckState returns an error unless the node considers the cluster able
+	// to serve queries.
+	CheckState(ctx context.Context, node string, creds redisCredentials) error
+}
+
+// redisClusterClient connects to the cluster nodes the same way the receive
+// adapter does.
+type redisClusterClient struct{}
+
+func (redisClusterClient) StreamNode(ctx context.Context, cluster *sourcesv1alpha1.RedisCluster, stream string, creds redisCredentials) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	return scan.SlotNode(cluster.Addresses, scan.KeySlot(stream), dialNode(ctx, creds))
+}
+
+func (redisClusterClient) CheckState(ctx context.Context, node string, creds redisCredentials) error {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	conn, err := dialNode(ctx, creds)(node)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return scan.CheckClusterState(conn)
+}
+
+// dialNode returns how to connect to the cluster nodes with the credentials.
+func dialNode(ctx context.Context, creds redisCredentials) func(address string) (redis.Conn, error) {
+	return func(address string) (redis.Conn, error) {
+		return dialRedis(ctx, redisTarget{Address: "redis://" + address, redisCredentials: creds},
+			redis.DialReadTimeout(discoveryTimeout),
+			redis.DialWriteTimeout(discoveryTimeout),
+		)
+	}
+}
+
+// reconcileCluster reflects in the status whether the node of the Redis
+// cluster serving the stream of the source is found and can serve it.
+func (r *Reconciler) reconcileCluster(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) {
+	cluster := source.Spec.Cluster
+	if cluster == nil {
+		source.Status.MarkNoCluster()
+		return
+	}
+
+	stream := source.Spec.GetStream()
+	creds, err := r.redisCredentials(ctx, source)
+	if err != nil {
+		source.Status.MarkClusterNotJoined("CredentialsUnavailable", "Cannot find the node serving stream %q: %v", stream, err)
+		return
+	}
+	node, err := r.clusters.StreamNode(ctx, cluster, stream, creds)
+	if err != nil {
+		source.Status.MarkClusterNotJoined("ClusterUnreachable", "Cannot find the node serving stream %q: %v", stream, err)
+		return
+	}
+	if err := r.clusters.CheckState(ctx, node, creds); err != nil {
+		source.Status.MarkClusterNotJoined("ClusterDown", "Node %s serving stream %q cannot serve queries: %v", node, stream, err)
+		return
+	}
+	source.Status.MarkClusterJoined()
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+type fakeClusterClient struct {
+	node     string
+	nodeErr  error
+	stateErr error
+}
+
+func (f *fakeClusterClient) StreamNode(ctx context.Context, cluster *sourcesv1alpha1.RedisCluster, stream string, creds redisCredentials) (string, error) {
+	return f.node, f.nodeErr
+}
+
+func (f *fakeClusterClient) CheckState(ctx context.Context, node string, creds redisCredentials) error {
+	return f.stateErr
+}
+
+func TestReconcileCluster(t *testing.T) {
+	cluster := &sourcesv1alpha1.RedisCluster{Addresses: []string{"redis-cluster:6379"}}
+
+	tests := []struct {
+		name       string
+		cluster    *sourcesv1alpha1.RedisCluster
+		client     *fakeClusterClient
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{{
+		name:   "no cluster",
+		client: &fakeClusterClient{},
+	}, {
+		name:       "joined",
+		cluster:    cluster,
+		client:     &fakeClusterClient{node: "10.0.0.2:6379"},
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:       "nodes unreachable",
+		cluster:    cluster,
+		client:     &fakeClusterClient{nodeErr: errors.New("dial tcp: connection refused")},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "ClusterUnreachable",
+	}, {
+		name:       "cluster down",
+		cluster:    cluster,
+		client:     &fakeClusterClient{node: "10.0.0.2:6379", stateErr: errors.New("cluster state is fail")},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "ClusterDown",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{clusters: test.client}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: sourcesv1alpha1.RedisConnection{Cluster: test.cluster},
+					Stream:          "{orders}",
+				},
+			}
+			// A previous condition is removed with the cluster spec.
+			source.Status.MarkClusterJoined()
+
+			r.reconcileCluster(context.Background(), source)
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionClusterMode)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("ClusterMode = %+v, want none", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatal("ClusterMode condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("ClusterMode = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
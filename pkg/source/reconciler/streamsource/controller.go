@@ -18,6 +18,7 @@ package streamsource
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/kelseyhightower/envconfig"
 	"k8s.io/client-go/tools/cache"
@@ -27,11 +28,14 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	statefulsetinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/statefulset"
+	secretinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/secret/filtered"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
@@ -40,6 +44,7 @@ import (
 	redisstreamsourceinformer "knative.dev/eventing-redis/pkg/source/client/injection/informers/sources/v1alpha1/redisstreamsource"
 	redisstreamsourcereconciler "knative.dev/eventing-redis/pkg/source/client/injection/reconciler/sources/v1alpha1/redisstreamsource"
 	"knative.dev/eventing-redis/pkg/source/reconciler"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
 )
 
 // envConfig will be used to extract the required environment variables using
@@ -47,6 +52,9 @@ import (
 // NewController will panic.
 type envConfig struct {
 	Image string `envconfig:"STREAMSOURCE_RA_IMAGE" required:"true"`
+
+	// TrimJobImage runs the CronJobs of the sources with a trimming policy.
+	TrimJobImage string `envconfig:"STREAMSOURCE_TRIM_JOB_IMAGE"`
 }
 
 //Removing watch on Redis config and not reloading numConsumers from CM dynamically since we don't automatically rollout new adapters on watch change. Will scale adapters via replicas
@@ -64,19 +72,33 @@ func NewController(
 
 	statefulsetInformer := statefulsetinformer.Get(ctx)
 	redisstreamSourceInformer := redisstreamsourceinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx, resources.EnvSecretSelector)
 
 	r := &Reconciler{
 		kubeClientSet:       kubeclient.Get(ctx),
+		dynamicClientSet:    dynamicclient.Get(ctx),
 		ssr:                 &reconciler.StatefulSetReconciler{KubeClientSet: kubeclient.Get(ctx)},
 		rbr:                 &reconciler.RoleBindingReconciler{KubeClientSet: kubeclient.Get(ctx)},
 		sar:                 &reconciler.ServiceAccountReconciler{KubeClientSet: kubeclient.Get(ctx)},
 		configs:             reconcilersource.WatchConfigurations(ctx, component, cmw),
 		receiveAdapterImage: env.Image,
+		trimJobImage:        env.TrimJobImage,
+		sourceLister:        redisstreamSourceInformer.Lister(),
+		groups:              redisGroupDestroyer{},
+		inspector:           redisGroupInspector{},
+		keys:                redisKeyTyper{},
+		schemas:             httpSchemaResolver{client: &http.Client{}},
+		tls:                 redisTLSChecker{},
+		sentinels:           redisSentinelClient{},
+		clusters:            redisClusterClient{},
+		secretLister:        secretInformer.Lister(),
+		oidcPasswords:       &oidcPasswordCache{},
 	}
 
 	impl := redisstreamsourcereconciler.NewImpl(ctx, r)
 
 	r.sinkResolver = resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
+	r.tracker = impl.Tracker
 
 	// Get Redis config map and set Redis configuration, to pass data to receive adapter.
 	// Not rolling out new adapters on watch change. Will scale adapters via replicas at a later time.
@@ -102,6 +124,30 @@ func NewController(
 
 	redisstreamSourceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
+	// Sources sharing a stream are reconciled together so that their group
+	// collision conditions are set, and cleared, on both sides.
+	redisstreamSourceInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		source, ok := obj.(*v1alpha1.RedisStreamSource)
+		if !ok {
+			return
+		}
+		sources, err := redisstreamSourceInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, other := range sources {
+			if other.Namespace != source.Namespace && other.Spec.Endpoint() == source.Spec.Endpoint() && other.Spec.GetStream() == source.Spec.GetStream() {
+				impl.Enqueue(other)
+			}
+		}
+	}))
+
+	// Sources whose environment variables reference a secret are reconciled
+	// when it changes, to replace their receive adapter pods.
+	secretInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(impl.Tracker.OnChanged, v1.SchemeGroupVersion.WithKind("Secret")),
+	))
+
 	statefulsetInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterController(&v1alpha1.RedisStreamSource{}),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
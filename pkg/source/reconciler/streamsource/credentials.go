@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// oidcTokenExpiration is the lifetime of the service account tokens the
+	// controller requests to exchange for a Redis password.
+	oidcTokenExpiration = 10 * time.Minute
+
+	// oidcPasswordLifetime is how long the controller uses a password
+	// exchanged for an OIDC token when the security token service does not
+	// tell when it expires.
+	oidcPasswordLifetime = 5 * time.Minute
+)
+
+// errCredentialsUnavailable tells that the controller cannot authenticate to
+// Redis as the receive adapter of the source does.
+var errCredentialsUnavailable = errors.New("cannot authenticate to Redis as the receive adapter")
+
+// redisCredentials is how the receive adapter of a source authenticates to
+// Redis, and to the nodes of its cluster.
+type redisCredentials struct {
+	// Username and Password replace the credentials of the address URL when
+	// Password is set.
+	Username string
+	Password string
+
+	// TLS is the configuration of the TLS secret of the source, if any.
+	TLS *tls.Config
+
+	// TLSCert is the certificate of the TLS secret of the controller, used
+	// with a password when the source has no TLS secret.
+	TLSCert string
+}
+
+// redisTarget is the Redis serving the stream of a source, and how to
+// authenticate to it.
+type redisTarget struct {
+	// Address is the address URL of Redis, or of the master or the cluster
+	// node serving the stream.
+	Address string
+
+	redisCredentials
+}
+
+// redisTarget returns the Redis serving the first stream of the source, and
+// how to authenticate to it. The error wraps errCredentialsUnavailable when
+// the controller cannot authenticate as the receive adapter does.
+func (r *Reconciler) redisTarget(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (redisTarget, error) {
+	creds, err := r.redisCredentials(ctx, source)
+	if err != nil {
+		return redisTarget{}, err
+	}
+	address, err := r.redisAddress(ctx, source, creds)
+	if err != nil {
+		return redisTarget{}, err
+	}
+	return redisTarget{Address: address, redisCredentials: creds}, nil
+}
+
+// redisCredentials returns how the receive adapter of the source
+// authenticates to Redis: with the password of its auth, from a secret or
+// exchanged for an OIDC token, and the TLS secret of the source. The
+// password of a password file is only readable in the receive adapter pods.
+func (r *Reconciler) redisCredentials(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (redisCredentials, error) {
+	creds := redisCredentials{TLSCert: r.tlsCert}
+	if source.Spec.TLS != nil {
+		config, err := r.redisTLSConfig(ctx, source)
+		if err != nil {
+			return creds, fmt.Errorf("%w: %v", errCredentialsUnavailable, err)
+		}
+		creds.TLS = config
+	}
+	username, password, err := r.redisAuth(ctx, source)
+	if err != nil {
+		return creds, fmt.Errorf("%w: %v", errCredentialsUnavailable, err)
+	}
+	creds.Username, creds.Password = username, password
+	return creds, nil
+}
+
+// redisAuth returns the username and password of the auth of the source, none
+// without a password.
+func (r *Reconciler) redisAuth(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (string, string, error) {
+	auth := source.Spec.Auth
+	if auth == nil {
+		return "", "", nil
+	}
+	var password string
+	var err error
+	switch {
+	case auth.OIDC != nil:
+		password, err = r.oidcPassword(ctx, source)
+	case auth.PasswordSecretRef != nil:
+		password, err = r.secretValue(ctx, source.Namespace, auth.PasswordSecretRef)
+	case auth.PasswordFile != "":
+		err = fmt.Errorf("password file %s is only readable in the receive adapter pods", auth.PasswordFile)
+	default:
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if auth.UsernameSecretRef == nil {
+		return auth.Username, password, nil
+	}
+	username, err := r.secretValue(ctx, source.Namespace, auth.UsernameSecretRef)
+	return username, password, err
+}
+
+// secretValue returns the value of the key of a secret of the namespace.
+func (r *Reconciler) secretValue(ctx context.Context, namespace string, selector *corev1.SecretKeySelector) (string, error) {
+	secret, err := r.kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, selector.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no key %q", selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
+
+// oidcPassword returns the Redis password exchanged for a token of the
+// service account of the receive adapter of the source, requested from the
+// Kubernetes API with the audience of its OIDC authentication. Passwords are
+// reused until three quarters of their lifetime elapsed.
+func (r *Reconciler) oidcPassword(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (string, error) {
+	oidc := source.Spec.Auth.OIDC
+	exchange := scan.OIDCExchange{TokenURL: oidc.TokenURL, ClientID: oidc.ClientID, Scope: oidc.Scope}
+	serviceAccount := resources.AdapterServiceAccountName(source)
+	key := oidcPasswordKey{exchange: exchange, serviceAccount: serviceAccount, audience: oidc.Audience}
+	now := time.Now()
+	if password, ok := r.oidcPasswords.get(source.UID, key, now); ok {
+		return password, nil
+	}
+
+	token, err := r.kubeClientSet.CoreV1().ServiceAccounts(source.Namespace).CreateToken(ctx, serviceAccount, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{oidc.Audience},
+			ExpirationSeconds: pointer.Int64(int64(oidcTokenExpiration.Seconds())),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("cannot request a token of service account %q: %v", serviceAccount, err)
+	}
+	password, lifetime, err := exchange.Exchange(ctx, http.DefaultClient, token.Status.Token)
+	if err != nil {
+		return "", err
+	}
+	if lifetime <= 0 {
+		lifetime = oidcPasswordLifetime
+	}
+	r.oidcPasswords.set(source.UID, key, password, now, now.Add(lifetime*3/4))
+	return password, nil
+}
+
+// oidcPasswordKey is what a password exchanged for an OIDC token depends on.
+type oidcPasswordKey struct {
+	exchange       scan.OIDCExchange
+	serviceAccount string
+	audience       string
+}
+
+type cachedOIDCPassword struct {
+	key      oidcPasswordKey
+	password string
+	expires  time.Time
+}
+
+// oidcPasswordCache holds the passwords exchanged for the OIDC tokens of the
+// sources, by UID. A nil cache holds none.
+type oidcPasswordCache struct {
+	mu        sync.Mutex
+	passwords map[types.UID]cachedOIDCPassword
+}
+
+func (c *oidcPasswordCache) get(uid types.UID, key oidcPasswordKey, now time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.passwords[uid]
+	if !ok || cached.key != key || !now.Before(cached.expires) {
+		return "", false
+	}
+	return cached.password, true
+}
+
+func (c *oidcPasswordCache) set(uid types.UID, key oidcPasswordKey, password string, now, expires time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.passwords == nil {
+		c.passwords = make(map[types.UID]cachedOIDCPassword)
+	}
+	// The passwords of the sources deleted since are dropped once expired.
+	for other, cached := range c.passwords {
+		if !now.Before(cached.expires) {
+			delete(c.passwords, other)
+		}
+	}
+	c.passwords[uid] = cachedOIDCPassword{key: key, password: password, expires: expires}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestRedisCredentials(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "redis-auth"},
+		Data:       map[string][]byte{"username": []byte("app"), "password": []byte("s3cret")},
+	}
+	selector := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: key}
+	}
+
+	tests := []struct {
+		name         string
+		auth         *sourcesv1alpha1.RedisAuth
+		wantUsername string
+		wantPassword string
+		wantErr      bool
+	}{{
+		name: "no auth",
+	}, {
+		name:         "password secret",
+		auth:         &sourcesv1alpha1.RedisAuth{Username: "app", PasswordSecretRef: selector("password")},
+		wantUsername: "app",
+		wantPassword: "s3cret",
+	}, {
+		name:         "username secret",
+		auth:         &sourcesv1alpha1.RedisAuth{UsernameSecretRef: selector("username"), PasswordSecretRef: selector("password")},
+		wantUsername: "app",
+		wantPassword: "s3cret",
+	}, {
+		name:    "missing key",
+		auth:    &sourcesv1alpha1.RedisAuth{PasswordSecretRef: selector("token")},
+		wantErr: true,
+	}, {
+		name: "missing secret",
+		auth: &sourcesv1alpha1.RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "other"}, Key: "password"}},
+		wantErr: true,
+	}, {
+		name:    "password file",
+		auth:    &sourcesv1alpha1.RedisAuth{PasswordFile: "/vault/secrets/redis"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{kubeClientSet: kubefake.NewSimpleClientset(secret), tlsCert: "controller-cert"}
+			source := &sourcesv1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+					Auth:            test.auth,
+				},
+			}
+
+			creds, err := r.redisCredentials(context.Background(), source)
+			if test.wantErr {
+				if !errors.Is(err, errCredentialsUnavailable) {
+					t.Fatalf("redisCredentials() = %v, want errCredentialsUnavailable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("redisCredentials() = %v", err)
+			}
+			if creds.Username != test.wantUsername || creds.Password != test.wantPassword {
+				t.Errorf("credentials = %q %q, want %q %q", creds.Username, creds.Password, test.wantUsername, test.wantPassword)
+			}
+			if creds.TLSCert != "controller-cert" {
+				t.Errorf("TLSCert = %q, want the certificate of the controller", creds.TLSCert)
+			}
+		})
+	}
+}
+
+func TestRedisCredentialsOIDC(t *testing.T) {
+	exchanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_assertion") != "sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token": "entra-password", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	kubeClient := kubefake.NewSimpleClientset()
+	var requested *authenticationv1.TokenRequest
+	kubeClient.PrependReactor("create", "serviceaccounts", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		requested = action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "sa-token"}}, nil
+	})
+	r := &Reconciler{kubeClientSet: kubeClient, oidcPasswords: &oidcPasswordCache{}}
+	source := &sourcesv1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "uid"},
+		Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection: sourcesv1alpha1.RedisConnection{Address: "rediss://cache.redis.cache.windows.net:6380"},
+			Auth: &sourcesv1alpha1.RedisAuth{
+				Username: "object-id",
+				OIDC:     &sourcesv1alpha1.RedisOIDC{ServiceAccountName: "redis-reader", Audience: "api://AzureADTokenExchange", TokenURL: server.URL},
+			},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		creds, err := r.redisCredentials(context.Background(), source)
+		if err != nil {
+			t.Fatalf("redisCredentials() = %v", err)
+		}
+		if creds.Username != "object-id" || creds.Password != "entra-password" {
+			t.Errorf("credentials = %q %q, want %q %q", creds.Username, creds.Password, "object-id", "entra-password")
+		}
+	}
+	if requested == nil || len(requested.Spec.Audiences) != 1 || requested.Spec.Audiences[0] != "api://AzureADTokenExchange" {
+		t.Errorf("token request = %+v, want the audience of the OIDC authentication", requested)
+	}
+	// The password is reused until it is about to expire.
+	if exchanges != 1 {
+		t.Errorf("exchanges = %d, want 1", exchanges)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func newWarningCursorFailed(name string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "CursorFailed", "Failed to reconcile cursor ConfigMap %q: %v", name, err)
+}
+
+// reconcileCursor creates the ConfigMap the receive adapter checkpoints the
+// IDs of the last entries acknowledged to, or deletes it once the source has
+// no checkpoint interval anymore. The last processed ID of the status is read
+// from it.
+func (r *Reconciler) reconcileCursor(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
+	name := resources.CursorConfigMapName(source)
+	configMaps := r.kubeClientSet.CoreV1().ConfigMaps(source.Namespace)
+	existing, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+
+	if source.Spec.CheckpointInterval == 0 {
+		source.Status.LastProcessedID = ""
+		if err == nil && metav1.IsControlledBy(existing, source) {
+			if err := configMaps.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return newWarningCursorFailed(name, err)
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		source.Status.LastProcessedID = ""
+		if _, err := configMaps.Create(ctx, resources.MakeCursorConfigMap(source), metav1.CreateOptions{}); err != nil {
+			return newWarningCursorFailed(name, err)
+		}
+		return nil
+	case err != nil:
+		return newWarningCursorFailed(name, err)
+	case !metav1.IsControlledBy(existing, source):
+		return newWarningCursorFailed(name, fmt.Errorf("not owned by %s %q", source.GetGroupVersionKind().Kind, source.Name))
+	}
+
+	cursors, err := sourcesv1alpha1.ParseCursors(existing.Data)
+	if err != nil {
+		// The last processed ID is kept, the receive adapter writes the
+		// cursors again on its next checkpoint.
+		logging.FromContext(ctx).Warnw("Cannot parse the cursors", "configMap", name, "error", err)
+		return nil
+	}
+	source.Status.LastProcessedID = cursors.LastProcessedID(source.Spec.GetStream())
+	return nil
+}
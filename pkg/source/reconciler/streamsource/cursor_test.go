@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func TestReconcileCursor(t *testing.T) {
+	newSource := func(checkpointInterval int32) *sourcesv1alpha1.RedisStreamSource {
+		return &sourcesv1alpha1.RedisStreamSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "1234"},
+			Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+				RedisConnection:    sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+				Stream:             "mystream",
+				CheckpointInterval: checkpointInterval,
+			},
+		}
+	}
+	checkpointed := resources.MakeCursorConfigMap(newSource(100))
+	checkpointed.Data = map[string]string{sourcesv1alpha1.CursorsKey: `{"mystream": {"mygroup": "1680000000000-1"}}`}
+
+	tests := []struct {
+		name                string
+		checkpointInterval  int32
+		existing            *corev1.ConfigMap
+		wantEvent           bool
+		wantConfigMap       bool
+		wantLastProcessedID string
+	}{{
+		name: "no checkpoint interval",
+	}, {
+		name:               "created",
+		checkpointInterval: 100,
+		wantConfigMap:      true,
+	}, {
+		name:                "checkpointed",
+		checkpointInterval:  100,
+		existing:            checkpointed,
+		wantConfigMap:       true,
+		wantLastProcessedID: "1680000000000-1",
+	}, {
+		name:     "deleted",
+		existing: checkpointed,
+	}, {
+		name:               "not owned",
+		checkpointInterval: 100,
+		existing: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source-cursor"},
+		},
+		wantEvent:     true,
+		wantConfigMap: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			if test.existing != nil {
+				kubeClient = kubefake.NewSimpleClientset(test.existing)
+			}
+			r := &Reconciler{kubeClientSet: kubeClient}
+			source := newSource(test.checkpointInterval)
+
+			if event := r.reconcileCursor(context.Background(), source); (event != nil) != test.wantEvent {
+				t.Fatalf("reconcileCursor() = %v, want event %v", event, test.wantEvent)
+			}
+			if source.Status.LastProcessedID != test.wantLastProcessedID {
+				t.Errorf("LastProcessedID = %q, want %q", source.Status.LastProcessedID, test.wantLastProcessedID)
+			}
+
+			configMap, err := kubeClient.CoreV1().ConfigMaps("ns").Get(context.Background(), "source-cursor", metav1.GetOptions{})
+			if !test.wantConfigMap {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("ConfigMap = %v, %v, want none", configMap, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Get ConfigMap =", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// keyTyper returns the type of the value held by Redis keys.
+type keyTyper interface {
+	KeyType(ctx context.Context, target redisTarget, key string) (string, error)
+}
+
+// redisKeyTyper connects to Redis the same way the receive adapter does to
+// ask the type of keys with TYPE.
+type redisKeyTyper struct{}
+
+func (redisKeyTyper) KeyType(ctx context.Context, target redisTarget, key string) (string, error) {
+	conn, err := dialRedis(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return redis.String(conn.Do("TYPE", key))
+}
+
+// reconcileDeadLetterStream reflects in the status whether the receive
+// adapter can add entries to the dead-letter stream of the source, that is
+// whether its key is a stream or does not exist yet.
+func (r *Reconciler) reconcileDeadLetterStream(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) {
+	if !source.Spec.DeadLettering() {
+		source.Status.MarkNoDeadLetterStream()
+		return
+	}
+
+	key := source.Spec.GetDeadLetterStream()
+	target, err := r.redisTarget(ctx, source)
+	if errors.Is(err, errCredentialsUnavailable) {
+		source.Status.MarkDeadLetterStreamUnknown("CredentialsUnavailable", "Cannot check dead-letter stream %q: %v", key, err)
+		return
+	}
+	if err != nil {
+		source.Status.MarkDeadLetterStreamUnknown("RedisUnreachable", "Cannot connect to Redis: %v", err)
+		return
+	}
+	kind, err := r.keys.KeyType(ctx, target, key)
+	if err != nil {
+		source.Status.MarkDeadLetterStreamUnknown("RedisUnreachable", "Cannot check dead-letter stream %q: %v", key, err)
+		return
+	}
+	if kind != "none" && kind != "stream" {
+		source.Status.MarkDeadLetterStreamNotReady("NotAStream", "Key %q of the dead-letter stream holds a %s", key, kind)
+		return
+	}
+	source.Status.MarkDeadLetterStreamReady()
+}
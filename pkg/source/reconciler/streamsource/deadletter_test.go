@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+type fakeKeyTyper struct {
+	key  string
+	kind string
+	err  error
+}
+
+func (f *fakeKeyTyper) KeyType(ctx context.Context, target redisTarget, key string) (string, error) {
+	f.key = key
+	return f.kind, f.err
+}
+
+func TestReconcileDeadLetterStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     sourcesv1alpha1.EmptyEntryPolicy
+		auth       *sourcesv1alpha1.RedisAuth
+		keys       *fakeKeyTyper
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{{
+		name: "no dead-lettering",
+		keys: &fakeKeyTyper{},
+	}, {
+		name:       "new stream",
+		policy:     sourcesv1alpha1.EmptyEntryDeadLetter,
+		keys:       &fakeKeyTyper{kind: "none"},
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:       "existing stream",
+		policy:     sourcesv1alpha1.EmptyEntryDeadLetter,
+		keys:       &fakeKeyTyper{kind: "stream"},
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:       "not a stream",
+		policy:     sourcesv1alpha1.EmptyEntryDeadLetter,
+		keys:       &fakeKeyTyper{kind: "list"},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "NotAStream",
+	}, {
+		name:       "unreachable",
+		policy:     sourcesv1alpha1.EmptyEntryDeadLetter,
+		keys:       &fakeKeyTyper{err: errors.New("dial tcp: connection refused")},
+		wantStatus: corev1.ConditionUnknown,
+		wantReason: "RedisUnreachable",
+	}, {
+		name:   "credentials unavailable",
+		policy: sourcesv1alpha1.EmptyEntryDeadLetter,
+		auth: &sourcesv1alpha1.RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"}},
+		keys:       &fakeKeyTyper{kind: "stream"},
+		wantStatus: corev1.ConditionUnknown,
+		wantReason: "CredentialsUnavailable",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{keys: test.keys, kubeClientSet: kubefake.NewSimpleClientset()}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection:  sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+					Auth:             test.auth,
+					Stream:           "orders",
+					OnEmptyEntry:     test.policy,
+					DeadLetterStream: "orders-dlq",
+				},
+			}
+			// A previous condition is removed when nothing is dead-lettered.
+			source.Status.MarkDeadLetterStreamReady()
+
+			r.reconcileDeadLetterStream(context.Background(), source)
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionDeadLetterStreamReady)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("DeadLetterStreamReady = %+v, want none", cond)
+				}
+				return
+			}
+			if test.auth == nil && test.keys.key != "orders-dlq" {
+				t.Errorf("checked key %q, want %q", test.keys.key, "orders-dlq")
+			}
+			if cond == nil {
+				t.Fatal("DeadLetterStreamReady condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("DeadLetterStreamReady = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
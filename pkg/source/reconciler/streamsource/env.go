@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func newWarningReservedEnv(names []string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ReservedEnv", "spec.env cannot override the variables set by the controller: %s", strings.Join(names, ", "))
+}
+
+// reconcileEnv adds the environment variables of the source to the receive
+// adapter container, unless they override the variables the controller sets,
+// and annotates its pods with the hash of the versions of the secrets they
+// reference, so that the pods are replaced when the secrets change. The secrets are tracked
+// for the source to be reconciled when they do.
+func (r *Reconciler) reconcileEnv(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, expected *appsv1.StatefulSet) pkgreconciler.Event {
+	if len(source.Spec.Env) == 0 {
+		source.Status.MarkNoEnv()
+		return nil
+	}
+
+	template := &expected.Spec.Template
+	container := &template.Spec.Containers[0]
+	if reserved := resources.ReservedEnv(container, source.Spec.Env); len(reserved) > 0 {
+		event := newWarningReservedEnv(reserved)
+		source.Status.MarkInvalidConfiguration("ReservedEnv", "%v", event)
+		return event
+	}
+	container.Env = append(container.Env, source.Spec.Env...)
+
+	hash, err := r.envSecretsHash(source)
+	if err != nil {
+		return err
+	}
+	if hash != "" {
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string, 1)
+		}
+		template.Annotations[resources.EnvSecretsHashAnnotation] = hash
+	}
+	source.Status.MarkValidConfiguration()
+	return nil
+}
+
+// envSecretsHash returns the hash of the UIDs and resource versions of the
+// secrets the environment variables of the source reference, empty when they
+// reference none. The values of the secrets are not part of it, so that the
+// annotation reveals nothing about them. Secrets the controller does not see,
+// because they are missing or lack the EnvSecretLabel, are part of the hash
+// too, so that the pods are replaced once they are created or labeled.
+func (r *Reconciler) envSecretsHash(source *sourcesv1alpha1.RedisStreamSource) (string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, env := range source.Spec.Env {
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+			continue
+		}
+		if name := env.ValueFrom.SecretKeyRef.Name; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		if err := r.tracker.TrackReference(tracker.Reference{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Namespace:  source.Namespace,
+			Name:       name,
+		}, source); err != nil {
+			return "", fmt.Errorf("cannot track secret %q: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s=", name)
+		secret, err := r.secretLister.Secrets(source.Namespace).Get(name)
+		if apierrors.IsNotFound(err) {
+			h.Write([]byte{0})
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("cannot get secret %q: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s/%s;", secret.UID, secret.ResourceVersion)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/tracker"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func TestReconcileEnv(t *testing.T) {
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	r := &Reconciler{
+		secretLister: corev1listers.NewSecretLister(secrets),
+		tracker:      tracker.New(func(types.NamespacedName) {}, time.Minute),
+	}
+	apiKey := corev1.EnvVar{
+		Name: "API_KEY",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+			Key:                  "apiKey",
+		}},
+	}
+	reconcile := func(env ...corev1.EnvVar) (*sourcesv1alpha1.RedisStreamSource, corev1.PodTemplateSpec, error) {
+		source := &sourcesv1alpha1.RedisStreamSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "1234"},
+			Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+				RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis:6379"},
+				Stream:          "mystream",
+				Env:             env,
+			},
+		}
+		source.Status.InitializeConditions()
+		expected := resources.MakeReceiveAdapter(source, "image", resources.SinkURIs{Sink: "http://sink"}, "1", "")
+		err := r.reconcileEnv(context.Background(), source, expected)
+		return source, expected.Spec.Template, err
+	}
+	envNamed := func(template corev1.PodTemplateSpec, name string) *corev1.EnvVar {
+		for i, env := range template.Spec.Containers[0].Env {
+			if env.Name == name {
+				return &template.Spec.Containers[0].Env[i]
+			}
+		}
+		return nil
+	}
+
+	// No environment variables, no condition.
+	source, template, err := reconcile()
+	if err != nil {
+		t.Fatal("reconcileEnv() =", err)
+	}
+	if cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionValidConfiguration); cond != nil {
+		t.Errorf("ValidConfiguration = %v, want none", cond)
+	}
+	if _, ok := template.Annotations[resources.EnvSecretsHashAnnotation]; ok {
+		t.Error("env secrets hash set without secrets")
+	}
+
+	// The variables are added, and the pods annotated with the hash of the
+	// version of the secret, which changes with the secret.
+	source, template, err = reconcile(corev1.EnvVar{Name: "GOGC", Value: "50"}, apiKey)
+	if err != nil {
+		t.Fatal("reconcileEnv() =", err)
+	}
+	if !source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionValidConfiguration).IsTrue() {
+		t.Error("ValidConfiguration is not True")
+	}
+	if env := envNamed(template, "GOGC"); env == nil || env.Value != "50" {
+		t.Errorf("GOGC = %v, want 50", env)
+	}
+	if env := envNamed(template, "API_KEY"); env == nil || env.ValueFrom == nil {
+		t.Errorf("API_KEY = %v, want from the secret", env)
+	}
+	missing := template.Annotations[resources.EnvSecretsHashAnnotation]
+	if missing == "" {
+		t.Error("env secrets hash not set")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "credentials", UID: "5678", ResourceVersion: "1"},
+		Data:       map[string][]byte{"apiKey": []byte("v1")},
+	}
+	secrets.Add(secret)
+	_, template, _ = reconcile(corev1.EnvVar{Name: "GOGC", Value: "50"}, apiKey)
+	created := template.Annotations[resources.EnvSecretsHashAnnotation]
+	if created == missing {
+		t.Error("env secrets hash unchanged once the secret is created")
+	}
+	_, template, _ = reconcile(corev1.EnvVar{Name: "GOGC", Value: "50"}, apiKey)
+	if got := template.Annotations[resources.EnvSecretsHashAnnotation]; got != created {
+		t.Errorf("env secrets hash = %s, want %s while the secret is unchanged", got, created)
+	}
+
+	rotated := secret.DeepCopy()
+	rotated.Data["apiKey"] = []byte("v2")
+	rotated.ResourceVersion = "2"
+	secrets.Update(rotated)
+	_, template, _ = reconcile(corev1.EnvVar{Name: "GOGC", Value: "50"}, apiKey)
+	if template.Annotations[resources.EnvSecretsHashAnnotation] == created {
+		t.Error("env secrets hash unchanged once the secret is rotated")
+	}
+
+	// Variables of the controller cannot be overridden.
+	for _, name := range []string{"ADDRESS", "K_SINK", "K_CE_OVERRIDES", "REDIS_PASSWORD"} {
+		source, template, err = reconcile(corev1.EnvVar{Name: name, Value: "x"})
+		if err == nil {
+			t.Errorf("reconcileEnv(%s) = nil, want error", name)
+		}
+		if cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionValidConfiguration); !cond.IsFalse() || cond.Reason != "ReservedEnv" {
+			t.Errorf("ValidConfiguration = %v, want False with reason ReservedEnv", cond)
+		}
+		if !source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionDeployed).IsFalse() {
+			t.Errorf("Deployed is not False with %s", name)
+		}
+		for _, env := range template.Spec.Containers[0].Env {
+			if env.Value == "x" {
+				t.Errorf("%s added to the receive adapter", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// groupDestroyer destroys the consumer groups of Redis streams.
+type groupDestroyer interface {
+	DestroyGroup(ctx context.Context, target redisTarget, stream, group string) error
+}
+
+// redisGroupDestroyer connects to Redis the same way the receive adapter
+// does to destroy consumer groups.
+type redisGroupDestroyer struct{}
+
+func (redisGroupDestroyer) DestroyGroup(ctx context.Context, target redisTarget, stream, group string) error {
+	conn, err := dialRedis(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Destroying a group that does not exist is not an error.
+	_, err = conn.Do("XGROUP", "DESTROY", stream, group)
+	if isNoStream(err) {
+		// The stream, and so its groups, was deleted.
+		return nil
+	}
+	return err
+}
+
+// groupInspector reads the state of the consumer groups of Redis streams.
+type groupInspector interface {
+	InspectGroup(ctx context.Context, target redisTarget, stream, group string) (sourcesv1alpha1.ConsumerGroupInfo, error)
+}
+
+// redisGroupInspector connects to Redis the same way the receive adapter
+// does to read the lag and pending entries of consumer groups.
+type redisGroupInspector struct{}
+
+func (redisGroupInspector) InspectGroup(ctx context.Context, target redisTarget, stream, group string) (sourcesv1alpha1.ConsumerGroupInfo, error) {
+	info := sourcesv1alpha1.ConsumerGroupInfo{Stream: stream, Group: group}
+
+	conn, err := dialRedis(ctx, target)
+	if err != nil {
+		return info, err
+	}
+	defer conn.Close()
+
+	// The summary form replies with the number of pending entries, the
+	// smallest and greatest pending IDs and the entries per consumer.
+	summary, err := redis.Values(conn.Do("XPENDING", stream, group))
+	if isNoGroup(err) {
+		return info, nil
+	}
+	if err != nil {
+		return info, err
+	}
+	if len(summary) == 0 {
+		return info, errors.New("unexpected XPENDING reply")
+	}
+	info.Exists = true
+
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", stream))
+	if err != nil {
+		return info, err
+	}
+	info.Lag = groups[group].Lag
+	info.LastDeliveredID = groups[group].LastDeliveredId
+
+	if info.Pending, err = redis.Int64(summary[0], nil); err != nil || info.Pending == 0 {
+		return info, err
+	}
+
+	// The smallest pending ID is the one delivered first.
+	oldest, err := redis.Values(conn.Do("XPENDING", stream, group, "-", "+", 1))
+	if err != nil {
+		return info, err
+	}
+	if len(oldest) == 1 {
+		// Each pending entry is [id, consumer, idle time, delivery count].
+		if entry, err := redis.Values(oldest[0], nil); err == nil && len(entry) == 4 {
+			idle, err := redis.Int64(entry[2], nil)
+			if err != nil {
+				return info, err
+			}
+			info.OldestPendingIdle = time.Duration(idle) * time.Millisecond
+		}
+	}
+	return info, nil
+}
+
+// dialRedis connects to Redis the same way the receive adapter does: with
+// the credentials of its auth, if any, or else of the address URL, and the TLS
+// secret of the source, or the TLS secret of the controller with a password.
+func dialRedis(ctx context.Context, target redisTarget, options ...redis.DialOption) (redis.Conn, error) {
+	opt, err := scan.ParseAddress(target.Address)
+	if err != nil {
+		return nil, err
+	}
+	if target.Password != "" {
+		// An empty username authenticates the default user with the
+		// password only.
+		opt.Username, opt.Password = target.Username, target.Password
+	}
+	options = append(options,
+		redis.DialUsername(opt.Username),
+		redis.DialPassword(opt.Password),
+		redis.DialDatabase(opt.DB),
+	)
+	switch {
+	case target.TLS != nil:
+		options = append(options,
+			redis.DialTLSConfig(target.TLS),
+			redis.DialUseTLS(true),
+		)
+	case opt.Password != "" && target.TLSCert != "":
+		roots := x509.NewCertPool()
+		if ok := roots.AppendCertsFromPEM([]byte(target.TLSCert)); !ok {
+			return nil, errors.New("cannot parse TLS certificate")
+		}
+		options = append(options,
+			redis.DialTLSConfig(&tls.Config{
+				RootCAs: roots,
+			}),
+			redis.DialTLSSkipVerify(true),
+			redis.DialUseTLS(true),
+		)
+	}
+	// No AUTH is sent without a password.
+	return redis.DialContext(ctx, "tcp", opt.Addr, options...)
+}
+
+// isNoStream returns true when Redis replied that the stream does not exist.
+func isNoStream(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.Contains(string(rerr), "requires the key to exist")
+}
+
+// isNoGroup returns true when Redis replied that the stream or the consumer
+// group does not exist.
+func isNoGroup(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "NOGROUP")
+}
+
+// isResharding returns whether the error is one of the transient errors
+// returned by a Redis cluster while its slots are being moved.
+func isResharding(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && (strings.HasPrefix(string(rerr), "CLUSTERDOWN") || strings.HasPrefix(string(rerr), "TRYAGAIN"))
+}
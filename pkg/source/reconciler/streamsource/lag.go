@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// reconcileGroupLag reflects in the status the lag and pending entries of the
+// consumer group of the source on each of its streams, and returns when to
+// read them again. The pending entries and lag of the source are the totals
+// of its streams.
+func (r *Reconciler) reconcileGroupLag(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, now time.Time) time.Duration {
+	group := source.ConsumerGroup()
+	if group == "" {
+		clearGroupLag(source)
+		return 0
+	}
+
+	// The source is reconciled far more often than its lag is due, on each
+	// change of its receive adapter for instance: the lag read last is kept
+	// until then, unless the spec of the source changed since.
+	interval := source.Spec.GetLagRefreshInterval()
+	if observed := source.Status.LagObservedTime; observed != nil && source.Status.ObservedGeneration == source.Generation {
+		if elapsed := now.Sub(observed.Time); elapsed >= 0 && elapsed < interval {
+			return interval - elapsed
+		}
+	}
+
+	// The streams of a source share the Redis instance, or the cluster node,
+	// serving the first one.
+	streams := source.Spec.GetStreams()
+	target, err := r.redisTarget(ctx, source)
+	if errors.Is(err, errCredentialsUnavailable) {
+		clearGroupLag(source)
+		source.Status.MarkConsumerGroupsNotReady("CredentialsUnavailable", "Cannot read consumer group %q: %v", group, err)
+		return interval
+	}
+	if err != nil {
+		clearGroupLag(source)
+		source.Status.MarkConsumerGroupsNotReady("RedisUnreachable", "Cannot find the Redis serving stream %q: %v", source.Spec.GetStream(), err)
+		return interval
+	}
+
+	infos := make([]sourcesv1alpha1.ConsumerGroupInfo, 0, len(streams))
+	var pending int64
+	lag := new(int64)
+	for _, stream := range streams {
+		info, err := r.inspector.InspectGroup(ctx, target, stream, group)
+		if err != nil {
+			clearGroupLag(source)
+			source.Status.MarkConsumerGroupsNotReady("GroupUnreadable", "Cannot read consumer group %q of stream %q: %v", group, stream, err)
+			return interval
+		}
+		infos = append(infos, info)
+		pending += info.Pending
+		if info.Lag == nil {
+			lag = nil
+		} else if lag != nil {
+			*lag += *info.Lag
+		}
+	}
+	source.Status.PropagateConsumerGroupStatuses(infos)
+	source.Status.MarkConsumerGroupPending(pending)
+	source.Status.Lag = lag
+	source.Status.LagObservedTime = &metav1.Time{Time: now}
+	return interval
+}
+
+// clearGroupLag removes the lag and pending entries of the consumer group from
+// the status, so that the next reconcile reads them again.
+func clearGroupLag(source *sourcesv1alpha1.RedisStreamSource) {
+	source.Status.MarkNoConsumerGroupPending()
+	source.Status.Lag = nil
+	source.Status.LagObservedTime = nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+type fakeGroupInspector struct {
+	info sourcesv1alpha1.ConsumerGroupInfo
+	err  error
+}
+
+func (f *fakeGroupInspector) InspectGroup(ctx context.Context, target redisTarget, stream, group string) (sourcesv1alpha1.ConsumerGroupInfo, error) {
+	info := f.info
+	info.Stream, info.Group = stream, group
+	return info, f.err
+}
+
+func TestReconcileGroupLag(t *testing.T) {
+	tests := []struct {
+		name        string
+		group       string
+		inspector   *fakeGroupInspector
+		wantPending string
+		wantLag     *int64
+		wantStatus  corev1.ConditionStatus
+		wantReason  string
+		wantRequeue time.Duration
+	}{{
+		name:      "no group",
+		inspector: &fakeGroupInspector{},
+	}, {
+		name:        "pending entries",
+		group:       "orders-group",
+		inspector:   &fakeGroupInspector{info: sourcesv1alpha1.ConsumerGroupInfo{Exists: true, Pending: 42, OldestPendingIdle: time.Second, Lag: pointer.Int64(7), LastDeliveredID: "1700000000000-3"}},
+		wantPending: "42",
+		wantLag:     pointer.Int64(7),
+		wantStatus:  corev1.ConditionTrue,
+		wantRequeue: sourcesv1alpha1.DefaultLagSampleInterval,
+	}, {
+		name:        "group not created yet",
+		group:       "orders-group",
+		inspector:   &fakeGroupInspector{},
+		wantPending: "0",
+		wantStatus:  corev1.ConditionFalse,
+		wantReason:  "GroupsNotReady",
+		wantRequeue: sourcesv1alpha1.DefaultLagSampleInterval,
+	}, {
+		name:        "unreadable",
+		group:       "orders-group",
+		inspector:   &fakeGroupInspector{err: errors.New("dial tcp: connection refused")},
+		wantStatus:  corev1.ConditionFalse,
+		wantReason:  "GroupUnreadable",
+		wantRequeue: sourcesv1alpha1.DefaultLagSampleInterval,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{inspector: test.inspector}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+					Stream:          "orders",
+					Group:           test.group,
+				},
+			}
+			// A previous annotation and lag are removed with the group.
+			source.Status.MarkConsumerGroupPending(7)
+			source.Status.Lag = pointer.Int64(3)
+			source.Status.LagObservedTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+
+			if got := r.reconcileGroupLag(context.Background(), source, time.Now()); got != test.wantRequeue {
+				t.Errorf("requeue = %v, want %v", got, test.wantRequeue)
+			}
+
+			pending, ok := source.Status.Annotations[sourcesv1alpha1.ConsumerGroupPendingAnnotation]
+			if test.wantPending == "" && ok {
+				t.Errorf("pending annotation = %q, want none", pending)
+			}
+			if test.wantPending != "" && pending != test.wantPending {
+				t.Errorf("pending annotation = %q, want %q", pending, test.wantPending)
+			}
+
+			if diff := cmp.Diff(test.wantLag, source.Status.Lag); diff != "" {
+				t.Errorf("unexpected lag (-want, +got) = %s", diff)
+			}
+			// The group was read when its pending entries were.
+			if observed := source.Status.LagObservedTime; (observed != nil) != (test.wantPending != "") {
+				t.Errorf("LagObservedTime = %v, want it set once the group is read", observed)
+			}
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamSourceConditionGroupsReady)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("GroupsReady = %+v, want none", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatal("GroupsReady condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("GroupsReady = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestReconcileGroupLagStreams(t *testing.T) {
+	r := &Reconciler{inspector: &fakeGroupInspector{info: sourcesv1alpha1.ConsumerGroupInfo{Exists: true, Pending: 2, Lag: pointer.Int64(5)}}}
+	source := &sourcesv1alpha1.RedisStreamSource{
+		Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Streams:         []string{"events:{0}", "events:{1}"},
+			Group:           "events-group",
+		},
+	}
+
+	r.reconcileGroupLag(context.Background(), source, time.Now())
+
+	// The source reports the totals of its streams, and each stream its own lag.
+	if got := source.Status.Annotations[sourcesv1alpha1.ConsumerGroupPendingAnnotation]; got != "4" {
+		t.Errorf("pending annotation = %q, want %q", got, "4")
+	}
+	if diff := cmp.Diff(pointer.Int64(10), source.Status.Lag); diff != "" {
+		t.Errorf("unexpected lag (-want, +got) = %s", diff)
+	}
+	if len(source.Status.ConsumerGroupStatuses) != 2 {
+		t.Fatalf("len(ConsumerGroupStatuses) = %d, want 2", len(source.Status.ConsumerGroupStatuses))
+	}
+	for i, stream := range source.Spec.Streams {
+		got := source.Status.ConsumerGroupStatuses[i]
+		if got.Stream != stream {
+			t.Errorf("ConsumerGroupStatuses[%d].Stream = %q, want %q", i, got.Stream, stream)
+		}
+		if diff := cmp.Diff(pointer.Int64(5), got.Lag); diff != "" {
+			t.Errorf("unexpected lag of stream %s (-want, +got) = %s", stream, diff)
+		}
+	}
+}
+
+func TestReconcileGroupLagRefreshInterval(t *testing.T) {
+	now := time.Now()
+	inspector := &fakeGroupInspector{info: sourcesv1alpha1.ConsumerGroupInfo{Exists: true, Lag: pointer.Int64(5), LastDeliveredID: "1700000000000-0"}}
+	r := &Reconciler{inspector: inspector}
+	source := &sourcesv1alpha1.RedisStreamSource{
+		Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:   sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:            "orders",
+			Group:             "orders-group",
+			LagSampleInterval: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+
+	if got := r.reconcileGroupLag(context.Background(), source, now); got != time.Minute {
+		t.Errorf("requeue = %v, want %v", got, time.Minute)
+	}
+	if got := source.Status.ConsumerGroupStatuses[0].LastDeliveredID; got != "1700000000000-0" {
+		t.Errorf("LastDeliveredID = %q, want %q", got, "1700000000000-0")
+	}
+
+	// The lag is not read again before it is due.
+	inspector.info.Lag = pointer.Int64(8)
+	if got := r.reconcileGroupLag(context.Background(), source, now.Add(20*time.Second)); got != 40*time.Second {
+		t.Errorf("requeue = %v, want %v", got, 40*time.Second)
+	}
+	if diff := cmp.Diff(pointer.Int64(5), source.Status.Lag); diff != "" {
+		t.Errorf("unexpected lag (-want, +got) = %s", diff)
+	}
+
+	// A change of the spec reads it again.
+	source.Generation++
+	r.reconcileGroupLag(context.Background(), source, now.Add(30*time.Second))
+	if diff := cmp.Diff(pointer.Int64(8), source.Status.Lag); diff != "" {
+		t.Errorf("unexpected lag (-want, +got) = %s", diff)
+	}
+	if want := now.Add(30 * time.Second); !source.Status.LagObservedTime.Time.Equal(want) {
+		t.Errorf("LagObservedTime = %v, want %v", source.Status.LagObservedTime, want)
+	}
+}
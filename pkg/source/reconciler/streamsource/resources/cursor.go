@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// CursorConfigMapName returns the name of the ConfigMap the receive adapter
+// checkpoints the IDs of the last entries acknowledged to.
+func CursorConfigMapName(source *sourcesv1alpha1.RedisStreamSource) string {
+	return source.Name + "-cursor"
+}
+
+// MakeCursorConfigMap generates (but does not insert into K8s) the cursor
+// ConfigMap of the source, without cursors. They are only written by the
+// receive adapter.
+func MakeCursorConfigMap(source *sourcesv1alpha1.RedisStreamSource) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      CursorConfigMapName(source),
+			Labels:    Labels(source.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+	}
+}
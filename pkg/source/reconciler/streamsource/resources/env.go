@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EnvSecretsHashAnnotation is the annotation of the receive adapter pods
+// holding the hash of the versions of the secrets their environment variables
+// reference, so that the pods are replaced when the secrets change.
+const EnvSecretsHashAnnotation = "redisstream.sources.knative.dev/env-secrets-hash"
+
+// EnvSecretLabel is the label of the secrets environment variables reference
+// that the controller watches. Only the secrets with the label set to "true"
+// are cached by the controller, not every secret of the cluster.
+const EnvSecretLabel = "redisstream.sources.knative.dev/env-secret"
+
+// EnvSecretSelector selects the secrets the controller watches.
+const EnvSecretSelector = EnvSecretLabel + "=true"
+
+// reservedEnvPrefixes are the prefixes of the environment variables the
+// controller may set on the receive adapter container, depending on the
+// source: those Knative injects, and the connection settings of Redis, which
+// must come from the spec, e.g. the password from auth.
+var reservedEnvPrefixes = []string{"K_", "REDIS_"}
+
+// ReservedEnv returns the names of the environment variables of the source
+// that would override the variables the controller sets on the receive
+// adapter container, or that have a reserved prefix.
+func ReservedEnv(container *corev1.Container, env []corev1.EnvVar) []string {
+	set := make(map[string]bool, len(container.Env))
+	for _, e := range container.Env {
+		set[e.Name] = true
+	}
+	var reserved []string
+	for _, e := range env {
+		if set[e.Name] || hasReservedPrefix(e.Name) {
+			reserved = append(reserved, e.Name)
+		}
+	}
+	return reserved
+}
+
+func hasReservedPrefix(name string) bool {
+	for _, prefix := range reservedEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+const (
+	// oidcTokenVolumeName is the name of the volume projecting the service
+	// account token exchanged for the Redis password.
+	oidcTokenVolumeName = "redis-oidc-token"
+
+	// oidcTokenMountPath is where the volume of the token is mounted.
+	oidcTokenMountPath = "/var/run/secrets/redis-oidc"
+
+	// oidcTokenExpirationSeconds is how long the token is valid for. The
+	// kubelet renews it once 80% of its lifetime elapsed.
+	oidcTokenExpirationSeconds = 3600
+)
+
+// oidcTokenVolume returns the volume projecting the token of the service
+// account of the pod for the audience of the OIDC authentication, and its
+// mount.
+func oidcTokenVolume(oidc *sourcesv1alpha1.RedisOIDC) (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: oidcTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          oidc.Audience,
+						ExpirationSeconds: pointer.Int64(oidcTokenExpirationSeconds),
+						Path:              "token",
+					},
+				}},
+			},
+		},
+	}
+	mount := corev1.VolumeMount{
+		Name:      oidcTokenVolumeName,
+		MountPath: oidcTokenMountPath,
+		ReadOnly:  true,
+	}
+	return volume, mount
+}
+
+// oidcEnv returns the environment variables configuring the exchange of the
+// token for the Redis password.
+func oidcEnv(oidc *sourcesv1alpha1.RedisOIDC) []corev1.EnvVar {
+	return []corev1.EnvVar{{
+		Name:  "REDIS_OIDC_TOKEN_FILE",
+		Value: path.Join(oidcTokenMountPath, "token"),
+	}, {
+		Name:  "REDIS_OIDC_TOKEN_URL",
+		Value: oidc.TokenURL,
+	}, {
+		Name:  "REDIS_OIDC_CLIENT_ID",
+		Value: oidc.ClientID,
+	}, {
+		Name:  "REDIS_OIDC_SCOPE",
+		Value: oidc.Scope,
+	}}
+}
@@ -17,25 +17,659 @@ limitations under the License.
 package resources
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	"knative.dev/pkg/kmeta"
 
 	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 )
 
+// targetMountPath is where the target ConfigMap of a source is mounted in the
+// receive adapter container.
+const targetMountPath = "/etc/redis-target"
+
 func AdapterName(source *sourcesv1alpha1.RedisStreamSource) string {
 	return kmeta.ChildName(fmt.Sprintf("redissource-%s-", source.Name), "1234") //TODO: must be no more than 63 characters, spec.hostname: Invalid value error
 }
 
+// SinkURIs are the resolved URIs of the sinks of a source the receive adapter
+// sends events to. All but Sink are empty when the source does not set them.
+type SinkURIs struct {
+	Sink          string
+	Audit         string
+	FailureReport string
+	DeadLetter    string
+	// Additional are in the order of the additional sinks of the spec.
+	Additional []string
+}
+
 // MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter Deployment for
 // RedisStream Sources.
-func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string, sinkURI string, numConsumers string, tlsCert string) *appsv1.StatefulSet {
+func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string, sinks SinkURIs, numConsumers string, tlsCert string) *appsv1.StatefulSet {
 	labels := Labels(source.Name)
+	replicas := source.Spec.GetReplicas()
+	if source.Spec.ExclusiveConsumer {
+		// A single pod runs a single consumer.
+		replicas = pointer.Int32(1)
+		numConsumers = "1"
+	}
+	env := []corev1.EnvVar{{
+		Name:  "STREAM",
+		Value: source.Spec.GetStream(),
+	}, {
+		Name:  "GROUP",
+		Value: source.ConsumerGroup(),
+	}, {
+		Name:  "ADDRESS",
+		Value: source.Spec.Address,
+	}, {
+		Name:  "K_SINK",
+		Value: sinks.Sink,
+	}, {
+		Name:  "NUM_CONSUMERS",
+		Value: numConsumers,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}, {
+		Name: "NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}, {
+		Name:  "SOURCE_NAME",
+		Value: source.Name,
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+
+	if tls := source.Spec.TLS; tls != nil {
+		env = append(env, redisTLSEnv(tls)...)
+	}
+
+	if auth := source.Spec.Auth; auth != nil && (auth.PasswordSecretRef != nil || auth.PasswordFile != "" || auth.OIDC != nil) {
+		username := corev1.EnvVar{
+			Name:  "REDIS_USERNAME",
+			Value: auth.Username,
+		}
+		if auth.UsernameSecretRef != nil {
+			username = corev1.EnvVar{
+				Name: "REDIS_USERNAME",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: auth.UsernameSecretRef.DeepCopy(),
+				},
+			}
+		}
+		switch {
+		case auth.OIDC != nil:
+			env = append(env, username)
+			env = append(env, oidcEnv(auth.OIDC)...)
+		case auth.PasswordSecretRef != nil:
+			env = append(env, username, corev1.EnvVar{
+				Name: "REDIS_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: auth.PasswordSecretRef.DeepCopy(),
+				},
+			})
+		default:
+			// The adapter reads the file again when it changes.
+			env = append(env, username, corev1.EnvVar{
+				Name:  "REDIS_PASSWORD_FILE",
+				Value: auth.PasswordFile,
+			})
+		}
+	}
+
+	if window := source.Spec.DeliveryWindow; window != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "DELIVERY_WINDOW_START",
+			Value: window.Start,
+		}, corev1.EnvVar{
+			Name:  "DELIVERY_WINDOW_END",
+			Value: window.End,
+		}, corev1.EnvVar{
+			Name:  "DELIVERY_WINDOW_TIMEZONE",
+			Value: window.Timezone,
+		})
+	}
+
+	ports := []corev1.ContainerPort{{
+		Name:          "metrics",
+		ContainerPort: sourcesv1alpha1.DefaultMetricsPort,
+	}}
+	if port := source.Spec.MetricsPort; port != nil {
+		ports[0].ContainerPort = *port
+		env = append(env, corev1.EnvVar{
+			Name:  "METRICS_PROMETHEUS_PORT",
+			Value: strconv.Itoa(int(*port)),
+		})
+	}
+	if port := source.Spec.ProfilingPort; port != nil {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          "profiling",
+			ContainerPort: *port,
+		})
+		env = append(env, corev1.EnvVar{
+			Name:  "PROFILING_PORT",
+			Value: strconv.Itoa(int(*port)),
+		})
+	}
+	if source.Spec.DisableHTTP2 {
+		env = append(env, corev1.EnvVar{
+			Name:  "DISABLE_HTTP2",
+			Value: "true",
+		})
+	}
+	if source.Spec.StreamRequestBody {
+		env = append(env, corev1.EnvVar{
+			Name:  "STREAM_REQUEST_BODY",
+			Value: "true",
+		})
+	}
+
+	env = append(env, sinkHeadersEnv(source)...)
+	env = append(env, fieldMappingEnv(source)...)
+
+	if source.Spec.SinkContentEncoding != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "SINK_CONTENT_ENCODING",
+			Value: source.Spec.SinkContentEncoding,
+		})
+	}
+
+	if respect := source.Spec.RespectRetryAfter; respect != nil && !*respect {
+		env = append(env, corev1.EnvVar{
+			Name:  "RESPECT_RETRY_AFTER",
+			Value: "false",
+		})
+	}
+
+	if source.Spec.SequenceExtension {
+		env = append(env, corev1.EnvVar{
+			Name:  "SEQUENCE_EXTENSION",
+			Value: "true",
+		})
+	}
+
+	if source.Spec.ProvenanceExtensions {
+		env = append(env, corev1.EnvVar{
+			Name:  "PROVENANCE_EXTENSIONS",
+			Value: "true",
+		}, corev1.EnvVar{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		})
+	}
+
+	if json := source.Spec.JSON; json != nil {
+		if json.DisableHTMLEscape {
+			env = append(env, corev1.EnvVar{
+				Name:  "JSON_DISABLE_HTML_ESCAPE",
+				Value: "true",
+			})
+		}
+		if json.Indent {
+			env = append(env, corev1.EnvVar{
+				Name:  "JSON_INDENT",
+				Value: "true",
+			})
+		}
+	}
+
+	if source.Spec.DataEncoding != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "DATA_ENCODING",
+			Value: source.Spec.DataEncoding,
+		})
+	}
+
+	if source.Spec.DeliveryDelay != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "DELIVERY_DELAY",
+			Value: source.Spec.DeliveryDelay.Duration.String(),
+		})
+	}
+
+	if source.Spec.AckRetries != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "ACK_RETRIES",
+			Value: strconv.Itoa(int(*source.Spec.AckRetries)),
+		})
+	}
+
+	if source.Spec.AckSweepInterval != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "ACK_SWEEP_INTERVAL",
+			Value: source.Spec.AckSweepInterval.Duration.String(),
+		})
+	}
+
+	if source.Spec.LagSampleInterval != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "LAG_SAMPLE_INTERVAL",
+			Value: source.Spec.LagSampleInterval.Duration.String(),
+		})
+	}
+
+	if source.Spec.ExclusiveConsumer {
+		env = append(env, corev1.EnvVar{
+			Name:  "LEASE_NAME",
+			Value: AdapterName(source),
+		}, corev1.EnvVar{
+			Name:  "LEASE_DURATION",
+			Value: source.Spec.GetLeaseDuration().String(),
+		})
+	}
+
+	if source.Spec.CheckpointInterval > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "CHECKPOINT_INTERVAL",
+			Value: strconv.Itoa(int(source.Spec.CheckpointInterval)),
+		}, corev1.EnvVar{
+			Name:  "CURSOR_CONFIGMAP",
+			Value: CursorConfigMapName(source),
+		})
+	}
+
+	if source.Spec.HealthCheckInterval != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "HEALTH_CHECK_INTERVAL",
+			Value: source.Spec.HealthCheckInterval.Duration.String(),
+		})
+	}
+
+	// The adapter drains within the grace period the kubelet gives the pod.
+	var terminationGracePeriodSeconds *int64
+	if period := source.Spec.TerminationGracePeriod; period != nil {
+		seconds := int64((period.Duration + time.Second - 1) / time.Second)
+		terminationGracePeriodSeconds = &seconds
+		env = append(env, corev1.EnvVar{
+			Name:  "TERMINATION_GRACE_PERIOD",
+			Value: (time.Duration(seconds) * time.Second).String(),
+		})
+	}
+
+	if source.Spec.ReadCount > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "READ_COUNT",
+			Value: strconv.Itoa(int(source.Spec.ReadCount)),
+		})
+	}
+
+	if source.Spec.ReadBlockTimeout != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "READ_BLOCK_TIMEOUT",
+			Value: source.Spec.ReadBlockTimeout.Duration.String(),
+		})
+	}
+
+	if t := source.Spec.GetTrimming(); t != nil && t.OnAck {
+		env = append(env, corev1.EnvVar{
+			Name:  "TRIM_MAX_LEN",
+			Value: t.Threshold,
+		}, corev1.EnvVar{
+			Name:  "TRIM_EXACT",
+			Value: strconv.FormatBool(!t.Approximate),
+		})
+	}
+
+	if source.Spec.SchemaURL != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "SCHEMA_URL",
+			Value: source.Spec.SchemaURL,
+		}, corev1.EnvVar{
+			Name:  "SCHEMA_REFRESH_INTERVAL",
+			Value: source.Spec.GetSchemaRefreshInterval().String(),
+		})
+		if source.Spec.SchemaValidation != "" {
+			env = append(env, corev1.EnvVar{
+				Name:  "SCHEMA_VALIDATION",
+				Value: string(source.Spec.SchemaValidation),
+			})
+		}
+	}
+
+	if t := source.Spec.Tracing; t != nil && t.SamplingRate != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "TRACING_SAMPLING_RATE",
+			Value: t.SamplingRate,
+		})
+	}
+
+	if b := source.Spec.ReconnectBackoff; b != nil {
+		if b.InitialDelay != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "REDIS_BACKOFF_INITIAL",
+				Value: b.InitialDelay.Duration.String(),
+			})
+		}
+		if b.MaxDelay != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "REDIS_BACKOFF_MAX",
+				Value: b.MaxDelay.Duration.String(),
+			})
+		}
+		if b.Multiplier != "" {
+			env = append(env, corev1.EnvVar{
+				Name:  "REDIS_BACKOFF_MULTIPLIER",
+				Value: b.Multiplier,
+			})
+		}
+	}
+
+	if source.Spec.BatchSize > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "BATCH_SIZE",
+			Value: strconv.Itoa(int(source.Spec.BatchSize)),
+		}, corev1.EnvVar{
+			Name:  "BATCH_LINGER",
+			Value: source.Spec.GetBatchLinger().String(),
+		})
+		if source.Spec.BatchMaxBytes > 0 {
+			env = append(env, corev1.EnvVar{
+				Name:  "BATCH_MAX_BYTES",
+				Value: strconv.FormatInt(source.Spec.BatchMaxBytes, 10),
+			})
+		}
+	}
+
+	if reclaim := source.Spec.Reclaim; reclaim != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "RECLAIM_MIN_IDLE_TIME",
+			Value: reclaim.MinIdleTime.Duration.String(),
+		}, corev1.EnvVar{
+			Name:  "RECLAIM_INTERVAL",
+			Value: reclaim.GetInterval().String(),
+		}, corev1.EnvVar{
+			Name:  "RECLAIM_MAX_DELIVERY_ATTEMPTS",
+			Value: strconv.Itoa(int(reclaim.MaxDeliveryAttempts)),
+		})
+	}
+
+	if sentinel := source.Spec.Sentinel; sentinel != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "SENTINEL_MASTER_NAME",
+			Value: sentinel.MasterName,
+		}, corev1.EnvVar{
+			Name:  "SENTINEL_ADDRESSES",
+			Value: strings.Join(sentinel.Addresses, ","),
+		})
+	}
+
+	if cluster := source.Spec.Cluster; cluster != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "CLUSTER_ADDRESSES",
+			Value: strings.Join(cluster.Addresses, ","),
+		})
+	}
+
+	if source.Spec.DisableAutoAck {
+		env = append(env, corev1.EnvVar{
+			Name:  "DISABLE_AUTO_ACK",
+			Value: "true",
+		})
+	}
+
+	if binary := source.Spec.BinaryData; binary != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "BINARY_DATA_FIELD",
+			Value: binary.Field,
+		}, corev1.EnvVar{
+			Name:  "BINARY_DATA_CONTENT_TYPE",
+			Value: binary.ContentType,
+		})
+	}
+
+	if bridge := source.Spec.KafkaBridge; bridge != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "KAFKA_BRIDGE_TOPIC",
+			Value: bridge.Topic,
+		})
+	}
+
+	if key := source.Spec.PartitionKey; key != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "PARTITION_KEY_FIELD",
+			Value: key.Field,
+		}, corev1.EnvVar{
+			Name:  "PARTITION_KEY_PARTITIONS",
+			Value: strconv.Itoa(int(key.Partitions)),
+		})
+	}
+
+	if source.Spec.ConditionalRequests {
+		env = append(env, corev1.EnvVar{
+			Name:  "CONDITIONAL_REQUESTS",
+			Value: "true",
+		})
+	}
+
+	if dedup := source.Spec.Dedup; dedup != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "DEDUP_KEY",
+			Value: dedup.GetKey(source.Spec.GetStream()),
+		})
+	}
+
+	if streams := source.Spec.GetStreams(); len(streams) > 1 {
+		env = append(env, corev1.EnvVar{
+			Name:  "STREAMS",
+			Value: strings.Join(streams, ","),
+		})
+	}
+
+	if source.Spec.DeadLetterStream != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "DEAD_LETTER_STREAM",
+			Value: source.Spec.DeadLetterStream,
+		})
+	}
+
+	if startID := source.Spec.GetStartID(); startID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "START_ID",
+			Value: startID,
+		})
+	}
+
+	if source.Spec.MinID != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "MIN_ID",
+			Value: source.Spec.MinID,
+		})
+	}
+
+	if report := source.Spec.FailureReport; report != nil && sinks.FailureReport != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "FAILURE_REPORT_SINK",
+			Value: sinks.FailureReport,
+		}, corev1.EnvVar{
+			Name:  "FAILURE_REPORT_THRESHOLD",
+			Value: strconv.Itoa(int(report.Threshold)),
+		}, corev1.EnvVar{
+			Name:  "FAILURE_REPORT_WINDOW",
+			Value: report.GetWindow().String(),
+		})
+	}
+
+	if delivery := source.Spec.Delivery; delivery != nil {
+		if delivery.Retry != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "DELIVERY_RETRY",
+				Value: strconv.Itoa(int(*delivery.Retry)),
+			})
+		}
+		if delivery.BackoffPolicy != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "DELIVERY_BACKOFF_POLICY",
+				Value: string(*delivery.BackoffPolicy),
+			})
+		}
+		if delivery.BackoffDelay != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "DELIVERY_BACKOFF_DELAY",
+				Value: sourcesv1alpha1.DeliveryDuration(delivery.BackoffDelay).String(),
+			})
+		}
+		if delivery.Timeout != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "DELIVERY_TIMEOUT",
+				Value: sourcesv1alpha1.DeliveryDuration(delivery.Timeout).String(),
+			})
+		}
+	}
+
+	if sinks.DeadLetter != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "DEAD_LETTER_SINK",
+			Value: sinks.DeadLetter,
+		})
+	}
+
+	if sinks.Audit != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "AUDIT_SINK",
+			Value: sinks.Audit,
+		})
+	}
+
+	for i, uri := range sinks.Additional {
+		env = append(env, corev1.EnvVar{
+			Name:  fmt.Sprintf("ADDITIONAL_SINK_%d_URI", i),
+			Value: uri,
+		}, corev1.EnvVar{
+			Name:  fmt.Sprintf("ADDITIONAL_SINK_%d_REQUIRED", i),
+			Value: strconv.FormatBool(source.Spec.AdditionalSinks[i].Required),
+		})
+	}
+
+	if source.Spec.OnSinkAddressPending == sourcesv1alpha1.SinkAddressPendingHold {
+		env = append(env, corev1.EnvVar{
+			Name:  "HOLD_ON_SINK_UNAVAILABLE",
+			Value: "true",
+		})
+	}
+
+	if source.Spec.OnEmptyEntry != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "ON_EMPTY_ENTRY",
+			Value: string(source.Spec.OnEmptyEntry),
+		})
+	}
+
+	if source.Spec.SequenceCounter {
+		env = append(env, corev1.EnvVar{
+			Name:  "SEQUENCE_COUNTER",
+			Value: "true",
+		})
+	}
+
+	if source.Spec.RedeliveredTypeSuffix != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "REDELIVERED_TYPE_SUFFIX",
+			Value: source.Spec.RedeliveredTypeSuffix,
+		})
+	}
+
+	if source.Spec.Type != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "EVENT_TYPE",
+			Value: source.Spec.Type,
+		})
+	}
+
+	if source.Spec.TypeFieldName != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "TYPE_FIELD_NAME",
+			Value: source.Spec.TypeFieldName,
+		})
+	}
+
+	if source.Spec.EventSource != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "EVENT_SOURCE",
+			Value: source.Spec.EventSource,
+		})
+	}
+
+	if overrides := source.Spec.CloudEventOverrides; overrides != nil {
+		// The CloudEvents client of the adapter sets the extensions of the overrides.
+		value, _ := json.Marshal(overrides) // maps of strings always marshal
+		env = append(env, corev1.EnvVar{
+			Name:  "K_CE_OVERRIDES",
+			Value: string(value),
+		})
+	}
+
+	if callback := source.Spec.ProducerCallback; callback != nil {
+		if callback.URL != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  "PRODUCER_CALLBACK_URL",
+				Value: callback.URL.String(),
+			})
+		}
+		if callback.URLField != "" {
+			env = append(env, corev1.EnvVar{
+				Name:  "PRODUCER_CALLBACK_URL_FIELD",
+				Value: callback.URLField,
+			})
+		}
+	}
+
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if ref := source.Spec.TargetConfigMap; ref != nil {
+		// The kubelet refreshes the files when the ConfigMap is updated, which the
+		// adapter watches. A missing ConfigMap leaves the stream and group of the spec.
+		volumes = append(volumes, corev1.Volume{
+			Name: "target",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: *ref,
+					Optional:             pointer.Bool(true),
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "target",
+			MountPath: targetMountPath,
+			ReadOnly:  true,
+		})
+		env = append(env, corev1.EnvVar{
+			Name:  "TARGET_PATH",
+			Value: targetMountPath,
+		})
+	}
+	if oidc := source.Spec.GetOIDC(); oidc != nil {
+		volume, mount := oidcTokenVolume(oidc)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mount)
+	}
+
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: source.Namespace,
@@ -49,61 +683,135 @@ func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Replicas: source.Spec.Consumers,
+			Replicas: replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: source.Spec.PodAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: ServiceAccountName(source),
+					ServiceAccountName:            AdapterServiceAccountName(source),
+					TerminationGracePeriodSeconds: terminationGracePeriodSeconds,
 					Containers: []corev1.Container{
 						{
-							Name:  "receive-adapter",
-							Image: image,
-							Env: []corev1.EnvVar{{
-								Name:  "STREAM",
-								Value: source.Spec.Stream,
-							}, {
-								Name:  "GROUP",
-								Value: source.Spec.Group,
-							}, {
-								Name:  "ADDRESS",
-								Value: source.Spec.Address,
-							}, {
-								Name:  "K_SINK",
-								Value: sinkURI,
-							}, {
-								Name:  "NUM_CONSUMERS",
-								Value: numConsumers,
-							}, {
-								Name:  "TLS_CERTIFICATE",
-								Value: tlsCert,
-							}, {
-								Name: "NAMESPACE",
-								ValueFrom: &corev1.EnvVarSource{
-									FieldRef: &corev1.ObjectFieldSelector{
-										FieldPath: "metadata.namespace",
-									},
-								},
-							}, {
-								Name: "NAME",
-								ValueFrom: &corev1.EnvVarSource{
-									FieldRef: &corev1.ObjectFieldSelector{
-										FieldPath: "metadata.name",
-									},
-								},
-							}, {
-								Name:  "METRICS_DOMAIN",
-								Value: "knative.dev/eventing",
-							}},
-							Ports: []corev1.ContainerPort{{
-								Name:          "metrics",
-								ContainerPort: 9090,
-							}},
+							Name:         "receive-adapter",
+							Image:        image,
+							Env:          env,
+							Ports:        ports,
+							VolumeMounts: volumeMounts,
+							Resources:    *source.Spec.Resources.DeepCopy(),
 						},
 					},
+					Volumes:      volumes,
+					NodeSelector: source.Spec.NodeSelector,
+					Tolerations:  source.Spec.Tolerations,
+					Affinity:     source.Spec.Affinity,
 				},
 			},
 		},
 	}
 }
+
+// sinkHeadersEnv returns the environment variables passing the sink headers to
+// the receive adapter, as SINK_HEADER_<i>_NAME and SINK_HEADER_<i>_VALUE pairs.
+func sinkHeadersEnv(source *sourcesv1alpha1.RedisStreamSource) []corev1.EnvVar {
+	names := make([]string, 0, len(source.Spec.SinkHeaders)+len(source.Spec.SinkHeadersFrom))
+	for name := range source.Spec.SinkHeaders {
+		names = append(names, name)
+	}
+	for name := range source.Spec.SinkHeadersFrom {
+		if _, ok := source.Spec.SinkHeaders[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	env := make([]corev1.EnvVar, 0, 2*len(names))
+	for i, name := range names {
+		value := corev1.EnvVar{Name: fmt.Sprintf("SINK_HEADER_%d_VALUE", i)}
+		if v, ok := source.Spec.SinkHeaders[name]; ok {
+			value.Value = v
+		} else {
+			value.ValueFrom = &corev1.EnvVarSource{
+				SecretKeyRef: source.Spec.SinkHeadersFrom[name].SecretKeyRef,
+			}
+		}
+		env = append(env, corev1.EnvVar{
+			Name:  fmt.Sprintf("SINK_HEADER_%d_NAME", i),
+			Value: name,
+		}, value)
+	}
+	return env
+}
+
+// fieldMappingEnv returns the environment variables passing the field mapping
+// to the receive adapter, as FIELD_MAPPING_<i>_FIELD and
+// FIELD_MAPPING_<i>_EXTENSION pairs, with FIELD_MAPPING_<i>_REQUIRED for the
+// required fields.
+func fieldMappingEnv(source *sourcesv1alpha1.RedisStreamSource) []corev1.EnvVar {
+	fields := make([]string, 0, len(source.Spec.FieldMapping))
+	for field := range source.Spec.FieldMapping {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	required := make(map[string]bool, len(source.Spec.RequiredFields))
+	for _, field := range source.Spec.RequiredFields {
+		required[field] = true
+	}
+
+	env := make([]corev1.EnvVar, 0, 2*len(fields)+len(required))
+	for i, field := range fields {
+		env = append(env, corev1.EnvVar{
+			Name:  fmt.Sprintf("FIELD_MAPPING_%d_FIELD", i),
+			Value: field,
+		}, corev1.EnvVar{
+			Name:  fmt.Sprintf("FIELD_MAPPING_%d_EXTENSION", i),
+			Value: source.Spec.FieldMapping[field],
+		})
+		if required[field] {
+			env = append(env, corev1.EnvVar{
+				Name:  fmt.Sprintf("FIELD_MAPPING_%d_REQUIRED", i),
+				Value: "true",
+			})
+		}
+	}
+	if len(required) > 0 && source.Spec.OnMissingField != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "ON_MISSING_FIELD",
+			Value: string(source.Spec.OnMissingField),
+		})
+	}
+	return env
+}
+
+// redisTLSEnv returns the environment variables passing the CA certificate,
+// and the optional client certificate and key, of the TLS secret to the
+// receive adapter.
+func redisTLSEnv(tls *sourcesv1alpha1.RedisTLS) []corev1.EnvVar {
+	secretName := tls.SecretName
+	fromSecret := func(name, key string, optional bool) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+					Optional:             pointer.Bool(optional),
+				},
+			},
+		}
+	}
+	env := []corev1.EnvVar{
+		fromSecret("REDIS_TLS_CA_CERT", sourcesv1alpha1.RedisTLSCACertKey, false),
+		fromSecret("REDIS_TLS_CERT", sourcesv1alpha1.RedisTLSCertKey, true),
+		fromSecret("REDIS_TLS_KEY", sourcesv1alpha1.RedisTLSKeyKey, true),
+	}
+	if tls.InsecureSkipVerify {
+		env = append(env, corev1.EnvVar{
+			Name:  "REDIS_TLS_INSECURE_SKIP_VERIFY",
+			Value: "true",
+		})
+	}
+	return env
+}
@@ -18,10 +18,17 @@ package resources
 
 import (
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/kmp"
 
@@ -42,7 +49,7 @@ func TestMakeReceiveAdapter(t *testing.T) {
 		},
 	}
 
-	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "5", "")
+	got := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "")
 
 	one := int32(1)
 	labels := Labels(src.Name)
@@ -100,6 +107,9 @@ func TestMakeReceiveAdapter(t *testing.T) {
 											FieldPath: "metadata.name",
 										},
 									},
+								}, {
+									Name:  "SOURCE_NAME",
+									Value: "source-name",
 								}, {
 									Name:  "METRICS_DOMAIN",
 									Value: "knative.dev/eventing",
@@ -120,3 +130,1295 @@ func TestMakeReceiveAdapter(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakeReceiveAdapterPorts(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:        "mystream",
+			MetricsPort:   pointer.Int32(19090),
+			ProfilingPort: pointer.Int32(18008),
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	wantPorts := []corev1.ContainerPort{{
+		Name:          "metrics",
+		ContainerPort: 19090,
+	}, {
+		Name:          "profiling",
+		ContainerPort: 18008,
+	}}
+	if diff, err := kmp.SafeDiff(wantPorts, container.Ports); err != nil {
+		t.Fatal("Error diffing ports:", err)
+	} else if diff != "" {
+		t.Error("unexpected ports (-want, +got) =", diff)
+	}
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["METRICS_PROMETHEUS_PORT"]; got != "19090" {
+		t.Errorf("METRICS_PROMETHEUS_PORT = %q, want %q", got, "19090")
+	}
+	if got := env["PROFILING_PORT"]; got != "18008" {
+		t.Errorf("PROFILING_PORT = %q, want %q", got, "18008")
+	}
+}
+
+func TestMakeReceiveAdapterResources(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	if diff, err := kmp.SafeDiff(src.Spec.Resources, container.Resources); err != nil {
+		t.Fatal("Error diffing resources:", err)
+	} else if diff != "" {
+		t.Error("unexpected resources (-want, +got) =", diff)
+	}
+
+	// Without resources, the container has no requests or limits.
+	src.Spec.Resources = corev1.ResourceRequirements{}
+	container = MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+	if container.Resources.Requests != nil || container.Resources.Limits != nil {
+		t.Errorf("Resources = %+v, want none", container.Resources)
+	}
+}
+
+func TestMakeTrimJob(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:             "mystream",
+			ServiceAccountName: "workload-identity",
+			Trimming:           &v1alpha1.Trimming{Strategy: v1alpha1.TrimmingMinID, Threshold: "1700000000000-0", Approximate: true},
+		},
+	}
+	adapterEnv := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0].Env
+
+	cronJob := MakeTrimJob(src, "trim-image", adapterEnv)
+
+	if got, want := cronJob.Spec.Schedule, v1alpha1.DefaultTrimmingSchedule; got != want {
+		t.Errorf("Schedule = %q, want %q", got, want)
+	}
+	if got, want := cronJob.Spec.ConcurrencyPolicy, batchv1.ForbidConcurrent; got != want {
+		t.Errorf("ConcurrencyPolicy = %q, want %q", got, want)
+	}
+	if got, want := cronJob.OwnerReferences[0].UID, src.UID; got != want {
+		t.Errorf("owner UID = %q, want %q", got, want)
+	}
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	if got, want := podSpec.ServiceAccountName, "workload-identity"; got != want {
+		t.Errorf("ServiceAccountName = %q, want %q", got, want)
+	}
+	container := podSpec.Containers[0]
+	if container.Image != "trim-image" {
+		t.Errorf("Image = %q, want trim-image", container.Image)
+	}
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"STREAM":           "mystream",
+		"TRIM_STRATEGY":    "MINID",
+		"TRIM_THRESHOLD":   "1700000000000-0",
+		"TRIM_APPROXIMATE": "true",
+		"SOURCE_UID":       "1234",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	// The environment of the receive adapter is not modified.
+	if got, want := len(adapterEnv), len(container.Env)-4; got != want {
+		t.Errorf("receive adapter environment has %d variables, want %d", got, want)
+	}
+}
+
+func TestMakeScaledObject(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			RedisConnection: v1alpha1.RedisConnection{
+				Sentinel: &v1alpha1.RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel-0:26379", "sentinel-1:26379"}},
+			},
+			TLS:         &v1alpha1.RedisTLS{SecretName: "redis-tls", InsecureSkipVerify: true},
+			Streams:     []string{"orders-0", "orders-1"},
+			Group:       "mygroup",
+			Autoscaling: &v1alpha1.Autoscaling{MinReplicas: pointer.Int32(0), MaxReplicas: 5, LagThreshold: 50},
+		},
+	}
+
+	scaledObject := MakeScaledObject(src)
+
+	if got, want := scaledObject.GetName(), AdapterName(src); got != want {
+		t.Errorf("name = %q, want %q", got, want)
+	}
+	if got := scaledObject.GetOwnerReferences(); len(got) != 1 || got[0].UID != src.UID {
+		t.Errorf("owner references = %v, want the source", got)
+	}
+	spec := scaledObject.Object["spec"].(map[string]interface{})
+	if got, want := spec["scaleTargetRef"], map[string]interface{}{"apiVersion": "apps/v1", "kind": "StatefulSet", "name": AdapterName(src)}; !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("scaleTargetRef = %v, want %v", got, want)
+	}
+	if spec["minReplicaCount"] != int64(0) || spec["maxReplicaCount"] != int64(5) {
+		t.Errorf("replica counts = %v, %v, want 0, 5", spec["minReplicaCount"], spec["maxReplicaCount"])
+	}
+	triggers := spec["triggers"].([]interface{})
+	if len(triggers) != 2 {
+		t.Fatalf("%d triggers, want one per stream", len(triggers))
+	}
+	for i, stream := range src.Spec.Streams {
+		trigger := triggers[i].(map[string]interface{})
+		if trigger["type"] != "redis-sentinel-streams" {
+			t.Errorf("trigger type = %v, want redis-sentinel-streams", trigger["type"])
+		}
+		want := map[string]interface{}{
+			"addresses":      "sentinel-0:26379,sentinel-1:26379",
+			"sentinelMaster": "mymaster",
+			"stream":         stream,
+			"consumerGroup":  "mygroup",
+			"lagCount":       "50",
+			"enableTLS":      "true",
+			"unsafeSsl":      "true",
+		}
+		if diff, err := kmp.SafeDiff(want, trigger["metadata"]); err != nil {
+			t.Fatal("Error diffing trigger metadata:", err)
+		} else if diff != "" {
+			t.Errorf("unexpected trigger metadata (-want, +got) = %s", diff)
+		}
+		if trigger["authenticationRef"] == nil {
+			t.Error("trigger has no authenticationRef")
+		}
+	}
+
+	// KEDA reads the TLS certificates from the environment of the receive adapter.
+	auth := MakeTriggerAuthentication(src)
+	if auth == nil {
+		t.Fatal("MakeTriggerAuthentication() = nil")
+	}
+	if got := len(auth.Object["spec"].(map[string]interface{})["env"].([]interface{})); got != 3 {
+		t.Errorf("TriggerAuthentication has %d env parameters, want 3", got)
+	}
+
+	src.Spec.TLS = nil
+	if auth := MakeTriggerAuthentication(src); auth != nil {
+		t.Errorf("MakeTriggerAuthentication() = %v, want nil without credentials", auth)
+	}
+}
+
+func TestMakeReceiveAdapterExclusiveConsumer(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:            "mystream",
+			ExclusiveConsumer: true,
+			LeaseDuration:     &metav1.Duration{Duration: 30 * time.Second},
+		},
+	}
+
+	ra := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "")
+
+	if ra.Spec.Replicas == nil || *ra.Spec.Replicas != 1 {
+		t.Errorf("Replicas = %v, want 1", ra.Spec.Replicas)
+	}
+	env := map[string]string{}
+	for _, e := range ra.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"NUM_CONSUMERS":  "1",
+		"LEASE_NAME":     AdapterName(src),
+		"LEASE_DURATION": "30s",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Without an exclusive consumer, the consumers do not hold a lease.
+	src.Spec.ExclusiveConsumer = false
+	src.Spec.LeaseDuration = nil
+	for _, e := range MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0].Env {
+		if e.Name == "LEASE_NAME" || e.Name == "LEASE_DURATION" {
+			t.Errorf("unexpected %s environment variable", e.Name)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterReplicas(t *testing.T) {
+	tests := []struct {
+		name      string
+		consumers *int32
+		replicas  *int32
+		want      *int32
+	}{{
+		name: "default",
+	}, {
+		name:      "consumers",
+		consumers: pointer.Int32(3),
+		want:      pointer.Int32(3),
+	}, {
+		name:      "replicas override consumers",
+		consumers: pointer.Int32(3),
+		replicas:  pointer.Int32(5),
+		want:      pointer.Int32(5),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &v1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "source-name",
+					Namespace: "source-namespace",
+				},
+				Spec: v1alpha1.RedisStreamSourceSpec{
+					Stream:    "mystream",
+					Group:     "mygroup",
+					Consumers: test.consumers,
+					Replicas:  test.replicas,
+				},
+			}
+
+			ra := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "")
+			if diff, err := kmp.SafeDiff(test.want, ra.Spec.Replicas); err != nil {
+				t.Error("Replicas diff error:", err)
+			} else if diff != "" {
+				t.Error("unexpected replicas (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestMakeReceiveAdapterTerminationGracePeriod(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:                 "mystream",
+			TerminationGracePeriod: &metav1.Duration{Duration: 90500 * time.Millisecond},
+		},
+	}
+
+	ra := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "")
+
+	if got := ra.Spec.Template.Spec.TerminationGracePeriodSeconds; got == nil || *got != 91 {
+		t.Errorf("TerminationGracePeriodSeconds = %v, want 91", got)
+	}
+	env := map[string]string{}
+	for _, e := range ra.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if got, want := env["TERMINATION_GRACE_PERIOD"], "1m31s"; got != want {
+		t.Errorf("TERMINATION_GRACE_PERIOD = %q, want %q", got, want)
+	}
+}
+
+func TestMakeReceiveAdapterCheckpointInterval(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:             "mystream",
+			CheckpointInterval: 100,
+		},
+	}
+
+	env := map[string]string{}
+	for _, e := range MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"CHECKPOINT_INTERVAL": "100",
+		"CURSOR_CONFIGMAP":    "source-name-cursor",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Without a checkpoint interval, nothing is checkpointed.
+	src.Spec.CheckpointInterval = 0
+	for _, e := range MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0].Env {
+		if e.Name == "CHECKPOINT_INTERVAL" || e.Name == "CURSOR_CONFIGMAP" {
+			t.Errorf("unexpected %s environment variable", e.Name)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterServiceAccount(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+		},
+	}
+
+	if got, want := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.ServiceAccountName, ServiceAccountName(src); got != want {
+		t.Errorf("ServiceAccountName = %q, want %q", got, want)
+	}
+	if got, want := MakeRoleBinding(src, "adapter-role").Subjects[0].Name, ServiceAccountName(src); got != want {
+		t.Errorf("role binding subject = %q, want %q", got, want)
+	}
+
+	// The service account of the spec replaces the one of the source.
+	src.Spec.ServiceAccountName = "workload-identity"
+	if got, want := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.ServiceAccountName, "workload-identity"; got != want {
+		t.Errorf("ServiceAccountName = %q, want %q", got, want)
+	}
+	if got, want := MakeRoleBinding(src, "adapter-role").Subjects[0].Name, "workload-identity"; got != want {
+		t.Errorf("role binding subject = %q, want %q", got, want)
+	}
+}
+
+func TestMakeReceiveAdapterScheduling(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:       "mystream",
+			NodeSelector: map[string]string{"topology.kubernetes.io/zone": "eu-west-1a"},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "redis", Effect: corev1.TaintEffectNoSchedule},
+			},
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"eventing.knative.dev/SourceName": "source-name"}},
+							TopologyKey:   "kubernetes.io/hostname",
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	podSpec := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec
+
+	if diff, err := kmp.SafeDiff(src.Spec.NodeSelector, podSpec.NodeSelector); err != nil {
+		t.Fatal("Error diffing node selector:", err)
+	} else if diff != "" {
+		t.Error("unexpected node selector (-want, +got) =", diff)
+	}
+	if diff, err := kmp.SafeDiff(src.Spec.Tolerations, podSpec.Tolerations); err != nil {
+		t.Fatal("Error diffing tolerations:", err)
+	} else if diff != "" {
+		t.Error("unexpected tolerations (-want, +got) =", diff)
+	}
+	if diff, err := kmp.SafeDiff(src.Spec.Affinity, podSpec.Affinity); err != nil {
+		t.Fatal("Error diffing affinity:", err)
+	} else if diff != "" {
+		t.Error("unexpected affinity (-want, +got) =", diff)
+	}
+
+	// Without scheduling constraints, the pods can be scheduled on any node.
+	src.Spec.NodeSelector, src.Spec.Tolerations, src.Spec.Affinity = nil, nil, nil
+	podSpec = MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec
+	if podSpec.NodeSelector != nil || podSpec.Tolerations != nil || podSpec.Affinity != nil {
+		t.Errorf("NodeSelector, Tolerations, Affinity = %v, %v, %v, want none", podSpec.NodeSelector, podSpec.Tolerations, podSpec.Affinity)
+	}
+}
+
+func TestMakeReceiveAdapterAdditionalSinks(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			AdditionalSinks: []v1alpha1.AdditionalSink{
+				{Required: true},
+				{},
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri", Additional: []string{"http://primary", "http://metrics"}}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"ADDITIONAL_SINK_0_URI":      "http://primary",
+		"ADDITIONAL_SINK_0_REQUIRED": "true",
+		"ADDITIONAL_SINK_1_URI":      "http://metrics",
+		"ADDITIONAL_SINK_1_REQUIRED": "false",
+	}
+	for name, value := range want {
+		if got := env[name]; got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterProvenanceExtensions(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:               "mystream",
+			ProvenanceExtensions: true,
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	var nodeName *corev1.EnvVar
+	env := map[string]string{}
+	for i, e := range container.Env {
+		env[e.Name] = e.Value
+		if e.Name == "NODE_NAME" {
+			nodeName = &container.Env[i]
+		}
+	}
+	if got := env["PROVENANCE_EXTENSIONS"]; got != "true" {
+		t.Errorf("PROVENANCE_EXTENSIONS = %q, want %q", got, "true")
+	}
+	if nodeName == nil || nodeName.ValueFrom == nil || nodeName.ValueFrom.FieldRef == nil {
+		t.Fatal("NODE_NAME is not set from the downward API")
+	}
+	if got := nodeName.ValueFrom.FieldRef.FieldPath; got != "spec.nodeName" {
+		t.Errorf("NODE_NAME field path = %q, want %q", got, "spec.nodeName")
+	}
+}
+
+func TestMakeReceiveAdapterSentinel(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			RedisConnection: v1alpha1.RedisConnection{
+				Sentinel: &v1alpha1.RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel-0:26379", "sentinel-1:26379"}},
+			},
+			Stream: "mystream",
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["SENTINEL_MASTER_NAME"]; got != "mymaster" {
+		t.Errorf("SENTINEL_MASTER_NAME = %q, want %q", got, "mymaster")
+	}
+	if got := env["SENTINEL_ADDRESSES"]; got != "sentinel-0:26379,sentinel-1:26379" {
+		t.Errorf("SENTINEL_ADDRESSES = %q, want %q", got, "sentinel-0:26379,sentinel-1:26379")
+	}
+}
+
+func TestMakeReceiveAdapterCluster(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			RedisConnection: v1alpha1.RedisConnection{
+				Cluster: &v1alpha1.RedisCluster{Addresses: []string{"redis-cluster-0:6379", "redis-cluster-1:6379"}},
+			},
+			Stream: "{orders}",
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["CLUSTER_ADDRESSES"]; got != "redis-cluster-0:6379,redis-cluster-1:6379" {
+		t.Errorf("CLUSTER_ADDRESSES = %q, want %q", got, "redis-cluster-0:6379,redis-cluster-1:6379")
+	}
+}
+
+func TestMakeReceiveAdapterDisableAutoAck(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:         "mystream",
+			Group:          "mygroup",
+			DisableAutoAck: true,
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["DISABLE_AUTO_ACK"]; got != "true" {
+		t.Errorf("DISABLE_AUTO_ACK = %q, want %q", got, "true")
+	}
+}
+
+func TestMakeReceiveAdapterReclaim(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Group:  "mygroup",
+			Reclaim: &v1alpha1.Reclaim{
+				MinIdleTime:         metav1.Duration{Duration: 5 * time.Minute},
+				MaxDeliveryAttempts: 3,
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"RECLAIM_MIN_IDLE_TIME":         "5m0s",
+		"RECLAIM_INTERVAL":              "5m0s",
+		"RECLAIM_MAX_DELIVERY_ATTEMPTS": "3",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterBatch(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:        "mystream",
+			Group:         "mygroup",
+			BatchSize:     50,
+			BatchMaxBytes: 65536,
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"BATCH_SIZE":      "50",
+		"BATCH_MAX_BYTES": "65536",
+		"BATCH_LINGER":    "1s",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterLagSampleInterval(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:            "mystream",
+			LagSampleInterval: &metav1.Duration{Duration: 10 * time.Second},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	for _, e := range container.Env {
+		if e.Name == "LAG_SAMPLE_INTERVAL" {
+			if e.Value != "10s" {
+				t.Errorf("LAG_SAMPLE_INTERVAL = %q, want %q", e.Value, "10s")
+			}
+			return
+		}
+	}
+	t.Error("LAG_SAMPLE_INTERVAL is not set")
+}
+
+func TestMakeReceiveAdapterHealthCheckInterval(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:              "mystream",
+			HealthCheckInterval: &metav1.Duration{Duration: 10 * time.Second},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	for _, e := range container.Env {
+		if e.Name == "HEALTH_CHECK_INTERVAL" {
+			if e.Value != "10s" {
+				t.Errorf("HEALTH_CHECK_INTERVAL = %q, want %q", e.Value, "10s")
+			}
+			return
+		}
+	}
+	t.Error("HEALTH_CHECK_INTERVAL is not set")
+}
+
+func TestMakeReceiveAdapterReadOptions(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:           "mystream",
+			ReadCount:        10,
+			ReadBlockTimeout: &metav1.Duration{},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["READ_COUNT"]; got != "10" {
+		t.Errorf("READ_COUNT = %q, want %q", got, "10")
+	}
+	if got := env["READ_BLOCK_TIMEOUT"]; got != "0s" {
+		t.Errorf("READ_BLOCK_TIMEOUT = %q, want %q", got, "0s")
+	}
+}
+
+func TestMakeReceiveAdapterTrimOnAck(t *testing.T) {
+	tests := []struct {
+		name string
+		spec v1alpha1.RedisStreamSourceSpec
+	}{{
+		name: "trimming on ack",
+		spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:   "mystream",
+			Trimming: &v1alpha1.Trimming{Strategy: v1alpha1.TrimmingMaxLen, Threshold: "10000", Approximate: true, OnAck: true},
+		},
+	}, {
+		name: "deprecated trim strategy",
+		spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:       "mystream",
+			TrimStrategy: &v1alpha1.TrimStrategy{MaxLen: 10000},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := &v1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "source-name",
+					Namespace: "source-namespace",
+				},
+				Spec: test.spec,
+			}
+
+			container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+			env := map[string]string{}
+			for _, e := range container.Env {
+				env[e.Name] = e.Value
+			}
+			if got := env["TRIM_MAX_LEN"]; got != "10000" {
+				t.Errorf("TRIM_MAX_LEN = %q, want %q", got, "10000")
+			}
+			if got := env["TRIM_EXACT"]; got != "false" {
+				t.Errorf("TRIM_EXACT = %q, want %q", got, "false")
+			}
+		})
+	}
+}
+
+func TestMakeReceiveAdapterSchema(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:           "mystream",
+			SchemaURL:        "https://schemas.example.com/order.json",
+			SchemaValidation: v1alpha1.SchemaValidationReject,
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"SCHEMA_URL":              "https://schemas.example.com/order.json",
+		"SCHEMA_VALIDATION":       "Reject",
+		"SCHEMA_REFRESH_INTERVAL": "5m0s",
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterTracing(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:  "mystream",
+			Tracing: &v1alpha1.Tracing{SamplingRate: "0.25"},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	for _, e := range container.Env {
+		if e.Name == "TRACING_SAMPLING_RATE" {
+			if e.Value != "0.25" {
+				t.Errorf("TRACING_SAMPLING_RATE = %q, want %q", e.Value, "0.25")
+			}
+			return
+		}
+	}
+	t.Error("TRACING_SAMPLING_RATE is not set")
+}
+
+func TestMakeReceiveAdapterReconnectBackoff(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			ReconnectBackoff: &v1alpha1.ReconnectBackoff{
+				InitialDelay: &metav1.Duration{Duration: time.Second},
+				Multiplier:   "1.5",
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["REDIS_BACKOFF_INITIAL"]; got != "1s" {
+		t.Errorf("REDIS_BACKOFF_INITIAL = %q, want %q", got, "1s")
+	}
+	if _, ok := env["REDIS_BACKOFF_MAX"]; ok {
+		t.Error("REDIS_BACKOFF_MAX is set, want the default of the receive adapter")
+	}
+	if got := env["REDIS_BACKOFF_MULTIPLIER"]; got != "1.5" {
+		t.Errorf("REDIS_BACKOFF_MULTIPLIER = %q, want %q", got, "1.5")
+	}
+}
+
+func TestMakeReceiveAdapterTargetConfigMap(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:          "mystream",
+			TargetConfigMap: &corev1.LocalObjectReference{Name: "redis-target"},
+		},
+	}
+
+	spec := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec
+	container := spec.Containers[0]
+
+	wantVolumes := []corev1.Volume{{
+		Name: "target",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "redis-target"},
+				Optional:             pointer.Bool(true),
+			},
+		},
+	}}
+	if diff, err := kmp.SafeDiff(wantVolumes, spec.Volumes); err != nil {
+		t.Fatal("Error diffing volumes:", err)
+	} else if diff != "" {
+		t.Error("unexpected volumes (-want, +got) =", diff)
+	}
+
+	wantMounts := []corev1.VolumeMount{{
+		Name:      "target",
+		MountPath: targetMountPath,
+		ReadOnly:  true,
+	}}
+	if diff, err := kmp.SafeDiff(wantMounts, container.VolumeMounts); err != nil {
+		t.Fatal("Error diffing volume mounts:", err)
+	} else if diff != "" {
+		t.Error("unexpected volume mounts (-want, +got) =", diff)
+	}
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if got := env["TARGET_PATH"]; got != targetMountPath {
+		t.Errorf("TARGET_PATH = %q, want %q", got, targetMountPath)
+	}
+}
+
+func TestMakeReceiveAdapterTLS(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			TLS:    &v1alpha1.RedisTLS{SecretName: "redis-tls"},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	want := map[string]*corev1.SecretKeySelector{
+		"REDIS_TLS_CA_CERT": {LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"}, Key: "ca.crt", Optional: pointer.Bool(false)},
+		"REDIS_TLS_CERT":    {LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"}, Key: "tls.crt", Optional: pointer.Bool(true)},
+		"REDIS_TLS_KEY":     {LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"}, Key: "tls.key", Optional: pointer.Bool(true)},
+	}
+	for _, e := range container.Env {
+		selector, ok := want[e.Name]
+		if !ok {
+			continue
+		}
+		delete(want, e.Name)
+		if e.ValueFrom == nil {
+			t.Errorf("%s is not set from the TLS secret", e.Name)
+			continue
+		}
+		if diff, err := kmp.SafeDiff(selector, e.ValueFrom.SecretKeyRef); err != nil {
+			t.Fatal("Error diffing secret key selectors:", err)
+		} else if diff != "" {
+			t.Errorf("unexpected %s secret key selector (-want, +got) = %s", e.Name, diff)
+		}
+	}
+	for name := range want {
+		t.Errorf("%s is not set", name)
+	}
+}
+
+func TestMakeReceiveAdapterAuth(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Auth: &v1alpha1.RedisAuth{
+				Username: "knative",
+				PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+					Key:                  "password",
+				},
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]corev1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	if got := env["REDIS_USERNAME"].Value; got != "knative" {
+		t.Errorf("REDIS_USERNAME = %q, want %q", got, "knative")
+	}
+	want := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"}
+	if from := env["REDIS_PASSWORD"].ValueFrom; from == nil {
+		t.Error("REDIS_PASSWORD is not set from the secret")
+	} else if diff, err := kmp.SafeDiff(want, from.SecretKeyRef); err != nil {
+		t.Fatal("Error diffing secret key selectors:", err)
+	} else if diff != "" {
+		t.Errorf("unexpected REDIS_PASSWORD secret key selector (-want, +got) = %s", diff)
+	}
+}
+
+func TestMakeReceiveAdapterAuthUsernameSecret(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Auth: &v1alpha1.RedisAuth{
+				UsernameSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+					Key:                  "username",
+				},
+				PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"},
+					Key:                  "password",
+				},
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]corev1.EnvVar{}
+	for _, e := range container.Env {
+		env[e.Name] = e
+	}
+	want := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "username"}
+	if from := env["REDIS_USERNAME"].ValueFrom; from == nil {
+		t.Error("REDIS_USERNAME is not set from the secret")
+	} else if diff, err := kmp.SafeDiff(want, from.SecretKeyRef); err != nil {
+		t.Fatal("Error diffing secret key selectors:", err)
+	} else if diff != "" {
+		t.Errorf("unexpected REDIS_USERNAME secret key selector (-want, +got) = %s", diff)
+	}
+}
+
+func TestMakeReceiveAdapterAuthPasswordFile(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Auth: &v1alpha1.RedisAuth{
+				Username:     "knative",
+				PasswordFile: "/vault/secrets/redis-password",
+			},
+			PodAnnotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+		},
+	}
+
+	template := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template
+
+	env := map[string]corev1.EnvVar{}
+	for _, e := range template.Spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	if got := env["REDIS_PASSWORD_FILE"].Value; got != "/vault/secrets/redis-password" {
+		t.Errorf("REDIS_PASSWORD_FILE = %q, want %q", got, "/vault/secrets/redis-password")
+	}
+	if _, ok := env["REDIS_PASSWORD"]; ok {
+		t.Error("REDIS_PASSWORD is set with a password file")
+	}
+	if got := template.Annotations["vault.hashicorp.com/agent-inject"]; got != "true" {
+		t.Errorf("pod annotation vault.hashicorp.com/agent-inject = %q, want %q", got, "true")
+	}
+}
+
+func TestMakeReceiveAdapterAuthOIDC(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Auth: &v1alpha1.RedisAuth{
+				Username: "redis-identity",
+				OIDC: &v1alpha1.RedisOIDC{
+					ServiceAccountName: "redis-reader",
+					Audience:           "api://AzureADTokenExchange",
+					TokenURL:           "https://login.microsoftonline.com/tenant/oauth2/v2.0/token",
+					ClientID:           "myclient",
+					Scope:              "https://redis.azure.com/.default",
+				},
+			},
+		},
+	}
+
+	spec := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec
+
+	if spec.ServiceAccountName != "redis-reader" {
+		t.Errorf("serviceAccountName = %q, want %q", spec.ServiceAccountName, "redis-reader")
+	}
+	env := map[string]corev1.EnvVar{}
+	for _, e := range spec.Containers[0].Env {
+		env[e.Name] = e
+	}
+	for name, want := range map[string]string{
+		"REDIS_USERNAME":        "redis-identity",
+		"REDIS_OIDC_TOKEN_FILE": "/var/run/secrets/redis-oidc/token",
+		"REDIS_OIDC_TOKEN_URL":  "https://login.microsoftonline.com/tenant/oauth2/v2.0/token",
+		"REDIS_OIDC_CLIENT_ID":  "myclient",
+		"REDIS_OIDC_SCOPE":      "https://redis.azure.com/.default",
+	} {
+		if got := env[name].Value; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, ok := env["REDIS_PASSWORD"]; ok {
+		t.Error("REDIS_PASSWORD is set with OIDC")
+	}
+
+	var volume *corev1.Volume
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == oidcTokenVolumeName {
+			volume = &spec.Volumes[i]
+		}
+	}
+	if volume == nil || volume.Projected == nil {
+		t.Fatal("no projected service account token volume")
+	}
+	if got := volume.Projected.Sources[0].ServiceAccountToken.Audience; got != "api://AzureADTokenExchange" {
+		t.Errorf("token audience = %q, want %q", got, "api://AzureADTokenExchange")
+	}
+	mounted := false
+	for _, m := range spec.Containers[0].VolumeMounts {
+		mounted = mounted || (m.Name == oidcTokenVolumeName && m.MountPath == oidcTokenMountPath)
+	}
+	if !mounted {
+		t.Errorf("the token volume is not mounted at %s", oidcTokenMountPath)
+	}
+}
+
+func TestMakeReceiveAdapterTLSInsecureSkipVerify(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		src := &v1alpha1.RedisStreamSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source-name",
+				Namespace: "source-namespace",
+			},
+			Spec: v1alpha1.RedisStreamSourceSpec{
+				Stream: "mystream",
+				TLS:    &v1alpha1.RedisTLS{SecretName: "redis-tls", InsecureSkipVerify: skip},
+			},
+		}
+
+		container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+		got := false
+		for _, e := range container.Env {
+			if e.Name == "REDIS_TLS_INSECURE_SKIP_VERIFY" {
+				got = e.Value == "true"
+			}
+		}
+		if got != skip {
+			t.Errorf("REDIS_TLS_INSECURE_SKIP_VERIFY set = %v, want %v", got, skip)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterDeadLetterSink(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{Stream: "mystream"},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri", DeadLetter: "http://dls"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	got := ""
+	for _, e := range container.Env {
+		if e.Name == "DEAD_LETTER_SINK" {
+			got = e.Value
+		}
+	}
+	if got != "http://dls" {
+		t.Errorf("DEAD_LETTER_SINK = %q, want %q", got, "http://dls")
+	}
+}
+
+func TestMakeReceiveAdapterStreams(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{Streams: []string{"orders", "payments"}},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	if env["STREAM"] != "orders" {
+		t.Errorf("STREAM = %q, want %q", env["STREAM"], "orders")
+	}
+	if env["STREAMS"] != "orders,payments" {
+		t.Errorf("STREAMS = %q, want %q", env["STREAMS"], "orders,payments")
+	}
+}
+
+func TestMakeReceiveAdapterDelivery(t *testing.T) {
+	linear := eventingduckv1.BackoffPolicyLinear
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream: "mystream",
+			Delivery: &eventingduckv1.DeliverySpec{
+				Retry:         pointer.Int32(3),
+				BackoffPolicy: &linear,
+				BackoffDelay:  pointer.String("PT0.2S"),
+				Timeout:       pointer.String("PT10S"),
+			},
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"DELIVERY_RETRY":          "3",
+		"DELIVERY_BACKOFF_POLICY": "linear",
+		"DELIVERY_BACKOFF_DELAY":  "200ms",
+		"DELIVERY_TIMEOUT":        "10s",
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+}
+
+func TestMakeReceiveAdapterFieldMapping(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Stream:         "mystream",
+			FieldMapping:   map[string]string{"tenant": "tenantid", "region": "region"},
+			RequiredFields: []string{"tenant"},
+			OnMissingField: v1alpha1.MissingFieldDeadLetter,
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"FIELD_MAPPING_0_FIELD":     "region",
+		"FIELD_MAPPING_0_EXTENSION": "region",
+		"FIELD_MAPPING_1_FIELD":     "tenant",
+		"FIELD_MAPPING_1_EXTENSION": "tenantid",
+		"FIELD_MAPPING_1_REQUIRED":  "true",
+		"ON_MISSING_FIELD":          "DeadLetter",
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+	if _, ok := env["FIELD_MAPPING_0_REQUIRED"]; ok {
+		t.Error("FIELD_MAPPING_0_REQUIRED is set for a field that is not required")
+	}
+}
+
+func TestMakeReceiveAdapterEventAttributes(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				CloudEventOverrides: &duckv1.CloudEventOverrides{Extensions: map[string]string{"tenant": "acme"}},
+			},
+			Stream:        "mystream",
+			Type:          "com.example.order",
+			TypeFieldName: "kind",
+			EventSource:   "/orders",
+		},
+	}
+
+	container := MakeReceiveAdapter(src, "test-image", SinkURIs{Sink: "sink-uri"}, "5", "").Spec.Template.Spec.Containers[0]
+
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"EVENT_TYPE":      "com.example.order",
+		"TYPE_FIELD_NAME": "kind",
+		"EVENT_SOURCE":    "/orders",
+		"K_CE_OVERRIDES":  `{"extensions":{"tenant":"acme"}}`,
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+}
@@ -32,7 +32,7 @@ func RoleBindingName(source *sourcesv1alpha1.RedisStreamSource) string {
 }
 
 // MakeRoleBinding creates a RoleBinding object for the single-tenant receive adapter
-// service account 'sa' in the Namespace 'ns'.
+// service account in the namespace of the source.
 func MakeRoleBinding(source *v1alpha1.RedisStreamSource, clusterRoleName string) *rbacv1.RoleBinding {
 	name := RoleBindingName(source)
 	return &rbacv1.RoleBinding{
@@ -52,7 +52,7 @@ func MakeRoleBinding(source *v1alpha1.RedisStreamSource, clusterRoleName string)
 			{
 				Kind:      "ServiceAccount",
 				Namespace: source.Namespace,
-				Name:      name,
+				Name:      AdapterServiceAccountName(source),
 			},
 		},
 	}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+var (
+	// ScaledObjectGVR is the resource of the KEDA ScaledObjects scaling the
+	// receive adapters.
+	ScaledObjectGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+	// TriggerAuthenticationGVR is the resource of the KEDA
+	// TriggerAuthentications passing the credentials of the receive adapters
+	// to their ScaledObjects.
+	TriggerAuthenticationGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "triggerauthentications"}
+)
+
+// MakeScaledObject generates (but does not insert into K8s) the KEDA
+// ScaledObject scaling the receive adapter StatefulSet of the source on the
+// lag of its consumer group, with a trigger per stream.
+func MakeScaledObject(source *sourcesv1alpha1.RedisStreamSource) *unstructured.Unstructured {
+	autoscaling := source.Spec.Autoscaling
+	triggerType, connection := redisTriggerMetadata(source)
+	authenticated := MakeTriggerAuthentication(source) != nil
+
+	streams := source.Spec.GetStreams()
+	triggers := make([]interface{}, 0, len(streams))
+	for _, stream := range streams {
+		metadata := map[string]interface{}{
+			"stream":        stream,
+			"consumerGroup": source.ConsumerGroup(),
+			"lagCount":      strconv.FormatInt(autoscaling.GetLagThreshold(), 10),
+		}
+		for key, value := range connection {
+			metadata[key] = value
+		}
+		trigger := map[string]interface{}{
+			"type":     triggerType,
+			"metadata": metadata,
+		}
+		if authenticated {
+			trigger["authenticationRef"] = map[string]interface{}{"name": AdapterName(source)}
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return kedaObject(source, "ScaledObject", map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"name":       AdapterName(source),
+		},
+		"minReplicaCount": int64(autoscaling.GetMinReplicas()),
+		"maxReplicaCount": int64(autoscaling.MaxReplicas),
+		"triggers":        triggers,
+	})
+}
+
+// MakeTriggerAuthentication generates (but does not insert into K8s) the KEDA
+// TriggerAuthentication passing the credentials and the TLS certificates of
+// the receive adapter of the source to the triggers of its ScaledObject, or
+// nil when it has none. KEDA reads them from the environment of the receive
+// adapter container.
+func MakeTriggerAuthentication(source *sourcesv1alpha1.RedisStreamSource) *unstructured.Unstructured {
+	var env []interface{}
+	fromEnv := func(parameter, name string) {
+		env = append(env, map[string]interface{}{
+			"parameter":     parameter,
+			"name":          name,
+			"containerName": "receive-adapter",
+		})
+	}
+	if auth := source.Spec.Auth; auth != nil && auth.PasswordSecretRef != nil {
+		if auth.Username != "" || auth.UsernameSecretRef != nil {
+			fromEnv("username", "REDIS_USERNAME")
+		}
+		fromEnv("password", "REDIS_PASSWORD")
+	}
+	if source.Spec.TLS != nil {
+		fromEnv("ca", "REDIS_TLS_CA_CERT")
+		fromEnv("cert", "REDIS_TLS_CERT")
+		fromEnv("key", "REDIS_TLS_KEY")
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	return kedaObject(source, "TriggerAuthentication", map[string]interface{}{
+		"env": env,
+	})
+}
+
+// redisTriggerMetadata returns the type of the KEDA trigger reading the lag of
+// the streams of the source, and the metadata connecting it to Redis the same
+// way as the receive adapter.
+func redisTriggerMetadata(source *sourcesv1alpha1.RedisStreamSource) (string, map[string]string) {
+	metadata := make(map[string]string)
+	triggerType := "redis-streams"
+	switch {
+	case source.Spec.Sentinel != nil:
+		triggerType = "redis-sentinel-streams"
+		metadata["addresses"] = strings.Join(source.Spec.Sentinel.Addresses, ",")
+		metadata["sentinelMaster"] = source.Spec.Sentinel.MasterName
+	case source.Spec.Cluster != nil:
+		triggerType = "redis-cluster-streams"
+		metadata["addresses"] = strings.Join(source.Spec.Cluster.Addresses, ",")
+	default:
+		metadata["address"] = source.Spec.Address
+		if opt, err := scan.ParseAddress(source.Spec.Address); err == nil {
+			metadata["address"] = opt.Addr
+			metadata["databaseIndex"] = strconv.Itoa(opt.DB)
+			if opt.TLSConfig != nil {
+				metadata["enableTLS"] = "true"
+			}
+		}
+	}
+	if tls := source.Spec.TLS; tls != nil {
+		metadata["enableTLS"] = "true"
+		if tls.InsecureSkipVerify {
+			metadata["unsafeSsl"] = "true"
+		}
+	}
+	return triggerType, metadata
+}
+
+// kedaObject returns the KEDA object of the given kind and spec, owned by the
+// source, with the name of its receive adapter.
+func kedaObject(source *sourcesv1alpha1.RedisStreamSource, kind string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ScaledObjectGVR.GroupVersion().String(),
+		"kind":       kind,
+		"spec":       spec,
+	}}
+	obj.SetNamespace(source.Namespace)
+	obj.SetName(AdapterName(source))
+	obj.SetLabels(Labels(source.Name))
+	obj.SetOwnerReferences([]metav1.OwnerReference{*kmeta.NewControllerRef(source)})
+	return obj
+}
@@ -20,8 +20,20 @@ import (
 	"fmt"
 
 	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 )
 
 func ServiceAccountName(source kmeta.OwnerRefable) string {
 	return kmeta.ChildName(fmt.Sprintf("redistreamsource-%s-", source.GetObjectMeta().GetName()), string(source.GetObjectMeta().GetUID()))
 }
+
+// AdapterServiceAccountName returns the name of the service account the
+// receive adapter pods run as: the one of the source spec or of its OIDC
+// authentication, or else the one created for the source.
+func AdapterServiceAccountName(source *sourcesv1alpha1.RedisStreamSource) string {
+	if name := source.Spec.GetServiceAccountName(); name != "" {
+		return name
+	}
+	return ServiceAccountName(source)
+}
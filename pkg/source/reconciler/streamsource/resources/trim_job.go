@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"knative.dev/pkg/kmeta"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// trimJobComponentLabel tells the pods of the trim job from the receive
+// adapter pods, which have the same source labels.
+const trimJobComponentLabel = "eventing.knative.dev/sourceComponent"
+
+// TrimJobName returns the name of the CronJob trimming the streams of the source.
+func TrimJobName(source *sourcesv1alpha1.RedisStreamSource) string {
+	return kmeta.ChildName(fmt.Sprintf("redissource-%s-trim-", source.Name), string(source.UID))
+}
+
+// MakeTrimJob generates (but does not insert into K8s) the CronJob trimming
+// the streams of the source following its trimming policy. Its container runs
+// with env, the environment of the receive adapter container, so that it
+// connects to Redis the same way.
+func MakeTrimJob(source *sourcesv1alpha1.RedisStreamSource, image string, env []corev1.EnvVar) *batchv1.CronJob {
+	trimming := source.Spec.GetTrimming()
+	labels := Labels(source.Name)
+	labels[trimJobComponentLabel] = "trim-job"
+
+	env = append(append(make([]corev1.EnvVar, 0, len(env)+4), env...), corev1.EnvVar{
+		Name:  "TRIM_STRATEGY",
+		Value: string(trimming.Strategy),
+	}, corev1.EnvVar{
+		Name:  "TRIM_THRESHOLD",
+		Value: trimming.Threshold,
+	}, corev1.EnvVar{
+		Name:  "TRIM_APPROXIMATE",
+		Value: strconv.FormatBool(trimming.Approximate),
+	}, corev1.EnvVar{
+		Name:  "SOURCE_UID",
+		Value: string(source.UID),
+	})
+
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if oidc := source.Spec.GetOIDC(); oidc != nil {
+		// The pods exchange the service account token for the password too.
+		volume, mount := oidcTokenVolume(oidc)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mount)
+	}
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      TrimJobName(source),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: trimming.GetSchedule(),
+			// A trim that did not finish yet is not started again.
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: pointer.Int32(1),
+			FailedJobsHistoryLimit:     pointer.Int32(1),
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: pointer.Int32(2),
+					Template: corev1.PodTemplateSpec{
+						// The pods read the password file of the auth too.
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      labels,
+							Annotations: source.Spec.PodAnnotations,
+						},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: AdapterServiceAccountName(source),
+							RestartPolicy:      corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:         "trim-job",
+									Image:        image,
+									Env:          env,
+									VolumeMounts: volumeMounts,
+								},
+							},
+							Volumes:      volumes,
+							NodeSelector: source.Spec.NodeSelector,
+							Tolerations:  source.Spec.Tolerations,
+							Affinity:     source.Spec.Affinity,
+						},
+					},
+				},
+			},
+		},
+	}
+}
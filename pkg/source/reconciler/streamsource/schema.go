@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/schema"
+)
+
+// schemaFetchTimeout bounds the time spent downloading a schema, and the
+// schemas it references, during a reconciliation.
+const schemaFetchTimeout = 10 * time.Second
+
+// schemaResolver checks that the JSON Schemas of the sources can be used.
+type schemaResolver interface {
+	Resolve(ctx context.Context, url string) error
+}
+
+// httpSchemaResolver downloads and compiles the schemas the same way the
+// receive adapter does.
+type httpSchemaResolver struct {
+	client *http.Client
+}
+
+func (r httpSchemaResolver) Resolve(ctx context.Context, url string) error {
+	_, err := schema.Fetch(ctx, r.client, url)
+	return err
+}
+
+// reconcileSchema reflects in the status whether the schema the entries are
+// validated against can be downloaded and compiled.
+func (r *Reconciler) reconcileSchema(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) {
+	url := source.Spec.SchemaURL
+	if url == "" {
+		source.Status.MarkNoSchema()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, schemaFetchTimeout)
+	defer cancel()
+	err := r.schemas.Resolve(ctx, url)
+	switch {
+	case err == nil:
+		source.Status.MarkSchemaResolved()
+	case errors.Is(err, schema.ErrNotFound):
+		source.Status.MarkSchemaNotResolved("SchemaNotFound", "Schema %q not found", url)
+	case errors.Is(err, schema.ErrInvalid):
+		source.Status.MarkSchemaNotResolved("SchemaInvalid", "Schema %q is not a valid JSON Schema: %v", url, err)
+	default:
+		source.Status.MarkSchemaNotResolved("SchemaFetchFailed", "Cannot download schema %q: %v", url, err)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/schema"
+)
+
+type fakeSchemaResolver struct {
+	url string
+	err error
+}
+
+func (f *fakeSchemaResolver) Resolve(ctx context.Context, url string) error {
+	f.url = url
+	return f.err
+}
+
+func TestReconcileSchema(t *testing.T) {
+	const url = "https://schemas.example.com/order.json"
+	tests := []struct {
+		name       string
+		url        string
+		schemas    *fakeSchemaResolver
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{{
+		name:    "no schema",
+		schemas: &fakeSchemaResolver{},
+	}, {
+		name:       "resolved",
+		url:        url,
+		schemas:    &fakeSchemaResolver{},
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:       "not found",
+		url:        url,
+		schemas:    &fakeSchemaResolver{err: fmt.Errorf("%w at %s", schema.ErrNotFound, url)},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "SchemaNotFound",
+	}, {
+		name:       "invalid",
+		url:        url,
+		schemas:    &fakeSchemaResolver{err: fmt.Errorf("%w: unexpected EOF", schema.ErrInvalid)},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "SchemaInvalid",
+	}, {
+		name:       "unreachable",
+		url:        url,
+		schemas:    &fakeSchemaResolver{err: errors.New("dial tcp: connection refused")},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "SchemaFetchFailed",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{schemas: test.schemas}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{SchemaURL: test.url},
+			}
+			// A previous condition is removed when there is no schema.
+			source.Status.MarkSchemaResolved()
+
+			r.reconcileSchema(context.Background(), source)
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionSchemaResolved)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("SchemaResolved = %+v, want none", cond)
+				}
+				return
+			}
+			if test.schemas.url != url {
+				t.Errorf("resolved schema %q, want %q", test.schemas.url, url)
+			}
+			if cond == nil {
+				t.Fatal("SchemaResolved condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("SchemaResolved = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// discoveryTimeout bounds the connections to the sentinels and cluster
+	// nodes asked where Redis is.
+	discoveryTimeout = 5 * time.Second
+
+	// sentinelQuorumRequeue is how long to wait before checking again the
+	// sentinels that do not reach quorum yet.
+	sentinelQuorumRequeue = 10 * time.Second
+
+	// sentinelUnavailableRequeue is how long to wait before asking again the
+	// sentinels that cannot be reached, so that the condition clears once
+	// they are back.
+	sentinelUnavailableRequeue = 30 * time.Second
+)
+
+// sentinelClient asks the sentinels monitoring the Redis master of a source.
+type sentinelClient interface {
+	// MasterAddr returns the host:port address of the master.
+	MasterAddr(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) (string, error)
+	// CheckQuorum returns an error unless the sentinels reach the quorum
+	// needed to fail the master over.
+	CheckQuorum(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) error
+}
+
+// redisSentinelClient connects to the sentinels the same way the receive
+// adapter does.
+type redisSentinelClient struct{}
+
+func (redisSentinelClient) MasterAddr(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	return scan.MasterAddr(sentinel.Addresses, sentinel.MasterName, dialDiscovery(ctx))
+}
+
+func (redisSentinelClient) CheckQuorum(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) error {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	return scan.CheckQuorum(sentinel.Addresses, sentinel.MasterName, dialDiscovery(ctx))
+}
+
+func dialDiscovery(ctx context.Context) func(address string) (redis.Conn, error) {
+	return func(address string) (redis.Conn, error) {
+		return redis.DialContext(ctx, "tcp", address,
+			redis.DialReadTimeout(discoveryTimeout),
+			redis.DialWriteTimeout(discoveryTimeout),
+		)
+	}
+}
+
+// reconcileSentinel reflects in the status whether the sentinels of the
+// source reach quorum, and returns when to check again while they do not or
+// cannot be reached.
+func (r *Reconciler) reconcileSentinel(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) time.Duration {
+	sentinel := source.Spec.Sentinel
+	if sentinel == nil {
+		source.Status.MarkNoSentinel()
+		return 0
+	}
+
+	if err := r.sentinels.CheckQuorum(ctx, sentinel); err != nil {
+		if scan.IsNoQuorum(err) {
+			source.Status.MarkSourceAvailableUnknown("QuorumPending", "Sentinels of master %q do not reach quorum yet: %v", sentinel.MasterName, err)
+			return sentinelQuorumRequeue
+		}
+		source.Status.MarkSourceNotAvailable("SentinelUnavailable", "Cannot check the sentinels of master %q: %v", sentinel.MasterName, err)
+		return sentinelUnavailableRequeue
+	}
+	source.Status.MarkSourceAvailable()
+	return 0
+}
+
+// redisAddress returns the address of Redis for the source: its address, or
+// the URL of the master its sentinels know, or of the node of its cluster
+// serving the stream, asked with the credentials of the source.
+func (r *Reconciler) redisAddress(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, creds redisCredentials) (string, error) {
+	var addr string
+	var err error
+	switch {
+	case source.Spec.Sentinel != nil:
+		addr, err = r.sentinels.MasterAddr(ctx, source.Spec.Sentinel)
+	case source.Spec.Cluster != nil:
+		addr, err = r.clusters.StreamNode(ctx, source.Spec.Cluster, source.Spec.GetStream(), creds)
+	default:
+		return source.Spec.Address, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "redis://" + addr, nil
+}
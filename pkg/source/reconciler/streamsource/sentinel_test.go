@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	corev1 "k8s.io/api/core/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+type fakeSentinelClient struct {
+	master    string
+	masterErr error
+	quorumErr error
+}
+
+func (f *fakeSentinelClient) MasterAddr(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) (string, error) {
+	return f.master, f.masterErr
+}
+
+func (f *fakeSentinelClient) CheckQuorum(ctx context.Context, sentinel *sourcesv1alpha1.RedisSentinel) error {
+	return f.quorumErr
+}
+
+func TestReconcileSentinel(t *testing.T) {
+	sentinel := &sourcesv1alpha1.RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}}
+
+	tests := []struct {
+		name        string
+		sentinel    *sourcesv1alpha1.RedisSentinel
+		quorumErr   error
+		wantStatus  corev1.ConditionStatus
+		wantReason  string
+		wantRequeue time.Duration
+	}{{
+		name: "no sentinel",
+	}, {
+		name:       "quorum",
+		sentinel:   sentinel,
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:        "no quorum",
+		sentinel:    sentinel,
+		quorumErr:   redis.Error("NOQUORUM 1 usable Sentinels"),
+		wantStatus:  corev1.ConditionUnknown,
+		wantReason:  "QuorumPending",
+		wantRequeue: sentinelQuorumRequeue,
+	}, {
+		name:        "sentinels unreachable",
+		sentinel:    sentinel,
+		quorumErr:   errors.New("dial tcp: connection refused"),
+		wantStatus:  corev1.ConditionFalse,
+		wantReason:  "SentinelUnavailable",
+		wantRequeue: sentinelUnavailableRequeue,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{sentinels: &fakeSentinelClient{quorumErr: test.quorumErr}}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: sourcesv1alpha1.RedisConnection{Sentinel: test.sentinel},
+				},
+			}
+			// A previous condition is removed with the sentinel spec.
+			source.Status.MarkSourceAvailable()
+
+			if got := r.reconcileSentinel(context.Background(), source); got != test.wantRequeue {
+				t.Errorf("reconcileSentinel() = %v, want %v", got, test.wantRequeue)
+			}
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionSourceAvailable)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("SourceAvailable = %+v, want none", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatal("SourceAvailable condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("SourceAvailable = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+			if test.quorumErr != nil && cond.Message == "" {
+				t.Error("SourceAvailable has no message, want the sentinel error")
+			}
+		})
+	}
+}
+
+func TestRedisAddress(t *testing.T) {
+	r := &Reconciler{sentinels: &fakeSentinelClient{master: "10.0.0.1:6379"}}
+
+	source := &sourcesv1alpha1.RedisStreamSource{
+		Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+		},
+	}
+	if got, err := r.redisAddress(context.Background(), source, redisCredentials{}); err != nil || got != "redis://redis:6379" {
+		t.Errorf("redisAddress() = %q, %v, want %q", got, err, "redis://redis:6379")
+	}
+
+	source.Spec.RedisConnection = sourcesv1alpha1.RedisConnection{
+		Sentinel: &sourcesv1alpha1.RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}},
+	}
+	if got, err := r.redisAddress(context.Background(), source, redisCredentials{}); err != nil || got != "redis://10.0.0.1:6379" {
+		t.Errorf("redisAddress() = %q, %v, want %q", got, err, "redis://10.0.0.1:6379")
+	}
+
+	r.sentinels = &fakeSentinelClient{masterErr: errors.New("unknown master")}
+	if _, err := r.redisAddress(context.Background(), source, redisCredentials{}); err == nil {
+		t.Error("redisAddress() = nil error, want the sentinel error")
+	}
+
+	r.clusters = &fakeClusterClient{node: "10.0.0.2:6379"}
+	source.Spec.RedisConnection = sourcesv1alpha1.RedisConnection{
+		Cluster: &sourcesv1alpha1.RedisCluster{Addresses: []string{"redis-cluster:6379"}},
+	}
+	if got, err := r.redisAddress(context.Background(), source, redisCredentials{}); err != nil || got != "redis://10.0.0.2:6379" {
+		t.Errorf("redisAddress() = %q, %v, want %q", got, err, "redis://10.0.0.2:6379")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingresources "knative.dev/eventing/pkg/reconciler/resources"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func newWarningServiceAccountNotFound(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ServiceAccountNotFound", "Service account %q not found in namespace %q", name, namespace)
+}
+
+// reconcileServiceAccount makes sure the service account of the receive
+// adapter exists. The service account of the source spec, or of its OIDC
+// authentication, is only looked up, as it is managed by the user: the
+// receive adapter is not deployed until it is created. Otherwise, a service
+// account is created for the source.
+func (r *Reconciler) reconcileServiceAccount(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
+	if name := source.Spec.GetServiceAccountName(); name != "" {
+		_, err := r.kubeClientSet.CoreV1().ServiceAccounts(source.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			event := newWarningServiceAccountNotFound(source.Namespace, name)
+			source.Status.MarkNoServiceAccount(event.Error())
+			return event
+		} else if err != nil {
+			err = fmt.Errorf("error getting service account %q: %v", name, err)
+			source.Status.MarkNoServiceAccount(err.Error())
+			return err
+		}
+		source.Status.MarkServiceAccount()
+		return nil
+	}
+
+	expectedServiceAccount := eventingresources.MakeServiceAccount(source, resources.ServiceAccountName(source))
+	sa, event := r.sar.ReconcileServiceAccount(ctx, source, expectedServiceAccount)
+	if sa == nil {
+		source.Status.MarkNoServiceAccount(event.Error())
+		return event
+	}
+	source.Status.MarkServiceAccount()
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func TestReconcileServiceAccount(t *testing.T) {
+	workloadIdentity := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "workload-identity"},
+	}
+
+	tests := []struct {
+		name           string
+		serviceAccount string
+		existing       []*corev1.ServiceAccount
+		wantErr        bool
+		wantAnnotation string
+		wantCreated    bool
+	}{{
+		name:        "created for the source",
+		wantCreated: true,
+	}, {
+		name:           "existing",
+		serviceAccount: "workload-identity",
+		existing:       []*corev1.ServiceAccount{workloadIdentity},
+	}, {
+		name:           "not found",
+		serviceAccount: "workload-identity",
+		wantErr:        true,
+		wantAnnotation: `Service account "workload-identity" not found in namespace "ns"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			for _, sa := range test.existing {
+				kubeClient.Tracker().Add(sa)
+			}
+			r := &Reconciler{kubeClientSet: kubeClient, sar: &reconciler.ServiceAccountReconciler{KubeClientSet: kubeClient}}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "1234"},
+				Spec:       sourcesv1alpha1.RedisStreamSourceSpec{ServiceAccountName: test.serviceAccount},
+			}
+			// A previous failure is cleared once the service account exists.
+			source.Status.MarkNoServiceAccount("previous failure")
+
+			err := r.reconcileServiceAccount(context.Background(), source)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("reconcileServiceAccount() = %v, want error %v", err, test.wantErr)
+			}
+			if got := source.Status.Annotations["serviceAccount"]; !strings.Contains(got, test.wantAnnotation) || (test.wantAnnotation == "" && got != "") {
+				t.Errorf("serviceAccount annotation = %q, want %q", got, test.wantAnnotation)
+			}
+
+			_, err = kubeClient.CoreV1().ServiceAccounts("ns").Get(context.Background(), resources.ServiceAccountName(source), metav1.GetOptions{})
+			if created := err == nil; created != test.wantCreated {
+				t.Errorf("service account of the source created = %v, want %v", created, test.wantCreated)
+			}
+		})
+	}
+}
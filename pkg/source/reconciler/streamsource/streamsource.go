@@ -19,21 +19,30 @@ package streamsource
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sort"
+	"time"
 
 	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/tracker"
 
-	eventingresources "knative.dev/eventing/pkg/reconciler/resources"
 	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
 
 	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 	streamsourcereconciler "knative.dev/eventing-redis/pkg/source/client/injection/reconciler/sources/v1alpha1/redisstreamsource"
+	sourceslisters "knative.dev/eventing-redis/pkg/source/client/listers/sources/v1alpha1"
 	"knative.dev/eventing-redis/pkg/source/reconciler"
 	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
 )
@@ -41,12 +50,67 @@ import (
 const (
 	component              = "redisstreamsource"
 	adapterClusterRoleName = "knative-sources-redisstream-adapter"
+
+	sinkAddressPendingMinBackoff = time.Second
+	sinkAddressPendingMaxBackoff = 5 * time.Minute
+
+	// clusterReshardingRequeue is how long to wait for a resharding Redis cluster.
+	clusterReshardingRequeue = 5 * time.Second
+
+	// groupDeleteTimeout is how long after the deletion of a source its
+	// consumer group is retried to be destroyed, before its finalizer is
+	// removed anyway.
+	groupDeleteTimeout = 10 * time.Minute
 )
 
 func newFinalizedNormal(namespace, name string) pkgreconciler.Event {
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "RedisStreamSourceFinalized", "RedisStreamSource finalized: \"%s/%s\"", namespace, name)
 }
 
+func newGroupDeletedNormal(group string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "ConsumerGroupDeleted", "Consumer group %q deleted", group)
+}
+
+func newWarningGroupNotDeleted(group string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ConsumerGroupDeleteFailed", "Failed to delete consumer group %q: %v", group, err)
+}
+
+func newWarningGroupDeleteSkipped(group string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ConsumerGroupDeleteSkipped", "Consumer group %q left in Redis: %v", group, err)
+}
+
+// groupNotDeleted returns the warning event of the consumer group of the
+// source not destroyed, which keeps the finalizer so that it is retried, until
+// groupDeleteTimeout elapsed since the source was deleted.
+func groupNotDeleted(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, group string, err error) pkgreconciler.Event {
+	if groupDeleteTimedOut(source, time.Now()) {
+		return releaseFinalizer(ctx, source, newWarningGroupDeleteAbandoned(group, err))
+	}
+	return newWarningGroupNotDeleted(group, err)
+}
+
+// groupDeleteTimedOut returns whether the consumer group of the source was
+// retried to be destroyed for groupDeleteTimeout.
+func groupDeleteTimedOut(source *sourcesv1alpha1.RedisStreamSource, now time.Time) bool {
+	deleted := source.GetDeletionTimestamp()
+	return deleted != nil && now.Sub(deleted.Time) > groupDeleteTimeout
+}
+
+// releaseFinalizer records the event of the source, and returns nil so that
+// its finalizer is removed anyway: the generated reconciler keeps it on
+// warning events.
+func releaseFinalizer(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, event pkgreconciler.Event) pkgreconciler.Event {
+	var e *pkgreconciler.ReconcilerEvent
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil && pkgreconciler.EventAs(event, &e) {
+		recorder.Event(source, e.EventType, e.Reason, e.Error())
+	}
+	return nil
+}
+
+func newWarningGroupDeleteAbandoned(group string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ConsumerGroupDeleteAbandoned", "Gave up deleting consumer group %q after %s: %v", group, groupDeleteTimeout, err)
+}
+
 func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
 	b, _ := json.Marshal(sink)
 	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
@@ -55,15 +119,28 @@ func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
 // Reconciler reconciles a streamsource object
 type Reconciler struct {
 	kubeClientSet       kubernetes.Interface
+	dynamicClientSet    dynamic.Interface
 	ssr                 *reconciler.StatefulSetReconciler
 	rbr                 *reconciler.RoleBindingReconciler
 	sar                 *reconciler.ServiceAccountReconciler
 	receiveAdapterImage string
+	trimJobImage        string
 	ceSource            string
 	sinkResolver        *resolver.URIResolver
 	configs             reconcilersource.ConfigAccessor
 	numConsumers        string
 	tlsCert             string
+	sourceLister        sourceslisters.RedisStreamSourceLister
+	groups              groupDestroyer
+	inspector           groupInspector
+	keys                keyTyper
+	schemas             schemaResolver
+	tls                 tlsChecker
+	sentinels           sentinelClient
+	clusters            clusterClient
+	secretLister        corev1listers.SecretLister
+	tracker             tracker.Interface
+	oidcPasswords       *oidcPasswordCache
 }
 
 // Check that our Reconciler implements ReconcileKind.
@@ -73,7 +150,17 @@ var _ streamsourcereconciler.Interface = (*Reconciler)(nil)
 var _ streamsourcereconciler.Finalizer = (*Reconciler)(nil)
 
 func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
+	// The receive adapter reports whether it reaches Redis in annotations of the
+	// source, whose updates enqueue it.
+	source.Status.PropagateRedisConnected(source.Annotations)
+	if source.Spec.ExclusiveConsumer {
+		source.Status.PropagateLeaseHolder(source.Annotations)
+	} else {
+		source.Status.LeaseHolder = ""
+	}
 	source.Annotations = nil
+	// The status is only updated when the summary, or anything else, changed.
+	defer func() { source.Status.Summary = source.Summary() }()
 
 	dest := source.Spec.Sink.DeepCopy()
 	if dest.Ref != nil {
@@ -83,16 +170,42 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 	}
 
 	sinkURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, source)
+	sinkAddressPending := false
 	if err != nil {
-		source.Status.MarkNoSink("NotFound", "")
-		return newWarningSinkNotFound(dest)
+		if dest.Ref == nil || source.Spec.OnSinkAddressPending != sourcesv1alpha1.SinkAddressPendingHold {
+			source.Status.MarkNoSink("NotFound", "")
+			return newWarningSinkNotFound(dest)
+		}
+		// Keep delivering to the last resolved address, the receive adapter
+		// holds the entries it cannot deliver until the sink is back.
+		source.Status.MarkSinkAddressPending("Holding entries until the sink has an address: %v", err)
+		if source.Status.SinkURI == nil {
+			source.Status.MarkNoSink("NotFound", "")
+			return controller.NewRequeueAfter(sinkAddressPendingBackoff(source, time.Now()))
+		}
+		sinkURI = source.Status.SinkURI
+		sinkAddressPending = true
+	} else {
+		source.Status.MarkSink(sinkURI.String())
+		source.Status.MarkNoSinkAddressPending()
 	}
-	source.Status.MarkSink(sinkURI.String())
 
-	expectedServiceAccount := eventingresources.MakeServiceAccount(source, resources.ServiceAccountName(source))
-	sa, event := r.sar.ReconcileServiceAccount(ctx, source, expectedServiceAccount)
-	if sa == nil {
-		source.Status.MarkNoServiceAccount(event.Error())
+	sinks := resources.SinkURIs{Sink: sinkURI.String()}
+	var event pkgreconciler.Event
+	if sinks.Audit, event = r.resolveAuditSink(ctx, source); event != nil {
+		return event
+	}
+	if sinks.FailureReport, event = r.resolveFailureReportSink(ctx, source); event != nil {
+		return event
+	}
+	if sinks.DeadLetter, event = r.resolveDeadLetterSink(ctx, source); event != nil {
+		return event
+	}
+	if sinks.Additional, event = r.resolveAdditionalSinks(ctx, source); event != nil {
+		return event
+	}
+
+	if event := r.reconcileServiceAccount(ctx, source); event != nil {
 		return event
 	}
 
@@ -103,7 +216,20 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 		return event
 	}
 
-	expectedStatefulSet := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinkURI.String(), r.numConsumers, r.tlsCert)
+	expectedStatefulSet := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinks, r.numConsumers, r.tlsCert)
+	if r.configs != nil {
+		// The receive adapter follows the logging, metrics and tracing
+		// configuration of the controller, exporting its metrics alongside
+		// the other eventing components.
+		container := &expectedStatefulSet.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, r.configs.ToEnvVars()...)
+	}
+	if event := r.reconcileEnv(ctx, source, expectedStatefulSet); event != nil {
+		return event
+	}
+	if event := r.reconcileAutoscaling(ctx, source, expectedStatefulSet); event != nil {
+		return event
+	}
 	ra, event := r.ssr.ReconcileStatefulSet(ctx, source, expectedStatefulSet)
 	if ra == nil {
 		if source.Status.Annotations == nil {
@@ -112,14 +238,227 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 		source.Status.Annotations["StatefulSet"] = event.Error()
 		return event
 	}
-	source.Status.PropagateStatefulSetAvailability(ra)
+	if event := r.reconcileTrimJob(ctx, source, expectedStatefulSet.Spec.Template.Spec.Containers[0].Env); event != nil {
+		return event
+	}
+	if event := r.reconcileCursor(ctx, source); event != nil {
+		return event
+	}
+	source.Status.StartID = source.Spec.EffectiveStartID()
+	now := time.Now()
+	warmup := source.Status.PropagateStatefulSetWarmup(ra, source.Spec.GetWarmupPeriod(), now)
+
+	if err := r.reconcileGroupCollision(source); err != nil {
+		return err
+	}
+
+	r.reconcileTLS(ctx, source)
+	sentinelRecheck := r.reconcileSentinel(ctx, source)
+	r.reconcileCluster(ctx, source)
+	r.reconcileDeadLetterStream(ctx, source)
+	r.reconcileSchema(ctx, source)
+	lagResync := r.reconcileGroupLag(ctx, source, now)
+
+	event = r.reconcileDeliveryWindow(source, now)
+	if sentinelRecheck > 0 {
+		event = requeueBefore(event, sentinelRecheck)
+	}
+	if sinkAddressPending {
+		event = requeueBefore(event, sinkAddressPendingBackoff(source, now))
+	}
+	if warmup > 0 {
+		event = requeueBefore(event, warmup)
+	}
+	if lagResync > 0 {
+		event = requeueBefore(event, lagResync)
+	}
+	return event
+}
+
+// requeueBefore returns a requeue after the given delay, unless event is an
+// error or an earlier requeue.
+func requeueBefore(event pkgreconciler.Event, after time.Duration) pkgreconciler.Event {
+	if requeue, current := controller.IsRequeueKey(event); event == nil || (requeue && after < current) {
+		return controller.NewRequeueAfter(after)
+	}
+	return event
+}
+
+// sinkAddressPendingBackoff returns when to resolve the sink again while its
+// address is pending. The delay doubles with the time the address has been
+// pending, between one second and five minutes.
+func sinkAddressPendingBackoff(source *sourcesv1alpha1.RedisStreamSource, now time.Time) time.Duration {
+	backoff := sinkAddressPendingMinBackoff
+	if cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionSinkAddressPending); cond != nil {
+		if pending := now.Sub(cond.LastTransitionTime.Inner.Time); pending > backoff {
+			backoff = pending
+		}
+	}
+	if backoff > sinkAddressPendingMaxBackoff {
+		backoff = sinkAddressPendingMaxBackoff
+	}
+	return backoff
+}
+
+// resolveAuditSink resolves the audit sink of the source, if any, to the URI
+// passed to the receive adapter.
+func (r *Reconciler) resolveAuditSink(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (string, pkgreconciler.Event) {
+	if source.Spec.AuditSink == nil {
+		source.Status.AuditSinkURI = nil
+		return "", nil
+	}
+
+	uri, dest, err := r.resolveDestination(ctx, source, source.Spec.AuditSink)
+	if err != nil {
+		source.Status.MarkNoSink("AuditSinkNotFound", "Audit sink not found: %v", err)
+		return "", newWarningSinkNotFound(dest)
+	}
+	source.Status.AuditSinkURI = uri
+	return uri.String(), nil
+}
+
+// resolveFailureReportSink resolves the sink failure reports are sent to, if
+// any, to the URI passed to the receive adapter.
+func (r *Reconciler) resolveFailureReportSink(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (string, pkgreconciler.Event) {
+	if source.Spec.FailureReport == nil {
+		source.Status.FailureReportSinkURI = nil
+		return "", nil
+	}
+
+	uri, dest, err := r.resolveDestination(ctx, source, &source.Spec.FailureReport.Sink)
+	if err != nil {
+		source.Status.MarkNoSink("FailureReportSinkNotFound", "Failure report sink not found: %v", err)
+		return "", newWarningSinkNotFound(dest)
+	}
+	source.Status.FailureReportSinkURI = uri
+	return uri.String(), nil
+}
+
+// resolveDeadLetterSink resolves the dead-letter sink of the source, if any, to
+// the URI passed to the receive adapter.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (string, pkgreconciler.Event) {
+	deadLetterSink := source.Spec.GetDeadLetterSink()
+	if deadLetterSink == nil {
+		source.Status.MarkNoDeadLetterSink()
+		return "", nil
+	}
+
+	uri, dest, err := r.resolveDestination(ctx, source, deadLetterSink)
+	if err != nil {
+		source.Status.MarkDeadLetterSinkNotResolved("NotFound", "Dead-letter sink not found: %v", err)
+		source.Status.MarkNoSink("DeadLetterSinkNotFound", "Dead-letter sink not found: %v", err)
+		return "", newWarningSinkNotFound(dest)
+	}
+	source.Status.MarkDeadLetterSinkResolved(uri)
+	return uri.String(), nil
+}
+
+// resolveAdditionalSinks resolves the additional sinks of the source to the
+// URIs passed to the receive adapter.
+func (r *Reconciler) resolveAdditionalSinks(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) ([]string, pkgreconciler.Event) {
+	source.Status.AdditionalSinkURIs = nil
+	var uris []string
+	for i := range source.Spec.AdditionalSinks {
+		uri, dest, err := r.resolveDestination(ctx, source, &source.Spec.AdditionalSinks[i].Destination)
+		if err != nil {
+			source.Status.MarkNoSink("AdditionalSinkNotFound", "Additional sink not found: %v", err)
+			return nil, newWarningSinkNotFound(dest)
+		}
+		source.Status.AdditionalSinkURIs = append(source.Status.AdditionalSinkURIs, uri)
+		uris = append(uris, uri.String())
+	}
+	return uris, nil
+}
 
+// resolveDestination resolves a destination of the source, referencing
+// objects in the namespace of the source by default.
+func (r *Reconciler) resolveDestination(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, dest *duckv1.Destination) (*apis.URL, *duckv1.Destination, error) {
+	dest = dest.DeepCopy()
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = source.GetNamespace()
+	}
+	uri, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, source)
+	return uri, dest, err
+}
+
+// reconcileDeliveryWindow reflects in the status whether the source is currently
+// paused by its delivery window, and requeues the source for the next time the
+// window opens or closes.
+func (r *Reconciler) reconcileDeliveryWindow(source *sourcesv1alpha1.RedisStreamSource, now time.Time) pkgreconciler.Event {
+	window := source.Spec.DeliveryWindow
+	if window == nil {
+		source.Status.MarkNoDeliveryWindow()
+		return nil
+	}
+
+	open, err := window.Contains(now)
+	if err != nil {
+		return err
+	}
+	next, err := window.NextTransition(now)
+	if err != nil {
+		return err
+	}
+
+	if open {
+		source.Status.MarkWithinDeliveryWindow(next)
+	} else {
+		source.Status.MarkOutsideDeliveryWindow(next)
+	}
+	return controller.NewRequeueAfter(next.Sub(now))
+}
+
+// reconcileGroupCollision warns when sources in other namespaces read the
+// same stream with the same consumer group, stealing each other's entries.
+func (r *Reconciler) reconcileGroupCollision(source *sourcesv1alpha1.RedisStreamSource) error {
+	sources, err := r.sourceLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	collisions := source.GroupCollisions(sources)
+	if len(collisions) == 0 {
+		source.Status.MarkNoGroupCollision()
+		return nil
+	}
+
+	names := make([]string, 0, len(collisions))
+	for _, other := range collisions {
+		names = append(names, other.Namespace+"/"+other.Name)
+	}
+	sort.Strings(names)
+	source.Status.MarkGroupCollision(names)
 	return nil
 }
 
 func (r *Reconciler) FinalizeKind(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
-	//Nothing to do since adapter will gracefully shutdown the consumers
-	return nil //ok to remove finalizer
+	group := source.ConsumerGroup()
+	if !source.Spec.DeleteGroupOnDelete || group == "" {
+		//Nothing to do since adapter will gracefully shutdown the consumers
+		return nil //ok to remove finalizer
+	}
+
+	// Keep the finalizer until the group is destroyed, so that it is retried,
+	// unless retrying cannot help, or for groupDeleteTimeout at most.
+	target, err := r.redisTarget(ctx, source)
+	if errors.Is(err, errCredentialsUnavailable) {
+		return releaseFinalizer(ctx, source, newWarningGroupDeleteSkipped(group, err))
+	}
+	if err != nil {
+		return groupNotDeleted(ctx, source, group, err)
+	}
+	for _, stream := range source.Spec.GetStreams() {
+		if err := r.groups.DestroyGroup(ctx, target, stream, group); err != nil {
+			if isResharding(err) && !groupDeleteTimedOut(source, time.Now()) {
+				// Not a failure, the group is destroyed once the cluster recovers.
+				source.Status.MarkClusterResharding(err)
+				return controller.NewRequeueAfter(clusterReshardingRequeue)
+			}
+			return groupNotDeleted(ctx, source, group, err)
+		}
+	}
+	source.Status.MarkNoClusterResharding()
+	return newGroupDeletedNormal(group)
 }
 
 func (r *Reconciler) updateRedisConfig(ctx context.Context, configMap *corev1.ConfigMap) {
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+type fakeGroupDestroyer struct {
+	err       error
+	destroyed []string
+}
+
+func (f *fakeGroupDestroyer) DestroyGroup(ctx context.Context, target redisTarget, stream, group string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.destroyed = append(f.destroyed, target.Address+" "+stream+" "+group)
+	return nil
+}
+
+func TestFinalizeKind(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          sourcesv1alpha1.RedisStreamSourceSpec
+		deletedSince  time.Duration
+		err           error
+		wantDestroyed []string
+		wantEvent     string
+		wantRequeue   bool
+	}{{
+		name: "disabled",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:          "mystream",
+			Group:           "mygroup",
+		},
+	}, {
+		name: "automatic group",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			DeleteGroupOnDelete: true,
+		},
+	}, {
+		name: "deleted",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		wantDestroyed: []string{"redis://redis:6379 mystream mygroup"},
+		wantEvent:     corev1.EventTypeNormal,
+	}, {
+		name: "namespaced group",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			NamespaceGroup:      true,
+			DeleteGroupOnDelete: true,
+		},
+		wantDestroyed: []string{"redis://redis:6379 mystream ns.mygroup"},
+		wantEvent:     corev1.EventTypeNormal,
+	}, {
+		name: "several streams",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Streams:             []string{"otherstream"},
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		wantDestroyed: []string{"redis://redis:6379 mystream mygroup", "redis://redis:6379 otherstream mygroup"},
+		wantEvent:     corev1.EventTypeNormal,
+	}, {
+		name: "failed",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		err:       errors.New("connection refused"),
+		wantEvent: corev1.EventTypeWarning,
+	}, {
+		// The finalizer is removed, with a warning event, once retrying
+		// timed out.
+		name: "failed for too long",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		deletedSince: time.Hour,
+		err:          errors.New("connection refused"),
+	}, {
+		name: "resharding for too long",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		deletedSince: time.Hour,
+		err:          redis.Error("CLUSTERDOWN The cluster is down"),
+	}, {
+		name: "cluster resharding",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection:     sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+		err:         redis.Error("CLUSTERDOWN The cluster is down"),
+		wantRequeue: true,
+	}, {
+		// The finalizer is removed, with a warning event, as retrying cannot
+		// authenticate either.
+		name: "credentials unavailable",
+		spec: sourcesv1alpha1.RedisStreamSourceSpec{
+			RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Auth: &sourcesv1alpha1.RedisAuth{PasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "redis-auth"}, Key: "password"}},
+			Stream:              "mystream",
+			Group:               "mygroup",
+			DeleteGroupOnDelete: true,
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			groups := &fakeGroupDestroyer{err: test.err}
+			r := &Reconciler{groups: groups, kubeClientSet: kubefake.NewSimpleClientset()}
+			source := &sourcesv1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+				Spec:       test.spec,
+			}
+			if test.deletedSince > 0 {
+				source.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-test.deletedSince)}
+			}
+
+			event := r.FinalizeKind(context.Background(), source)
+
+			if len(groups.destroyed) != len(test.wantDestroyed) {
+				t.Fatalf("destroyed = %v, want %v", groups.destroyed, test.wantDestroyed)
+			}
+			for i := range test.wantDestroyed {
+				if groups.destroyed[i] != test.wantDestroyed[i] {
+					t.Errorf("destroyed = %v, want %v", groups.destroyed, test.wantDestroyed)
+				}
+			}
+
+			if test.wantRequeue {
+				if requeue, _ := controller.IsRequeueKey(event); !requeue {
+					t.Errorf("FinalizeKind() = %v, want a requeue", event)
+				}
+				if cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionClusterResharding); cond == nil {
+					t.Error("ClusterResharding condition not set")
+				}
+				return
+			}
+			if test.wantEvent == "" {
+				if event != nil {
+					t.Errorf("FinalizeKind() = %v, want nil", event)
+				}
+				return
+			}
+			var re *pkgreconciler.ReconcilerEvent
+			if !pkgreconciler.EventAs(event, &re) || re.EventType != test.wantEvent {
+				t.Errorf("FinalizeKind() = %v, want a %s event", event, test.wantEvent)
+			}
+		})
+	}
+}
+
+func TestIsResharding(t *testing.T) {
+	if !isResharding(redis.Error("TRYAGAIN Multiple keys request during rehashing of slot")) {
+		t.Error("isResharding() = false for TRYAGAIN, want true")
+	}
+	if isResharding(redis.Error("NOGROUP No such key 'mystream'")) {
+		t.Error("isResharding() = true for NOGROUP, want false")
+	}
+}
+
+func TestIsNoStream(t *testing.T) {
+	if !isNoStream(redis.Error("ERR The XGROUP subcommand requires the key to exist. Note that for CREATE you may want to use the MKSTREAM option to create an empty stream automatically.")) {
+		t.Error("isNoStream() = false for a missing stream, want true")
+	}
+	if isNoStream(errors.New("connection refused")) {
+		t.Error("isNoStream() = true for a connection error, want false")
+	}
+}
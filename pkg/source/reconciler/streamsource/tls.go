@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// tlsCheckTimeout bounds the TLS handshake with Redis.
+const tlsCheckTimeout = 5 * time.Second
+
+// tlsChecker checks that TLS can be negotiated with Redis.
+type tlsChecker interface {
+	CheckTLS(ctx context.Context, address string, config *tls.Config) error
+}
+
+// redisTLSChecker negotiates TLS with Redis the same way the receive adapter
+// does, without authenticating or sending any command.
+type redisTLSChecker struct{}
+
+func (redisTLSChecker) CheckTLS(ctx context.Context, address string, config *tls.Config) error {
+	opt, err := scan.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tlsCheckTimeout)
+	defer cancel()
+	conn, err := redis.DialContext(ctx, "tcp", opt.Addr,
+		redis.DialTLSConfig(config),
+		redis.DialUseTLS(true),
+	)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// reconcileTLS reflects in the status whether the receive adapter can
+// negotiate TLS with Redis using the TLS secret of the source.
+func (r *Reconciler) reconcileTLS(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) {
+	if source.Spec.TLS == nil {
+		source.Status.MarkNoTLS()
+		return
+	}
+
+	name := source.Spec.TLS.SecretName
+	config, err := r.redisTLSConfig(ctx, source)
+	var invalid *invalidTLSSecretError
+	switch {
+	case apierrors.IsNotFound(err):
+		source.Status.MarkTLSNotConfigured("SecretNotFound", "TLS secret %q not found", name)
+		return
+	case errors.As(err, &invalid):
+		source.Status.MarkTLSNotConfigured("InvalidSecret", "%v", invalid.err)
+		return
+	case err != nil:
+		source.Status.MarkTLSUnknown("SecretUnavailable", "Cannot get TLS secret %q: %v", name, err)
+		return
+	}
+
+	// The node of a cluster serving the stream is found with the password of
+	// the source, if the controller can read it.
+	creds := redisCredentials{TLS: config, TLSCert: r.tlsCert}
+	if username, password, err := r.redisAuth(ctx, source); err == nil {
+		creds.Username, creds.Password = username, password
+	}
+	address, err := r.redisAddress(ctx, source, creds)
+	if err != nil {
+		source.Status.MarkTLSUnknown("RedisUnreachable", "Cannot connect to Redis: %v", err)
+		return
+	}
+	if err := r.tls.CheckTLS(ctx, address, config); err != nil {
+		if scan.IsTLSError(err) {
+			source.Status.MarkTLSNotConfigured("TLSNegotiationFailed", "Cannot negotiate TLS with Redis: %v", err)
+		} else {
+			source.Status.MarkTLSUnknown("RedisUnreachable", "Cannot connect to Redis: %v", err)
+		}
+		return
+	}
+	source.Status.MarkTLSConfigured()
+}
+
+// invalidTLSSecretError tells that the TLS secret of a source does not hold a
+// usable CA certificate, or client certificate and key.
+type invalidTLSSecretError struct {
+	err error
+}
+
+func (e *invalidTLSSecretError) Error() string {
+	return e.err.Error()
+}
+
+// redisTLSConfig returns the TLS configuration of the TLS secret of the
+// source, the one of its receive adapter.
+func (r *Reconciler) redisTLSConfig(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (*tls.Config, error) {
+	name := source.Spec.TLS.SecretName
+	secret, err := r.kubeClientSet.CoreV1().Secrets(source.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := sourcesv1alpha1.ValidateTLSSecret(secret); err != nil {
+		return nil, &invalidTLSSecretError{err: err}
+	}
+	config, err := scan.NewTLSConfig(secret.Data[sourcesv1alpha1.RedisTLSCACertKey],
+		secret.Data[sourcesv1alpha1.RedisTLSCertKey], secret.Data[sourcesv1alpha1.RedisTLSKeyKey])
+	if err != nil {
+		return nil, &invalidTLSSecretError{err: fmt.Errorf("invalid TLS secret %q: %w", name, err)}
+	}
+	config.InsecureSkipVerify = source.Spec.TLS.InsecureSkipVerify
+	return config, nil
+}
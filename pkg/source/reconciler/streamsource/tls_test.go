@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+type fakeTLSChecker struct {
+	err error
+}
+
+func (f *fakeTLSChecker) CheckTLS(ctx context.Context, address string, config *tls.Config) error {
+	return f.err
+}
+
+func TestRedisTLSChecker(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	address := "rediss://" + server.Listener.Addr().String()
+
+	config, err := scan.NewTLSConfig(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), nil, nil)
+	if err != nil {
+		t.Fatal("NewTLSConfig() =", err)
+	}
+	if err := (redisTLSChecker{}).CheckTLS(context.Background(), address, config); err != nil {
+		t.Error("CheckTLS() =", err)
+	}
+
+	err = (redisTLSChecker{}).CheckTLS(context.Background(), address, &tls.Config{RootCAs: x509.NewCertPool()})
+	if !scan.IsTLSError(err) {
+		t.Errorf("CheckTLS() with an untrusted server = %v, want a TLS error", err)
+	}
+}
+
+func TestReconcileTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	tlsSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "redis-tls"},
+			Data:       data,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		tls        *sourcesv1alpha1.RedisTLS
+		secret     *corev1.Secret
+		checkErr   error
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{{
+		name: "no TLS",
+	}, {
+		name:       "configured",
+		tls:        &sourcesv1alpha1.RedisTLS{SecretName: "redis-tls"},
+		secret:     tlsSecret(map[string][]byte{"ca.crt": caCert}),
+		wantStatus: corev1.ConditionTrue,
+	}, {
+		name:       "secret not found",
+		tls:        &sourcesv1alpha1.RedisTLS{SecretName: "redis-tls"},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "SecretNotFound",
+	}, {
+		name:       "missing CA certificate",
+		tls:        &sourcesv1alpha1.RedisTLS{SecretName: "redis-tls"},
+		secret:     tlsSecret(map[string][]byte{"tls.crt": caCert}),
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "InvalidSecret",
+	}, {
+		name:       "negotiation failed",
+		tls:        &sourcesv1alpha1.RedisTLS{SecretName: "redis-tls"},
+		secret:     tlsSecret(map[string][]byte{"ca.crt": caCert}),
+		checkErr:   x509.UnknownAuthorityError{},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "TLSNegotiationFailed",
+	}, {
+		name:       "redis unreachable",
+		tls:        &sourcesv1alpha1.RedisTLS{SecretName: "redis-tls"},
+		secret:     tlsSecret(map[string][]byte{"ca.crt": caCert}),
+		checkErr:   errors.New("dial tcp: connection refused"),
+		wantStatus: corev1.ConditionUnknown,
+		wantReason: "RedisUnreachable",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			if test.secret != nil {
+				kubeClient = kubefake.NewSimpleClientset(test.secret)
+			}
+			r := &Reconciler{kubeClientSet: kubeClient, tls: &fakeTLSChecker{err: test.checkErr}}
+
+			source := &sourcesv1alpha1.RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: sourcesv1alpha1.RedisConnection{Address: "rediss://redis:6379"},
+					TLS:             test.tls,
+				},
+			}
+			// A previously configured TLS condition is removed with the TLS spec.
+			source.Status.MarkTLSConfigured()
+
+			r.reconcileTLS(context.Background(), source)
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionTLSConfigured)
+			if test.wantStatus == "" {
+				if cond != nil {
+					t.Errorf("TLSConfigured = %+v, want none", cond)
+				}
+				return
+			}
+			if cond == nil {
+				t.Fatal("TLSConfigured condition not set")
+			}
+			if cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("TLSConfigured = %s %q, want %s %q", cond.Status, cond.Reason, test.wantStatus, test.wantReason)
+			}
+		})
+	}
+}
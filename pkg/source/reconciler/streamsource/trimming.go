@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func newWarningTrimJobFailed(name string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "TrimJobFailed", "Failed to reconcile trim job %q: %v", name, err)
+}
+
+// reconcileTrimJob creates, updates or deletes the CronJob trimming the
+// streams of the source following its trimming policy, unless the receive
+// adapter trims them once entries are acknowledged. Its container runs with
+// adapterEnv, the environment of the receive adapter container.
+func (r *Reconciler) reconcileTrimJob(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, adapterEnv []corev1.EnvVar) pkgreconciler.Event {
+	name := resources.TrimJobName(source)
+	cronJobs := r.kubeClientSet.BatchV1().CronJobs(source.Namespace)
+	existing, err := cronJobs.Get(ctx, name, metav1.GetOptions{})
+
+	if t := source.Spec.GetTrimming(); t == nil || t.OnAck {
+		if err == nil && metav1.IsControlledBy(existing, source) {
+			if err := cronJobs.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return newWarningTrimJobFailed(name, err)
+			}
+		}
+		return nil
+	}
+
+	if r.trimJobImage == "" {
+		return newWarningTrimJobFailed(name, fmt.Errorf("the controller has no trim job image"))
+	}
+	expected := resources.MakeTrimJob(source, r.trimJobImage, adapterEnv)
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := cronJobs.Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return newWarningTrimJobFailed(name, err)
+		}
+	case err != nil:
+		return newWarningTrimJobFailed(name, err)
+	case !metav1.IsControlledBy(existing, source):
+		return newWarningTrimJobFailed(name, fmt.Errorf("not owned by %s %q", source.GetGroupVersionKind().Kind, source.Name))
+	case !equality.Semantic.DeepDerivative(expected.Spec, existing.Spec):
+		existing.Spec = expected.Spec
+		if _, err := cronJobs.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return newWarningTrimJobFailed(name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+)
+
+func TestReconcileTrimJob(t *testing.T) {
+	newSource := func(trimming *sourcesv1alpha1.Trimming) *sourcesv1alpha1.RedisStreamSource {
+		return &sourcesv1alpha1.RedisStreamSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source", UID: "1234"},
+			Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+				RedisConnection: sourcesv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+				Stream:          "mystream",
+				Trimming:        trimming,
+			},
+		}
+	}
+	hourly := &sourcesv1alpha1.Trimming{Strategy: sourcesv1alpha1.TrimmingMaxLen, Threshold: "1000", Schedule: "0 * * * *"}
+	env := []corev1.EnvVar{{Name: "ADDRESS", Value: "redis://redis:6379"}}
+
+	tests := []struct {
+		name         string
+		trimming     *sourcesv1alpha1.Trimming
+		existing     *batchv1.CronJob
+		wantEvent    bool
+		wantSchedule string // empty when the CronJob does not exist
+	}{{
+		name: "no trimming",
+	}, {
+		name:         "created",
+		trimming:     hourly,
+		wantSchedule: "0 * * * *",
+	}, {
+		name:         "updated",
+		trimming:     hourly,
+		existing:     resources.MakeTrimJob(newSource(&sourcesv1alpha1.Trimming{Strategy: sourcesv1alpha1.TrimmingMaxLen, Threshold: "1000"}), "trim-image", env),
+		wantSchedule: "0 * * * *",
+	}, {
+		name:     "deleted",
+		existing: resources.MakeTrimJob(newSource(hourly), "trim-image", env),
+	}, {
+		name:     "trimming on ack",
+		trimming: &sourcesv1alpha1.Trimming{Strategy: sourcesv1alpha1.TrimmingMaxLen, Threshold: "1000", OnAck: true},
+		existing: resources.MakeTrimJob(newSource(hourly), "trim-image", env),
+	}, {
+		name:     "not owned",
+		trimming: hourly,
+		existing: &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: resources.TrimJobName(newSource(hourly))},
+			Spec:       batchv1.CronJobSpec{Schedule: "*/5 * * * *"},
+		},
+		wantEvent:    true,
+		wantSchedule: "*/5 * * * *",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			if test.existing != nil {
+				kubeClient = kubefake.NewSimpleClientset(test.existing)
+			}
+			r := &Reconciler{kubeClientSet: kubeClient, trimJobImage: "trim-image"}
+			source := newSource(test.trimming)
+
+			if event := r.reconcileTrimJob(context.Background(), source, env); (event != nil) != test.wantEvent {
+				t.Fatalf("reconcileTrimJob() = %v, want event %v", event, test.wantEvent)
+			}
+
+			cronJob, err := kubeClient.BatchV1().CronJobs("ns").Get(context.Background(), resources.TrimJobName(source), metav1.GetOptions{})
+			if test.wantSchedule == "" {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("CronJob = %v, %v, want none", cronJob, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Get CronJob =", err)
+			}
+			if cronJob.Spec.Schedule != test.wantSchedule {
+				t.Errorf("Schedule = %q, want %q", cronJob.Spec.Schedule, test.wantSchedule)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	redisParse "github.com/go-redis/redis/v8"
+)
+
+// ParseAddress parses the address of a Redis instance: a host:port address,
+// or a redis:// or rediss:// URL, whose port defaults to 6379.
+func ParseAddress(address string) (*redisParse.Options, error) {
+	if !strings.Contains(address, "://") {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("must be host:port or a redis:// or rediss:// URL: %w", err)
+		}
+		if host == "" {
+			return nil, errors.New("missing host")
+		}
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return nil, fmt.Errorf("invalid port %q", port)
+		}
+		address = "redis://" + address
+	} else if scheme := address[:strings.Index(address, "://")]; scheme != "redis" && scheme != "rediss" {
+		return nil, fmt.Errorf("invalid URL scheme %q, must be redis or rediss", scheme)
+	}
+	return redisParse.ParseURL(address)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		wantAddr string
+		wantTLS  bool
+		wantErr  bool
+	}{
+		{address: "redis.redis.svc.cluster.local:6379", wantAddr: "redis.redis.svc.cluster.local:6379"},
+		{address: "10.0.0.1:6380", wantAddr: "10.0.0.1:6380"},
+		{address: "[::1]:6379", wantAddr: "[::1]:6379"},
+		{address: "redis://redis:6379", wantAddr: "redis:6379"},
+		{address: "redis://:s3cr3t@redis", wantAddr: "redis:6379"},
+		{address: "rediss://mycache.redis.cache.windows.net:6380/1", wantAddr: "mycache.redis.cache.windows.net:6380", wantTLS: true},
+		{address: "", wantErr: true},
+		{address: "mymaster", wantErr: true},
+		{address: "redis", wantErr: true},
+		{address: ":6379", wantErr: true},
+		{address: "redis:port", wantErr: true},
+		{address: "redis:70000", wantErr: true},
+		{address: "http://redis:6379", wantErr: true},
+		{address: "unix:///var/run/redis.sock", wantErr: true},
+		{address: "redis://redis:6379/db", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			opt, err := ParseAddress(test.address)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseAddress() = %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if opt.Addr != test.wantAddr {
+				t.Errorf("Addr = %q, want %q", opt.Addr, test.wantAddr)
+			}
+			if got := opt.TLSConfig != nil; got != test.wantTLS {
+				t.Errorf("TLS = %v, want %v", got, test.wantTLS)
+			}
+		})
+	}
+}
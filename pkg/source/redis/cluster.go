@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ClusterSlots is the number of hash slots of a Redis cluster.
+const ClusterSlots = 16384
+
+// KeySlot returns the hash slot of a key in a Redis cluster. Only the hash
+// tag of the key, the part between its first { and the next }, is hashed
+// when it is not empty, so that keys sharing it share the slot.
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % ClusterSlots)
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum Redis Cluster hashes keys with.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// SlotNode asks the cluster nodes, in order, which master serves the slot,
+// and returns the host:port address of the first answer. Nodes are connected
+// to with dial.
+func SlotNode(addresses []string, slot int, dial func(address string) (redis.Conn, error)) (string, error) {
+	var errs []string
+	for _, address := range addresses {
+		node, err := slotNode(address, slot, dial)
+		if err == nil {
+			return node, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+	}
+	return "", fmt.Errorf("cannot get the node serving slot %d from the cluster: %s", slot, strings.Join(errs, "; "))
+}
+
+//1) 1) (integer) 0
+//   2) (integer) 5460
+//   3) 1) "127.0.0.1"
+//      2) (integer) 30001
+//      3) "09dbe9720cda62f7865eabc5fd8857c5d2678366"
+//   4) 1) "127.0.0.1"
+//      2) (integer) 30004
+//      3) "821d8ca00d7ccf931ed3ffc7e3db0599d2271abf"
+
+func slotNode(address string, slot int, dial func(address string) (redis.Conn, error)) (string, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	ranges, err := redis.Values(conn.Do("CLUSTER", "SLOTS"))
+	if err != nil {
+		return "", err
+	}
+	for _, r := range ranges {
+		values, err := redis.Values(r, nil)
+		if err != nil {
+			return "", err
+		}
+		if len(values) < 3 {
+			return "", errors.New("unexpected slot range")
+		}
+		start, _ := redis.Int(values[0], nil)
+		end, _ := redis.Int(values[1], nil)
+		if slot < start || slot > end {
+			continue
+		}
+		master, err := redis.Values(values[2], nil)
+		if err != nil || len(master) < 2 {
+			return "", errors.New("unexpected slot master")
+		}
+		host, _ := redis.String(master[0], nil)
+		port, err := redis.Int(master[1], nil)
+		if err != nil {
+			return "", err
+		}
+		if host == "" {
+			// The node does not know its own address, it is the one asked.
+			host, _, _ = net.SplitHostPort(address)
+		}
+		return net.JoinHostPort(host, fmt.Sprint(port)), nil
+	}
+	return "", fmt.Errorf("slot %d is not served", slot)
+}
+
+// CheckClusterState returns an error unless the node connected to considers
+// the cluster able to serve queries.
+func CheckClusterState(conn redis.Conn) error {
+	info, err := redis.String(conn.Do("CLUSTER", "INFO"))
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(info, "\n") {
+		if state := strings.TrimPrefix(strings.TrimSpace(line), "cluster_state:"); state != strings.TrimSpace(line) {
+			if state != "ok" {
+				return fmt.Errorf("cluster state is %s", state)
+			}
+			return nil
+		}
+	}
+	return errors.New("unknown cluster state")
+}
+
+// IsMoved returns whether a cluster node replied that the slot of the key is
+// served by another node.
+func IsMoved(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "MOVED")
+}
+
+// AskTarget returns the address of the node a cluster node redirected a
+// single command to with an ASK reply, while the slot of the key is being
+// migrated to that node.
+func AskTarget(err error) (string, bool) {
+	var rerr redis.Error
+	if !errors.As(err, &rerr) {
+		return "", false
+	}
+	// The reply is "ASK <slot> <host>:<port>".
+	parts := strings.Fields(string(rerr))
+	if len(parts) != 3 || parts[0] != "ASK" {
+		return "", false
+	}
+	return parts[2], true
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{key: "somekey", want: 11058},
+		{key: "foo{hash_tag}", want: 2515},
+		{key: "redisdedup:{hash_tag}", want: 2515},
+		{key: "123456789", want: 0x31c3 % ClusterSlots},
+	}
+	for _, test := range tests {
+		if got := KeySlot(test.key); got != test.want {
+			t.Errorf("KeySlot(%q) = %d, want %d", test.key, got, test.want)
+		}
+	}
+	// An empty hash tag is not used.
+	if KeySlot("foo{}{hash_tag}") == KeySlot("hash_tag") {
+		t.Error("KeySlot() hashed the second hash tag")
+	}
+}
+
+func TestSlotNode(t *testing.T) {
+	slots := []interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("10.0.0.1"), int64(6379), []byte("id1")}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte(""), int64(6380), []byte("id2")}, []interface{}{[]byte("10.0.0.3"), int64(6379), []byte("id3")}},
+	}
+	dial := dialSentinels(map[string]map[string]interface{}{
+		"node:6379": {"CLUSTER SLOTS": slots},
+	})
+
+	node, err := SlotNode([]string{"down:6379", "node:6379"}, 100, dial)
+	if err != nil || node != "10.0.0.1:6379" {
+		t.Errorf("SlotNode() = %q, %v, want %q", node, err, "10.0.0.1:6379")
+	}
+	// The node asked serves the slot, without knowing its own address.
+	node, err = SlotNode([]string{"node:6379"}, 10000, dial)
+	if err != nil || node != "node:6380" {
+		t.Errorf("SlotNode() = %q, %v, want %q", node, err, "node:6380")
+	}
+
+	_, err = SlotNode([]string{"down:6379"}, 100, dial)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("SlotNode() = %v, want the errors of all the nodes", err)
+	}
+}
+
+func TestCheckClusterState(t *testing.T) {
+	ok := &sentinelConn{replies: map[string]interface{}{"CLUSTER INFO": []byte("cluster_enabled:1\r\ncluster_state:ok\r\ncluster_slots_assigned:16384\r\n")}}
+	if err := CheckClusterState(ok); err != nil {
+		t.Error("CheckClusterState() =", err)
+	}
+	fail := &sentinelConn{replies: map[string]interface{}{"CLUSTER INFO": []byte("cluster_state:fail\r\n")}}
+	if err := CheckClusterState(fail); err == nil || !strings.Contains(err.Error(), "fail") {
+		t.Errorf("CheckClusterState() = %v, want the cluster to be down", err)
+	}
+	disabled := &sentinelConn{replies: map[string]interface{}{"CLUSTER INFO": redis.Error("ERR This instance has cluster support disabled")}}
+	if err := CheckClusterState(disabled); err == nil {
+		t.Error("CheckClusterState() = nil without cluster support, want an error")
+	}
+}
+
+func TestIsMoved(t *testing.T) {
+	if !IsMoved(redis.Error("MOVED 3999 127.0.0.1:6381")) {
+		t.Error("IsMoved() = false for a MOVED reply")
+	}
+	if IsMoved(redis.Error("TRYAGAIN Multiple keys request during rehashing of slot")) {
+		t.Error("IsMoved() = true for a TRYAGAIN reply")
+	}
+}
+
+func TestAskTarget(t *testing.T) {
+	if target, ok := AskTarget(redis.Error("ASK 3999 127.0.0.1:6381")); !ok || target != "127.0.0.1:6381" {
+		t.Errorf("AskTarget() = %q, %v, want 127.0.0.1:6381, true", target, ok)
+	}
+	if _, ok := AskTarget(redis.Error("MOVED 3999 127.0.0.1:6381")); ok {
+		t.Error("AskTarget() = true for a MOVED reply")
+	}
+	if _, ok := AskTarget(nil); ok {
+		t.Error("AskTarget() = true without an error")
+	}
+}
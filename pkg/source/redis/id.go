@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StreamID is the ID of a stream entry, <millisecondsTime>-<sequenceNumber>.
+type StreamID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+// ParseStreamID parses an entry ID. The sequence number defaults to 0 when
+// omitted, as in the ranges of XRANGE.
+func ParseStreamID(id string) (StreamID, error) {
+	ms, seq, hasSeq := strings.Cut(id, "-")
+	var sid StreamID
+	var err error
+	if sid.Ms, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return StreamID{}, fmt.Errorf("invalid entry ID %q, expected <millisecondsTime>-<sequenceNumber>", id)
+	}
+	if hasSeq {
+		if sid.Seq, err = strconv.ParseUint(seq, 10, 64); err != nil {
+			return StreamID{}, fmt.Errorf("invalid entry ID %q, expected <millisecondsTime>-<sequenceNumber>", id)
+		}
+	}
+	return sid, nil
+}
+
+// The special IDs of the stream commands.
+const (
+	// LastID is the ID of the last entry of the stream, as in XGROUP CREATE
+	// and XGROUP SETID.
+	LastID = "$"
+	// NewID reads the entries never delivered to any consumer of the group,
+	// as in XREADGROUP.
+	NewID = ">"
+	// MinID is the smallest possible ID, as the start of XRANGE.
+	MinID = "-"
+	// MaxID is the greatest possible ID, as the end of XRANGE.
+	MaxID = "+"
+	// AutoID lets Redis generate the ID of the entry added with XADD.
+	AutoID = "*"
+)
+
+// IDPosition is where an ID is used, which determines the special IDs it
+// can be.
+type IDPosition int
+
+const (
+	// EntryPosition only accepts entry IDs, e.g. to compare them to the IDs
+	// of the entries read.
+	EntryPosition IDPosition = iota
+	// GroupStartPosition is where a consumer group starts reading from, as in
+	// XGROUP CREATE and XGROUP SETID: an entry ID or LastID.
+	GroupStartPosition
+	// ReadPosition is where a consumer reads from, as in XREADGROUP: an entry
+	// ID, to read its pending entries, or NewID.
+	ReadPosition
+	// RangeStartPosition is the start of a range of entries, as in XRANGE: an
+	// entry ID or MinID.
+	RangeStartPosition
+	// RangeEndPosition is the end of a range of entries, as in XRANGE: an
+	// entry ID or MaxID.
+	RangeEndPosition
+)
+
+var positionSpecialIDs = map[IDPosition]string{
+	GroupStartPosition: LastID,
+	ReadPosition:       NewID,
+	RangeStartPosition: MinID,
+	RangeEndPosition:   MaxID,
+}
+
+var positionNames = map[IDPosition]string{
+	EntryPosition:      "an entry ID",
+	GroupStartPosition: "a group start position",
+	ReadPosition:       "a read position",
+	RangeStartPosition: "a range start",
+	RangeEndPosition:   "a range end",
+}
+
+// ID is an entry ID or a special ID.
+type ID struct {
+	// Special is the special ID, or empty for an entry ID.
+	Special string
+	// StreamID is the entry ID, when Special is empty.
+	StreamID StreamID
+}
+
+// ParseID parses an ID used at the given position, which is either an entry
+// ID or the special ID valid there. Special IDs are rejected elsewhere, e.g.
+// AutoID as a read position, which Redis would either reject or interpret
+// differently.
+func ParseID(id string, position IDPosition) (ID, error) {
+	switch id {
+	case LastID, NewID, MinID, MaxID, AutoID:
+		if positionSpecialIDs[position] != id {
+			return ID{}, fmt.Errorf("special ID %q cannot be used as %s", id, positionNames[position])
+		}
+		return ID{Special: id}, nil
+	}
+	sid, err := ParseStreamID(id)
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{StreamID: sid}, nil
+}
+
+func (id ID) String() string {
+	if id.Special != "" {
+		return id.Special
+	}
+	return id.StreamID.String()
+}
+
+// Less returns true when id is before other in the stream.
+func (id StreamID) Less(other StreamID) bool {
+	return id.Ms < other.Ms || (id.Ms == other.Ms && id.Seq < other.Seq)
+}
+
+func (id StreamID) String() string {
+	return fmt.Sprintf("%d-%d", id.Ms, id.Seq)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStreamID(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    StreamID
+		wantErr bool
+	}{
+		{id: "1526919030474-55", want: StreamID{Ms: 1526919030474, Seq: 55}},
+		{id: "1526919030474", want: StreamID{Ms: 1526919030474}},
+		{id: "0-1", want: StreamID{Seq: 1}},
+		{id: "", wantErr: true},
+		{id: "$", wantErr: true},
+		{id: "1526919030474-", wantErr: true},
+		{id: "-55", wantErr: true},
+		{id: "1526919030474-55-1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseStreamID(test.id)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseStreamID(%q) error = %v, wantErr %v", test.id, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("ParseStreamID(%q) = %v, want %v", test.id, got, test.want)
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		id       string
+		position IDPosition
+		want     ID
+		wantErr  string
+	}{
+		{id: "1526919030474-55", position: EntryPosition, want: ID{StreamID: StreamID{Ms: 1526919030474, Seq: 55}}},
+		{id: "1526919030474", position: EntryPosition, want: ID{StreamID: StreamID{Ms: 1526919030474}}},
+		{id: "$", position: EntryPosition, wantErr: `special ID "$" cannot be used as an entry ID`},
+		{id: ">", position: EntryPosition, wantErr: `special ID ">" cannot be used as an entry ID`},
+		{id: "-", position: EntryPosition, wantErr: `special ID "-" cannot be used as an entry ID`},
+		{id: "+", position: EntryPosition, wantErr: `special ID "+" cannot be used as an entry ID`},
+		{id: "*", position: EntryPosition, wantErr: `special ID "*" cannot be used as an entry ID`},
+		{id: "", position: EntryPosition, wantErr: "invalid entry ID"},
+		{id: "1526919030474-*", position: EntryPosition, wantErr: "invalid entry ID"},
+
+		{id: "$", position: GroupStartPosition, want: ID{Special: LastID}},
+		{id: "0", position: GroupStartPosition, want: ID{}},
+		{id: "1526919030474-55", position: GroupStartPosition, want: ID{StreamID: StreamID{Ms: 1526919030474, Seq: 55}}},
+		{id: ">", position: GroupStartPosition, wantErr: `special ID ">" cannot be used as a group start position`},
+		{id: "*", position: GroupStartPosition, wantErr: `special ID "*" cannot be used as a group start position`},
+		{id: "-", position: GroupStartPosition, wantErr: `special ID "-" cannot be used as a group start position`},
+
+		{id: ">", position: ReadPosition, want: ID{Special: NewID}},
+		{id: "0", position: ReadPosition, want: ID{}},
+		{id: "$", position: ReadPosition, wantErr: `special ID "$" cannot be used as a read position`},
+		{id: "*", position: ReadPosition, wantErr: `special ID "*" cannot be used as a read position`},
+
+		{id: "-", position: RangeStartPosition, want: ID{Special: MinID}},
+		{id: "1526919030474", position: RangeStartPosition, want: ID{StreamID: StreamID{Ms: 1526919030474}}},
+		{id: "+", position: RangeStartPosition, wantErr: `special ID "+" cannot be used as a range start`},
+		{id: "$", position: RangeStartPosition, wantErr: `special ID "$" cannot be used as a range start`},
+
+		{id: "+", position: RangeEndPosition, want: ID{Special: MaxID}},
+		{id: "1526919030474-55", position: RangeEndPosition, want: ID{StreamID: StreamID{Ms: 1526919030474, Seq: 55}}},
+		{id: "-", position: RangeEndPosition, wantErr: `special ID "-" cannot be used as a range end`},
+		{id: "*", position: RangeEndPosition, wantErr: `special ID "*" cannot be used as a range end`},
+	}
+
+	for _, test := range tests {
+		got, err := ParseID(test.id, test.position)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("ParseID(%q, %d) error = %v, want %q", test.id, test.position, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseID(%q, %d) error = %v", test.id, test.position, err)
+		}
+		if got != test.want {
+			t.Errorf("ParseID(%q, %d) = %v, want %v", test.id, test.position, got, test.want)
+		}
+	}
+}
+
+func TestIDString(t *testing.T) {
+	if got := (ID{Special: LastID}).String(); got != "$" {
+		t.Errorf("String() = %q, want %q", got, "$")
+	}
+	if got := (ID{StreamID: StreamID{Ms: 1526919030474}}).String(); got != "1526919030474-0" {
+		t.Errorf("String() = %q, want %q", got, "1526919030474-0")
+	}
+}
+
+func TestStreamIDLess(t *testing.T) {
+	tests := []struct {
+		id, other StreamID
+		want      bool
+	}{
+		{id: StreamID{Ms: 1, Seq: 5}, other: StreamID{Ms: 2, Seq: 0}, want: true},
+		{id: StreamID{Ms: 2, Seq: 0}, other: StreamID{Ms: 2, Seq: 1}, want: true},
+		{id: StreamID{Ms: 2, Seq: 1}, other: StreamID{Ms: 2, Seq: 1}},
+		{id: StreamID{Ms: 3, Seq: 0}, other: StreamID{Ms: 2, Seq: 9}},
+	}
+
+	for _, test := range tests {
+		if got := test.id.Less(test.other); got != test.want {
+			t.Errorf("%v.Less(%v) = %v, want %v", test.id, test.other, got, test.want)
+		}
+	}
+}
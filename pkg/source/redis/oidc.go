@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// oidcExchangeTimeout bounds each exchange of an OIDC token for a
+	// password.
+	oidcExchangeTimeout = 10 * time.Second
+
+	// JWTBearerAssertion is the type of the client assertion of RFC 7523.
+	JWTBearerAssertion = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// OIDCExchange is the exchange of a service account token for a Redis
+// password at the OAuth 2.0 token endpoint of a security token service.
+type OIDCExchange struct {
+	TokenURL string
+	ClientID string
+	Scope    string
+}
+
+// oidcTokenResponse is the response of the OAuth 2.0 token endpoint.
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange exchanges the token for a Redis password, with the client
+// credentials grant authenticated by the token as a client assertion. It
+// returns the password and how long it is valid for, 0 when the token
+// endpoint does not tell.
+func (e OIDCExchange) Exchange(ctx context.Context, client *http.Client, token string) (string, time.Duration, error) {
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {JWTBearerAssertion},
+		"client_assertion":      {strings.TrimSpace(token)},
+	}
+	if e.ClientID != "" {
+		form.Set("client_id", e.ClientID)
+	}
+	if e.Scope != "" {
+		form.Set("scope", e.Scope)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oidcExchangeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	var body oidcTokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return "", 0, fmt.Errorf("cannot decode the response of the token endpoint: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("the token endpoint answered %s: %s %s", resp.Status, body.Error, body.ErrorDescription)
+	}
+	if body.AccessToken == "" {
+		return "", 0, fmt.Errorf("the token endpoint answered no access token")
+	}
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
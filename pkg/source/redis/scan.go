@@ -96,9 +96,14 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 			}
 			dst[i].Items[j].ID = id
 
-			fvs, err := redis.Values(item[1], nil)
-			if err != nil {
-				return nil, err
+			// The field-value pairs of pending entries deleted since they were
+			// read are nil.
+			var fvs []interface{}
+			if item[1] != nil {
+				fvs, err = redis.Values(item[1], nil)
+				if err != nil {
+					return nil, err
+				}
 			}
 
 			if len(dst[i].Items[j].FieldValues) != len(fvs) {
@@ -135,6 +140,8 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 //6) (integer) 0
 //7) last-delivered-id
 //8) "1588152498034-0"
+//
+// Since Redis 7.0, each group also has entries-read and lag fields.
 
 type StreamGroups map[string]StreamGroup
 
@@ -145,6 +152,10 @@ type StreamGroup struct {
 	Pending int
 	// LastDeliveredId is the ID of the last delivered item
 	LastDeliveredId string
+	// Lag is the number of items not delivered to the group yet. It is nil
+	// when Redis does not report it, before Redis 7.0 or when it cannot be
+	// computed, for instance after entries were deleted.
+	Lag *int64
 }
 
 func ScanXInfoGroupReply(reply interface{}, err error) (StreamGroups, error) {
@@ -187,10 +198,20 @@ func ScanXInfoGroupReply(reply interface{}, err error) (StreamGroups, error) {
 			return nil, err
 		}
 
+		var lag *int64
+		if len(entries) == 12 && entries[11] != nil {
+			n, err := redis.Int64(entries[11], nil)
+			if err != nil {
+				return nil, err
+			}
+			lag = &n
+		}
+
 		dst[name] = StreamGroup{
 			Consumers:       consumers,
 			Pending:         pending,
 			LastDeliveredId: lastid,
+			Lag:             lag,
 		}
 	}
 	return dst, nil
@@ -59,6 +59,26 @@ func TestScanXRead(t *testing.T) {
 				},
 			},
 		},
+		{
+			// pending entry deleted since it was read
+			reply: []interface{}{
+				[]interface{}{
+					[]byte("mystream"),
+					[]interface{}{
+						[]interface{}{
+							[]byte("1519073278252-0"),
+							nil}}}},
+			expected: []StreamElement{
+				{
+					Name: "mystream",
+					Items: []StreamItem{
+						{
+							ID: "1519073278252-0",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		actual, err := ScanXReadReply(tc.reply, nil)
@@ -73,3 +93,60 @@ func TestScanXRead(t *testing.T) {
 	}
 
 }
+
+func TestScanXInfoGroup(t *testing.T) {
+	lag := int64(3)
+	tests := []struct {
+		name     string
+		reply    []interface{}
+		expected StreamGroups
+	}{{
+		name: "before Redis 7",
+		reply: []interface{}{
+			[]interface{}{
+				[]byte("name"), []byte("mygroup"),
+				[]byte("consumers"), int64(2),
+				[]byte("pending"), int64(2),
+				[]byte("last-delivered-id"), []byte("1588152489012-0")}},
+		expected: StreamGroups{
+			"mygroup": {Consumers: 2, Pending: 2, LastDeliveredId: "1588152489012-0"},
+		},
+	}, {
+		name: "with lag",
+		reply: []interface{}{
+			[]interface{}{
+				[]byte("name"), []byte("mygroup"),
+				[]byte("consumers"), int64(2),
+				[]byte("pending"), int64(2),
+				[]byte("last-delivered-id"), []byte("1588152489012-0"),
+				[]byte("entries-read"), int64(5),
+				[]byte("lag"), int64(3)}},
+		expected: StreamGroups{
+			"mygroup": {Consumers: 2, Pending: 2, LastDeliveredId: "1588152489012-0", Lag: &lag},
+		},
+	}, {
+		name: "unknown lag",
+		reply: []interface{}{
+			[]interface{}{
+				[]byte("name"), []byte("mygroup"),
+				[]byte("consumers"), int64(2),
+				[]byte("pending"), int64(2),
+				[]byte("last-delivered-id"), []byte("1588152489012-0"),
+				[]byte("entries-read"), nil,
+				[]byte("lag"), nil}},
+		expected: StreamGroups{
+			"mygroup": {Consumers: 2, Pending: 2, LastDeliveredId: "1588152489012-0"},
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ScanXInfoGroupReply(tc.reply, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Unexpected difference (-want, +got): %v", diff)
+			}
+		})
+	}
+}
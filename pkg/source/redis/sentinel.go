@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// MasterAddr asks the sentinels, in order, for the host:port address of the
+// master named masterName, and returns the first answer. Sentinels are
+// connected to with dial.
+func MasterAddr(addresses []string, masterName string, dial func(address string) (redis.Conn, error)) (string, error) {
+	var errs []string
+	for _, address := range addresses {
+		addr, err := masterAddr(address, masterName, dial)
+		if err == nil {
+			return addr, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+	}
+	return "", fmt.Errorf("cannot get the address of master %q from the sentinels: %s", masterName, strings.Join(errs, "; "))
+}
+
+func masterAddr(address, masterName string, dial func(address string) (redis.Conn, error)) (string, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	reply, err := redis.Strings(conn.Do("SENTINEL", "GET-MASTER-ADDR-BY-NAME", masterName))
+	if errors.Is(err, redis.ErrNil) {
+		return "", errors.New("unknown master")
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(reply) != 2 {
+		return "", fmt.Errorf("unexpected master address %q", reply)
+	}
+	return net.JoinHostPort(reply[0], reply[1]), nil
+}
+
+// CheckQuorum asks the first sentinel that can be reached whether the
+// sentinels reach the quorum needed to fail the master named masterName over.
+func CheckQuorum(addresses []string, masterName string, dial func(address string) (redis.Conn, error)) error {
+	var errs []string
+	for _, address := range addresses {
+		conn, err := dial(address)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+		_, err = conn.Do("SENTINEL", "CKQUORUM", masterName)
+		conn.Close()
+		return err
+	}
+	return fmt.Errorf("cannot reach the sentinels: %s", strings.Join(errs, "; "))
+}
+
+// IsNoQuorum returns whether a sentinel replied that the sentinels do not
+// reach the quorum needed to fail the master over, e.g. while they are still
+// discovering each other.
+func IsNoQuorum(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.HasPrefix(string(rerr), "NOQUORUM")
+}
+
+// CheckMaster returns an error unless conn is connected to a Redis master,
+// which a master reported by the sentinels may no longer be while it is
+// failed over.
+func CheckMaster(conn redis.Conn) error {
+	role, err := redis.Values(conn.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+	if len(role) == 0 {
+		return errors.New("empty role")
+	}
+	name, err := redis.String(role[0], nil)
+	if err != nil {
+		return err
+	}
+	if name != "master" {
+		return fmt.Errorf("connected to a %s instead of the master", name)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// sentinelConn replies to the commands with the scripted replies, keyed by
+// command and first argument.
+type sentinelConn struct {
+	redis.Conn
+	replies map[string]interface{}
+}
+
+func (c *sentinelConn) Close() error { return nil }
+func (c *sentinelConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	key := cmd
+	if len(args) > 0 {
+		key += " " + args[0].(string)
+	}
+	reply := c.replies[key]
+	if err, ok := reply.(error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func dialSentinels(sentinels map[string]map[string]interface{}) func(string) (redis.Conn, error) {
+	return func(address string) (redis.Conn, error) {
+		replies, ok := sentinels[address]
+		if !ok {
+			return nil, errors.New("connection refused")
+		}
+		return &sentinelConn{replies: replies}, nil
+	}
+}
+
+func TestMasterAddr(t *testing.T) {
+	dial := dialSentinels(map[string]map[string]interface{}{
+		"unknown:26379":  {"SENTINEL GET-MASTER-ADDR-BY-NAME": nil},
+		"sentinel:26379": {"SENTINEL GET-MASTER-ADDR-BY-NAME": []interface{}{[]byte("10.0.0.1"), []byte("6379")}},
+	})
+
+	addr, err := MasterAddr([]string{"down:26379", "unknown:26379", "sentinel:26379"}, "mymaster", dial)
+	if err != nil {
+		t.Fatal("MasterAddr() =", err)
+	}
+	if addr != "10.0.0.1:6379" {
+		t.Errorf("MasterAddr() = %q, want %q", addr, "10.0.0.1:6379")
+	}
+
+	_, err = MasterAddr([]string{"down:26379", "unknown:26379"}, "mymaster", dial)
+	if err == nil || !strings.Contains(err.Error(), "connection refused") || !strings.Contains(err.Error(), "unknown master") {
+		t.Errorf("MasterAddr() = %v, want the errors of all the sentinels", err)
+	}
+}
+
+func TestCheckQuorum(t *testing.T) {
+	noQuorum := redis.Error("NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the specified quorum for this master")
+	dial := dialSentinels(map[string]map[string]interface{}{
+		"ok:26379":       {"SENTINEL CKQUORUM": "OK 3 usable Sentinels. Quorum and failover authorization can be reached"},
+		"noquorum:26379": {"SENTINEL CKQUORUM": noQuorum},
+	})
+
+	if err := CheckQuorum([]string{"down:26379", "ok:26379"}, "mymaster", dial); err != nil {
+		t.Error("CheckQuorum() =", err)
+	}
+	if err := CheckQuorum([]string{"noquorum:26379", "ok:26379"}, "mymaster", dial); !IsNoQuorum(err) {
+		t.Errorf("CheckQuorum() = %v, want no quorum", err)
+	}
+	if err := CheckQuorum([]string{"down:26379"}, "mymaster", dial); err == nil || IsNoQuorum(err) {
+		t.Errorf("CheckQuorum() = %v, want the sentinels to be unreachable", err)
+	}
+}
+
+func TestCheckMaster(t *testing.T) {
+	master := &sentinelConn{replies: map[string]interface{}{"ROLE": []interface{}{[]byte("master"), int64(0), []interface{}{}}}}
+	if err := CheckMaster(master); err != nil {
+		t.Error("CheckMaster() =", err)
+	}
+	replica := &sentinelConn{replies: map[string]interface{}{"ROLE": []interface{}{[]byte("slave"), []byte("10.0.0.1"), int64(6379), []byte("connected"), int64(0)}}}
+	if err := CheckMaster(replica); err == nil {
+		t.Error("CheckMaster() = nil for a replica, want an error")
+	}
+}
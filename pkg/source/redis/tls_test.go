@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func certificatePEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	caCert := certificatePEM(server)
+
+	config, err := NewTLSConfig(caCert, nil, nil)
+	if err != nil {
+		t.Fatal("NewTLSConfig() =", err)
+	}
+	if len(config.Certificates) != 0 {
+		t.Errorf("Certificates = %d, want none", len(config.Certificates))
+	}
+
+	conn, err := tls.Dial("tcp", server.Listener.Addr().String(), config)
+	if err != nil {
+		t.Fatal("TLS handshake failed:", err)
+	}
+	conn.Close()
+
+	if _, err := NewTLSConfig([]byte("not a certificate"), nil, nil); err == nil {
+		t.Error("NewTLSConfig() with an invalid CA certificate succeeded")
+	}
+	if _, err := NewTLSConfig(caCert, caCert, nil); err == nil {
+		t.Error("NewTLSConfig() with a client certificate without key succeeded")
+	}
+}
+
+func TestIsTLSError(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	// Trusting no CA.
+	config := &tls.Config{RootCAs: x509.NewCertPool()}
+	_, err := tls.Dial("tcp", server.Listener.Addr().String(), config)
+	if !IsTLSError(err) {
+		t.Errorf("IsTLSError(%v) = false, want true", err)
+	}
+
+	// Nothing listening.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	_, err = tls.Dial("tcp", addr, config)
+	if err == nil || IsTLSError(err) {
+		t.Errorf("IsTLSError(%v) = true, want false", err)
+	}
+
+	if IsTLSError(nil) {
+		t.Error("IsTLSError(nil) = true, want false")
+	}
+}
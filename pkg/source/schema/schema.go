@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema fetches the JSON Schemas the entries of RedisStreamSources
+// are validated against, for the reconciler to check that they resolve and for
+// the receive adapter to validate the entries.
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	// ErrNotFound is returned when the URL of the schema answers 404 Not Found.
+	ErrNotFound = errors.New("schema not found")
+
+	// ErrInvalid is returned when the document of the schema is not a valid
+	// JSON Schema.
+	ErrInvalid = errors.New("invalid schema")
+)
+
+// maxSchemaBytes bounds the size of the documents of the schemas.
+const maxSchemaBytes = 1 << 20
+
+// Fetch downloads the JSON Schema at url with client and compiles it. The
+// schemas it references are downloaded with client too.
+func Fetch(ctx context.Context, client *http.Client, url string) (*jsonschema.Schema, error) {
+	body, err := get(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(ref string) (io.ReadCloser, error) {
+		return get(ctx, client, ref)
+	}
+	if err := compiler.AddResource(url, body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	schema, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return schema, nil
+}
+
+// get returns the body of the document at url, which the caller closes.
+func get(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w at %s", ErrNotFound, url)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, maxSchemaBytes), resp.Body}, nil
+}
+
+// Entry returns the JSON value the fields of a stream entry are validated as:
+// an object holding the value of each field, as a string.
+func Entry(fieldValues []string) map[string]interface{} {
+	entry := make(map[string]interface{}, len(fieldValues)/2)
+	for i := 0; i+1 < len(fieldValues); i += 2 {
+		entry[fieldValues[i]] = fieldValues[i+1]
+	}
+	return entry
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/order.json":
+			w.Write([]byte(`{"type": "object", "required": ["id"], "properties": {"id": {"$ref": "id.json"}}}`))
+		case "/id.json":
+			w.Write([]byte(`{"type": "string", "pattern": "^[0-9]+$"}`))
+		case "/invalid.json":
+			w.Write([]byte(`{"type": 42}`))
+		case "/broken.json":
+			w.Write([]byte(`{"type":`))
+		case "/unavailable.json":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	schema, err := Fetch(context.Background(), server.Client(), server.URL+"/order.json")
+	if err != nil {
+		t.Fatal("Fetch() =", err)
+	}
+	if err := schema.Validate(Entry([]string{"id", "42", "note", "rush"})); err != nil {
+		t.Error("Validate(valid entry) =", err)
+	}
+	if err := schema.Validate(Entry([]string{"id", "forty-two"})); err == nil {
+		t.Error("Validate(entry not matching the referenced schema) = nil, want error")
+	}
+	if err := schema.Validate(Entry([]string{"note", "rush"})); err == nil {
+		t.Error("Validate(entry without a required field) = nil, want error")
+	}
+
+	tests := []struct {
+		path string
+		want error
+	}{
+		{path: "/missing.json", want: ErrNotFound},
+		{path: "/invalid.json", want: ErrInvalid},
+		{path: "/broken.json", want: ErrInvalid},
+		{path: "/unavailable.json"},
+	}
+	for _, test := range tests {
+		_, err := Fetch(context.Background(), server.Client(), server.URL+test.path)
+		if err == nil {
+			t.Errorf("Fetch(%s) = nil, want error", test.path)
+			continue
+		}
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("Fetch(%s) = %v, want %v", test.path, err, test.want)
+		}
+		if test.want == nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalid)) {
+			t.Errorf("Fetch(%s) = %v, want a fetch error", test.path, err)
+		}
+	}
+}